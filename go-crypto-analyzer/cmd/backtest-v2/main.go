@@ -9,8 +9,12 @@ import (
 	"github.com/fatih/color"
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
+	"github.com/zjc/go-crypto-analyzer/internal/config"
+	"github.com/zjc/go-crypto-analyzer/pkg/analysis"
 	"github.com/zjc/go-crypto-analyzer/pkg/backtest"
 	"github.com/zjc/go-crypto-analyzer/pkg/data"
+	"github.com/zjc/go-crypto-analyzer/pkg/i18n"
+	"github.com/zjc/go-crypto-analyzer/pkg/indicators"
 )
 
 var (
@@ -26,31 +30,74 @@ var (
 	useYahoo       bool
 	enableShort    bool
 	useImproved    bool
+	avwapStop      bool
+	stopMode       string
+	stPeriod       int
+	stMultiplier   float64
+	sarStep        float64
+	sarMax         float64
+	// K线变换
+	transformName string
+	brickSize     float64
+	brickATR      int
+	// 相对强度
+	rsUniverse  []string
+	rsLookbacks string
+	minRS       float64
+	// 配置文件
+	configFile  string
+	configDir   string
+	profileName string
+	// 自定义证据规则文件
+	ruleFiles []string
+	// 输出语言
+	lang string
 )
 
 var rootCmd = &cobra.Command{
-	Use:   "backtest-v2",
-	Short: "双向交易策略回测",
-	Long:  `支持做多做空的交易策略回测工具`,
-	Run:   runBacktest,
+	Use: "backtest-v2",
+	Run: runBacktest,
 }
 
-func init() {
-	rootCmd.Flags().StringVarP(&symbol, "symbol", "s", "BTCUSDT", "交易对")
-	rootCmd.Flags().StringVarP(&interval, "interval", "i", "1h", "K线时间间隔")
-	rootCmd.Flags().IntVarP(&days, "days", "d", 30, "回测天数")
-	rootCmd.Flags().Float64VarP(&initialCapital, "capital", "c", 10000, "初始资金")
-	rootCmd.Flags().Float64VarP(&longThreshold, "long", "L", 0.5, "做多阈值")
-	rootCmd.Flags().Float64VarP(&shortThreshold, "short", "S", -0.5, "做空阈值")
-	rootCmd.Flags().Float64VarP(&closeThreshold, "close", "C", 0.0, "平仓阈值")
-	rootCmd.Flags().Float64VarP(&stopLoss, "stoploss", "l", 0.03, "止损百分比")
-	rootCmd.Flags().Float64VarP(&takeProfit, "takeprofit", "t", 0.06, "止盈百分比")
-	rootCmd.Flags().BoolVarP(&useYahoo, "yahoo", "y", false, "使用Yahoo Finance数据源")
-	rootCmd.Flags().BoolVarP(&enableShort, "enable-short", "E", true, "启用做空")
-	rootCmd.Flags().BoolVarP(&useImproved, "improved", "I", false, "使用改进的策略")
+// setupCommand 设置命令说明和参数；帮助文本按当前语言生成，需在设置语言之后调用
+func setupCommand() {
+	rootCmd.Short = i18n.T("cli.backtest_v2_short")
+	rootCmd.Long = i18n.T("cli.backtest_v2_long")
+	rootCmd.Flags().StringVarP(&symbol, "symbol", "s", "BTCUSDT", i18n.T("flag.symbol"))
+	rootCmd.Flags().StringVarP(&interval, "interval", "i", "1h", i18n.T("flag.interval"))
+	rootCmd.Flags().IntVarP(&days, "days", "d", 30, i18n.T("flag.days"))
+	rootCmd.Flags().Float64VarP(&initialCapital, "capital", "c", 10000, i18n.T("flag.capital"))
+	rootCmd.Flags().Float64VarP(&longThreshold, "long", "L", 0.5, i18n.T("flag.long"))
+	rootCmd.Flags().Float64VarP(&shortThreshold, "short", "S", -0.5, i18n.T("flag.short"))
+	rootCmd.Flags().Float64VarP(&closeThreshold, "close", "C", 0.0, i18n.T("flag.close"))
+	rootCmd.Flags().Float64VarP(&stopLoss, "stoploss", "l", 0.03, i18n.T("flag.stoploss"))
+	rootCmd.Flags().Float64VarP(&takeProfit, "takeprofit", "t", 0.06, i18n.T("flag.takeprofit"))
+	rootCmd.Flags().BoolVarP(&useYahoo, "yahoo", "y", false, i18n.T("flag.yahoo"))
+	rootCmd.Flags().BoolVarP(&enableShort, "enable-short", "E", true, i18n.T("flag.enable_short"))
+	rootCmd.Flags().BoolVarP(&useImproved, "improved", "I", false, i18n.T("flag.improved"))
+	rootCmd.Flags().BoolVar(&avwapStop, "avwap-stop", false, i18n.T("flag.improved_avwap_stop"))
+	rootCmd.Flags().StringVar(&stopMode, "stop-mode", "atr", i18n.T("flag.stop_mode"))
+	rootCmd.Flags().IntVar(&stPeriod, "st-period", 10, i18n.T("flag.st_period"))
+	rootCmd.Flags().Float64Var(&stMultiplier, "st-mult", 3.0, i18n.T("flag.st_mult"))
+	rootCmd.Flags().Float64Var(&sarStep, "sar-step", 0.02, i18n.T("flag.sar_step"))
+	rootCmd.Flags().Float64Var(&sarMax, "sar-max", 0.2, i18n.T("flag.sar_max"))
+	rootCmd.Flags().StringVar(&transformName, "transform", "", i18n.T("flag.transform"))
+	rootCmd.Flags().Float64Var(&brickSize, "brick-size", 0, i18n.T("flag.brick_size"))
+	rootCmd.Flags().IntVar(&brickATR, "brick-atr", 14, i18n.T("flag.brick_atr"))
+	rootCmd.Flags().StringSliceVar(&rsUniverse, "rs-universe", []string{}, i18n.T("flag.rs_universe"))
+	rootCmd.Flags().StringVar(&rsLookbacks, "rs-lookbacks", "1d:0.2,7d:0.3,30d:0.5", i18n.T("flag.rs_lookbacks"))
+	rootCmd.Flags().StringVar(&configFile, "config", "", i18n.T("flag.config"))
+	rootCmd.Flags().StringVar(&configDir, "config-dir", "", i18n.T("flag.config_dir"))
+	rootCmd.Flags().StringVar(&profileName, "profile", "", i18n.T("flag.profile"))
+	rootCmd.Flags().Float64Var(&minRS, "min-rs", 0, i18n.T("flag.min_rs"))
+	rootCmd.Flags().StringArrayVar(&ruleFiles, "rules", []string{}, i18n.T("flag.rules"))
+	rootCmd.Flags().StringVar(&lang, "lang", "", i18n.T("flag.lang"))
 }
 
 func main() {
+	// 帮助文本在解析参数之前生成，先按 --lang 或系统语言环境设置语言
+	i18n.SetLanguage(i18n.ArgsLanguage(os.Args[1:]))
+	setupCommand()
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
 		os.Exit(1)
@@ -58,66 +105,141 @@ func main() {
 }
 
 func runBacktest(cmd *cobra.Command, args []string) {
+	if lang == "" {
+		lang = i18n.DetectLanguage()
+	}
+	if err := i18n.SetLanguage(lang); err != nil {
+		color.Red("❌ %v", err)
+		return
+	}
+
 	fmt.Printf("\n%s\n", strings.Repeat("=", 80))
-	fmt.Printf("📊 双向交易回测 - %s\n", time.Now().Format("2006-01-02 15:04:05"))
+	fmt.Println(i18n.T("backtest.title_v2", time.Now().Format("2006-01-02 15:04:05")))
 	fmt.Printf("%s\n", strings.Repeat("=", 80))
 	
+	mode, err := backtest.ParseStopMode(stopMode)
+	if err != nil {
+		color.Red("❌ %v", err)
+		return
+	}
+	transform, err := indicators.NewTransform(transformName, brickSize, brickATR)
+	if err != nil {
+		color.Red("❌ %v", err)
+		return
+	}
+	
+	// 加载配置；显式指定的命令行参数优先
+	appConfig, err := config.Load(config.LoadOptions{Dir: configDir, File: configFile, Profile: profileName})
+	if err != nil {
+		color.Red("❌ %v", err)
+		return
+	}
+	if !cmd.Flags().Changed("interval") {
+		interval = appConfig.Analysis.Interval
+	}
+	if !cmd.Flags().Changed("yahoo") {
+		useYahoo = appConfig.Datasource.Primary == "yahoo"
+	}
+	
 	// 创建数据获取器
 	var fetcher data.Fetcher
 	if useYahoo {
 		fetcher = data.NewYahooFinanceFetcher()
-		fmt.Println("使用Yahoo Finance数据源")
+		fmt.Println(i18n.T("common.source_yahoo"))
 	} else {
 		fetcher = data.NewBinanceFetcher()
-		fmt.Println("使用Binance数据源")
+		fmt.Println(i18n.T("common.source_binance"))
 	}
 	
 	// 计算需要的K线数量
 	limit := calculateLimit(interval, days)
 	
-	fmt.Printf("\n⏳ 获取历史数据: %s, %s, %d根K线...\n", symbol, interval, limit)
+	fmt.Printf("\n%s\n", i18n.T("backtest.fetching", symbol, interval, limit))
 	
 	// 获取历史数据
 	ohlcv, err := fetcher.FetchOHLCV(symbol, interval, limit)
 	if err != nil {
-		color.Red("❌ 获取数据失败: %v", err)
+		color.Red(i18n.T("common.fetch_failed"), err)
 		return
 	}
 	
-	fmt.Printf("✅ 成功获取 %d 根K线数据\n", len(ohlcv))
+	fmt.Println(i18n.T("backtest.fetched", len(ohlcv)))
 	
 	// 创建支持做空的回测器
 	backtester := backtest.NewBacktesterV2(initialCapital)
 	backtester.EnableShort(enableShort)
 	backtester.SetThresholds(longThreshold, shortThreshold, closeThreshold)
 	backtester.UseImprovedStrategy(useImproved)
+	backtester.UseAnchoredVWAPStop(avwapStop)
+	backtester.SetStopMode(mode)
+	backtester.SetSuperTrendParams(stPeriod, stMultiplier)
+	backtester.SetSARParams(sarStep, sarMax)
+	backtester.SetTransform(transform)
+	if err := backtester.SetAnalysisSettings(appConfig.AnalysisSettings()); err != nil {
+		color.Red("❌ %v", err)
+		return
+	}
+	backtester.SetLearning(appConfig.LearningSettings())
+	ruleSet, err := analysis.LoadEvidenceRules(ruleFiles...)
+	if err != nil {
+		color.Red(i18n.T("common.rules_load_failed"), err)
+		return
+	}
+	if err := backtester.SetEvidenceRules(ruleSet); err != nil {
+		color.Red("❌ %v", err)
+		return
+	}
+	if len(rsUniverse) > 0 {
+		rsConfig := backtest.RelativeStrengthConfig{Universe: rsUniverse, Lookbacks: rsLookbacks, MinPercentile: minRS}
+		if err := backtest.SetupRelativeStrength(backtester, fetcher, symbol, interval, limit, rsConfig); err != nil {
+			color.Red("❌ %v", err)
+			return
+		}
+	}
+	if cfg, ok := config.CryptoConfig[symbol]; ok {
+		backtester.SetKeyLevels(cfg.KeyLevels)
+	}
 	
-	fmt.Printf("\n📈 回测参数:\n")
-	fmt.Printf("  初始资金: $%.2f\n", initialCapital)
+	fmt.Printf("\n%s\n", i18n.T("backtest.params"))
+	fmt.Println(i18n.T("backtest.initial_capital", initialCapital))
 	if !useImproved {
-		fmt.Printf("  做多阈值: %.2f\n", longThreshold)
-		fmt.Printf("  做空阈值: %.2f\n", shortThreshold)
-		fmt.Printf("  平仓阈值: %.2f\n", closeThreshold)
-		fmt.Printf("  止损: %.1f%%\n", stopLoss*100)
-		fmt.Printf("  止盈: %.1f%%\n", takeProfit*100)
+		fmt.Println(i18n.T("backtest.long_threshold", longThreshold))
+		fmt.Println(i18n.T("backtest.short_threshold", shortThreshold))
+		fmt.Println(i18n.T("backtest.close_threshold", closeThreshold))
+		fmt.Println(i18n.T("backtest.stop_loss", stopLoss*100))
+		fmt.Println(i18n.T("backtest.take_profit", takeProfit*100))
+	}
+	if appConfig.Profile != "" {
+		fmt.Println(i18n.T("backtest.profile", appConfig.Profile, appConfig.Profiles[appConfig.Profile].Description))
+	}
+	if transform.Active() {
+		fmt.Println(i18n.T("backtest.transform", transform))
+	}
+	if len(rsUniverse) > 0 {
+		fmt.Print(i18n.T("backtest.rs", strings.Join(rsUniverse, ","), rsLookbacks))
+		if minRS > 0 {
+			fmt.Print(i18n.T("backtest.rs_min", minRS))
+		}
+		fmt.Println(i18n.T("backtest.rs_end"))
 	}
 	if enableShort {
-		color.Green("  ✅ 启用做空")
+		color.Green(i18n.T("backtest.short_enabled"))
 	} else {
-		color.Yellow("  ❌ 禁用做空")
+		color.Yellow(i18n.T("backtest.short_disabled"))
 	}
 	if useImproved {
-		color.Cyan("  📊 使用改进的策略（动态止损、市场状态适应）")
+		color.Cyan(i18n.T("backtest.improved"))
+		fmt.Println(i18n.T("backtest.stop_mode", mode))
 	} else {
-		fmt.Printf("  📊 使用基础策略")
+		fmt.Print(i18n.T("backtest.basic"))
 	}
 	
-	fmt.Printf("\n⚙️  运行回测...\n")
+	fmt.Printf("\n%s\n", i18n.T("backtest.running"))
 	
 	// 运行回测
 	result, err := backtester.RunBacktestV2(symbol, ohlcv)
 	if err != nil {
-		color.Red("❌ 回测失败: %v", err)
+		color.Red(i18n.T("backtest.failed"), err)
 		return
 	}
 	
@@ -144,51 +266,51 @@ func calculateLimit(interval string, days int) int {
 
 func displayResults(result *backtest.BacktestResultV2) {
 	fmt.Printf("\n%s\n", strings.Repeat("=", 80))
-	fmt.Println("📊 双向交易回测结果")
+	fmt.Println(i18n.T("backtest.result_title_v2"))
 	fmt.Printf("%s\n", strings.Repeat("=", 80))
 	
 	// 基本统计
-	fmt.Printf("\n💰 资金变化:\n")
-	fmt.Printf("  初始资金: $%.2f\n", result.InitialCapital)
-	fmt.Printf("  最终资金: ")
+	fmt.Printf("\n%s\n", i18n.T("backtest.capital"))
+	fmt.Println(i18n.T("backtest.initial_capital", result.InitialCapital))
+	fmt.Print(i18n.T("backtest.final_capital"))
 	if result.FinalCapital > result.InitialCapital {
 		color.Green("$%.2f", result.FinalCapital)
 	} else {
 		color.Red("$%.2f", result.FinalCapital)
 	}
-	fmt.Printf("\n  总收益: ")
+	fmt.Print("\n" + i18n.T("backtest.total_return"))
 	if result.TotalReturn > 0 {
 		color.Green("$%.2f (%.2f%%)", result.TotalReturn, result.TotalReturnPct*100)
 	} else {
 		color.Red("$%.2f (%.2f%%)", result.TotalReturn, result.TotalReturnPct*100)
 	}
-	fmt.Printf("\n  最大回撤: ")
+	fmt.Print("\n" + i18n.T("backtest.max_drawdown"))
 	color.Red("%.2f%%", result.MaxDrawdownPct*100)
 	
-	fmt.Printf("\n\n📈 交易统计:\n")
-	fmt.Printf("  总交易次数: %d\n", result.TotalTrades)
-	fmt.Printf("  做多交易: %s\n", color.BlueString("%d", result.LongTrades))
-	fmt.Printf("  做空交易: %s\n", color.MagentaString("%d", result.ShortTrades))
-	fmt.Printf("  获利交易: %s\n", color.GreenString("%d", result.WinningTrades))
-	fmt.Printf("  亏损交易: %s\n", color.RedString("%d", result.LosingTrades))
-	fmt.Printf("  胜率: %.1f%%\n", result.WinRate*100)
+	fmt.Printf("\n\n%s\n", i18n.T("backtest.trade_stats"))
+	fmt.Println(i18n.T("backtest.total_trades", result.TotalTrades))
+	fmt.Println(i18n.T("backtest.long_trades", color.BlueString("%d", result.LongTrades)))
+	fmt.Println(i18n.T("backtest.short_trades", color.MagentaString("%d", result.ShortTrades)))
+	fmt.Println(i18n.T("backtest.winning_trades", color.GreenString("%d", result.WinningTrades)))
+	fmt.Println(i18n.T("backtest.losing_trades", color.RedString("%d", result.LosingTrades)))
+	fmt.Println(i18n.T("backtest.win_rate", result.WinRate*100))
 	
 	if result.AverageWin > 0 || result.AverageLoss > 0 {
-		fmt.Printf("\n💵 盈亏分析:\n")
-		fmt.Printf("  平均盈利: $%.2f\n", result.AverageWin)
-		fmt.Printf("  平均亏损: $%.2f\n", result.AverageLoss)
+		fmt.Printf("\n%s\n", i18n.T("backtest.pnl"))
+		fmt.Println(i18n.T("backtest.average_win", result.AverageWin))
+		fmt.Println(i18n.T("backtest.average_loss", result.AverageLoss))
 		if result.ProfitFactor > 0 {
-			fmt.Printf("  盈亏比: %.2f\n", result.ProfitFactor)
+			fmt.Println(i18n.T("backtest.profit_factor", result.ProfitFactor))
 		}
 	}
 	
-	fmt.Printf("\n📊 风险指标:\n")
-	fmt.Printf("  夏普比率: %.2f\n", result.SharpeRatio)
-	fmt.Printf("  卡尔玛比率: %.2f\n", result.CalmarRatio)
+	fmt.Printf("\n%s\n", i18n.T("backtest.risk"))
+	fmt.Println(i18n.T("backtest.sharpe", result.SharpeRatio))
+	fmt.Println(i18n.T("backtest.calmar", result.CalmarRatio))
 	
 	// 做多做空统计
 	if result.LongTrades > 0 || result.ShortTrades > 0 {
-		fmt.Printf("\n📊 方向统计:\n")
+		fmt.Printf("\n%s\n", i18n.T("backtest.direction_stats"))
 		
 		longWins := 0
 		shortWins := 0
@@ -211,7 +333,7 @@ func displayResults(result *backtest.BacktestResultV2) {
 		
 		if result.LongTrades > 0 {
 			longWinRate := float64(longWins) / float64(result.LongTrades) * 100
-			fmt.Printf("  做多胜率: %.1f%% (盈亏: ", longWinRate)
+			fmt.Print(i18n.T("backtest.long_win_rate", longWinRate))
 			if longProfit > 0 {
 				color.Green("$%.2f", longProfit)
 			} else {
@@ -222,7 +344,7 @@ func displayResults(result *backtest.BacktestResultV2) {
 		
 		if result.ShortTrades > 0 {
 			shortWinRate := float64(shortWins) / float64(result.ShortTrades) * 100
-			fmt.Printf("  做空胜率: %.1f%% (盈亏: ", shortWinRate)
+			fmt.Print(i18n.T("backtest.short_win_rate", shortWinRate))
 			if shortProfit > 0 {
 				color.Green("$%.2f", shortProfit)
 			} else {
@@ -234,10 +356,10 @@ func displayResults(result *backtest.BacktestResultV2) {
 	
 	// 交易明细表
 	if len(result.Trades) > 0 {
-		fmt.Printf("\n📋 交易明细 (最近15笔):\n")
+		fmt.Printf("\n%s\n", i18n.T("backtest.trades_title", 15))
 		
 		table := tablewriter.NewWriter(os.Stdout)
-		table.SetHeader([]string{"时间", "方向", "入场价", "出场价", "收益", "收益率", "信号"})
+		table.SetHeader(i18n.List("backtest.trades_header_v2"))
 		table.SetBorder(false)
 		table.SetAlignment(tablewriter.ALIGN_LEFT)
 		
@@ -252,9 +374,9 @@ func displayResults(result *backtest.BacktestResultV2) {
 			
 			directionStr := trade.Direction
 			if trade.Direction == "LONG" {
-				directionStr = color.BlueString("做多")
+				directionStr = color.BlueString(i18n.T("backtest.long"))
 			} else if trade.Direction == "SHORT" {
-				directionStr = color.MagentaString("做空")
+				directionStr = color.MagentaString(i18n.T("backtest.short"))
 			}
 			
 			profitStr := fmt.Sprintf("$%.2f", trade.Profit)
@@ -275,42 +397,42 @@ func displayResults(result *backtest.BacktestResultV2) {
 				fmt.Sprintf("$%.2f", trade.ExitPrice),
 				profitStr,
 				profitPctStr,
-				trade.ExitSignal,
+				trade.ExitSignal.String(),
 			})
 		}
 		
 		table.Render()
 		
-		fmt.Printf("\n共 %d 笔交易，显示最近 %d 笔\n", len(result.Trades), len(result.Trades)-start)
+		fmt.Printf("\n%s\n", i18n.T("backtest.trades_shown", len(result.Trades), len(result.Trades)-start))
 	}
 	
 	// 策略评价
-	fmt.Printf("\n💡 策略评价:\n")
+	fmt.Printf("\n%s\n", i18n.T("backtest.evaluation"))
 	if result.TotalReturnPct > 0.2 {
-		color.Green("  ✅ 策略表现优秀，年化收益可观")
+		color.Green(i18n.T("backtest.excellent"))
 	} else if result.TotalReturnPct > 0 {
-		color.Yellow("  ⚠️  策略有盈利，但收益率一般")
+		color.Yellow(i18n.T("backtest.profitable"))
 	} else {
-		color.Red("  ❌ 策略亏损，需要优化参数或改进策略")
+		color.Red(i18n.T("backtest.losing"))
 	}
 	
 	if result.MaxDrawdownPct > 0.2 {
-		color.Red("  ⚠️  最大回撤较大，风险控制需要加强")
+		color.Red(i18n.T("backtest.high_drawdown"))
 	}
 	
 	if result.WinRate < 0.4 {
-		color.Yellow("  ⚠️  胜率较低，考虑优化入场条件")
+		color.Yellow(i18n.T("backtest.low_win_rate"))
 	}
 	
 	if result.SharpeRatio < 1 {
-		color.Yellow("  ⚠️  夏普比率较低，收益风险比需要改善")
+		color.Yellow(i18n.T("backtest.low_sharpe"))
 	} else if result.SharpeRatio > 2 {
-		color.Green("  ✅ 夏普比率优秀，风险调整后收益良好")
+		color.Green(i18n.T("backtest.high_sharpe"))
 	}
 	
 	if result.CalmarRatio > 3 {
-		color.Green("  ✅ 卡尔玛比率优秀，收益回撤比良好")
+		color.Green(i18n.T("backtest.high_calmar"))
 	} else if result.CalmarRatio < 1 {
-		color.Red("  ⚠️  卡尔玛比率较低，回撤控制需要改善")
+		color.Red(i18n.T("backtest.low_calmar"))
 	}
 }
\ No newline at end of file
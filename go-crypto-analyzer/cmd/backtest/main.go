@@ -9,8 +9,12 @@ import (
 	"github.com/fatih/color"
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
+	"github.com/zjc/go-crypto-analyzer/internal/config"
+	"github.com/zjc/go-crypto-analyzer/pkg/analysis"
 	"github.com/zjc/go-crypto-analyzer/pkg/backtest"
 	"github.com/zjc/go-crypto-analyzer/pkg/data"
+	"github.com/zjc/go-crypto-analyzer/pkg/i18n"
+	"github.com/zjc/go-crypto-analyzer/pkg/indicators"
 )
 
 var (
@@ -24,29 +28,64 @@ var (
 	takeProfit     float64
 	useYahoo       bool
 	strategyType   string
+	avwapStop      bool
+	rsiSpec        string
+	// K线变换
+	transformName string
+	brickSize     float64
+	brickATR      int
+	// 相对强度
+	rsUniverse  []string
+	rsLookbacks string
+	minRS       float64
+	// 配置文件
+	configFile  string
+	configDir   string
+	profileName string
+	// 自定义证据规则文件
+	ruleFiles []string
+	// 输出语言
+	lang string
 )
 
 var rootCmd = &cobra.Command{
-	Use:   "backtest",
-	Short: "回测交易策略",
-	Long:  `基于技术指标的交易策略回测工具`,
-	Run:   runBacktest,
+	Use: "backtest",
+	Run: runBacktest,
 }
 
-func init() {
-	rootCmd.Flags().StringVarP(&symbol, "symbol", "s", "BTCUSDT", "交易对")
-	rootCmd.Flags().StringVarP(&interval, "interval", "i", "1h", "K线时间间隔")
-	rootCmd.Flags().IntVarP(&days, "days", "d", 30, "回测天数")
-	rootCmd.Flags().Float64VarP(&initialCapital, "capital", "c", 10000, "初始资金")
-	rootCmd.Flags().Float64VarP(&entryThreshold, "entry", "e", 0.5, "入场阈值")
-	rootCmd.Flags().Float64VarP(&exitThreshold, "exit", "x", -0.2, "出场阈值")
-	rootCmd.Flags().Float64VarP(&stopLoss, "stoploss", "l", 0.05, "止损百分比")
-	rootCmd.Flags().Float64VarP(&takeProfit, "takeprofit", "t", 0.10, "止盈百分比")
-	rootCmd.Flags().BoolVarP(&useYahoo, "yahoo", "y", false, "使用Yahoo Finance数据源")
-	rootCmd.Flags().StringVarP(&strategyType, "strategy", "S", "simple", "策略类型: simple|trend|momentum|reversal|combo")
+// setupCommand 设置命令说明和参数；帮助文本按当前语言生成，需在设置语言之后调用
+func setupCommand() {
+	rootCmd.Short = i18n.T("cli.backtest_short")
+	rootCmd.Long = i18n.T("cli.backtest_long")
+	rootCmd.Flags().StringVarP(&symbol, "symbol", "s", "BTCUSDT", i18n.T("flag.symbol"))
+	rootCmd.Flags().StringVarP(&interval, "interval", "i", "1h", i18n.T("flag.interval"))
+	rootCmd.Flags().IntVarP(&days, "days", "d", 30, i18n.T("flag.days"))
+	rootCmd.Flags().Float64VarP(&initialCapital, "capital", "c", 10000, i18n.T("flag.capital"))
+	rootCmd.Flags().Float64VarP(&entryThreshold, "entry", "e", 0.5, i18n.T("flag.entry"))
+	rootCmd.Flags().Float64VarP(&exitThreshold, "exit", "x", -0.2, i18n.T("flag.exit"))
+	rootCmd.Flags().Float64VarP(&stopLoss, "stoploss", "l", 0.05, i18n.T("flag.stoploss"))
+	rootCmd.Flags().Float64VarP(&takeProfit, "takeprofit", "t", 0.10, i18n.T("flag.takeprofit"))
+	rootCmd.Flags().BoolVarP(&useYahoo, "yahoo", "y", false, i18n.T("flag.yahoo"))
+	rootCmd.Flags().StringVarP(&strategyType, "strategy", "S", "simple", i18n.T("flag.strategy"))
+	rootCmd.Flags().BoolVar(&avwapStop, "avwap-stop", false, i18n.T("flag.trend_avwap_stop"))
+	rootCmd.Flags().StringVar(&rsiSpec, "rsi-spec", "", i18n.T("flag.rsi_spec"))
+	rootCmd.Flags().StringVar(&transformName, "transform", "", i18n.T("flag.transform"))
+	rootCmd.Flags().Float64Var(&brickSize, "brick-size", 0, i18n.T("flag.brick_size"))
+	rootCmd.Flags().IntVar(&brickATR, "brick-atr", 14, i18n.T("flag.brick_atr"))
+	rootCmd.Flags().StringSliceVar(&rsUniverse, "rs-universe", []string{}, i18n.T("flag.rs_universe"))
+	rootCmd.Flags().StringVar(&rsLookbacks, "rs-lookbacks", "1d:0.2,7d:0.3,30d:0.5", i18n.T("flag.rs_lookbacks"))
+	rootCmd.Flags().StringVar(&configFile, "config", "", i18n.T("flag.config"))
+	rootCmd.Flags().StringVar(&configDir, "config-dir", "", i18n.T("flag.config_dir"))
+	rootCmd.Flags().StringVar(&profileName, "profile", "", i18n.T("flag.profile"))
+	rootCmd.Flags().Float64Var(&minRS, "min-rs", 0, i18n.T("flag.min_rs"))
+	rootCmd.Flags().StringArrayVar(&ruleFiles, "rules", []string{}, i18n.T("flag.rules"))
+	rootCmd.Flags().StringVar(&lang, "lang", "", i18n.T("flag.lang"))
 }
 
 func main() {
+	// 帮助文本在解析参数之前生成，先按 --lang 或系统语言环境设置语言
+	i18n.SetLanguage(i18n.ArgsLanguage(os.Args[1:]))
+	setupCommand()
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
 		os.Exit(1)
@@ -54,77 +93,150 @@ func main() {
 }
 
 func runBacktest(cmd *cobra.Command, args []string) {
+	if lang == "" {
+		lang = i18n.DetectLanguage()
+	}
+	if err := i18n.SetLanguage(lang); err != nil {
+		color.Red("❌ %v", err)
+		return
+	}
+
 	fmt.Printf("\n%s\n", strings.Repeat("=", 80))
-	fmt.Printf("📊 回测分析 - %s\n", time.Now().Format("2006-01-02 15:04:05"))
+	fmt.Println(i18n.T("backtest.title", time.Now().Format("2006-01-02 15:04:05")))
 	fmt.Printf("%s\n", strings.Repeat("=", 80))
 	
+	transform, err := indicators.NewTransform(transformName, brickSize, brickATR)
+	if err != nil {
+		color.Red("❌ %v", err)
+		return
+	}
+	
+	// 加载配置；显式指定的命令行参数优先
+	appConfig, err := config.Load(config.LoadOptions{Dir: configDir, File: configFile, Profile: profileName})
+	if err != nil {
+		color.Red("❌ %v", err)
+		return
+	}
+	if !cmd.Flags().Changed("interval") {
+		interval = appConfig.Analysis.Interval
+	}
+	if !cmd.Flags().Changed("yahoo") {
+		useYahoo = appConfig.Datasource.Primary == "yahoo"
+	}
+	
 	// 创建数据获取器
 	var fetcher data.Fetcher
 	if useYahoo {
 		fetcher = data.NewYahooFinanceFetcher()
-		fmt.Println("使用Yahoo Finance数据源")
+		fmt.Println(i18n.T("common.source_yahoo"))
 	} else {
 		fetcher = data.NewBinanceFetcher()
-		fmt.Println("使用Binance数据源")
+		fmt.Println(i18n.T("common.source_binance"))
 	}
 	
 	// 计算需要的K线数量
 	limit := calculateLimit(interval, days)
 	
-	fmt.Printf("\n⏳ 获取历史数据: %s, %s, %d根K线...\n", symbol, interval, limit)
+	fmt.Printf("\n%s\n", i18n.T("backtest.fetching", symbol, interval, limit))
 	
 	// 获取历史数据
 	ohlcv, err := fetcher.FetchOHLCV(symbol, interval, limit)
 	if err != nil {
-		color.Red("❌ 获取数据失败: %v", err)
+		color.Red(i18n.T("common.fetch_failed"), err)
 		return
 	}
 	
-	fmt.Printf("✅ 成功获取 %d 根K线数据\n", len(ohlcv))
+	fmt.Println(i18n.T("backtest.fetched", len(ohlcv)))
 	
 	// 创建回测器
 	backtester := backtest.NewBacktester(initialCapital)
+	if cfg, ok := config.CryptoConfig[symbol]; ok {
+		backtester.SetKeyLevels(cfg.KeyLevels)
+	}
+	backtester.SetTransform(transform)
+	if err := backtester.SetAnalysisSettings(appConfig.AnalysisSettings()); err != nil {
+		color.Red("❌ %v", err)
+		return
+	}
+	ruleSet, err := analysis.LoadEvidenceRules(ruleFiles...)
+	if err != nil {
+		color.Red(i18n.T("common.rules_load_failed"), err)
+		return
+	}
+	if err := backtester.SetEvidenceRules(ruleSet); err != nil {
+		color.Red("❌ %v", err)
+		return
+	}
+	if len(rsUniverse) > 0 {
+		rsConfig := backtest.RelativeStrengthConfig{Universe: rsUniverse, Lookbacks: rsLookbacks, MinPercentile: minRS}
+		if err := backtest.SetupRelativeStrength(backtester, fetcher, symbol, interval, limit, rsConfig); err != nil {
+			color.Red("❌ %v", err)
+			return
+		}
+	}
 	
 	// 根据策略类型设置策略
 	var strategy backtest.TradingStrategy
 	switch strategyType {
 	case "trend":
-		strategy = backtest.NewTrendFollowingStrategy()
-		fmt.Println("📊 使用趋势跟踪策略")
+		trendStrategy := backtest.NewTrendFollowingStrategy()
+		trendStrategy.UseAnchoredVWAPStop(avwapStop)
+		strategy = trendStrategy
+		fmt.Println(i18n.T("backtest.strategy_trend"))
 	case "momentum":
 		strategy = backtest.NewMomentumBreakoutStrategy()
-		fmt.Println("📊 使用动量突破策略")
+		fmt.Println(i18n.T("backtest.strategy_momentum"))
 	case "reversal":
-		strategy = backtest.NewMeanReversionStrategy()
-		fmt.Println("📊 使用均值回归策略")
+		reversalStrategy := backtest.NewMeanReversionStrategy()
+		if rsiSpec != "" {
+			if err := reversalStrategy.SetRSISpec(rsiSpec); err != nil {
+				color.Red("❌ %v", err)
+				return
+			}
+		}
+		strategy = reversalStrategy
+		fmt.Println(i18n.T("backtest.strategy_reversal"))
 	case "combo":
 		strategy = backtest.NewComboAdaptiveStrategy()
-		fmt.Println("📊 使用自适应组合策略")
+		fmt.Println(i18n.T("backtest.strategy_combo"))
 	default:
 		// 使用简单策略
 		backtester.SetStrategy(entryThreshold, exitThreshold, stopLoss, takeProfit)
-		fmt.Println("📊 使用简单阈值策略")
+		fmt.Println(i18n.T("backtest.strategy_simple"))
 	}
 	
 	if strategy != nil {
 		backtester.SetTradingStrategy(strategy)
 	}
 	
-	fmt.Printf("\n📈 回测参数:\n")
-	fmt.Printf("  初始资金: $%.2f\n", initialCapital)
+	fmt.Printf("\n%s\n", i18n.T("backtest.params"))
+	fmt.Println(i18n.T("backtest.initial_capital", initialCapital))
 	if strategyType == "simple" {
-		fmt.Printf("  入场阈值: %.2f\n", entryThreshold)
-		fmt.Printf("  出场阈值: %.2f\n", exitThreshold)
-		fmt.Printf("  止损: %.1f%%\n", stopLoss*100)
-		fmt.Printf("  止盈: %.1f%%\n", takeProfit*100)
+		fmt.Println(i18n.T("backtest.entry_threshold", entryThreshold))
+		fmt.Println(i18n.T("backtest.exit_threshold", exitThreshold))
+		fmt.Println(i18n.T("backtest.stop_loss", stopLoss*100))
+		fmt.Println(i18n.T("backtest.take_profit", takeProfit*100))
+	}
+	if appConfig.Profile != "" {
+		fmt.Println(i18n.T("backtest.profile", appConfig.Profile, appConfig.Profiles[appConfig.Profile].Description))
+	}
+	if transform.Active() {
+		fmt.Println(i18n.T("backtest.transform", transform))
+	}
+	if len(rsUniverse) > 0 {
+		fmt.Print(i18n.T("backtest.rs", strings.Join(rsUniverse, ","), rsLookbacks))
+		if minRS > 0 {
+			fmt.Print(i18n.T("backtest.rs_min", minRS))
+		}
+		fmt.Println(i18n.T("backtest.rs_end"))
 	}
 	
-	fmt.Printf("\n⚙️  运行回测...\n")
+	fmt.Printf("\n%s\n", i18n.T("backtest.running"))
 	
 	// 运行回测
 	result, err := backtester.RunBacktest(symbol, ohlcv)
 	if err != nil {
-		color.Red("❌ 回测失败: %v", err)
+		color.Red(i18n.T("backtest.failed"), err)
 		return
 	}
 	
@@ -152,51 +264,51 @@ func calculateLimit(interval string, days int) int {
 
 func displayResults(result *backtest.BacktestResult) {
 	fmt.Printf("\n%s\n", strings.Repeat("=", 80))
-	fmt.Println("📊 回测结果")
+	fmt.Println(i18n.T("backtest.result_title"))
 	fmt.Printf("%s\n", strings.Repeat("=", 80))
 	
 	// 基本统计
-	fmt.Printf("\n💰 资金变化:\n")
-	fmt.Printf("  初始资金: $%.2f\n", result.InitialCapital)
-	fmt.Printf("  最终资金: ", )
+	fmt.Printf("\n%s\n", i18n.T("backtest.capital"))
+	fmt.Println(i18n.T("backtest.initial_capital", result.InitialCapital))
+	fmt.Print(i18n.T("backtest.final_capital"))
 	if result.FinalCapital > result.InitialCapital {
 		color.Green("$%.2f", result.FinalCapital)
 	} else {
 		color.Red("$%.2f", result.FinalCapital)
 	}
-	fmt.Printf("\n  总收益: ")
+	fmt.Print("\n" + i18n.T("backtest.total_return"))
 	if result.TotalReturn > 0 {
 		color.Green("$%.2f (%.2f%%)", result.TotalReturn, result.TotalReturnPct*100)
 	} else {
 		color.Red("$%.2f (%.2f%%)", result.TotalReturn, result.TotalReturnPct*100)
 	}
-	fmt.Printf("\n  最大回撤: ")
+	fmt.Print("\n" + i18n.T("backtest.max_drawdown"))
 	color.Red("%.2f%%", result.MaxDrawdownPct*100)
 	
-	fmt.Printf("\n\n📈 交易统计:\n")
-	fmt.Printf("  总交易次数: %d\n", result.TotalTrades)
-	fmt.Printf("  获利交易: %s\n", color.GreenString("%d", result.WinningTrades))
-	fmt.Printf("  亏损交易: %s\n", color.RedString("%d", result.LosingTrades))
-	fmt.Printf("  胜率: %.1f%%\n", result.WinRate*100)
+	fmt.Printf("\n\n%s\n", i18n.T("backtest.trade_stats"))
+	fmt.Println(i18n.T("backtest.total_trades", result.TotalTrades))
+	fmt.Println(i18n.T("backtest.winning_trades", color.GreenString("%d", result.WinningTrades)))
+	fmt.Println(i18n.T("backtest.losing_trades", color.RedString("%d", result.LosingTrades)))
+	fmt.Println(i18n.T("backtest.win_rate", result.WinRate*100))
 	
 	if result.AverageWin > 0 || result.AverageLoss > 0 {
-		fmt.Printf("\n💵 盈亏分析:\n")
-		fmt.Printf("  平均盈利: $%.2f\n", result.AverageWin)
-		fmt.Printf("  平均亏损: $%.2f\n", result.AverageLoss)
+		fmt.Printf("\n%s\n", i18n.T("backtest.pnl"))
+		fmt.Println(i18n.T("backtest.average_win", result.AverageWin))
+		fmt.Println(i18n.T("backtest.average_loss", result.AverageLoss))
 		if result.ProfitFactor > 0 {
-			fmt.Printf("  盈亏比: %.2f\n", result.ProfitFactor)
+			fmt.Println(i18n.T("backtest.profit_factor", result.ProfitFactor))
 		}
 	}
 	
-	fmt.Printf("\n📊 风险指标:\n")
-	fmt.Printf("  夏普比率: %.2f\n", result.SharpeRatio)
+	fmt.Printf("\n%s\n", i18n.T("backtest.risk"))
+	fmt.Println(i18n.T("backtest.sharpe", result.SharpeRatio))
 	
 	// 交易明细表
 	if len(result.Trades) > 0 {
-		fmt.Printf("\n📋 交易明细 (最近10笔):\n")
+		fmt.Printf("\n%s\n", i18n.T("backtest.trades_title", 10))
 		
 		table := tablewriter.NewWriter(os.Stdout)
-		table.SetHeader([]string{"入场时间", "入场价", "出场时间", "出场价", "收益", "收益率", "信号"})
+		table.SetHeader(i18n.List("backtest.trades_header"))
 		table.SetBorder(false)
 		table.SetAlignment(tablewriter.ALIGN_LEFT)
 		
@@ -227,36 +339,36 @@ func displayResults(result *backtest.BacktestResult) {
 				fmt.Sprintf("$%.2f", trade.ExitPrice),
 				profitStr,
 				profitPctStr,
-				trade.ExitSignal,
+				trade.ExitSignal.String(),
 			})
 		}
 		
 		table.Render()
 		
-		fmt.Printf("\n共 %d 笔交易，显示最近 %d 笔\n", len(result.Trades), len(result.Trades)-start)
+		fmt.Printf("\n%s\n", i18n.T("backtest.trades_shown", len(result.Trades), len(result.Trades)-start))
 	}
 	
 	// 策略评价
-	fmt.Printf("\n💡 策略评价:\n")
+	fmt.Printf("\n%s\n", i18n.T("backtest.evaluation"))
 	if result.TotalReturnPct > 0.2 {
-		color.Green("  ✅ 策略表现优秀，年化收益可观")
+		color.Green(i18n.T("backtest.excellent"))
 	} else if result.TotalReturnPct > 0 {
-		color.Yellow("  ⚠️  策略有盈利，但收益率一般")
+		color.Yellow(i18n.T("backtest.profitable"))
 	} else {
-		color.Red("  ❌ 策略亏损，需要优化参数或改进策略")
+		color.Red(i18n.T("backtest.losing"))
 	}
 	
 	if result.MaxDrawdownPct > 0.2 {
-		color.Red("  ⚠️  最大回撤较大，风险控制需要加强")
+		color.Red(i18n.T("backtest.high_drawdown"))
 	}
 	
 	if result.WinRate < 0.4 {
-		color.Yellow("  ⚠️  胜率较低，考虑优化入场条件")
+		color.Yellow(i18n.T("backtest.low_win_rate"))
 	}
 	
 	if result.SharpeRatio < 1 {
-		color.Yellow("  ⚠️  夏普比率较低，收益风险比需要改善")
+		color.Yellow(i18n.T("backtest.low_sharpe"))
 	} else if result.SharpeRatio > 2 {
-		color.Green("  ✅ 夏普比率优秀，风险调整后收益良好")
+		color.Green(i18n.T("backtest.high_sharpe"))
 	}
 }
\ No newline at end of file
@@ -2,7 +2,9 @@ package main
 
 import (
 	"fmt"
+	"math"
 	"os"
+	"sort"
 	"strings"
 	"time"
 
@@ -14,45 +16,116 @@ import (
 	"github.com/zjc/go-crypto-analyzer/pkg/analysis"
 	"github.com/zjc/go-crypto-analyzer/pkg/cache"
 	"github.com/zjc/go-crypto-analyzer/pkg/data"
+	"github.com/zjc/go-crypto-analyzer/pkg/export"
+	"github.com/zjc/go-crypto-analyzer/pkg/i18n"
+	"github.com/zjc/go-crypto-analyzer/pkg/indicators"
+	"github.com/zjc/go-crypto-analyzer/pkg/stats"
 	"github.com/zjc/go-crypto-analyzer/pkg/types"
+	"github.com/zjc/go-crypto-analyzer/pkg/utils"
 )
 
 var (
-	symbols    []string
-	watchlist  string
-	interval   string
-	limit      int
-	useYahoo   bool
-	continuous bool
-	delay      int
-	useCache   bool
-	clearCache bool
-	cacheDir   string
-	cacheTTL   int
+	symbols     []string
+	watchlist   string
+	interval    string
+	limit       int
+	useYahoo    bool
+	continuous  bool
+	delay       int
+	useCache    bool
+	clearCache  bool
+	cacheDir    string
+	cacheTTL    int
+	vwapSession string
+	avwapFrom   string
+	pivotMethod string
+	pivotPeriod string
+	// 自定义指标规格及分析器返回的规范规格
+	indicatorSpecs []string
+	indicatorKeys  []string
+	listIndicators bool
+	// 按规格导出的指标序列
+	exportIndicators string
+	exportSpecs      []string
+	// 自定义条件表达式
+	expressions []string
+	// 自定义证据规则文件
+	ruleFiles []string
+	// K线变换
+	transformName string
+	brickSize     float64
+	brickATR      int
+	transform     indicators.Transform
+	// 跨资产相关性
+	noCorrelation bool
+	corrWindow    int
+	corrThreshold float64
+	corrRolling   int
+	// 相对强度排行
+	noRelativeStrength bool
+	rsLookbacks        string
+	// 多周期共振
+	multiTimeframe bool
+	timeframes     []string
+	// 配置文件
+	configFile  string
+	configDir   string
+	profileName string
+	appConfig   *config.Config
+	// 输出语言
+	lang string
 )
 
 var rootCmd = &cobra.Command{
-	Use:   "crypto-analyzer",
-	Short: "加密货币市场趋势分析工具",
-	Long:  `使用Go语言开发的加密货币市场技术分析工具，支持多种技术指标和实时监控。`,
-	Run:   runAnalysis,
-}
-
-func init() {
-	rootCmd.Flags().StringSliceVarP(&symbols, "symbols", "s", []string{}, "要分析的交易对列表")
-	rootCmd.Flags().StringVarP(&watchlist, "watchlist", "w", "top3", "使用预设的监控列表")
-	rootCmd.Flags().StringVarP(&interval, "interval", "i", "1h", "K线时间间隔 (15m/30m/1h/4h/1d)")
-	rootCmd.Flags().IntVarP(&limit, "limit", "l", 100, "获取K线数量")
-	rootCmd.Flags().BoolVarP(&useYahoo, "yahoo", "y", false, "使用Yahoo Finance数据源")
-	rootCmd.Flags().BoolVarP(&continuous, "continuous", "c", false, "持续监控模式")
-	rootCmd.Flags().IntVarP(&delay, "delay", "d", 300, "监控间隔（秒）")
-	rootCmd.Flags().BoolVar(&useCache, "cache", true, "启用数据缓存（默认启用）")
-	rootCmd.Flags().BoolVar(&clearCache, "clear-cache", false, "清除所有缓存数据")
-	rootCmd.Flags().StringVar(&cacheDir, "cache-dir", ".cache", "缓存目录")
-	rootCmd.Flags().IntVar(&cacheTTL, "cache-ttl", 5, "缓存有效期（分钟）")
+	Use: "crypto-analyzer",
+	Run: runAnalysis,
+}
+
+// setupCommand 设置命令说明和参数；帮助文本按当前语言生成，需在设置语言之后调用
+func setupCommand() {
+	rootCmd.Short = i18n.T("cli.analyzer_short")
+	rootCmd.Long = i18n.T("cli.analyzer_long")
+	rootCmd.Flags().StringSliceVarP(&symbols, "symbols", "s", []string{}, i18n.T("flag.symbols"))
+	rootCmd.Flags().StringVarP(&watchlist, "watchlist", "w", "top3", i18n.T("flag.watchlist"))
+	rootCmd.Flags().StringVarP(&interval, "interval", "i", "1h", i18n.T("flag.analyzer_interval"))
+	rootCmd.Flags().IntVarP(&limit, "limit", "l", 100, i18n.T("flag.limit"))
+	rootCmd.Flags().BoolVarP(&useYahoo, "yahoo", "y", false, i18n.T("flag.yahoo"))
+	rootCmd.Flags().BoolVarP(&continuous, "continuous", "c", false, i18n.T("flag.continuous"))
+	rootCmd.Flags().IntVarP(&delay, "delay", "d", 300, i18n.T("flag.delay"))
+	rootCmd.Flags().BoolVar(&useCache, "cache", true, i18n.T("flag.cache"))
+	rootCmd.Flags().BoolVar(&clearCache, "clear-cache", false, i18n.T("flag.clear_cache"))
+	rootCmd.Flags().StringVar(&cacheDir, "cache-dir", ".cache", i18n.T("flag.cache_dir"))
+	rootCmd.Flags().IntVar(&cacheTTL, "cache-ttl", 5, i18n.T("flag.cache_ttl"))
+	rootCmd.Flags().StringVar(&vwapSession, "vwap-session", "00:00", i18n.T("flag.vwap_session"))
+	rootCmd.Flags().StringVar(&avwapFrom, "avwap-from", "", i18n.T("flag.avwap_from"))
+	rootCmd.Flags().StringVar(&pivotMethod, "pivot-method", "floor", i18n.T("flag.pivot_method"))
+	rootCmd.Flags().StringVar(&pivotPeriod, "pivot-period", "day", i18n.T("flag.pivot_period"))
+	rootCmd.Flags().StringVar(&transformName, "transform", "", i18n.T("flag.analyzer_transform"))
+	rootCmd.Flags().Float64Var(&brickSize, "brick-size", 0, i18n.T("flag.brick_size"))
+	rootCmd.Flags().IntVar(&brickATR, "brick-atr", 14, i18n.T("flag.brick_atr"))
+	rootCmd.Flags().BoolVar(&noCorrelation, "no-correlation", false, i18n.T("flag.no_correlation"))
+	rootCmd.Flags().IntVar(&corrWindow, "corr-window", 100, i18n.T("flag.corr_window"))
+	rootCmd.Flags().Float64Var(&corrThreshold, "corr-threshold", 0.8, i18n.T("flag.corr_threshold"))
+	rootCmd.Flags().IntVar(&corrRolling, "corr-rolling", 20, i18n.T("flag.corr_rolling"))
+	rootCmd.Flags().BoolVar(&noRelativeStrength, "no-rs", false, i18n.T("flag.no_rs"))
+	rootCmd.Flags().StringVar(&rsLookbacks, "rs-lookbacks", "1d:0.2,7d:0.3,30d:0.5", i18n.T("flag.rs_lookbacks"))
+	rootCmd.Flags().BoolVar(&multiTimeframe, "mtf", false, i18n.T("flag.mtf"))
+	rootCmd.Flags().StringSliceVar(&timeframes, "timeframes", []string{}, i18n.T("flag.timeframes"))
+	rootCmd.Flags().StringVar(&configFile, "config", "", i18n.T("flag.config"))
+	rootCmd.Flags().StringVar(&configDir, "config-dir", "", i18n.T("flag.config_dir"))
+	rootCmd.Flags().StringVar(&profileName, "profile", "", i18n.T("flag.profile"))
+	rootCmd.Flags().StringArrayVar(&indicatorSpecs, "indicator", []string{}, i18n.T("flag.indicator"))
+	rootCmd.Flags().BoolVar(&listIndicators, "list-indicators", false, i18n.T("flag.list_indicators"))
+	rootCmd.Flags().StringVar(&exportIndicators, "export-indicators", "", i18n.T("flag.export_indicators"))
+	rootCmd.Flags().StringArrayVar(&expressions, "expr", []string{}, i18n.T("flag.expr"))
+	rootCmd.Flags().StringArrayVar(&ruleFiles, "rules", []string{}, i18n.T("flag.rules"))
+	rootCmd.Flags().StringVar(&lang, "lang", "", i18n.T("flag.lang"))
 }
 
 func main() {
+	// 帮助文本在解析参数之前生成，先按 --lang 或系统语言环境设置语言
+	i18n.SetLanguage(i18n.ArgsLanguage(os.Args[1:]))
+	setupCommand()
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
 		os.Exit(1)
@@ -60,46 +133,162 @@ func main() {
 }
 
 func runAnalysis(cmd *cobra.Command, args []string) {
+	if lang == "" {
+		lang = i18n.DetectLanguage()
+	}
+	if err := i18n.SetLanguage(lang); err != nil {
+		color.Red("❌ %v", err)
+		return
+	}
+
 	// Handle cache clearing
 	if clearCache {
 		cacheManager := cache.NewOHLCVCache(cacheDir, time.Duration(cacheTTL)*time.Minute)
 		if err := cacheManager.ClearAll(); err != nil {
-			color.Red("清除缓存失败: %v", err)
+			color.Red(i18n.T("run.clear_cache_failed"), err)
 		} else {
-			color.Green("✅ 已清除所有缓存数据")
+			color.Green(i18n.T("run.cache_cleared"))
 		}
 		return
 	}
+	if listIndicators {
+		printIndicatorList()
+		return
+	}
+
+	// Load configuration; explicit command line flags win over it
+	cfg, err := config.Load(config.LoadOptions{Dir: configDir, File: configFile, Profile: profileName})
+	if err != nil {
+		color.Red("❌ %v", err)
+		return
+	}
+	appConfig = cfg
+	if !cmd.Flags().Changed("interval") {
+		interval = cfg.Analysis.Interval
+	}
+	if !cmd.Flags().Changed("limit") {
+		limit = cfg.Analysis.Limit
+	}
+	if !cmd.Flags().Changed("yahoo") {
+		useYahoo = cfg.Datasource.Primary == "yahoo"
+	}
 
 	// Determine symbols to analyze
 	symbolsToAnalyze := symbols
 	if len(symbolsToAnalyze) == 0 {
-		symbolsToAnalyze = config.GetWatchlist(watchlist)
+		symbolsToAnalyze = cfg.Watchlist(watchlist)
 	}
 
 	// Create base data fetcher
 	var baseFetcher data.Fetcher
 	if useYahoo {
 		baseFetcher = data.NewYahooFinanceFetcher()
-		fmt.Println("使用Yahoo Finance数据源")
+		fmt.Println(i18n.T("common.source_yahoo"))
 	} else {
 		baseFetcher = data.NewBinanceFetcher()
-		fmt.Println("使用Binance数据源")
+		fmt.Println(i18n.T("common.source_binance"))
 	}
 
 	// Wrap with cache if enabled
 	var fetcher data.Fetcher
 	if useCache {
-		fmt.Printf("✅ 缓存已启用 (目录: %s, TTL: %d分钟)\n", cacheDir, cacheTTL)
+		fmt.Println(i18n.T("run.cache_enabled", cacheDir, cacheTTL))
 		fetcher = data.NewCachedFetcher(baseFetcher, cacheDir, time.Duration(cacheTTL)*time.Minute)
 	} else {
-		fmt.Println("⚠️  缓存已禁用")
+		fmt.Println(i18n.T("run.cache_disabled"))
 		fetcher = baseFetcher
 	}
 
 	// Create analyzers
 	trendAnalyzer := analysis.NewTrendAnalyzer()
+	if err := trendAnalyzer.ApplySettings(cfg.AnalysisSettings()); err != nil {
+		color.Red("❌ %v", err)
+		return
+	}
+	sessionTime, err := time.Parse("15:04", vwapSession)
+	if err != nil {
+		color.Red(i18n.T("run.invalid_vwap_session"), vwapSession, err)
+		return
+	}
+	trendAnalyzer.SetVWAPSessionOffset(time.Duration(sessionTime.Hour())*time.Hour + time.Duration(sessionTime.Minute())*time.Minute)
+	if avwapFrom != "" {
+		anchor, err := time.Parse("2006-01-02 15:04", avwapFrom)
+		if err != nil {
+			color.Red(i18n.T("run.invalid_avwap_from"), avwapFrom, err)
+			return
+		}
+		trendAnalyzer.SetVWAPAnchor(anchor)
+	}
+	method, err := indicators.ParsePivotMethod(pivotMethod)
+	if err != nil {
+		color.Red("❌ %v", err)
+		return
+	}
+	period, err := indicators.ParsePivotPeriod(pivotPeriod)
+	if err != nil {
+		color.Red("❌ %v", err)
+		return
+	}
+	trendAnalyzer.SetPivotParams(method, period)
+	transform, err = indicators.NewTransform(transformName, brickSize, brickATR)
+	if err != nil {
+		color.Red("❌ %v", err)
+		return
+	}
+	indicatorKeys, err = trendAnalyzer.RequestIndicators(indicatorSpecs...)
+	if err != nil {
+		color.Red("❌ %v", err)
+		return
+	}
+	exportSpecs = indicators.SplitSpecs(exportIndicators)
+	for _, text := range exportSpecs {
+		spec, err := indicators.ParseSpec(text)
+		if err == nil {
+			_, _, err = indicators.DefaultRegistry().Resolve(spec)
+		}
+		if err != nil {
+			color.Red("❌ %v", err)
+			return
+		}
+	}
+	for _, text := range expressions {
+		if err := trendAnalyzer.AddExpression(text); err != nil {
+			color.Red("❌ %v", err)
+			return
+		}
+	}
+	ruleSet, err := analysis.LoadEvidenceRules(ruleFiles...)
+	if err != nil {
+		color.Red(i18n.T("common.rules_load_failed"), err)
+		return
+	}
+	for _, text := range ruleSet.Expressions() {
+		if err := trendAnalyzer.AddExpression(text); err != nil {
+			color.Red(i18n.T("run.rule_expression"), text, err)
+			return
+		}
+	}
 	evidenceCollector := analysis.NewEvidenceCollector()
+	evidenceCollector.SetThresholds(cfg.AnalysisSettings().Thresholds)
+	evidenceCollector.SetRules(ruleSet)
+	crossAnalyzer := analysis.NewCrossAssetAnalyzer()
+	crossAnalyzer.SetWindow(corrWindow)
+	crossAnalyzer.SetClusterThreshold(corrThreshold)
+	crossAnalyzer.SetRollingWindow(corrRolling)
+	rsAnalyzer := analysis.NewRelativeStrengthAnalyzer()
+	lookbacks, err := analysis.ParseRelativeStrengthLookbacks(rsLookbacks)
+	if err != nil {
+		color.Red("❌ %v", err)
+		return
+	}
+	rsAnalyzer.SetLookbacks(lookbacks)
+	for _, timeframe := range timeframes {
+		if err := utils.ValidateInterval(timeframe); err != nil {
+			color.Red("❌ %v", err)
+			return
+		}
+	}
+	mtfAnalyzer := analysis.NewMultiTimeframeAnalyzer(trendAnalyzer, evidenceCollector)
 
 	// Fetch Fear & Greed Index
 	fgFetcher := data.NewFearGreedFetcher()
@@ -111,25 +300,44 @@ func runAnalysis(cmd *cobra.Command, args []string) {
 	// Analysis loop
 	for {
 		fmt.Printf("\n%s\n", strings.Repeat("=", 80))
-		fmt.Printf("🚀 加密货币市场分析 - %s\n", time.Now().Format("2006-01-02 15:04:05"))
-		fmt.Printf("📊 时间周期: %s | 数据点: %d\n", interval, limit)
+		fmt.Println(i18n.T("run.title", time.Now().Format("2006-01-02 15:04:05")))
+		fmt.Println(i18n.T("run.timeframe", interval, limit))
+		if cfg.Profile != "" {
+			fmt.Println(i18n.T("run.profile", cfg.Profile, cfg.Profiles[cfg.Profile].Description))
+		}
+		if transform.Active() {
+			fmt.Println(i18n.T("run.transform", transform))
+		}
 		fmt.Printf("%s\n", strings.Repeat("=", 80))
 
+		series := make(map[string][]types.OHLCV, len(symbolsToAnalyze))
 		for _, symbol := range symbolsToAnalyze {
-			analyzeSymbol(symbol, fetcher, trendAnalyzer, evidenceCollector)
+			if ohlcv := analyzeSymbol(symbol, fetcher, trendAnalyzer, evidenceCollector); ohlcv != nil {
+				series[symbol] = ohlcv
+				if multiTimeframe {
+					analyzeMultiTimeframe(symbol, ohlcv, fetcher, mtfAnalyzer)
+				}
+			}
+		}
+		if len(symbolsToAnalyze) > 1 && !noCorrelation {
+			analyzeCrossAsset(symbolsToAnalyze, series, fetcher, crossAnalyzer)
+		}
+		if len(symbolsToAnalyze) > 1 && !noRelativeStrength {
+			analyzeRelativeStrength(symbolsToAnalyze, series, fetcher, rsAnalyzer)
 		}
 
 		if !continuous {
 			break
 		}
 
-		fmt.Printf("\n⏰ 下次更新: %d秒后\n", delay)
+		fmt.Printf("\n%s\n", i18n.T("run.next_update", delay))
 		time.Sleep(time.Duration(delay) * time.Second)
 	}
 }
 
-func analyzeSymbol(symbol string, fetcher data.Fetcher, analyzer *analysis.TrendAnalyzer, collector *analysis.EvidenceCollector) {
-	fmt.Printf("\n📊 分析 %s\n", color.YellowString(symbol))
+// analyzeSymbol 分析单个交易对，返回获取到的K线供跨资产分析使用
+func analyzeSymbol(symbol string, fetcher data.Fetcher, analyzer *analysis.TrendAnalyzer, collector *analysis.EvidenceCollector) []types.OHLCV {
+	fmt.Printf("\n%s\n", i18n.T("symbol.title", color.YellowString(symbol)))
 	fmt.Println(strings.Repeat("-", 60))
 
 	// 计算实际需要的数据量
@@ -143,7 +351,7 @@ func analyzeSymbol(symbol string, fetcher data.Fetcher, analyzer *analysis.Trend
 	minRequired := minForAnalysis + extraForHistory
 	if actualLimit < minRequired {
 		actualLimit = minRequired
-		fmt.Printf("  ℹ️  自动调整数据量: %d → %d (确保历史信号追踪)\n", limit, actualLimit)
+		fmt.Println(i18n.T("symbol.adjust_limit", limit, actualLimit))
 	}
 
 	// Fetch OHLCV data
@@ -151,60 +359,390 @@ func analyzeSymbol(symbol string, fetcher data.Fetcher, analyzer *analysis.Trend
 	if err != nil {
 		// 提供更友好的错误信息
 		if strings.Contains(err.Error(), "418") || strings.Contains(err.Error(), "banned") {
-			color.Red("  ❌ API访问被限制，请稍后再试或使用 -y 参数切换到Yahoo数据源")
+			color.Red(i18n.T("symbol.rate_limited"))
 		} else if strings.Contains(err.Error(), "network") || strings.Contains(err.Error(), "connection") {
-			color.Red("  ❌ 网络连接失败，请检查网络连接")
+			color.Red(i18n.T("symbol.network_failed"))
 		} else {
-			color.Red("  ❌ 获取数据失败: %v", err)
+			color.Red(i18n.T("symbol.fetch_failed"), err)
 		}
-		fmt.Println("  💡 提示: 可以尝试以下操作:")
-		fmt.Println("     1. 使用 -y 参数切换到Yahoo Finance数据源")
-		fmt.Println("     2. 减少请求频率或数据量")
-		fmt.Println("     3. 检查交易对名称是否正确")
-		return
+		fmt.Println(i18n.T("symbol.hint"))
+		fmt.Println(i18n.T("symbol.hint_yahoo"))
+		fmt.Println(i18n.T("symbol.hint_rate"))
+		fmt.Println(i18n.T("symbol.hint_symbol"))
+		return nil
 	}
 
 	if len(ohlcv) < 50 {
-		color.Red("  ❌ 数据不足（需要至少50根K线）")
-		return
+		color.Red(i18n.T("symbol.insufficient_data"))
+		return nil
 	}
 
+	// 配置的关键价位参与支撑阻力聚类，未配置的交易对清空上一个交易对的价位
+	analyzer.SetKeyLevels(config.CryptoConfig[symbol].KeyLevels)
+
 	// Perform analysis
-	result, err := analyzer.AnalyzeComprehensive(ohlcv)
+	result, err := analyzer.AnalyzeTransformed(ohlcv, transform, 0)
 	if err != nil {
-		color.Red("  ❌ 分析失败: %v", err)
-		return
+		color.Red(i18n.T("symbol.analysis_failed"), err)
+		if transform.Kind == indicators.TransformRenko {
+			fmt.Println(i18n.T("symbol.renko_hint"))
+		}
+		return ohlcv
 	}
 
-	// Collect evidence
-	collector.Clear()
-	collector.AnalyzeMAEvidence(result.MAAnalysis, result.CurrentPrice)
-	collector.AnalyzeMACDEvidence(result.MACDAnalysis)
-	collector.AnalyzeRSIEvidence(result.Momentum.RSI)
-	collector.AnalyzeSREvidence(result.CurrentPrice, result.SupportResistance)
-
 	// Calculate price change
 	priceChange := 0.0
 	if len(ohlcv) > 1 {
 		priceChange = (ohlcv[len(ohlcv)-1].Close - ohlcv[len(ohlcv)-2].Close) / ohlcv[len(ohlcv)-2].Close
 	}
-	collector.AnalyzeVolumeEvidence(result.Volume, priceChange)
+
+	// Collect evidence
+	collector.Clear()
+	if err := collector.Collect(result, priceChange); err != nil {
+		color.Red(i18n.T("symbol.rules_failed"), err)
+		return ohlcv
+	}
+	if significant := appConfig.Alerts.PriceChange.Significant; math.Abs(priceChange) >= significant {
+		color.Yellow(i18n.T("symbol.significant_change"), priceChange*100, significant*100)
+	}
 
 	// Get evidence summary
 	evidenceSummary := collector.GetSummary()
 
 	// Print results
-	printAnalysisResult(result, evidenceSummary)
+	printAnalysisResult(result, evidenceSummary, analyzer)
 
 	// Print price chart
-	printPriceChart(ohlcv)
+	if appConfig.Display.ShowChart {
+		printPriceChart(ohlcv)
+	}
 	
 	// Print historical signal tracking at the bottom
 	printHistoricalSignals(symbol, ohlcv, analyzer, collector)
+
+	// Export the requested indicator series, computed on the real candles
+	if len(exportSpecs) > 0 {
+		filename, err := export.NewExporter("csv").ExportIndicators(symbol, ohlcv, exportSpecs)
+		if err != nil {
+			color.Red(i18n.T("export.indicators_failed"), err)
+		} else {
+			color.Green(i18n.T("export.indicators_done"), filename)
+		}
+	}
+	return ohlcv
+}
+
+// analyzeCrossAsset 对观察列表做跨资产相关性分析；基准不在列表中时单独获取
+func analyzeCrossAsset(symbols []string, series map[string][]types.OHLCV, fetcher data.Fetcher, analyzer *analysis.CrossAssetAnalyzer) {
+	benchmark := analyzer.Benchmark()
+	if _, ok := series[benchmark]; !ok {
+		size := 0
+		for _, ohlcv := range series {
+			if len(ohlcv) > size {
+				size = len(ohlcv)
+			}
+		}
+		if ohlcv, err := fetcher.FetchOHLCV(benchmark, interval, size); err == nil {
+			series[benchmark] = ohlcv
+		}
+	}
+
+	result, err := analyzer.Analyze(symbols, series)
+	if err != nil {
+		color.Yellow("\n"+i18n.T("cross.skipped"), err)
+		return
+	}
+	printCrossAsset(result)
+}
+
+// analyzeMultiTimeframe 获取交易对各周期的K线并打印多周期共振分析，当前周期复用已获取的数据
+func analyzeMultiTimeframe(symbol string, ohlcv []types.OHLCV, fetcher data.Fetcher, analyzer *analysis.MultiTimeframeAnalyzer) {
+	intervals := timeframes
+	if len(intervals) == 0 {
+		intervals = config.CryptoConfig[symbol].Timeframes
+	}
+	if len(intervals) == 0 {
+		intervals = appConfig.Analysis.Timeframes
+	}
+
+	series := make(map[string][]types.OHLCV, len(intervals))
+	for _, tf := range intervals {
+		if tf == interval {
+			series[tf] = ohlcv
+			continue
+		}
+		data, err := fetcher.FetchOHLCV(symbol, tf, max(limit, 100))
+		if err != nil {
+			color.Yellow(i18n.T("mtf.fetch_failed"), tf, err)
+			continue
+		}
+		series[tf] = data
+	}
+
+	result, err := analyzer.Analyze(symbol, series)
+	if err != nil {
+		color.Yellow("\n"+i18n.T("mtf.skipped"), err)
+		return
+	}
+	printMultiTimeframe(result)
+}
+
+// printMultiTimeframe 打印多周期对齐矩阵、加权共振得分和周期冲突
+func printMultiTimeframe(result *types.MultiTimeframeAnalysis) {
+	fmt.Printf("\n%s\n", i18n.T("mtf.title", result.Symbol))
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader(i18n.List("mtf.header"))
+	table.SetBorder(false)
+	for _, tf := range result.Timeframes {
+		table.Append([]string{
+			tf.Interval,
+			fmt.Sprintf("%.0f", tf.Weight),
+			getTrendColor(tf.Trend),
+			fmt.Sprintf("%+.0f", tf.TrendScore),
+			fmt.Sprintf("%.1f %s", tf.RSI, tf.Momentum.String()),
+			tf.MACDTrend.String(),
+			fmt.Sprintf("%+.2f", tf.EvidenceStrength),
+			fmt.Sprintf("%+.0f%%", tf.Score*100),
+		})
+	}
+	table.Render()
+
+	fmt.Println(i18n.T("mtf.confluence", result.Confluence, getTrendColor(result.Direction), result.Alignment*100))
+	for _, conflict := range result.Conflicts {
+		description := i18n.T("mtf.conflict_description", conflict.Lower, conflict.LowerTrend, conflict.Higher, conflict.HigherTrend)
+		color.Yellow(i18n.T("mtf.conflict"), description)
+	}
+}
+
+// analyzeRelativeStrength 输出观察列表的相对强度排行；最长周期所需的K线不足时补充获取，
+// 获取失败则使用已有数据，未覆盖的周期显示为"-"
+func analyzeRelativeStrength(symbols []string, series map[string][]types.OHLCV, fetcher data.Fetcher, analyzer *analysis.RelativeStrengthAnalyzer) {
+	days := int(math.Ceil(analyzer.LongestLookback().Hours() / 24))
+	needed := utils.CalculateKlineLimit(interval, days) + 2
+	if needed > 1000 {
+		needed = 1000
+	}
+
+	history := make(map[string][]types.OHLCV, len(series)+1)
+	for _, symbol := range append(append([]string{}, symbols...), analyzer.Benchmark()) {
+		history[symbol] = series[symbol]
+		if len(history[symbol]) >= needed {
+			continue
+		}
+		if ohlcv, err := fetcher.FetchOHLCV(symbol, interval, needed); err == nil && len(ohlcv) > len(history[symbol]) {
+			history[symbol] = ohlcv
+		}
+		if len(history[symbol]) == 0 {
+			delete(history, symbol)
+		}
+	}
+
+	result, err := analyzer.Analyze(symbols, history)
+	if err != nil {
+		color.Yellow("\n"+i18n.T("rs.skipped"), err)
+		return
+	}
+	printRelativeStrength(result)
+}
+
+// printRelativeStrength 打印相对强度排行榜：各周期涨跌幅、相对基准和等权篮子的强弱及综合评分
+func printRelativeStrength(result *types.RelativeStrengthAnalysis) {
+	fmt.Printf("\n%s\n", strings.Repeat("=", 80))
+	fmt.Println(i18n.T("rs.title", result.AsOf.Format("01-02 15:04")))
+	fmt.Println(strings.Repeat("=", 80))
+
+	short := func(symbol string) string {
+		return strings.TrimSuffix(symbol, "USDT")
+	}
+	pct := func(value float64) string {
+		if math.IsNaN(value) {
+			return "-"
+		}
+		text := fmt.Sprintf("%+.2f%%", value*100)
+		if value >= 0 {
+			return color.GreenString(text)
+		}
+		return color.RedString(text)
+	}
+
+	// 对基准的强弱取数据覆盖的最长周期
+	covered := len(result.Lookbacks) - 1
+	for covered > 0 && math.IsNaN(result.BasketReturns[covered]) {
+		covered--
+	}
+	header := i18n.List("rs.header")
+	for _, label := range result.Lookbacks {
+		header = append(header, label)
+	}
+	for _, label := range result.Lookbacks {
+		header = append(header, i18n.T("rs.basket", label))
+	}
+	if result.Benchmark != "" {
+		header = append(header, i18n.T("rs.vs_benchmark", short(result.Benchmark), result.Lookbacks[covered]))
+	}
+	header = append(header, i18n.List("rs.score_header")...)
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader(header)
+	table.SetBorder(false)
+	table.SetAlignment(tablewriter.ALIGN_RIGHT)
+	for _, score := range result.Leaderboard {
+		rank, name := "-", short(score.Symbol)
+		if score.Rank > 0 {
+			rank = fmt.Sprintf("%d", score.Rank)
+		}
+		if score.RSLineNewHigh {
+			name += " ⭐"
+		}
+		row := []string{rank, name}
+		for _, value := range score.Returns {
+			row = append(row, pct(value))
+		}
+		for _, value := range score.VsBasket {
+			row = append(row, pct(value))
+		}
+		if result.Benchmark != "" {
+			if score.Symbol == result.Benchmark {
+				row = append(row, "-")
+			} else {
+				row = append(row, pct(score.VsBenchmark[covered]))
+			}
+		}
+		composite, percentile := "-", "-"
+		if score.Rank > 0 {
+			composite = fmt.Sprintf("%+.2f", score.Composite)
+			percentile = fmt.Sprintf("%.0f", score.Percentile)
+			switch {
+			case score.Percentile >= 80:
+				percentile = color.GreenString(i18n.T("rs.leading", percentile))
+			case score.Percentile <= 20:
+				percentile = color.RedString(i18n.T("rs.lagging", percentile))
+			}
+		}
+		table.Append(append(row, composite, percentile))
+	}
+	table.Render()
+
+	fmt.Println(i18n.T("rs.note", short(result.Benchmark)))
+	if covered < len(result.Lookbacks)-1 {
+		color.Yellow(i18n.T("rs.partial_cover"), result.Lookbacks[covered])
+	}
+}
+
+// printCrossAsset 打印相关性热力图（上三角Pearson、下三角Spearman）、对基准的贝塔和聚类
+func printCrossAsset(result *types.CrossAssetAnalysis) {
+	fmt.Printf("\n%s\n", strings.Repeat("=", 80))
+	fmt.Println(i18n.T("cross.title", result.Observations,
+		result.Start.Format("01-02 15:04"), result.End.Format("01-02 15:04")))
+	fmt.Println(strings.Repeat("=", 80))
+
+	short := func(symbol string) string {
+		return strings.TrimSuffix(symbol, "USDT")
+	}
+	heat := func(rho float64) string {
+		text := fmt.Sprintf("%.2f", rho)
+		switch {
+		case rho >= 0.8:
+			return color.RedString(text)
+		case rho >= 0.5:
+			return color.YellowString(text)
+		case rho < 0:
+			return color.CyanString(text)
+		}
+		return color.GreenString(text)
+	}
+
+	header := []string{""}
+	for _, symbol := range result.Symbols {
+		header = append(header, short(symbol))
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader(header)
+	table.SetBorder(false)
+	table.SetAlignment(tablewriter.ALIGN_RIGHT)
+	for i, symbol := range result.Symbols {
+		row := []string{short(symbol)}
+		for j := range result.Symbols {
+			switch {
+			case i == j:
+				row = append(row, "-")
+			case j > i:
+				row = append(row, heat(result.Pearson[i][j]))
+			default:
+				row = append(row, heat(result.Spearman[i][j]))
+			}
+		}
+		table.Append(row)
+	}
+	table.Render()
+	fmt.Println(i18n.T("cross.legend"))
+	fmt.Println(i18n.T("cross.summary", result.AverageCorrelation, result.EffectiveBets, len(result.Symbols)))
+
+	// 各交易对的滚动相关：在窗口内等距取5个点，并给出最低和最高值
+	if len(result.Rolling) > 0 {
+		fmt.Printf("\n%s\n", i18n.T("cross.rolling_title", result.RollingWindow))
+		rollingTable := tablewriter.NewWriter(os.Stdout)
+		rollingTable.SetHeader(i18n.List("cross.rolling_header"))
+		rollingTable.SetBorder(false)
+		rollingTable.SetAlignment(tablewriter.ALIGN_LEFT)
+		for _, pair := range result.Rolling {
+			n := len(pair.Values)
+			points := make([]string, 0, 5)
+			for k := 4; k >= 0; k-- {
+				rho := pair.Values[n-1-k*(n-1)/4]
+				if math.IsNaN(rho) {
+					points = append(points, "-")
+					continue
+				}
+				points = append(points, heat(rho))
+			}
+			low, high := math.Inf(1), math.Inf(-1)
+			for _, rho := range pair.Values {
+				if !math.IsNaN(rho) {
+					low, high = math.Min(low, rho), math.Max(high, rho)
+				}
+			}
+			lowText, highText := "-", "-"
+			if !math.IsInf(low, 0) {
+				lowText, highText = fmt.Sprintf("%.2f", low), fmt.Sprintf("%.2f", high)
+			}
+			rollingTable.Append([]string{short(pair.A) + "/" + short(pair.B), strings.Join(points, " → "), lowText, highText})
+		}
+		rollingTable.Render()
+	}
+
+	if len(result.Betas) > 0 {
+		fmt.Printf("\n%s\n", i18n.T("cross.beta_title", short(result.Benchmark)))
+		betaTable := tablewriter.NewWriter(os.Stdout)
+		betaTable.SetHeader(i18n.List("cross.beta_header"))
+		betaTable.SetBorder(false)
+		betaTable.SetAlignment(tablewriter.ALIGN_LEFT)
+		for _, beta := range result.Betas {
+			previous, change := "-", "-"
+			if !math.IsNaN(beta.PreviousCorrelation) {
+				previous = fmt.Sprintf("%.2f", beta.PreviousCorrelation)
+				change = fmt.Sprintf("%+.2f", beta.Correlation-beta.PreviousCorrelation)
+			}
+			betaTable.Append([]string{short(beta.Symbol), fmt.Sprintf("%.2f", beta.Beta), heat(beta.Correlation), previous, change})
+		}
+		betaTable.Render()
+	}
+
+	for i, cluster := range result.Clusters {
+		names := make([]string, len(cluster))
+		for j, symbol := range cluster {
+			names[j] = short(symbol)
+		}
+		fmt.Println(i18n.T("cross.cluster", i+1, strings.Join(names, ", ")))
+	}
+	for _, warning := range result.Warnings {
+		color.Yellow("  ⚠️  %s", warning)
+	}
 }
 
 func printFearGreedIndex(fg *types.FearGreedIndex) {
-	fmt.Printf("\n😱 恐慌贪婪指数: ")
+	fmt.Printf("\n%s", i18n.T("feargreed.title"))
 	
 	value := fg.Value
 	var colorFunc func(format string, a ...interface{}) string
@@ -222,46 +760,97 @@ func printFearGreedIndex(fg *types.FearGreedIndex) {
 	}
 	
 	fmt.Printf("%s (%s)\n", colorFunc("%d", value), fg.Classification)
-	fmt.Printf("   %s\n", fg.Sentiment)
+	fmt.Printf("   %s\n", fg.Sentiment.String())
 }
 
-func printAnalysisResult(result *types.Analysis, evidenceSummary map[string]interface{}) {
+func printAnalysisResult(result *types.Analysis, evidenceSummary map[string]interface{}, analyzer *analysis.TrendAnalyzer) {
 	// Basic info
-	fmt.Printf("\n💰 当前价格: %s\n", color.CyanString("$%.2f", result.CurrentPrice))
-	fmt.Printf("📈 整体趋势: %s\n", getTrendColor(result.OverallTrend))
-	fmt.Printf("📊 趋势评分: %.1f\n", result.TrendScore)
+	fmt.Printf("\n%s\n", i18n.T("analysis.price", color.CyanString("$%.2f", result.CurrentPrice)))
+	fmt.Println(i18n.T("analysis.trend", getTrendColor(result.OverallTrend)))
+	fmt.Println(i18n.T("analysis.trend_score", result.TrendScore))
+	if result.Structure.Available {
+		fmt.Println(i18n.T("analysis.ma_structure", getTrendColor(result.MAAnalysis.Trend), getTrendColor(result.Structure.Trend)))
+	}
 
 	// Technical indicators table - 展示所有原始数据
 	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"指标", "数值", "参考值", "状态"})
+	table.SetHeader(i18n.List("analysis.indicator_header"))
 	table.SetBorder(false)
 	table.SetAlignment(tablewriter.ALIGN_LEFT)
 
 	// RSI详细信息
-	rsiStatus := result.Momentum.Momentum
-	rsiRef := "超买>70, 超卖<30"
-	table.Append([]string{"RSI(14)", fmt.Sprintf("%.1f", result.Momentum.RSI), rsiRef, rsiStatus})
+	rsiStatus := result.Momentum.Momentum.String()
+	alerts := appConfig.Alerts
+	rsiRef := i18n.T("analysis.rsi_ref", alerts.RSI.Overbought, alerts.RSI.Oversold)
+	table.Append([]string{fmt.Sprintf("RSI(%d)", appConfig.Indicators.RSI.Period), fmt.Sprintf("%.1f", result.Momentum.RSI), rsiRef, rsiStatus})
+	stochStatus := result.Momentum.StochRSICross.String()
+	if stochStatus == "" && result.Momentum.StochRSIK > 80 {
+		stochStatus = i18n.T("analysis.overbought")
+	} else if stochStatus == "" && result.Momentum.StochRSIK < 20 {
+		stochStatus = i18n.T("analysis.oversold")
+	}
+	table.Append([]string{"StochRSI K/D", fmt.Sprintf("%.1f / %.1f", result.Momentum.StochRSIK, result.Momentum.StochRSID), i18n.T("analysis.stoch_ref"), stochStatus})
 	
 	// MACD详细信息
-	table.Append([]string{"MACD", fmt.Sprintf("%.2f", result.MACDAnalysis.MACD), fmt.Sprintf("Signal: %.2f", result.MACDAnalysis.Signal), result.MACDAnalysis.Trend})
-	table.Append([]string{"MACD柱", fmt.Sprintf("%.2f", result.MACDAnalysis.Histogram), ">0看涨, <0看跌", ""})
+	macd := appConfig.Indicators.MACD
+	table.Append([]string{fmt.Sprintf("MACD(%d,%d,%d)", macd.Fast, macd.Slow, macd.Signal), fmt.Sprintf("%.2f", result.MACDAnalysis.MACD), fmt.Sprintf("Signal: %.2f", result.MACDAnalysis.Signal), result.MACDAnalysis.Trend.String()})
+	table.Append([]string{i18n.T("analysis.macd_histogram"), fmt.Sprintf("%.2f", result.MACDAnalysis.Histogram), i18n.T("analysis.macd_histogram_ref"), result.MACDAnalysis.Divergence.String()})
 	
 	// ADX详细信息
-	adxRef := "强势>35, 弱势<20"
-	table.Append([]string{"ADX(14)", fmt.Sprintf("%.1f", result.TrendStrength.ADX), adxRef, string(result.TrendStrength.Strength)})
+	adxRef := i18n.T("analysis.adx_ref")
+	table.Append([]string{fmt.Sprintf("ADX(%d)", appConfig.Indicators.ADX.Period), fmt.Sprintf("%.1f", result.TrendStrength.ADX), adxRef, result.TrendStrength.Strength.String()})
+	diStatus := i18n.T("analysis.plus_di_dominant")
+	if result.TrendStrength.MinusDI > result.TrendStrength.PlusDI {
+		diStatus = i18n.T("analysis.minus_di_dominant")
+	}
+	if result.TrendStrength.DICross != "" {
+		diStatus = i18n.T("common.bars_ago", result.TrendStrength.DICross.String(), result.TrendStrength.DICrossBarsAgo)
+	}
+	table.Append([]string{"+DI/-DI", fmt.Sprintf("%.1f / %.1f", result.TrendStrength.PlusDI, result.TrendStrength.MinusDI), i18n.T("analysis.di_ref"), diStatus})
+
+	// 跟踪止损类指标，参数取分析器实际使用的规格
+	superTrendSpec, psarSpec := analyzer.TrailingStopSpecs()
+	stParams, sarParams := superTrendSpec.Params(), psarSpec.Params()
+	if result.SuperTrend.Available {
+		table.Append([]string{fmt.Sprintf("SuperTrend(%d,%g)", stParams.Int("period"), stParams["multiplier"]), fmt.Sprintf("$%.2f", result.SuperTrend.Level), i18n.T("analysis.supertrend_ref"), trailingStatus(result.SuperTrend)})
+	}
+	if result.ParabolicSAR.Available {
+		table.Append([]string{fmt.Sprintf("SAR(%g,%g)", sarParams["step"], sarParams["max"]), fmt.Sprintf("$%.2f", result.ParabolicSAR.Level), i18n.T("analysis.sar_ref"), trailingStatus(result.ParabolicSAR)})
+	}
+
+	// 布林带与挤压
+	if ch := result.Channels; ch.Available {
+		bbStatus := i18n.T("analysis.bb_inside")
+		if ch.PercentB > 1 {
+			bbStatus = i18n.T("analysis.bb_above")
+		} else if ch.PercentB < 0 {
+			bbStatus = i18n.T("analysis.bb_below")
+		}
+		table.Append([]string{i18n.T("analysis.percent_b", ch.BollingerPeriod, ch.BollingerStdDev), fmt.Sprintf("%.2f", ch.PercentB), i18n.T("analysis.bb_ref"), bbStatus})
+		table.Append([]string{i18n.T("analysis.bandwidth"), fmt.Sprintf("%.2f%%", ch.Bandwidth), i18n.T("analysis.bandwidth_rank", ch.BandwidthRank), ""})
+
+		squeezeStatus := i18n.T("analysis.no_squeeze")
+		if ch.SqueezeFired != "" {
+			squeezeStatus = i18n.T("common.bars_ago", ch.SqueezeFired.String(), ch.SqueezeFiredBarsAgo)
+		} else if ch.Squeeze {
+			squeezeStatus = i18n.T("analysis.squeezing", ch.SqueezeBars)
+		}
+		table.Append([]string{i18n.T("analysis.ttm_squeeze"), fmt.Sprintf("%.2f", ch.SqueezeMomentum), i18n.T("analysis.squeeze_ref"), squeezeStatus})
+		table.Append([]string{i18n.T("analysis.donchian", ch.DonchianPeriod), fmt.Sprintf("$%.2f - $%.2f", ch.DonchianLower, ch.DonchianUpper), i18n.T("analysis.donchian_ref"), ch.DonchianBreakout.String()})
+	}
 	
 	// 成交量详细信息
-	volumeRef := "放量>2x, 缩量<0.5x"
-	table.Append([]string{"成交量比", fmt.Sprintf("%.2fx", result.Volume.VolumeRatio), volumeRef, result.Volume.VolumeTrend})
-	table.Append([]string{"当前成交量", fmt.Sprintf("%.0f", result.Volume.CurrentVolume), fmt.Sprintf("均量: %.0f", result.Volume.VolumeMA), ""})
+	volumeRef := i18n.T("analysis.volume_ref", alerts.Volume.High, alerts.Volume.Low)
+	table.Append([]string{i18n.T("analysis.volume_ratio"), fmt.Sprintf("%.2fx", result.Volume.VolumeRatio), volumeRef, result.Volume.VolumeTrend.String()})
+	table.Append([]string{i18n.T("analysis.current_volume"), fmt.Sprintf("%.0f", result.Volume.CurrentVolume), i18n.T("analysis.volume_ma", result.Volume.VolumeMA), ""})
 
-	fmt.Println("\n📊 技术指标详情:")
+	fmt.Printf("\n%s\n", i18n.T("analysis.indicators_title"))
 	table.Render()
 
 	// Moving averages - 更详细的展示
-	fmt.Println("\n📉 移动平均线详情:")
+	fmt.Printf("\n%s\n", i18n.T("analysis.ma_title"))
 	maTable := tablewriter.NewWriter(os.Stdout)
-	maTable.SetHeader([]string{"均线", "价格", "相对位置", "偏离度"})
+	maTable.SetHeader(i18n.List("analysis.ma_header"))
 	maTable.SetBorder(false)
 	maTable.SetAlignment(tablewriter.ALIGN_LEFT)
 	
@@ -282,34 +871,53 @@ func printAnalysisResult(result *types.Analysis, evidenceSummary map[string]inte
 	
 	maTable.Render()
 	
+	// 一目均衡表
+	printIchimoku(result)
+	printVWAP(result)
+
 	// 支撑阻力位
-	fmt.Println("\n🎯 关键价位:")
+	sr := result.SupportResistance
+	pivotPeriod := sr.PivotPeriod
+	if pivotPeriod == "" {
+		pivotPeriod = "latest"
+	}
+	pivotSource := i18n.Label("pivot_period", pivotPeriod)
+	if sr.PivotPartial {
+		pivotSource += i18n.T("analysis.pivot_partial")
+	}
+	fmt.Printf("\n%s\n", i18n.T("analysis.pivot_title", i18n.Label("pivot", sr.PivotMethod), pivotSource))
 	srTable := tablewriter.NewWriter(os.Stdout)
-	srTable.SetHeader([]string{"类型", "价位", "距离", "强度"})
+	srTable.SetHeader(i18n.List("analysis.pivot_header"))
 	srTable.SetBorder(false)
 	srTable.SetAlignment(tablewriter.ALIGN_LEFT)
-	
-	// 阻力位
-	r1Distance := (result.SupportResistance.Resistance["R1"] - result.CurrentPrice) / result.CurrentPrice * 100
-	r2Distance := (result.SupportResistance.Resistance["R2"] - result.CurrentPrice) / result.CurrentPrice * 100
-	
-	srTable.Append([]string{"阻力R2", fmt.Sprintf("$%.2f", result.SupportResistance.Resistance["R2"]), 
-		fmt.Sprintf("+%.2f%%", r2Distance), "强"})
-	srTable.Append([]string{"阻力R1", fmt.Sprintf("$%.2f", result.SupportResistance.Resistance["R1"]), 
-		fmt.Sprintf("+%.2f%%", r1Distance), "中"})
-	srTable.Append([]string{"轴心点", fmt.Sprintf("$%.2f", result.SupportResistance.Pivot), 
-		"--", "参考"})
-	
-	// 支撑位
-	s1Distance := (result.CurrentPrice - result.SupportResistance.Support["S1"]) / result.CurrentPrice * 100
-	s2Distance := (result.CurrentPrice - result.SupportResistance.Support["S2"]) / result.CurrentPrice * 100
-	
-	srTable.Append([]string{"支撑S1", fmt.Sprintf("$%.2f", result.SupportResistance.Support["S1"]), 
-		fmt.Sprintf("-%.2f%%", s1Distance), "中"})
-	srTable.Append([]string{"支撑S2", fmt.Sprintf("$%.2f", result.SupportResistance.Support["S2"]), 
-		fmt.Sprintf("-%.2f%%", s2Distance), "强"})
+
+	levelStrength := i18n.List("analysis.level_strength")
+	distance := func(level float64) string {
+		return fmt.Sprintf("%+.2f%%", (level-result.CurrentPrice)/result.CurrentPrice*100)
+	}
+
+	// 阻力位从远到近，支撑位从近到远，DeMark只有R1/S1
+	for i := 4; i >= 1; i-- {
+		if level, ok := sr.Resistance[fmt.Sprintf("R%d", i)]; ok {
+			srTable.Append([]string{i18n.T("analysis.resistance_level", i), fmt.Sprintf("$%.2f", level), distance(level), levelStrength[i-1]})
+		}
+	}
+	srTable.Append([]string{i18n.T("analysis.pivot"), fmt.Sprintf("$%.2f", sr.Pivot), distance(sr.Pivot), i18n.T("analysis.pivot_strength")})
+	for i := 1; i <= 4; i++ {
+		if level, ok := sr.Support[fmt.Sprintf("S%d", i)]; ok {
+			srTable.Append([]string{i18n.T("analysis.support_level", i), fmt.Sprintf("$%.2f", level), distance(level), levelStrength[i-1]})
+		}
+	}
 	
 	srTable.Render()
+	printSRLevels(result)
+	printVolumeProfile(result)
+	printChartPatterns(result)
+	printMarketStructure(result)
+	printFibonacci(result)
+	printVolatility(result)
+	printCustomIndicators(result)
+	printExpressions(result)
 
 	// Evidence summary
 	bullishCount := evidenceSummary["bullishCount"].(int)
@@ -317,64 +925,476 @@ func printAnalysisResult(result *types.Analysis, evidenceSummary map[string]inte
 	warningCount := evidenceSummary["warningCount"].(int)
 	totalStrength := evidenceSummary["totalStrength"].(float64)
 
-	fmt.Printf("\n🔍 证据汇总:\n")
-	fmt.Printf("  看涨证据: %s\n", color.GreenString("%d条", bullishCount))
-	fmt.Printf("  看跌证据: %s\n", color.RedString("%d条", bearishCount))
-	fmt.Printf("  警告信号: %s\n", color.YellowString("%d条", warningCount))
-	fmt.Printf("  综合强度: %.2f\n", totalStrength)
+	fmt.Printf("\n%s\n", i18n.T("analysis.evidence_summary"))
+	fmt.Println(i18n.T("analysis.bullish_evidence", color.GreenString(i18n.T("analysis.count"), bullishCount)))
+	fmt.Println(i18n.T("analysis.bearish_evidence", color.RedString(i18n.T("analysis.count"), bearishCount)))
+	fmt.Println(i18n.T("analysis.warning_evidence", color.YellowString(i18n.T("analysis.count"), warningCount)))
+	fmt.Println(i18n.T("analysis.total_strength", totalStrength))
 
 	// 详细证据列表
-	fmt.Println("\n📋 详细证据分析:")
+	fmt.Printf("\n%s\n", i18n.T("analysis.evidence_title"))
 	evidenceTable := tablewriter.NewWriter(os.Stdout)
-	evidenceTable.SetHeader([]string{"类型", "类别", "描述", "权重"})
+	evidenceTable.SetHeader(i18n.List("analysis.evidence_header"))
 	evidenceTable.SetBorder(false)
 	evidenceTable.SetAlignment(tablewriter.ALIGN_LEFT)
 	
-	// 显示所有证据
+	// 按配置的条数显示最强的证据，保持原有顺序
+	hidden := 0
 	if allEvidences, ok := evidenceSummary["allEvidences"].([]types.Evidence); ok {
+		allEvidences, hidden = strongestEvidences(allEvidences, appConfig.Display.MaxEvidences)
 		for _, ev := range allEvidences {
 			typeStr := ""
 			switch ev.Type {
 			case types.BullishEvidence:
-				typeStr = "✅ 看涨"
+				typeStr = i18n.T("analysis.evidence_bullish")
 			case types.BearishEvidence:
-				typeStr = "❌ 看跌"
+				typeStr = i18n.T("analysis.evidence_bearish")
 			case types.WarningEvidence:
-				typeStr = "⚠️ 警告"
+				typeStr = i18n.T("analysis.evidence_warning")
 			case types.NeutralEvidence:
-				typeStr = "➖ 中性"
+				typeStr = i18n.T("analysis.evidence_neutral")
 			}
-			evidenceTable.Append([]string{typeStr, ev.Category, ev.Description, fmt.Sprintf("%.2f", ev.Strength)})
+			evidenceTable.Append([]string{typeStr, ev.CategoryLabel(), ev.Description, fmt.Sprintf("%.2f", ev.Strength)})
 		}
 	}
 	evidenceTable.Render()
+	if hidden > 0 {
+		fmt.Println(i18n.T("analysis.evidence_hidden", hidden))
+	}
 	
 	// 指标一致性分析
-	fmt.Println("\n🔍 指标一致性:")
-	fmt.Printf("  看涨信号: %d个\n", bullishCount)
-	fmt.Printf("  看跌信号: %d个\n", bearishCount)
-	fmt.Printf("  警告信号: %d个\n", warningCount)
+	fmt.Printf("\n%s\n", i18n.T("analysis.consistency_title"))
+	fmt.Println(i18n.T("analysis.bullish_signals", bullishCount))
+	fmt.Println(i18n.T("analysis.bearish_signals", bearishCount))
+	fmt.Println(i18n.T("analysis.warning_signals", warningCount))
 	
 	consistency := float64(max(bullishCount, bearishCount)) / float64(bullishCount+bearishCount+warningCount) * 100
-	fmt.Printf("  一致性: %.1f%%\n", consistency)
+	fmt.Println(i18n.T("analysis.consistency", consistency))
 	
 	// Trading suggestion - 基于原始数据
-	fmt.Println("\n💡 参考建议（仅供参考，请结合实际情况）:")
-	fmt.Printf("  综合得分: %.2f\n", totalStrength)
+	fmt.Printf("\n%s\n", i18n.T("analysis.suggestion_title"))
+	fmt.Println(i18n.T("analysis.overall_score", totalStrength))
 	
 	if totalStrength > 2 {
-		color.Green("  系统判断：强烈看涨信号")
+		color.Green(i18n.T("analysis.judgment", types.StrongBullishJudgment.String()))
 	} else if totalStrength > 0.5 {
-		color.Yellow("  系统判断：偏多信号")
+		color.Yellow(i18n.T("analysis.judgment", types.BullishJudgment.String()))
 	} else if totalStrength < -2 {
-		color.Red("  系统判断：强烈看跌信号")
+		color.Red(i18n.T("analysis.judgment", types.StrongBearishJudgment.String()))
 	} else if totalStrength < -0.5 {
-		color.Yellow("  系统判断：偏空信号")
+		color.Yellow(i18n.T("analysis.judgment", types.BearishJudgment.String()))
 	} else {
-		fmt.Println("  系统判断：信号不明确")
+		fmt.Println(i18n.T("analysis.judgment", types.NeutralJudgment.String()))
 	}
 	
-	fmt.Println("\n⚠️  提醒：以上为技术指标分析结果，投资决策需要综合考虑多方面因素")
+	fmt.Printf("\n%s\n", i18n.T("analysis.disclaimer"))
+}
+
+// trailingStatus 跟踪止损指标的状态描述
+func trailingStatus(ts types.TrailingStopAnalysis) string {
+	if ts.FlipBarsAgo >= 0 && ts.FlipBarsAgo <= 2 {
+		return i18n.T("analysis.trailing_flip", i18n.Label("flip", string(ts.Direction)), ts.FlipBarsAgo)
+	}
+	return ts.Direction.String()
+}
+
+// candleMarkers 当前K线完成的形态标记：▲看涨 ▼看跌 ◆犹豫
+func candleMarkers(patterns []types.CandlePattern) string {
+	var markers []string
+	for _, p := range patterns {
+		if p.BarsAgo != 0 {
+			continue
+		}
+		switch {
+		case p.Direction > 0:
+			markers = append(markers, color.GreenString("▲"+p.Name.String()))
+		case p.Direction < 0:
+			markers = append(markers, color.RedString("▼"+p.Name.String()))
+		default:
+			markers = append(markers, "◆"+p.Name.String())
+		}
+	}
+	return strings.Join(markers, " ")
+}
+
+// printIchimoku 打印一目均衡表
+func printIchimoku(result *types.Analysis) {
+	ich := result.Ichimoku
+	if !ich.Available {
+		return
+	}
+
+	fmt.Printf("\n%s\n", i18n.T("ichimoku.title"))
+	ichTable := tablewriter.NewWriter(os.Stdout)
+	ichTable.SetHeader(i18n.List("common.item_header"))
+	ichTable.SetBorder(false)
+	ichTable.SetAlignment(tablewriter.ALIGN_LEFT)
+
+	tkStatus := i18n.T("ichimoku.no_cross")
+	if ich.TKCross != "" {
+		tkStatus = i18n.T("ichimoku.tk_cross", ich.TKCross.String(), ich.TKCrossPosition.String(), ich.TKCrossBarsAgo)
+	}
+	twistStatus := i18n.T("common.none")
+	if ich.TwistBarsAhead > 0 {
+		twistStatus = i18n.T("ichimoku.twist", ich.TwistBarsAhead)
+	}
+
+	ichTable.Append([]string{i18n.T("ichimoku.tenkan_kijun"), fmt.Sprintf("$%.2f / $%.2f", ich.Tenkan, ich.Kijun), tkStatus})
+	ichTable.Append([]string{i18n.T("ichimoku.cloud"), fmt.Sprintf("$%.2f - $%.2f", ich.CloudBottom, ich.CloudTop), i18n.T("ichimoku.price_position", ich.PricePosition.String())})
+	ichTable.Append([]string{i18n.T("ichimoku.thickness"), fmt.Sprintf("%.2f%%", ich.CloudThickness), ""})
+	ichTable.Append([]string{i18n.T("ichimoku.future_cloud"), fmt.Sprintf("A: $%.2f / B: $%.2f", ich.FutureSenkouA, ich.FutureSenkouB), ich.CloudColor.String()})
+	ichTable.Append([]string{i18n.T("ichimoku.cloud_twist"), twistStatus, ""})
+	ichTable.Append([]string{i18n.T("ichimoku.chikou"), fmt.Sprintf("$%.2f", ich.Chikou), ich.ChikouStatus.String()})
+	ichTable.Render()
+}
+
+// printChartPatterns 打印识别到的图表形态
+func printChartPatterns(result *types.Analysis) {
+	if len(result.ChartPatterns) == 0 {
+		return
+	}
+
+	fmt.Printf("\n%s\n", i18n.T("pattern.title"))
+	cpTable := tablewriter.NewWriter(os.Stdout)
+	cpTable.SetHeader(i18n.List("pattern.header"))
+	cpTable.SetBorder(false)
+	cpTable.SetAlignment(tablewriter.ALIGN_LEFT)
+
+	for _, p := range result.ChartPatterns {
+		status := p.Status.String()
+		if p.BreakoutBarsAgo >= 0 {
+			status = i18n.T("common.bars_ago", status, p.BreakoutBarsAgo)
+		}
+		cpTable.Append([]string{
+			p.Name.String(),
+			status,
+			fmt.Sprintf("$%.2f", p.BreakoutLevel),
+			fmt.Sprintf("$%.2f", p.InvalidationLevel),
+			fmt.Sprintf("$%.2f", p.Target),
+			fmt.Sprintf("%.0f%%", p.Confidence*100),
+		})
+	}
+	cpTable.Render()
+}
+
+// printMarketStructure 打印最近的结构摆动点序列和结构突破事件
+func printMarketStructure(result *types.Analysis) {
+	ms := result.Structure
+	if !ms.Available || len(ms.Swings) == 0 {
+		return
+	}
+
+	fmt.Printf("\n%s\n", i18n.T("structure.title"))
+	labels := make([]string, 0, len(ms.Swings))
+	for _, swing := range ms.Swings {
+		label := string(swing.Label)
+		if label == "" {
+			label = "L"
+			if swing.IsHigh {
+				label = "H"
+			}
+		}
+		switch swing.Label {
+		case types.HigherHigh, types.HigherLow:
+			label = color.GreenString(label)
+		case types.LowerHigh, types.LowerLow:
+			label = color.RedString(label)
+		}
+		labels = append(labels, fmt.Sprintf("%s(%.2f)", label, swing.Price))
+	}
+	fmt.Println(i18n.T("structure.swings", strings.Join(labels, " → ")))
+
+	if ev := ms.LastEvent; ev != nil {
+		direction := color.GreenString(i18n.T("structure.up"))
+		if !ev.Bullish {
+			direction = color.RedString(i18n.T("structure.down"))
+		}
+		fmt.Println(i18n.T("structure.event", ev.Type, direction, ev.Level, ev.Time.Format("01-02 15:04"), ev.BarsAgo))
+	}
+	if ms.ProtectedLevel > 0 {
+		fmt.Println(i18n.T("structure.protected", ms.ProtectedLevel))
+	}
+}
+
+// printIndicatorList 打印注册表中的全部指标
+func printIndicatorList() {
+	fmt.Printf("\n%s\n", i18n.T("indicators.title"))
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader(i18n.List("indicators.header"))
+	table.SetBorder(false)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+
+	for _, def := range indicators.DefaultRegistry().Definitions() {
+		params := make([]string, 0, len(def.Params))
+		for _, param := range def.Params {
+			params = append(params, fmt.Sprintf("%s=%g", param.Name, param.Default))
+		}
+		inputs := make([]string, 0, len(def.Inputs))
+		for _, input := range def.Inputs {
+			inputs = append(inputs, string(input))
+		}
+		table.Append([]string{def.Name, def.Description, strings.Join(params, ","), strings.Join(inputs, ","), strings.Join(def.Outputs, ",")})
+	}
+	table.Render()
+	fmt.Println(i18n.T("indicators.sources"))
+}
+
+// printCustomIndicators 打印通过--indicator请求的指标
+func printCustomIndicators(result *types.Analysis) {
+	if len(indicatorKeys) == 0 {
+		return
+	}
+
+	fmt.Printf("\n%s\n", i18n.T("custom.title"))
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader(i18n.List("custom.header"))
+	table.SetBorder(false)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	for _, key := range indicatorKeys {
+		value := i18n.T("common.insufficient_data")
+		if v, ok := result.Indicators[key]; ok {
+			value = fmt.Sprintf("%.4f", v)
+		}
+		table.Append([]string{key, value})
+	}
+	table.Render()
+}
+
+// printVolatility 打印已实现波动率估计和Hurst/方差比市场状态
+func printVolatility(result *types.Analysis) {
+	vol := result.Volatility
+	if !vol.Available {
+		return
+	}
+
+	fmt.Printf("\n%s\n", i18n.T("volatility.title", vol.Interval, vol.PeriodsPerYear))
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{i18n.T("volatility.estimator"), i18n.T("volatility.annualized", vol.Window)})
+	table.SetBorder(false)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.Append([]string{i18n.T("volatility.close_to_close"), fmt.Sprintf("%.2f%%", vol.CloseToClose*100)})
+	table.Append([]string{"Parkinson", fmt.Sprintf("%.2f%%", vol.Parkinson*100)})
+	table.Append([]string{"Garman–Klass", fmt.Sprintf("%.2f%%", vol.GarmanKlass*100)})
+	table.Append([]string{"Rogers–Satchell", fmt.Sprintf("%.2f%%", vol.RogersSatchell*100)})
+	table.Append([]string{"Yang–Zhang", fmt.Sprintf("%.2f%%", vol.YangZhang*100)})
+	table.Render()
+
+	ratio := fmt.Sprintf("%.2f", vol.Ratio)
+	switch {
+	case vol.Ratio > 1.5:
+		ratio = color.RedString(i18n.T("volatility.expanding"), ratio)
+	case vol.Ratio > 0 && vol.Ratio < 0.7:
+		ratio = color.CyanString(i18n.T("volatility.contracting"), ratio)
+	}
+	fmt.Println(i18n.T("volatility.long_term", vol.LongWindow, vol.LongTerm*100, ratio))
+
+	if !vol.HurstAvailable {
+		fmt.Println(i18n.T("volatility.hurst_insufficient"))
+		return
+	}
+	regime := i18n.T("regime.random_walk")
+	switch vol.Regime {
+	case stats.RegimeTrending:
+		regime = color.GreenString(i18n.T("regime.trending"))
+	case stats.RegimeMeanReverting:
+		regime = color.YellowString(i18n.T("regime.mean_reverting"))
+	}
+	fmt.Println(i18n.T("volatility.hurst", vol.Hurst, vol.VarianceRatio, vol.VarianceRatioZ, regime))
+}
+
+// printExpressions 打印通过--expr定义的表达式在最新K线上的结果
+func printExpressions(result *types.Analysis) {
+	if len(result.Expressions) == 0 {
+		return
+	}
+
+	fmt.Printf("\n%s\n", i18n.T("expr.title"))
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader(i18n.List("expr.header"))
+	table.SetBorder(false)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	for _, res := range result.Expressions {
+		value := i18n.T("common.insufficient_data")
+		switch {
+		case !res.Valid:
+		case res.Boolean && res.Value != 0:
+			value = color.GreenString(i18n.T("common.yes"))
+		case res.Boolean:
+			value = i18n.T("common.no")
+		default:
+			value = fmt.Sprintf("%.4f", res.Value)
+		}
+		table.Append([]string{res.Expression, value})
+	}
+	table.Render()
+}
+
+// printFibonacci 打印主导波段的斐波那契回撤和扩展位
+func printFibonacci(result *types.Analysis) {
+	fib := result.Fibonacci
+	if !fib.Available {
+		return
+	}
+
+	swing := i18n.T("fibonacci.downswing", fib.SwingHigh, fib.SwingHighTime.Format("01-02 15:04"),
+		fib.SwingLow, fib.SwingLowTime.Format("01-02 15:04"))
+	if fib.Upswing {
+		swing = i18n.T("fibonacci.upswing", fib.SwingLow, fib.SwingLowTime.Format("01-02 15:04"),
+			fib.SwingHigh, fib.SwingHighTime.Format("01-02 15:04"))
+	}
+	fmt.Printf("\n%s\n", i18n.T("fibonacci.title", swing))
+	fibTable := tablewriter.NewWriter(os.Stdout)
+	fibTable.SetHeader(i18n.List("fibonacci.header"))
+	fibTable.SetBorder(false)
+	fibTable.SetAlignment(tablewriter.ALIGN_LEFT)
+
+	for _, level := range fib.Levels() {
+		status := ""
+		if level == fib.Testing {
+			status = i18n.T("fibonacci.testing")
+		}
+		fibTable.Append([]string{
+			level.Kind.String(),
+			fmt.Sprintf("%.1f%%", level.Ratio*100),
+			fmt.Sprintf("$%.2f", level.Price),
+			fmt.Sprintf("%+.2f%%", (level.Price-result.CurrentPrice)/result.CurrentPrice*100),
+			status,
+		})
+	}
+	fibTable.Render()
+}
+
+// printSRLevels 打印价格上下方最近的聚类支撑阻力位
+func printSRLevels(result *types.Analysis) {
+	sr := result.SupportResistance
+	if len(sr.Levels) == 0 {
+		return
+	}
+
+	// Levels按价格升序，取价格上下各3个
+	split := 0
+	for split < len(sr.Levels) && sr.Levels[split].Price < result.CurrentPrice {
+		split++
+	}
+	below := sr.Levels[max(0, split-3):split]
+	above := make([]types.SRLevel, 0, 3)
+	for _, level := range sr.Levels[split:] {
+		if level.Price > result.CurrentPrice && len(above) < 3 {
+			above = append(above, level)
+		}
+	}
+
+	fmt.Printf("\n%s\n", i18n.T("sr.title"))
+	levelTable := tablewriter.NewWriter(os.Stdout)
+	levelTable.SetHeader(i18n.List("sr.header"))
+	levelTable.SetBorder(false)
+	levelTable.SetAlignment(tablewriter.ALIGN_LEFT)
+
+	appendLevel := func(kind string, level types.SRLevel, nearest bool) {
+		if nearest {
+			kind = "★" + kind
+		}
+		touches := i18n.T("sr.touches", level.Touches)
+		if level.LastBarsAgo >= 0 {
+			touches += i18n.T("sr.touches_ago", level.LastBarsAgo)
+		} else {
+			touches = i18n.T("sr.untested")
+		}
+		levelTable.Append([]string{kind, fmt.Sprintf("$%.2f", level.Price),
+			fmt.Sprintf("%+.2f%%", (level.Price-result.CurrentPrice)/result.CurrentPrice*100),
+			level.Source.String(), touches, fmt.Sprintf("%.2f", level.Score)})
+	}
+	for i := len(above) - 1; i >= 0; i-- {
+		appendLevel(i18n.T("sr.resistance"), above[i], i == 0)
+	}
+	for i := len(below) - 1; i >= 0; i-- {
+		appendLevel(i18n.T("sr.support"), below[i], i == len(below)-1)
+	}
+	levelTable.Render()
+}
+
+// printVolumeProfile 打印成交量分布关键价位
+func printVolumeProfile(result *types.Analysis) {
+	sr := result.SupportResistance
+	if sr.POC == 0 {
+		return
+	}
+
+	distance := func(level float64) string {
+		return fmt.Sprintf("%+.2f%%", (level-result.CurrentPrice)/result.CurrentPrice*100)
+	}
+	joinLevels := func(levels []float64) string {
+		parts := make([]string, 0, len(levels))
+		for _, level := range levels {
+			parts = append(parts, fmt.Sprintf("$%.2f", level))
+		}
+		if len(parts) == 0 {
+			return i18n.T("common.none")
+		}
+		return strings.Join(parts, ", ")
+	}
+
+	fmt.Printf("\n%s\n", i18n.T("volume_profile.title"))
+	vpTable := tablewriter.NewWriter(os.Stdout)
+	vpTable.SetHeader(i18n.List("volume_profile.header"))
+	vpTable.SetBorder(false)
+	vpTable.SetAlignment(tablewriter.ALIGN_LEFT)
+
+	vpTable.Append([]string{i18n.T("volume_profile.vah"), fmt.Sprintf("$%.2f", sr.VAH), distance(sr.VAH)})
+	vpTable.Append([]string{i18n.T("volume_profile.poc"), fmt.Sprintf("$%.2f", sr.POC), distance(sr.POC)})
+	vpTable.Append([]string{i18n.T("volume_profile.val"), fmt.Sprintf("$%.2f", sr.VAL), distance(sr.VAL)})
+	vpTable.Append([]string{i18n.T("volume_profile.hvn"), joinLevels(sr.HVN), ""})
+	vpTable.Append([]string{i18n.T("volume_profile.lvn"), joinLevels(sr.LVN), ""})
+	vpTable.Render()
+}
+
+// printVWAP 打印VWAP及锚定VWAP
+func printVWAP(result *types.Analysis) {
+	vwap := result.VWAP
+	if vwap.SessionVWAP == 0 {
+		return
+	}
+
+	fmt.Printf("\n%s\n", i18n.T("vwap.title"))
+	vwapTable := tablewriter.NewWriter(os.Stdout)
+	vwapTable.SetHeader(i18n.List("common.item_header"))
+	vwapTable.SetBorder(false)
+	vwapTable.SetAlignment(tablewriter.ALIGN_LEFT)
+
+	vwapTable.Append([]string{i18n.T("vwap.session"), fmt.Sprintf("$%.2f", vwap.SessionVWAP), i18n.T("vwap.since", vwap.SessionStart.Format("01-02 15:04"))})
+	vwapTable.Append([]string{"±1σ", fmt.Sprintf("$%.2f - $%.2f", vwap.Lower1, vwap.Upper1), vwap.BandPosition.String()})
+	vwapTable.Append([]string{"±2σ", fmt.Sprintf("$%.2f - $%.2f", vwap.Lower2, vwap.Upper2), ""})
+	if vwap.AnchoredLow > 0 {
+		vwapTable.Append([]string{i18n.T("vwap.anchored_low"), fmt.Sprintf("$%.2f", vwap.AnchoredLow), i18n.T("vwap.anchor", vwap.AnchoredLowTime.Format("01-02 15:04"))})
+	}
+	if vwap.AnchoredHigh > 0 {
+		vwapTable.Append([]string{i18n.T("vwap.anchored_high"), fmt.Sprintf("$%.2f", vwap.AnchoredHigh), i18n.T("vwap.anchor", vwap.AnchoredHighTime.Format("01-02 15:04"))})
+	}
+	if vwap.AnchoredCustom > 0 {
+		vwapTable.Append([]string{i18n.T("vwap.anchored_custom"), fmt.Sprintf("$%.2f", vwap.AnchoredCustom), i18n.T("vwap.anchor", vwap.AnchoredCustomTime.Format("01-02 15:04"))})
+	}
+	vwapTable.Render()
+}
+
+// strongestEvidences 保留强度绝对值最大的limit条证据（保持原有顺序），返回隐藏的条数；limit为0时全部保留
+func strongestEvidences(evidences []types.Evidence, limit int) ([]types.Evidence, int) {
+	if limit <= 0 || len(evidences) <= limit {
+		return evidences, 0
+	}
+	order := make([]int, len(evidences))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return math.Abs(evidences[order[a]].Strength) > math.Abs(evidences[order[b]].Strength)
+	})
+	keep := order[:limit]
+	sort.Ints(keep)
+	kept := make([]types.Evidence, len(keep))
+	for i, index := range keep {
+		kept[i] = evidences[index]
+	}
+	return kept, len(evidences) - limit
 }
 
 func printPriceChart(ohlcv []types.OHLCV) {
@@ -415,11 +1435,11 @@ func printPriceChart(ohlcv []types.OHLCV) {
 	
 	// Create graph with caption
 	graph := asciigraph.Plot(closes, 
-		asciigraph.Height(10), 
-		asciigraph.Width(60),
-		asciigraph.Caption(fmt.Sprintf("价格区间: $%.2f - $%.2f", minPrice, maxPrice)))
+		asciigraph.Height(appConfig.Display.ChartHeight), 
+		asciigraph.Width(appConfig.Display.ChartWidth),
+		asciigraph.Caption(i18n.T("chart.price_range", minPrice, maxPrice)))
 	
-	fmt.Println("\n📈 价格走势图:")
+	fmt.Printf("\n%s\n", i18n.T("chart.title"))
 	fmt.Println(graph)
 	
 	// Time axis
@@ -447,7 +1467,7 @@ func printPriceChart(ohlcv []types.OHLCV) {
 	}
 	
 	// Calculate spacing
-	totalWidth := 60
+	totalWidth := appConfig.Display.ChartWidth
 	startLen := len(startTime)
 	midLen := len(midTime)
 	endLen := len(endTime)
@@ -470,41 +1490,31 @@ func printPriceChart(ohlcv []types.OHLCV) {
 	// Time period info
 	hoursStr := ""
 	if duration.Hours() < 24 {
-		hoursStr = fmt.Sprintf("%.0f小时", duration.Hours())
+		hoursStr = i18n.T("chart.hours", duration.Hours())
 	} else {
 		days := int(duration.Hours() / 24)
 		hours := int(duration.Hours()) % 24
 		if hours > 0 {
-			hoursStr = fmt.Sprintf("%d天%d小时", days, hours)
+			hoursStr = i18n.T("chart.days_hours", days, hours)
 		} else {
-			hoursStr = fmt.Sprintf("%d天", days)
+			hoursStr = i18n.T("chart.days", days)
 		}
 	}
 	
 	change := (closes[len(closes)-1] - closes[0]) / closes[0] * 100
-	fmt.Printf("\n时间跨度: %s  最高: $%.2f  最低: $%.2f  变化: %.2f%%\n", hoursStr, maxPrice, minPrice, change)
+	fmt.Printf("\n%s\n", i18n.T("chart.summary", hoursStr, maxPrice, minPrice, change))
 }
 
 func getTrendColor(trend types.TrendDirection) string {
-	trendStr := ""
 	switch trend {
-	case types.StrongUptrend:
-		trendStr = "强劲上涨趋势"
-		return color.GreenString(trendStr)
-	case types.Uptrend:
-		trendStr = "上涨趋势"
-		return color.GreenString(trendStr)
+	case types.StrongUptrend, types.Uptrend:
+		return color.GreenString(trend.String())
 	case types.Sideways:
-		trendStr = "横盘震荡"
-		return color.YellowString(trendStr)
-	case types.Downtrend:
-		trendStr = "下跌趋势"
-		return color.RedString(trendStr)
-	case types.StrongDowntrend:
-		trendStr = "强劲下跌趋势"
-		return color.RedString(trendStr)
+		return color.YellowString(trend.String())
+	case types.Downtrend, types.StrongDowntrend:
+		return color.RedString(trend.String())
 	default:
-		return string(trend)
+		return trend.String()
 	}
 }
 
@@ -545,7 +1555,7 @@ func max(a, b int) int {
 // printHistoricalSignals 打印历史信号追踪
 func printHistoricalSignals(symbol string, ohlcv []types.OHLCV, analyzer *analysis.TrendAnalyzer, collector *analysis.EvidenceCollector) {
 	fmt.Println("\n" + strings.Repeat("=", 80))
-	fmt.Println("📊 历史信号追踪（过去12小时）")
+	fmt.Println(i18n.T("history.title"))
 	fmt.Println(strings.Repeat("=", 80))
 	
 	// 根据时间间隔计算需要的数据点数
@@ -561,15 +1571,15 @@ func printHistoricalSignals(symbol string, ohlcv []types.OHLCV, analyzer *analys
 	if pointsNeeded < 12 {
 		hoursToShow = pointsNeeded / calculatePointsPerHour(interval)
 		if hoursToShow < 1 {
-			fmt.Println("  ⚠️  历史数据不足，无法显示信号追踪")
+			fmt.Println(i18n.T("history.no_history"))
 			return
 		}
-		fmt.Printf("  ℹ️  数据有限，显示过去%d小时\n", hoursToShow)
+		fmt.Println(i18n.T("history.limited", hoursToShow))
 	}
 	
 	// 创建信号追踪表
 	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"时间", "价格", "综合得分", "系统判断", "RSI", "MACD", "成交量"})
+	table.SetHeader(i18n.List("history.header"))
 	table.SetBorder(false)
 	table.SetAlignment(tablewriter.ALIGN_LEFT)
 	
@@ -583,8 +1593,8 @@ func printHistoricalSignals(symbol string, ohlcv []types.OHLCV, analyzer *analys
 	
 	// 确保有足够的历史数据
 	if len(ohlcv) <= minRequired {
-		fmt.Println("  ⚠️  数据不足，无法显示完整的历史信号")
-		fmt.Printf("  ℹ️  当前只有 %d 根K线，无法同时进行技术分析和历史追踪\n", len(ohlcv))
+		fmt.Println(i18n.T("history.insufficient"))
+		fmt.Println(i18n.T("history.candles", len(ohlcv)))
 		return
 	}
 	
@@ -601,7 +1611,7 @@ func printHistoricalSignals(symbol string, ohlcv []types.OHLCV, analyzer *analys
 	// 计算显示间隔
 	totalPoints := len(ohlcv) - startIdx
 	if totalPoints <= 0 {
-		fmt.Println("  ⚠️  没有足够的历史数据可显示")
+		fmt.Println(i18n.T("history.nothing"))
 		return
 	}
 	
@@ -615,10 +1625,10 @@ func printHistoricalSignals(symbol string, ohlcv []types.OHLCV, analyzer *analys
 	}
 	
 	// 为了避免重复计算，只在必要时重新分析
-	fmt.Printf("\n  ℹ️  分析时间范围: %s 至 %s\n", 
+	fmt.Printf("\n%s\n", i18n.T("history.range",
 		ohlcv[startIdx].Time.Format("01-02 15:04"),
-		ohlcv[len(ohlcv)-1].Time.Format("01-02 15:04"))
-	fmt.Printf("  ℹ️  数据点: 共%d个，每%d个显示一次\n\n", totalPoints, step)
+		ohlcv[len(ohlcv)-1].Time.Format("01-02 15:04")))
+	fmt.Printf("%s\n\n", i18n.T("history.points", totalPoints, step))
 	
 	for i := startIdx; i < len(ohlcv); i += step {
 		// 获取当前时间点的数据窗口（重用已有数据）
@@ -628,25 +1638,23 @@ func printHistoricalSignals(symbol string, ohlcv []types.OHLCV, analyzer *analys
 		}
 		window := ohlcv[windowStart : i+1]
 		
-		// 执行技术分析（这里会重用缓存的计算结果）
-		result, err := analyzer.AnalyzeComprehensive(window)
+		// 执行技术分析（这里会重用缓存的计算结果）；变换作用于截至该时间点的全部数据
+		result, err := analyzer.AnalyzeTransformed(ohlcv[:i+1], transform, len(window))
 		if err != nil {
 			continue
 		}
 		
-		// 收集证据
-		collector.Clear()
-		collector.AnalyzeMAEvidence(result.MAAnalysis, result.CurrentPrice)
-		collector.AnalyzeMACDEvidence(result.MACDAnalysis)
-		collector.AnalyzeRSIEvidence(result.Momentum.RSI)
-		collector.AnalyzeSREvidence(result.CurrentPrice, result.SupportResistance)
-		
 		// 计算价格变化
 		priceChange := 0.0
 		if i > 0 {
 			priceChange = (window[len(window)-1].Close - window[len(window)-2].Close) / window[len(window)-2].Close
 		}
-		collector.AnalyzeVolumeEvidence(result.Volume, priceChange)
+		
+		// 收集证据
+		collector.Clear()
+		if err := collector.Collect(result, priceChange); err != nil {
+			continue
+		}
 		
 		// 获取综合得分
 		summary := collector.GetSummary()
@@ -659,15 +1667,15 @@ func printHistoricalSignals(symbol string, ohlcv []types.OHLCV, analyzer *analys
 		// 确定系统判断
 		systemJudgment := ""
 		if totalStrength > 2 {
-			systemJudgment = color.GreenString("强烈看涨信号")
+			systemJudgment = color.GreenString(types.StrongBullishJudgment.String())
 		} else if totalStrength > 0.5 {
-			systemJudgment = color.YellowString("偏多信号")
+			systemJudgment = color.YellowString(types.BullishJudgment.String())
 		} else if totalStrength < -2 {
-			systemJudgment = color.RedString("强烈看跌信号")
+			systemJudgment = color.RedString(types.StrongBearishJudgment.String())
 		} else if totalStrength < -0.5 {
-			systemJudgment = color.YellowString("偏空信号")
+			systemJudgment = color.YellowString(types.BearishJudgment.String())
 		} else {
-			systemJudgment = "信号不明确"
+			systemJudgment = types.NeutralJudgment.String()
 		}
 		
 		// 格式化MACD
@@ -695,6 +1703,7 @@ func printHistoricalSignals(symbol string, ohlcv []types.OHLCV, analyzer *analys
 			fmt.Sprintf("%.1f", result.Momentum.RSI),
 			macdStr,
 			volumeStr,
+			candleMarkers(result.CandlePatterns),
 		})
 	}
 	
@@ -702,7 +1711,7 @@ func printHistoricalSignals(symbol string, ohlcv []types.OHLCV, analyzer *analys
 	
 	// 分析信号变化趋势
 	if len(scores) > 1 {
-		fmt.Println("\n🔄 信号变化分析:")
+		fmt.Printf("\n%s\n", i18n.T("history.change_title"))
 		
 		// 计算平均值
 		avgScore := 0.0
@@ -740,29 +1749,29 @@ func printHistoricalSignals(symbol string, ohlcv []types.OHLCV, analyzer *analys
 		}
 		recentAvg /= float64(len(scores) - halfPoint)
 		
-		fmt.Printf("  平均得分: %.2f\n", avgScore)
-		fmt.Printf("  最高得分: %.2f (%s)\n", maxScore, maxTime.Format("15:04"))
-		fmt.Printf("  最低得分: %.2f (%s)\n", minScore, minTime.Format("15:04"))
+		fmt.Println(i18n.T("history.average", avgScore))
+		fmt.Println(i18n.T("history.highest", maxScore, maxTime.Format("15:04")))
+		fmt.Println(i18n.T("history.lowest", minScore, minTime.Format("15:04")))
 		
 		// 趋势判断
-		fmt.Print("  信号趋势: ")
+		fmt.Print(i18n.T("history.trend"))
 		if recentAvg > historicalAvg + 0.3 {
-			color.Green("转强 ↗")
+			color.Green(i18n.T("history.strengthening"))
 		} else if recentAvg < historicalAvg - 0.3 {
-			color.Red("转弱 ↘")
+			color.Red(i18n.T("history.weakening"))
 		} else {
-			color.Yellow("横盘 →")
+			color.Yellow(i18n.T("history.flat"))
 		}
 		
 		// 当前位置
 		currentScore := scores[len(scores)-1]
-		fmt.Print("\n  当前位置: ")
+		fmt.Print("\n" + i18n.T("history.position"))
 		if currentScore > avgScore + 1.0 {
-			color.Red("可能超买")
+			color.Red(i18n.T("history.maybe_overbought"))
 		} else if currentScore < avgScore - 1.0 {
-			color.Green("可能超卖")
+			color.Green(i18n.T("history.maybe_oversold"))
 		} else {
-			fmt.Println("正常区间")
+			fmt.Println(i18n.T("history.normal"))
 		}
 	}
 }
@@ -8,6 +8,7 @@ require (
 	github.com/guptarohit/asciigraph v0.5.6
 	github.com/olekukonko/tablewriter v0.0.5
 	github.com/spf13/cobra v1.8.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
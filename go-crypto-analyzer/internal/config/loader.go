@@ -0,0 +1,641 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/zjc/go-crypto-analyzer/pkg/analysis"
+	"github.com/zjc/go-crypto-analyzer/pkg/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix prefixes environment variables overriding config values, e.g.
+// CRYPTO_ANALYZER_INDICATORS_RSI_PERIOD=9 or CRYPTO_ANALYZER_PROFILE=stable
+const EnvPrefix = "CRYPTO_ANALYZER_"
+
+// DefaultDir is the directory holding default.yaml, sensitivity.yaml and
+// ml_config.yaml
+const DefaultDir = "configs"
+
+// Config is the typed analyzer configuration. It mirrors configs/default.yaml;
+// Profiles and MarketConditions come from sensitivity.yaml and Learning from
+// ml_config.yaml
+type Config struct {
+	// Profile names the sensitivity profile applied on top of the base values
+	Profile    string               `yaml:"profile"`
+	Datasource DatasourceConfig     `yaml:"datasource"`
+	Analysis   AnalysisConfig       `yaml:"analysis"`
+	Indicators IndicatorConfig      `yaml:"indicators"`
+	Alerts     AlertConfig          `yaml:"alerts"`
+	Display    DisplayConfig        `yaml:"display"`
+	RateLimits map[string]RateLimit `yaml:"rate_limits"`
+	Logging    LoggingConfig        `yaml:"logging"`
+	Watchlists map[string][]string  `yaml:"watchlists"`
+
+	Profiles         map[string]Profile         `yaml:"-"`
+	MarketConditions map[string]MarketCondition `yaml:"-"`
+	Learning         LearningConfig             `yaml:"-"`
+}
+
+// DatasourceConfig selects the market data source
+type DatasourceConfig struct {
+	Primary  string `yaml:"primary"`
+	Fallback string `yaml:"fallback"`
+}
+
+// AnalysisConfig holds the default candle interval and count
+type AnalysisConfig struct {
+	Interval string `yaml:"interval"`
+	Limit    int    `yaml:"limit"`
+	// Timeframes are analyzed together in multi-timeframe mode for symbols
+	// without their own timeframes in CryptoConfig
+	Timeframes []string `yaml:"timeframes"`
+}
+
+// IndicatorConfig holds the indicator periods
+type IndicatorConfig struct {
+	MA struct {
+		Periods []int `yaml:"periods"`
+	} `yaml:"ma"`
+	MACD struct {
+		Fast   int `yaml:"fast"`
+		Slow   int `yaml:"slow"`
+		Signal int `yaml:"signal"`
+	} `yaml:"macd"`
+	RSI struct {
+		Period int `yaml:"period"`
+	} `yaml:"rsi"`
+	ADX struct {
+		Period int `yaml:"period"`
+	} `yaml:"adx"`
+	Bollinger struct {
+		Period int     `yaml:"period"`
+		StdDev float64 `yaml:"std_dev"`
+	} `yaml:"bollinger"`
+	Ichimoku struct {
+		Tenkan       int `yaml:"tenkan"`
+		Kijun        int `yaml:"kijun"`
+		SenkouB      int `yaml:"senkou_b"`
+		Displacement int `yaml:"displacement"`
+	} `yaml:"ichimoku"`
+}
+
+// AlertConfig holds the alert thresholds, also used by the backtest strategies
+type AlertConfig struct {
+	RSI struct {
+		Oversold   float64 `yaml:"oversold"`
+		Weak       float64 `yaml:"weak"`
+		Strong     float64 `yaml:"strong"`
+		Overbought float64 `yaml:"overbought"`
+		Midline    float64 `yaml:"midline"`
+		ChaseLimit float64 `yaml:"chase_limit"`
+		Extreme    float64 `yaml:"extreme"`
+	} `yaml:"rsi"`
+	Volume struct {
+		High float64 `yaml:"high"`
+		Low  float64 `yaml:"low"`
+	} `yaml:"volume"`
+	PriceChange struct {
+		Significant float64 `yaml:"significant"`
+	} `yaml:"price_change"`
+	ADX struct {
+		Weak       float64 `yaml:"weak"`
+		Moderate   float64 `yaml:"moderate"`
+		Strong     float64 `yaml:"strong"`
+		VeryStrong float64 `yaml:"very_strong"`
+	} `yaml:"adx"`
+	Volatility struct {
+		Low  float64 `yaml:"low"`
+		High float64 `yaml:"high"`
+	} `yaml:"volatility"`
+	// Strategy holds the entry and exit thresholds of the improved backtest
+	Strategy struct {
+		LongSignal         float64 `yaml:"long_signal"`
+		ShortSignal        float64 `yaml:"short_signal"`
+		VolumeConfirmation float64 `yaml:"volume_confirmation"`
+		Reversal           float64 `yaml:"reversal"`
+		TakeProfit         float64 `yaml:"take_profit"`
+	} `yaml:"strategy"`
+}
+
+// DisplayConfig controls the console output
+type DisplayConfig struct {
+	ShowChart   bool `yaml:"show_chart"`
+	ChartHeight int  `yaml:"chart_height"`
+	ChartWidth  int  `yaml:"chart_width"`
+	// MaxEvidences caps the evidence table, strongest first; 0 shows all
+	MaxEvidences  int `yaml:"max_evidences"`
+	DecimalPlaces struct {
+		Price     int `yaml:"price"`
+		Percent   int `yaml:"percent"`
+		Indicator int `yaml:"indicator"`
+	} `yaml:"decimal_places"`
+}
+
+// RateLimit is the request budget of a data source
+type RateLimit struct {
+	RequestsPerMinute int `yaml:"requests_per_minute"`
+	WeightPerMinute   int `yaml:"weight_per_minute"`
+}
+
+// LoggingConfig configures logging
+type LoggingConfig struct {
+	Level string `yaml:"level"`
+	File  string `yaml:"file"`
+}
+
+// Profile is a sensitivity profile of sensitivity.yaml; zero values keep the
+// base configuration
+type Profile struct {
+	Interval   string `yaml:"interval"`
+	Limit      int    `yaml:"limit"`
+	Indicators struct {
+		MAPeriods  []int `yaml:"ma_periods"`
+		RSIPeriod  int   `yaml:"rsi_period"`
+		MACDFast   int   `yaml:"macd_fast"`
+		MACDSlow   int   `yaml:"macd_slow"`
+		MACDSignal int   `yaml:"macd_signal"`
+	} `yaml:"indicators"`
+	Description string `yaml:"description"`
+}
+
+// MarketCondition recommends a profile for a market condition
+type MarketCondition struct {
+	RecommendedProfile string   `yaml:"recommended_profile"`
+	Characteristics    []string `yaml:"characteristics"`
+}
+
+// LearningConfig holds the indicator weights and adaptive RSI thresholds of
+// ml_config.yaml, which drive the dynamic analyzer
+type LearningConfig struct {
+	IndicatorWeights struct {
+		// Base weighs the evidence categories MA, MACD, RSI and Volume
+		Base map[string]float64 `yaml:"base"`
+		// Performance records the historical results of each indicator
+		Performance map[string]IndicatorPerformance `yaml:"performance"`
+	} `yaml:"indicator_weights"`
+	AdaptiveThresholds struct {
+		// RSI holds the zones by market: bull_market, bear_market and normal
+		RSI map[string]RSIThreshold `yaml:"rsi"`
+	} `yaml:"adaptive_thresholds"`
+}
+
+// IndicatorPerformance is the recorded historical performance of an indicator
+type IndicatorPerformance struct {
+	SuccessRate   float64 `yaml:"success_rate"`
+	AvgProfit     float64 `yaml:"avg_profit"`
+	BestTimeframe string  `yaml:"best_timeframe"`
+}
+
+// RSIThreshold is an oversold/overbought RSI pair
+type RSIThreshold struct {
+	Overbought float64 `yaml:"overbought"`
+	Oversold   float64 `yaml:"oversold"`
+}
+
+// MarketPattern describes the characteristics of a market in ml_config.yaml
+type MarketPattern struct {
+	Indicators        []string           `yaml:"indicators"`
+	WeightAdjustments map[string]float64 `yaml:"weight_adjustments"`
+}
+
+type mlConfigFile struct {
+	LearningConfig `yaml:",inline"`
+	MarketPatterns map[string]MarketPattern `yaml:"market_patterns"`
+	SignalQuality  map[string][]string      `yaml:"signal_quality"`
+}
+
+type sensitivityFile struct {
+	Profiles         map[string]Profile         `yaml:"profiles"`
+	MarketConditions map[string]MarketCondition `yaml:"market_conditions"`
+	SensitivityTips  []string                   `yaml:"sensitivity_tips"`
+}
+
+// LoadOptions locates the configuration
+type LoadOptions struct {
+	// Dir holds default.yaml, sensitivity.yaml and ml_config.yaml; empty uses
+	// DefaultDir and tolerates its absence
+	Dir string
+	// File is an optional override file with the layout of default.yaml
+	File string
+	// Profile overrides the profile named by the files and environment
+	Profile string
+}
+
+// Default returns the built-in configuration, matching configs/default.yaml
+func Default() *Config {
+	settings := analysis.DefaultSettings()
+	cfg := &Config{
+		Datasource: DatasourceConfig{Primary: "binance", Fallback: "yahoo"},
+		Analysis:   AnalysisConfig{Interval: "1h", Limit: 100, Timeframes: []string{"15m", "1h", "4h", "1d"}},
+		Display:    DisplayConfig{ShowChart: true, ChartHeight: 10, ChartWidth: 60, MaxEvidences: 10},
+		Watchlists: make(map[string][]string),
+		Profiles:   make(map[string]Profile),
+	}
+	cfg.Indicators.MA.Periods = settings.MAPeriods
+	cfg.Indicators.MACD.Fast = settings.MACDFast
+	cfg.Indicators.MACD.Slow = settings.MACDSlow
+	cfg.Indicators.MACD.Signal = settings.MACDSignal
+	cfg.Indicators.RSI.Period = settings.RSIPeriod
+	cfg.Indicators.ADX.Period = settings.ADXPeriod
+	cfg.Indicators.Bollinger.Period = settings.BollingerPeriod
+	cfg.Indicators.Bollinger.StdDev = settings.BollingerStdDev
+	cfg.Indicators.Ichimoku.Tenkan, cfg.Indicators.Ichimoku.Kijun = settings.IchimokuTenkan, settings.IchimokuKijun
+	cfg.Indicators.Ichimoku.SenkouB, cfg.Indicators.Ichimoku.Displacement = settings.IchimokuSenkouB, settings.IchimokuDisplacement
+	t := settings.Thresholds
+	cfg.Alerts.RSI.Oversold, cfg.Alerts.RSI.Weak = t.RSIOversold, t.RSIWeak
+	cfg.Alerts.RSI.Strong, cfg.Alerts.RSI.Overbought = t.RSIStrong, t.RSIOverbought
+	cfg.Alerts.RSI.Midline, cfg.Alerts.RSI.ChaseLimit, cfg.Alerts.RSI.Extreme = t.RSIMidline, t.RSIChaseLimit, t.RSIExtreme
+	cfg.Alerts.Volume.High, cfg.Alerts.Volume.Low = t.VolumeHigh, t.VolumeLow
+	cfg.Alerts.PriceChange.Significant = t.SignificantChange
+	cfg.Alerts.ADX.Weak, cfg.Alerts.ADX.Moderate = t.ADXWeak, t.ADXModerate
+	cfg.Alerts.ADX.Strong, cfg.Alerts.ADX.VeryStrong = t.ADXStrong, t.ADXVeryStrong
+	cfg.Alerts.Volatility.Low, cfg.Alerts.Volatility.High = t.VolatilityLow, t.VolatilityHigh
+	cfg.Alerts.Strategy.LongSignal, cfg.Alerts.Strategy.ShortSignal = t.LongSignal, t.ShortSignal
+	cfg.Alerts.Strategy.VolumeConfirmation, cfg.Alerts.Strategy.Reversal = t.VolumeConfirmation, t.ReversalStrength
+	cfg.Alerts.Strategy.TakeProfit = t.TakeProfit
+	cfg.Display.DecimalPlaces.Price, cfg.Display.DecimalPlaces.Percent, cfg.Display.DecimalPlaces.Indicator = 2, 2, 1
+	learning := analysis.DefaultLearning()
+	cfg.Learning.IndicatorWeights.Base = learning.BaseWeights
+	cfg.Learning.AdaptiveThresholds.RSI = make(map[string]RSIThreshold)
+	for market, band := range learning.RSIBands {
+		cfg.Learning.AdaptiveThresholds.RSI[market] = RSIThreshold{Overbought: band.Overbought, Oversold: band.Oversold}
+	}
+	return cfg
+}
+
+// Load layers the built-in defaults, the files in the config directory, the
+// selected profile, the override file and CRYPTO_ANALYZER_* environment
+// variables, then validates the result. The override file and environment
+// win over the profile
+func Load(opts LoadOptions) (*Config, error) {
+	cfg := Default()
+	dir, required := opts.Dir, true
+	if dir == "" {
+		dir, required = DefaultDir, false
+	}
+	if required {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("config directory %s not found", dir)
+		}
+	}
+
+	if err := decodeFile(filepath.Join(dir, "default.yaml"), cfg, true); err != nil {
+		return nil, err
+	}
+	var sensitivity sensitivityFile
+	if err := decodeFile(filepath.Join(dir, "sensitivity.yaml"), &sensitivity, true); err != nil {
+		return nil, err
+	}
+	for name, profile := range sensitivity.Profiles {
+		cfg.Profiles[name] = profile
+	}
+	cfg.MarketConditions = sensitivity.MarketConditions
+	var ml mlConfigFile
+	if err := decodeFile(filepath.Join(dir, "ml_config.yaml"), &ml, true); err != nil {
+		return nil, err
+	}
+	// The file's weights replace the built-in ones as a whole so they still
+	// sum to 1; its RSI zones replace those of the same market
+	if len(ml.IndicatorWeights.Base) > 0 {
+		cfg.Learning.IndicatorWeights.Base = ml.IndicatorWeights.Base
+	}
+	cfg.Learning.IndicatorWeights.Performance = ml.IndicatorWeights.Performance
+	for market, levels := range ml.AdaptiveThresholds.RSI {
+		cfg.Learning.AdaptiveThresholds.RSI[market] = levels
+	}
+
+	// The override file and environment may name the profile, and are applied
+	// again afterwards so their values win over it
+	overrides := func() error {
+		if opts.File != "" {
+			if _, err := os.Stat(opts.File); err != nil {
+				return fmt.Errorf("config file %s: %w", opts.File, err)
+			}
+			if err := decodeFile(opts.File, cfg, true); err != nil {
+				return err
+			}
+		}
+		return applyEnv(reflect.ValueOf(cfg).Elem(), strings.TrimSuffix(EnvPrefix, "_"))
+	}
+	if err := overrides(); err != nil {
+		return nil, err
+	}
+	if opts.Profile != "" {
+		cfg.Profile = opts.Profile
+	}
+	if cfg.Profile != "" {
+		profile, ok := cfg.Profiles[cfg.Profile]
+		if !ok {
+			return nil, fmt.Errorf("unknown profile %q (%s)", cfg.Profile, strings.Join(cfg.ProfileNames(), "|"))
+		}
+		cfg.applyProfile(profile)
+		if err := overrides(); err != nil {
+			return nil, err
+		}
+		if opts.Profile != "" {
+			cfg.Profile = opts.Profile
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// decodeFile decodes a YAML file into out; a missing file is not an error.
+// strict rejects keys that do not map to a field, catching typos
+func decodeFile(path string, out interface{}, strict bool) error {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(strict)
+	if err := decoder.Decode(out); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// applyProfile overrides the base values with the non-zero profile values
+func (c *Config) applyProfile(profile Profile) {
+	if profile.Interval != "" {
+		c.Analysis.Interval = profile.Interval
+	}
+	if profile.Limit > 0 {
+		c.Analysis.Limit = profile.Limit
+	}
+	ind := profile.Indicators
+	if len(ind.MAPeriods) > 0 {
+		c.Indicators.MA.Periods = append([]int{}, ind.MAPeriods...)
+	}
+	if ind.RSIPeriod > 0 {
+		c.Indicators.RSI.Period = ind.RSIPeriod
+	}
+	if ind.MACDFast > 0 {
+		c.Indicators.MACD.Fast = ind.MACDFast
+	}
+	if ind.MACDSlow > 0 {
+		c.Indicators.MACD.Slow = ind.MACDSlow
+	}
+	if ind.MACDSignal > 0 {
+		c.Indicators.MACD.Signal = ind.MACDSignal
+	}
+}
+
+// applyEnv sets scalar and list fields from environment variables named by
+// their upper-cased YAML path, e.g. CRYPTO_ANALYZER_ALERTS_RSI_OVERBOUGHT.
+// Lists are comma separated; maps are not overridable
+func applyEnv(v reflect.Value, prefix string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		tag := strings.Split(t.Field(i).Tag.Get("yaml"), ",")[0]
+		if tag == "" || tag == "-" {
+			continue
+		}
+		name := prefix + "_" + strings.ToUpper(tag)
+		field := v.Field(i)
+		if field.Kind() == reflect.Struct {
+			if err := applyEnv(field, name); err != nil {
+				return err
+			}
+			continue
+		}
+		text, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		if err := setFromString(field, text); err != nil {
+			return fmt.Errorf("%s=%q: %w", name, text, err)
+		}
+	}
+	return nil
+}
+
+func setFromString(field reflect.Value, text string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(text)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int:
+		n, err := strconv.Atoi(text)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(n))
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	case reflect.Slice:
+		parts := strings.Split(text, ",")
+		list := reflect.MakeSlice(field.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			if err := setFromString(list.Index(i), strings.TrimSpace(part)); err != nil {
+				return err
+			}
+		}
+		field.Set(list)
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+	return nil
+}
+
+// Validate reports every invalid value at once
+func (c *Config) Validate() error {
+	problems := make([]string, 0)
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			problems = append(problems, fmt.Sprintf(format, args...))
+		}
+	}
+
+	check(c.Datasource.Primary == "binance" || c.Datasource.Primary == "yahoo",
+		"datasource.primary must be binance or yahoo, got %q", c.Datasource.Primary)
+	if err := utils.ValidateInterval(c.Analysis.Interval); err != nil {
+		problems = append(problems, "analysis.interval: "+err.Error())
+	}
+	check(c.Analysis.Limit > 0 && c.Analysis.Limit <= 1000, "analysis.limit must be in 1-1000, got %d", c.Analysis.Limit)
+	check(len(c.Analysis.Timeframes) > 0, "analysis.timeframes must not be empty")
+	for _, timeframe := range c.Analysis.Timeframes {
+		if err := utils.ValidateInterval(timeframe); err != nil {
+			problems = append(problems, "analysis.timeframes: "+err.Error())
+		}
+	}
+
+	periods := c.Indicators.MA.Periods
+	check(len(periods) > 0, "indicators.ma.periods must not be empty")
+	for i, period := range periods {
+		check(period > 0 && (i == 0 || period > periods[i-1]),
+			"indicators.ma.periods must be positive and increasing, got %v", periods)
+	}
+	macd := c.Indicators.MACD
+	check(macd.Fast > 0 && macd.Slow > macd.Fast && macd.Signal > 0,
+		"indicators.macd needs 0 < fast < slow and signal > 0, got %d/%d/%d", macd.Fast, macd.Slow, macd.Signal)
+	check(c.Indicators.RSI.Period >= 2, "indicators.rsi.period must be at least 2, got %d", c.Indicators.RSI.Period)
+	check(c.Indicators.ADX.Period >= 2, "indicators.adx.period must be at least 2, got %d", c.Indicators.ADX.Period)
+	check(c.Indicators.Bollinger.Period >= 2 && c.Indicators.Bollinger.StdDev > 0,
+		"indicators.bollinger needs period >= 2 and std_dev > 0, got %d/%g", c.Indicators.Bollinger.Period, c.Indicators.Bollinger.StdDev)
+	ichimoku := c.Indicators.Ichimoku
+	check(0 < ichimoku.Tenkan && ichimoku.Tenkan < ichimoku.Kijun && ichimoku.Kijun < ichimoku.SenkouB && ichimoku.Displacement > 0,
+		"indicators.ichimoku needs 0 < tenkan < kijun < senkou_b and displacement > 0, got %d/%d/%d/%d",
+		ichimoku.Tenkan, ichimoku.Kijun, ichimoku.SenkouB, ichimoku.Displacement)
+
+	rsi := c.Alerts.RSI
+	check(0 < rsi.Oversold && rsi.Oversold <= rsi.Weak && rsi.Weak <= rsi.Strong && rsi.Strong <= rsi.Overbought && rsi.Overbought < 100,
+		"alerts.rsi needs 0 < oversold <= weak <= strong <= overbought < 100, got %g/%g/%g/%g",
+		rsi.Oversold, rsi.Weak, rsi.Strong, rsi.Overbought)
+	check(rsi.Oversold < rsi.Midline && rsi.Midline < rsi.Overbought,
+		"alerts.rsi.midline must be between oversold and overbought, got %g", rsi.Midline)
+	check(0 < rsi.ChaseLimit && rsi.ChaseLimit <= rsi.Extreme && rsi.Overbought <= rsi.Extreme && rsi.Extreme < 100,
+		"alerts.rsi needs 0 < chase_limit <= extreme, overbought <= extreme < 100, got %g/%g", rsi.ChaseLimit, rsi.Extreme)
+	check(0 < c.Alerts.Volume.Low && c.Alerts.Volume.Low < c.Alerts.Volume.High,
+		"alerts.volume needs 0 < low < high, got %g/%g", c.Alerts.Volume.Low, c.Alerts.Volume.High)
+	check(c.Alerts.PriceChange.Significant > 0 && c.Alerts.PriceChange.Significant < 1,
+		"alerts.price_change.significant must be a fraction in (0, 1), got %g", c.Alerts.PriceChange.Significant)
+	adx := c.Alerts.ADX
+	check(0 < adx.Weak && adx.Weak < adx.Moderate && adx.Moderate < adx.Strong && adx.Strong < adx.VeryStrong && adx.VeryStrong < 100,
+		"alerts.adx needs 0 < weak < moderate < strong < very_strong < 100, got %g/%g/%g/%g", adx.Weak, adx.Moderate, adx.Strong, adx.VeryStrong)
+	check(0 < c.Alerts.Volatility.Low && c.Alerts.Volatility.Low < c.Alerts.Volatility.High,
+		"alerts.volatility needs 0 < low < high, got %g/%g", c.Alerts.Volatility.Low, c.Alerts.Volatility.High)
+	strategy := c.Alerts.Strategy
+	check(strategy.ShortSignal < 0 && strategy.LongSignal > 0,
+		"alerts.strategy needs short_signal < 0 < long_signal, got %g/%g", strategy.ShortSignal, strategy.LongSignal)
+	check(strategy.VolumeConfirmation > 0, "alerts.strategy.volume_confirmation must be positive, got %g", strategy.VolumeConfirmation)
+	check(strategy.Reversal > 0, "alerts.strategy.reversal must be positive, got %g", strategy.Reversal)
+	check(strategy.TakeProfit > 0 && strategy.TakeProfit < 1,
+		"alerts.strategy.take_profit must be a fraction in (0, 1), got %g", strategy.TakeProfit)
+
+	check(c.Display.ChartHeight > 0 && c.Display.ChartWidth > 0,
+		"display chart size must be positive, got %dx%d", c.Display.ChartWidth, c.Display.ChartHeight)
+	check(c.Display.MaxEvidences >= 0, "display.max_evidences must not be negative, got %d", c.Display.MaxEvidences)
+
+	for name, symbols := range c.Watchlists {
+		check(len(symbols) > 0, "watchlists.%s is empty", name)
+		for _, symbol := range symbols {
+			if err := utils.ValidateSymbol(symbol); err != nil {
+				problems = append(problems, fmt.Sprintf("watchlists.%s: %v", name, err))
+			}
+		}
+	}
+	for name, condition := range c.MarketConditions {
+		_, ok := c.Profiles[condition.RecommendedProfile]
+		check(ok, "market_conditions.%s recommends unknown profile %q", name, condition.RecommendedProfile)
+	}
+	sum := 0.0
+	for name, weight := range c.Learning.IndicatorWeights.Base {
+		check(weight >= 0, "indicator_weights.base.%s must not be negative, got %g", name, weight)
+		sum += weight
+	}
+	check(math.Abs(sum-1) < 0.01, "indicator_weights.base must sum to 1, got %.2f", sum)
+	for market, levels := range c.Learning.AdaptiveThresholds.RSI {
+		check(0 < levels.Oversold && levels.Oversold < levels.Overbought && levels.Overbought < 100,
+			"adaptive_thresholds.rsi.%s needs 0 < oversold < overbought < 100, got %g/%g", market, levels.Oversold, levels.Overbought)
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	sort.Strings(problems)
+	return fmt.Errorf("invalid config:\n  %s", strings.Join(problems, "\n  "))
+}
+
+// ProfileNames returns the available profiles in sorted order
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// AnalysisSettings returns the periods and thresholds for the analyzer, the
+// evidence collector and the improved backtest strategy
+func (c *Config) AnalysisSettings() analysis.Settings {
+	return analysis.Settings{
+		MAPeriods:            append([]int{}, c.Indicators.MA.Periods...),
+		MACDFast:             c.Indicators.MACD.Fast,
+		MACDSlow:             c.Indicators.MACD.Slow,
+		MACDSignal:           c.Indicators.MACD.Signal,
+		RSIPeriod:            c.Indicators.RSI.Period,
+		ADXPeriod:            c.Indicators.ADX.Period,
+		BollingerPeriod:      c.Indicators.Bollinger.Period,
+		BollingerStdDev:      c.Indicators.Bollinger.StdDev,
+		IchimokuTenkan:       c.Indicators.Ichimoku.Tenkan,
+		IchimokuKijun:        c.Indicators.Ichimoku.Kijun,
+		IchimokuSenkouB:      c.Indicators.Ichimoku.SenkouB,
+		IchimokuDisplacement: c.Indicators.Ichimoku.Displacement,
+		Thresholds: analysis.Thresholds{
+			RSIOversold:        c.Alerts.RSI.Oversold,
+			RSIWeak:            c.Alerts.RSI.Weak,
+			RSIStrong:          c.Alerts.RSI.Strong,
+			RSIOverbought:      c.Alerts.RSI.Overbought,
+			RSIMidline:         c.Alerts.RSI.Midline,
+			RSIChaseLimit:      c.Alerts.RSI.ChaseLimit,
+			RSIExtreme:         c.Alerts.RSI.Extreme,
+			VolumeHigh:         c.Alerts.Volume.High,
+			VolumeLow:          c.Alerts.Volume.Low,
+			SignificantChange:  c.Alerts.PriceChange.Significant,
+			ADXWeak:            c.Alerts.ADX.Weak,
+			ADXModerate:        c.Alerts.ADX.Moderate,
+			ADXStrong:          c.Alerts.ADX.Strong,
+			ADXVeryStrong:      c.Alerts.ADX.VeryStrong,
+			LongSignal:         c.Alerts.Strategy.LongSignal,
+			ShortSignal:        c.Alerts.Strategy.ShortSignal,
+			VolumeConfirmation: c.Alerts.Strategy.VolumeConfirmation,
+			ReversalStrength:   c.Alerts.Strategy.Reversal,
+			VolatilityLow:      c.Alerts.Volatility.Low,
+			VolatilityHigh:     c.Alerts.Volatility.High,
+			TakeProfit:         c.Alerts.Strategy.TakeProfit,
+		},
+	}
+}
+
+// LearningSettings returns the indicator weights and adaptive RSI thresholds
+// for the dynamic analyzer
+func (c *Config) LearningSettings() analysis.Learning {
+	learning := analysis.Learning{
+		BaseWeights: make(map[string]float64, len(c.Learning.IndicatorWeights.Base)),
+		RSIBands:    make(map[string]analysis.RSIBand, len(c.Learning.AdaptiveThresholds.RSI)),
+	}
+	for name, weight := range c.Learning.IndicatorWeights.Base {
+		learning.BaseWeights[name] = weight
+	}
+	for market, levels := range c.Learning.AdaptiveThresholds.RSI {
+		learning.RSIBands[market] = analysis.RSIBand{Oversold: levels.Oversold, Overbought: levels.Overbought}
+	}
+	return learning
+}
+
+// Watchlist returns a watchlist by name, preferring the configured lists over
+// the built-in ones and falling back to top3
+func (c *Config) Watchlist(name string) []string {
+	if list, ok := c.Watchlists[name]; ok {
+		return list
+	}
+	return GetWatchlist(name)
+}
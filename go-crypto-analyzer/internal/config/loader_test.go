@@ -0,0 +1,114 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	dir := filepath.Join("..", "..", "configs")
+
+	cfg, err := Load(LoadOptions{Dir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(cfg.AnalysisSettings(), Default().AnalysisSettings()) {
+		t.Errorf("default.yaml should match the built-in defaults:\n%+v\n%+v", cfg.AnalysisSettings(), Default().AnalysisSettings())
+	}
+	if len(cfg.Watchlist("meme")) != 3 || len(cfg.Watchlist("top10")) != 10 {
+		t.Errorf("watchlists: meme %v top10 %v", cfg.Watchlist("meme"), cfg.Watchlist("top10"))
+	}
+	if names := strings.Join(cfg.ProfileNames(), ","); names != "balanced,sensitive,stable" {
+		t.Errorf("profiles: %s", names)
+	}
+	if !reflect.DeepEqual(cfg.LearningSettings(), Default().LearningSettings()) {
+		t.Errorf("ml_config.yaml should match the built-in learning settings:\n%+v\n%+v", cfg.LearningSettings(), Default().LearningSettings())
+	}
+	if perf := cfg.Learning.IndicatorWeights.Performance["MA"]; perf.BestTimeframe != "4h" {
+		t.Errorf("indicator performance: %+v", perf)
+	}
+
+	// profile < override file < environment
+	override := filepath.Join(t.TempDir(), "override.yaml")
+	content := "profile: sensitive\nindicators:\n  macd:\n    slow: 21\nalerts:\n  rsi:\n    overbought: 80\n  strategy:\n    take_profit: 0.08\n"
+	if err := os.WriteFile(override, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CRYPTO_ANALYZER_ALERTS_RSI_OVERBOUGHT", "75")
+	t.Setenv("CRYPTO_ANALYZER_INDICATORS_MA_PERIODS", "5,10,20,50")
+	t.Setenv("CRYPTO_ANALYZER_INDICATORS_ICHIMOKU_SENKOU_B", "44")
+	t.Setenv("CRYPTO_ANALYZER_ALERTS_ADX_VERY_STRONG", "45")
+	cfg, err = Load(LoadOptions{Dir: dir, File: override})
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings := cfg.AnalysisSettings()
+	if cfg.Analysis.Interval != "15m" || settings.RSIPeriod != 9 || settings.MACDFast != 8 || settings.MACDSlow != 21 {
+		t.Errorf("sensitive profile with override: interval %s %+v", cfg.Analysis.Interval, settings)
+	}
+	if settings.Thresholds.RSIOverbought != 75 || !reflect.DeepEqual(settings.MAPeriods, []int{5, 10, 20, 50}) {
+		t.Errorf("environment should win: %+v", settings)
+	}
+	// The backtest strategies read their RSI thresholds from the config too
+	if th := settings.Thresholds; th.RSIMidline != 50 || th.RSIChaseLimit != 75 || th.RSIExtreme != 80 {
+		t.Errorf("strategy thresholds: %+v", th)
+	}
+	if th := settings.Thresholds; th.TakeProfit != 0.08 || th.ADXVeryStrong != 45 || th.LongSignal != 0.6 || th.VolatilityHigh != 1.5 {
+		t.Errorf("strategy and ADX thresholds: %+v", th)
+	}
+	if settings.IchimokuSenkouB != 44 || settings.IchimokuKijun != 26 {
+		t.Errorf("ichimoku periods: %+v", settings)
+	}
+
+	// The explicit profile wins over the one named in the files
+	cfg, err = Load(LoadOptions{Dir: dir, File: override, Profile: "stable"})
+	if err != nil || cfg.Analysis.Interval != "4h" || cfg.Indicators.RSI.Period != 21 {
+		t.Errorf("stable profile: %v %+v", err, cfg)
+	}
+
+	if _, err := Load(LoadOptions{Dir: dir, Profile: "turbo"}); err == nil {
+		t.Error("expected unknown profile to fail")
+	}
+
+	typo := filepath.Join(t.TempDir(), "typo.yaml")
+	if err := os.WriteFile(typo, []byte("alerts:\n  rsi:\n    overbougth: 80\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(LoadOptions{Dir: dir, File: typo}); err == nil || !strings.Contains(err.Error(), "overbougth") {
+		t.Errorf("expected unknown key to fail, got %v", err)
+	}
+
+	t.Setenv("CRYPTO_ANALYZER_ALERTS_RSI_OVERBOUGHT", "25")
+	t.Setenv("CRYPTO_ANALYZER_INDICATORS_MACD_SLOW", "5")
+	t.Setenv("CRYPTO_ANALYZER_INDICATORS_ICHIMOKU_KIJUN", "60")
+	t.Setenv("CRYPTO_ANALYZER_ALERTS_ADX_STRONG", "60")
+	t.Setenv("CRYPTO_ANALYZER_ALERTS_STRATEGY_SHORT_SIGNAL", "0.6")
+	_, err = Load(LoadOptions{Dir: dir})
+	for _, problem := range []string{"alerts.rsi", "indicators.macd", "indicators.ichimoku", "alerts.adx", "alerts.strategy"} {
+		if err == nil || !strings.Contains(err.Error(), problem) {
+			t.Errorf("expected %s to be reported, got %v", problem, err)
+		}
+	}
+}
+
+// ml_config.yaml is decoded strictly and validated with the other files
+func TestLoadLearning(t *testing.T) {
+	dir := t.TempDir()
+	learning := "indicator_weights:\n  base:\n    MA: 0.5\n    RSI: 0.6\nadaptive_thresholds:\n  rsi:\n    bull_market:\n      overbought: 30\n      oversold: 40\n"
+	if err := os.WriteFile(filepath.Join(dir, "ml_config.yaml"), []byte(learning), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := Load(LoadOptions{Dir: dir})
+	if err == nil || !strings.Contains(err.Error(), "indicator_weights.base must sum to 1") || !strings.Contains(err.Error(), "adaptive_thresholds.rsi.bull_market") {
+		t.Errorf("expected learning problems reported, got %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "ml_config.yaml"), []byte("indicator_weights:\n  bse:\n    MA: 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(LoadOptions{Dir: dir}); err == nil || !strings.Contains(err.Error(), "bse") {
+		t.Errorf("expected unknown ml_config key to fail, got %v", err)
+	}
+}
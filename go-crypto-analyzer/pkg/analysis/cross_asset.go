@@ -0,0 +1,270 @@
+package analysis
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/zjc/go-crypto-analyzer/pkg/i18n"
+	"github.com/zjc/go-crypto-analyzer/pkg/stats"
+	"github.com/zjc/go-crypto-analyzer/pkg/types"
+)
+
+// CrossAssetAnalyzer analyzes correlations across the symbols of a watchlist
+type CrossAssetAnalyzer struct {
+	// window is the number of aligned returns the matrices are computed on
+	window int
+	// clusterThreshold is the average pairwise correlation above which symbols
+	// are clustered together
+	clusterThreshold float64
+	// rollingWindow is the number of returns of the rolling pair correlations
+	rollingWindow int
+	benchmark     string
+}
+
+// NewCrossAssetAnalyzer creates a CrossAssetAnalyzer measuring betas against BTC
+func NewCrossAssetAnalyzer() *CrossAssetAnalyzer {
+	return &CrossAssetAnalyzer{
+		window:           100,
+		clusterThreshold: 0.8,
+		rollingWindow:    20,
+		benchmark:        "BTCUSDT",
+	}
+}
+
+// SetWindow sets the number of aligned returns of the correlation window
+func (ca *CrossAssetAnalyzer) SetWindow(window int) {
+	ca.window = window
+}
+
+// SetClusterThreshold sets the correlation above which symbols are clustered
+func (ca *CrossAssetAnalyzer) SetClusterThreshold(threshold float64) {
+	ca.clusterThreshold = threshold
+}
+
+// SetRollingWindow sets the number of returns of the rolling pair correlations
+func (ca *CrossAssetAnalyzer) SetRollingWindow(window int) {
+	ca.rollingWindow = window
+}
+
+// SetBenchmark sets the symbol betas are measured against
+func (ca *CrossAssetAnalyzer) SetBenchmark(symbol string) {
+	ca.benchmark = symbol
+}
+
+// Benchmark returns the symbol betas are measured against
+func (ca *CrossAssetAnalyzer) Benchmark() string {
+	return ca.benchmark
+}
+
+// Analyze aligns the returns of the symbols on common timestamps and computes
+// the correlation matrices, betas to the benchmark, clusters and warnings.
+// series may hold the benchmark even when it is not one of the symbols
+func (ca *CrossAssetAnalyzer) Analyze(symbols []string, series map[string][]types.OHLCV) (*types.CrossAssetAnalysis, error) {
+	available := make([]string, 0, len(symbols))
+	for _, symbol := range symbols {
+		if len(series[symbol]) > 0 {
+			available = append(available, symbol)
+		}
+	}
+	if len(available) < 2 {
+		return nil, fmt.Errorf("need at least 2 symbols with data, got %d", len(available))
+	}
+
+	aligned := append([]string{}, available...)
+	_, hasBenchmark := series[ca.benchmark]
+	if hasBenchmark && !containsSymbol(aligned, ca.benchmark) {
+		aligned = append(aligned, ca.benchmark)
+	}
+	times, closes := alignCloses(aligned, series)
+	if len(times) < 11 {
+		return nil, fmt.Errorf("only %d common timestamps across %d symbols", len(times), len(aligned))
+	}
+
+	returns := make(map[string][]float64, len(aligned))
+	for _, symbol := range aligned {
+		returns[symbol] = stats.LogReturns(closes[symbol])
+	}
+	total := len(times) - 1
+	window := ca.window
+	if window <= 0 || window > total {
+		window = total
+	}
+	recent := func(symbol string) []float64 {
+		return returns[symbol][total-window:]
+	}
+
+	result := &types.CrossAssetAnalysis{
+		Symbols:      available,
+		Observations: window,
+		Start:        times[len(times)-1-window],
+		End:          times[len(times)-1],
+		Pearson:      make([][]float64, len(available)),
+		Spearman:     make([][]float64, len(available)),
+	}
+	pairs, pairSum := 0, 0.0
+	for i, a := range available {
+		result.Pearson[i] = make([]float64, len(available))
+		result.Spearman[i] = make([]float64, len(available))
+		for j, b := range available {
+			if i == j {
+				result.Pearson[i][j], result.Spearman[i][j] = 1, 1
+				continue
+			}
+			result.Pearson[i][j], _ = stats.Pearson(recent(a), recent(b))
+			result.Spearman[i][j], _ = stats.Spearman(recent(a), recent(b))
+			if j > i {
+				pairs++
+				pairSum += result.Pearson[i][j]
+			}
+		}
+	}
+	result.AverageCorrelation = pairSum / float64(pairs)
+
+	// Rolling correlations over the window show how each pair's correlation
+	// evolved; earlier returns fill the first rolling windows when available
+	result.RollingWindow = ca.rollingWindow
+	if result.RollingWindow <= 0 || result.RollingWindow > window {
+		result.RollingWindow = window
+	}
+	for i, a := range available {
+		for _, b := range available[i+1:] {
+			rolling := stats.RollingCorrelation(returns[a], returns[b], result.RollingWindow)
+			result.Rolling = append(result.Rolling, types.PairCorrelation{A: a, B: b, Values: rolling[total-window:]})
+		}
+	}
+	result.EffectiveBets = stats.EffectiveBets(result.Pearson)
+
+	if hasBenchmark {
+		result.Benchmark = ca.benchmark
+		for _, symbol := range available {
+			if symbol == ca.benchmark {
+				continue
+			}
+			beta := types.AssetBeta{Symbol: symbol, PreviousCorrelation: math.NaN()}
+			beta.Beta, _ = stats.Beta(recent(symbol), recent(ca.benchmark))
+			beta.Correlation, _ = stats.Pearson(recent(symbol), recent(ca.benchmark))
+			if total >= 2*window {
+				prev := total - 2*window
+				if rho, ok := stats.Pearson(returns[symbol][prev:prev+window], returns[ca.benchmark][prev:prev+window]); ok {
+					beta.PreviousCorrelation = rho
+				}
+			}
+			result.Betas = append(result.Betas, beta)
+		}
+	}
+
+	result.Clusters = clusterByCorrelation(available, result.Pearson, ca.clusterThreshold)
+	result.Warnings = ca.warnings(result)
+	return result, nil
+}
+
+// warnings flags watchlists that are effectively a single bet
+func (ca *CrossAssetAnalyzer) warnings(result *types.CrossAssetAnalysis) []string {
+	warnings := make([]string, 0)
+	n := len(result.Symbols)
+	if n >= 3 && result.EffectiveBets < 2 {
+		warnings = append(warnings, i18n.T("cross.warning_single", n, result.EffectiveBets))
+	}
+	if len(result.Clusters) > 0 && n >= 3 && float64(len(result.Clusters[0])) >= 0.7*float64(n) {
+		warnings = append(warnings, i18n.T("cross.warning_cluster",
+			strings.Join(result.Clusters[0], i18n.T("cross.cluster_separator")), ca.clusterThreshold, len(result.Clusters[0]), n))
+	}
+	if result.AverageCorrelation >= 0.7 {
+		warnings = append(warnings, i18n.T("cross.warning_systemic", result.AverageCorrelation))
+	}
+	return warnings
+}
+
+// alignCloses keeps the timestamps present in every series and returns the
+// closes of each symbol on them
+func alignCloses(symbols []string, series map[string][]types.OHLCV) ([]time.Time, map[string][]float64) {
+	counts := make(map[time.Time]int)
+	byTime := make(map[string]map[time.Time]float64, len(symbols))
+	for _, symbol := range symbols {
+		byTime[symbol] = make(map[time.Time]float64, len(series[symbol]))
+		for _, candle := range series[symbol] {
+			if _, seen := byTime[symbol][candle.Time]; !seen {
+				counts[candle.Time]++
+			}
+			byTime[symbol][candle.Time] = candle.Close
+		}
+	}
+
+	times := make([]time.Time, 0)
+	for t, count := range counts {
+		if count == len(symbols) {
+			times = append(times, t)
+		}
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+
+	closes := make(map[string][]float64, len(symbols))
+	for _, symbol := range symbols {
+		closes[symbol] = make([]float64, len(times))
+		for i, t := range times {
+			closes[symbol][i] = byTime[symbol][t]
+		}
+	}
+	return times, closes
+}
+
+// clusterByCorrelation merges clusters by average linkage while their average
+// pairwise correlation is at least threshold
+func clusterByCorrelation(symbols []string, matrix [][]float64, threshold float64) [][]string {
+	clusters := make([][]int, len(symbols))
+	for i := range symbols {
+		clusters[i] = []int{i}
+	}
+
+	linkage := func(a, b []int) float64 {
+		sum := 0.0
+		for _, i := range a {
+			for _, j := range b {
+				sum += matrix[i][j]
+			}
+		}
+		return sum / float64(len(a)*len(b))
+	}
+	for len(clusters) > 1 {
+		bestA, bestB, best := -1, -1, math.Inf(-1)
+		for a := range clusters {
+			for b := a + 1; b < len(clusters); b++ {
+				if rho := linkage(clusters[a], clusters[b]); rho > best {
+					bestA, bestB, best = a, b, rho
+				}
+			}
+		}
+		if best < threshold {
+			break
+		}
+		clusters[bestA] = append(clusters[bestA], clusters[bestB]...)
+		clusters = append(clusters[:bestB], clusters[bestB+1:]...)
+	}
+
+	result := make([][]string, 0)
+	for _, cluster := range clusters {
+		if len(cluster) < 2 {
+			continue
+		}
+		sort.Ints(cluster)
+		names := make([]string, len(cluster))
+		for i, index := range cluster {
+			names[i] = symbols[index]
+		}
+		result = append(result, names)
+	}
+	sort.SliceStable(result, func(i, j int) bool { return len(result[i]) > len(result[j]) })
+	return result
+}
+
+func containsSymbol(symbols []string, symbol string) bool {
+	for _, s := range symbols {
+		if s == symbol {
+			return true
+		}
+	}
+	return false
+}
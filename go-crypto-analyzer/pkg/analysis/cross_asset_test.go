@@ -0,0 +1,104 @@
+package analysis
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zjc/go-crypto-analyzer/pkg/types"
+)
+
+func TestCrossAssetAnalyzer(t *testing.T) {
+	// 小时K线：ETH价格为BTC的1.5次方，BNB跟随BTC并带少量噪声，XRP独立波动
+	rng := rand.New(rand.NewSource(1))
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	btc, bnb, xrp := 0.0, 0.0, 0.0
+	series := make(map[string][]types.OHLCV)
+	add := func(symbol string, i int, logPrice float64) {
+		price := 100 * math.Exp(logPrice)
+		series[symbol] = append(series[symbol], types.OHLCV{Time: start.Add(time.Duration(i) * time.Hour), Open: price, High: price, Low: price, Close: price})
+	}
+	for i := 0; i < 60; i++ {
+		step := rng.NormFloat64() * 0.01
+		btc += step
+		bnb += step + rng.NormFloat64()*0.002
+		xrp += rng.NormFloat64() * 0.01
+
+		add("BTCUSDT", i, btc)
+		// ETH缺少部分K线，BNB多出开头的K线，XRP有一根重复K线
+		if i%7 != 3 {
+			add("ETHUSDT", i, 1.5*btc)
+		}
+		add("BNBUSDT", i, bnb)
+		add("XRPUSDT", i, xrp)
+		if i == 20 {
+			add("XRPUSDT", i, xrp)
+		}
+	}
+	series["BNBUSDT"] = append([]types.OHLCV{{Time: start.Add(-time.Hour), Close: 90}}, series["BNBUSDT"]...)
+
+	// 基准不在观察列表中时仍计算贝塔
+	ca := NewCrossAssetAnalyzer()
+	result, err := ca.Analyze([]string{"ETHUSDT", "BNBUSDT", "XRPUSDT", "SOLUSDT"}, series)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// ETH缺少9根，SOL没有数据被忽略
+	if len(result.Symbols) != 3 || result.Observations != 60-9-1 || !result.Start.Equal(start) {
+		t.Fatalf("unexpected alignment: %v %d observations from %v", result.Symbols, result.Observations, result.Start)
+	}
+	// 按时间戳对齐，跨越缺口的收益率仍保持1.5倍关系
+	if len(result.Betas) != 3 || result.Benchmark != "BTCUSDT" {
+		t.Fatalf("unexpected betas: %+v", result.Betas)
+	}
+	if eth := result.Betas[0]; math.Abs(eth.Beta-1.5) > 1e-9 || math.Abs(eth.Correlation-1) > 1e-9 {
+		t.Errorf("ETH beta: %+v", eth)
+	}
+	if rho := result.Pearson[0][1]; rho < 0.9 || math.Abs(result.Pearson[0][2]) > 0.5 {
+		t.Errorf("unexpected correlations: %v", result.Pearson)
+	}
+
+	// 每对币种一条滚动相关序列，覆盖整个窗口，窗口填满前为NaN
+	if len(result.Rolling) != 3 || result.RollingWindow != 20 {
+		t.Fatalf("unexpected rolling correlations: %d pairs window %d", len(result.Rolling), result.RollingWindow)
+	}
+	ethBNB := result.Rolling[0]
+	if ethBNB.A != "ETHUSDT" || ethBNB.B != "BNBUSDT" || len(ethBNB.Values) != result.Observations {
+		t.Fatalf("unexpected ETH/BNB rolling correlation: %+v", ethBNB)
+	}
+	if !math.IsNaN(ethBNB.Values[18]) || ethBNB.Values[19] < 0.9 || ethBNB.Values[len(ethBNB.Values)-1] < 0.9 {
+		t.Errorf("ETH/BNB rolling correlation: %v", ethBNB.Values)
+	}
+
+	// ETH和BNB聚为一类，XRP单独不成类
+	if len(result.Clusters) != 1 || strings.Join(result.Clusters[0], ",") != "ETHUSDT,BNBUSDT" {
+		t.Errorf("unexpected clusters: %v", result.Clusters)
+	}
+	// 有效独立押注不足2个，但两个币种的聚类不足观察列表的70%
+	if len(result.Warnings) != 1 || result.EffectiveBets >= 2 || strings.Contains(result.Warnings[0], "ETHUSDT") {
+		t.Errorf("expected only the single bet warning: %.2f %v", result.EffectiveBets, result.Warnings)
+	}
+
+	// 提高阈值后不再聚类；包含BTC时三者聚为一类，触发集中警告
+	ca.SetClusterThreshold(0.999)
+	if result, _ = ca.Analyze([]string{"ETHUSDT", "BNBUSDT", "XRPUSDT"}, series); len(result.Clusters) != 0 {
+		t.Errorf("expected no clusters above 0.999: %v", result.Clusters)
+	}
+	ca.SetClusterThreshold(0.8)
+	result, err = ca.Analyze([]string{"BTCUSDT", "ETHUSDT", "BNBUSDT", "XRPUSDT"}, series)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Clusters) != 1 || len(result.Clusters[0]) != 3 || len(result.Betas) != 3 ||
+		len(result.Warnings) != 2 || !strings.Contains(result.Warnings[1], "BNBUSDT") {
+		t.Errorf("expected one cluster of 3 and a cluster warning: %v %v", result.Clusters, result.Warnings)
+	}
+
+	// 共同时间戳不足
+	series["XRPUSDT"] = series["XRPUSDT"][:5]
+	if _, err := ca.Analyze([]string{"ETHUSDT", "XRPUSDT"}, series); err == nil {
+		t.Error("expected too few common timestamps to fail")
+	}
+}
@@ -2,51 +2,113 @@ package analysis
 
 import (
 	"math"
+	"strings"
+
+	"github.com/zjc/go-crypto-analyzer/pkg/stats"
 	"github.com/zjc/go-crypto-analyzer/pkg/types"
 )
 
 // DynamicAnalyzer 提供动态权重的分析系统
 type DynamicAnalyzer struct {
-	// 权重配置
+	// 基础权重和自适应RSI阈值（通常来自 configs/ml_config.yaml）
+	learning Learning
+	// 按市场状态调整后的权重
 	weights map[string]float64
 	// 市场状态
 	marketCondition string
 }
 
+// conditionShifts 各市场状态在基础权重上的调整，调整后重新归一化
+var conditionShifts = map[string]map[string]float64{
+	// 高波动：降低MA权重，提高超买超卖和成交量权重
+	"high_volatility": {"MA": -0.10, "RSI": 0.05, "Volume": 0.05},
+	// 趋势：提高MA和动量权重，降低RSI权重
+	"trending": {"MA": 0.10, "MACD": 0.05, "RSI": -0.10, "Volume": -0.05},
+	// 震荡：提高RSI权重
+	"ranging": {"MA": -0.05, "MACD": -0.05, "RSI": 0.10},
+}
+
 // NewDynamicAnalyzer 创建动态分析器
 func NewDynamicAnalyzer() *DynamicAnalyzer {
-	return &DynamicAnalyzer{
-		weights: make(map[string]float64),
-		marketCondition: "normal",
+	da := &DynamicAnalyzer{marketCondition: "normal"}
+	da.SetLearning(DefaultLearning())
+	return da
+}
+
+// SetLearning 设置基础权重和自适应RSI阈值，权重回到基础权重
+func (da *DynamicAnalyzer) SetLearning(learning Learning) {
+	da.learning = learning
+	da.weights = da.shiftedWeights("")
+}
+
+// Weights 返回当前市场状态下各类指标的权重
+func (da *DynamicAnalyzer) Weights() map[string]float64 {
+	weights := make(map[string]float64, len(da.weights))
+	for name, weight := range da.weights {
+		weights[name] = weight
 	}
+	return weights
+}
+
+// shiftedWeights 在基础权重上应用市场状态的调整，负值截为0后归一化
+func (da *DynamicAnalyzer) shiftedWeights(condition string) map[string]float64 {
+	weights := make(map[string]float64, len(da.learning.BaseWeights))
+	sum := 0.0
+	for name, base := range da.learning.BaseWeights {
+		weights[name] = math.Max(0, base+conditionShifts[condition][name])
+		sum += weights[name]
+	}
+	if sum > 0 {
+		for name := range weights {
+			weights[name] /= sum
+		}
+	}
+	return weights
 }
 
 // 根据市场状态动态调整权重
-func (da *DynamicAnalyzer) AdjustWeights(volatility float64, volume types.VolumeAnalysis, adx float64) {
+// 波动率取分析结果中的已实现波动率：短期Yang–Zhang波动率高于长期基准50%视为高波动
+func (da *DynamicAnalyzer) AdjustWeights(volatility types.VolatilityAnalysis, volume types.VolumeAnalysis, adx float64) {
+	switch {
 	// 高波动市场
-	if volatility > 0.05 { // 5%波动率
+	case volatility.Available && volatility.Ratio > 1.5:
 		da.marketCondition = "high_volatility"
-		da.weights["MA"] = 0.15      // 降低MA权重
-		da.weights["MACD"] = 0.25    // 提高动量权重
-		da.weights["RSI"] = 0.30     // 提高超买超卖权重
-		da.weights["Volume"] = 0.30  // 提高成交量权重
-		
-	// 趋势市场
-	} else if adx > 35 {
+	// 趋势市场：ADX强趋势，或Hurst/方差比显示趋势延续且ADX确认
+	case adx > 35 || (volatility.Regime == stats.RegimeTrending && adx > 25):
 		da.marketCondition = "trending"
-		da.weights["MA"] = 0.35      // 提高MA权重
-		da.weights["MACD"] = 0.30    // 动量重要
-		da.weights["RSI"] = 0.15     // 降低RSI权重
-		da.weights["Volume"] = 0.20
-		
 	// 震荡市场
-	} else {
+	default:
 		da.marketCondition = "ranging"
-		da.weights["MA"] = 0.20
-		da.weights["MACD"] = 0.20
-		da.weights["RSI"] = 0.35     // 提高RSI权重
-		da.weights["Volume"] = 0.25
 	}
+	da.weights = da.shiftedWeights(da.marketCondition)
+}
+
+// RSIBand 返回趋势对应的自适应RSI阈值：上涨用牛市阈值，下跌用熊市阈值，其余用常规阈值
+func (da *DynamicAnalyzer) RSIBand(trend types.TrendDirection) RSIBand {
+	market := "normal"
+	switch trend {
+	case types.StrongUptrend, types.Uptrend:
+		market = "bull_market"
+	case types.StrongDowntrend, types.Downtrend:
+		market = "bear_market"
+	}
+	if band, ok := da.learning.RSIBands[market]; ok {
+		return band
+	}
+	if band, ok := da.learning.RSIBands["normal"]; ok {
+		return band
+	}
+	return DefaultLearning().RSIBands["normal"]
+}
+
+// categoryWeight 证据类别相对于平均权重的倍数，没有权重的类别为1
+func (da *DynamicAnalyzer) categoryWeight(category string) float64 {
+	for name, weight := range da.weights {
+		if strings.EqualFold(name, category) {
+			return weight * float64(len(da.weights))
+		}
+	}
+	return 1
 }
 
 // 计算指标可信度
@@ -74,6 +136,17 @@ func (da *DynamicAnalyzer) CalculateConfidence(indicators map[string]bool) float
 	return confidence
 }
 
+// 按证据ID识别的均线证据（见 evidence_rules.yaml）
+var (
+	// 价格与MA5
+	shortTermMAEvidence = map[string]bool{"ma.price_above_ma5": true, "ma.price_below_ma5": true}
+	// MA5与MA20、均线排列
+	longTermMAEvidence = map[string]bool{
+		"ma.ma5_above_ma20": true, "ma.ma5_below_ma20": true,
+		"ma.bullish_alignment": true, "ma.bearish_alignment": true,
+	}
+)
+
 // 智能证据评估
 func (da *DynamicAnalyzer) EvaluateEvidence(evidence types.Evidence, context map[string]interface{}) float64 {
 	baseStrength := evidence.Strength
@@ -82,17 +155,17 @@ func (da *DynamicAnalyzer) EvaluateEvidence(evidence types.Evidence, context map
 	switch da.marketCondition {
 	case "high_volatility":
 		// 高波动时，短期指标更重要
-		if evidence.Category == "移动平均线" && evidence.Description[:8] == "MA5" {
+		if shortTermMAEvidence[evidence.ID] {
 			baseStrength *= 1.5
 		}
 	case "trending":
 		// 趋势市场，中长期指标更重要
-		if evidence.Category == "移动平均线" && (evidence.Description[:9] == "MA50" || evidence.Description[:9] == "MA20") {
+		if longTermMAEvidence[evidence.ID] {
 			baseStrength *= 1.3
 		}
 	case "ranging":
 		// 震荡市场，超买超卖指标更重要
-		if evidence.Category == "RSI" {
+		if evidence.Category == "rsi" {
 			baseStrength *= 1.4
 		}
 	}
@@ -111,8 +184,8 @@ func (da *DynamicAnalyzer) EvaluateEvidence(evidence types.Evidence, context map
 	return baseStrength
 }
 
-// 多指标融合决策
-func (da *DynamicAnalyzer) FusionDecision(evidences []types.Evidence) (string, float64) {
+// 多指标融合决策，证据强度按其类别的权重加权
+func (da *DynamicAnalyzer) FusionDecision(evidences []types.Evidence) (types.Judgment, float64) {
 	// 贝叶斯推理
 	bullishProbability := 0.5 // 先验概率
 	
@@ -120,11 +193,12 @@ func (da *DynamicAnalyzer) FusionDecision(evidences []types.Evidence) (string, f
 		// 计算似然比
 		likelihoodRatio := 1.0
 		
+		strength := evidence.Strength * da.categoryWeight(evidence.Category)
 		switch evidence.Type {
 		case types.BullishEvidence:
-			likelihoodRatio = 1 + evidence.Strength
+			likelihoodRatio = 1 + strength
 		case types.BearishEvidence:
-			likelihoodRatio = 1 / (1 + math.Abs(evidence.Strength))
+			likelihoodRatio = 1 / (1 + math.Abs(strength))
 		}
 		
 		// 更新后验概率
@@ -134,53 +208,47 @@ func (da *DynamicAnalyzer) FusionDecision(evidences []types.Evidence) (string, f
 	
 	// 决策
 	if bullishProbability > 0.7 {
-		return "强烈看涨", bullishProbability
+		return types.StrongBullishJudgment, bullishProbability
 	} else if bullishProbability > 0.55 {
-		return "偏多", bullishProbability
+		return types.BullishJudgment, bullishProbability
 	} else if bullishProbability < 0.3 {
-		return "强烈看跌", bullishProbability
+		return types.StrongBearishJudgment, bullishProbability
 	} else if bullishProbability < 0.45 {
-		return "偏空", bullishProbability
+		return types.BearishJudgment, bullishProbability
 	}
 	
-	return "中性", bullishProbability
+	return types.NeutralJudgment, bullishProbability
 }
 
 // 指标冲突检测
-func (da *DynamicAnalyzer) DetectConflicts(evidences []types.Evidence) []string {
-	conflicts := []string{}
+func (da *DynamicAnalyzer) DetectConflicts(evidences []types.Evidence) []types.SignalConflict {
+	conflicts := []types.SignalConflict{}
 	
 	// 检查MA和MACD是否冲突
 	maSignal := ""
 	macdSignal := ""
 	
 	for _, ev := range evidences {
-		if ev.Category == "移动平均线" && ev.Description[:3] == "MA5" {
-			if ev.Type == types.BullishEvidence {
-				maSignal = "bullish"
-			} else {
-				maSignal = "bearish"
-			}
-		}
-		if ev.Category == "MACD" {
-			if ev.Type == types.BullishEvidence {
-				macdSignal = "bullish"
-			} else {
-				macdSignal = "bearish"
-			}
+		switch ev.ID {
+		case "ma.ma5_above_ma20":
+			maSignal = "bullish"
+		case "ma.ma5_below_ma20":
+			maSignal = "bearish"
+		case "macd.above_signal":
+			macdSignal = "bullish"
+		case "macd.below_signal":
+			macdSignal = "bearish"
 		}
 	}
 	
 	if maSignal != "" && macdSignal != "" && maSignal != macdSignal {
-		conflicts = append(conflicts, "MA和MACD信号冲突，谨慎操作")
+		conflicts = append(conflicts, types.MAMACDConflict)
 	}
 	
 	// 检查价格和成交量是否背离
 	for _, ev := range evidences {
-		if ev.Category == "成交量" && len(ev.Description) >= 4 && ev.Description[:4] == "放量" {
-			if ev.Type == types.BearishEvidence {
-				conflicts = append(conflicts, "放量下跌，卖压沉重")
-			}
+		if ev.ID == "volume.surge_down" {
+			conflicts = append(conflicts, types.VolumeSurgeDownConflict)
 		}
 	}
 	
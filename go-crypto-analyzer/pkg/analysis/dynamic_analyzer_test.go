@@ -0,0 +1,81 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+
+	"github.com/zjc/go-crypto-analyzer/pkg/types"
+)
+
+func TestDynamicAnalyzer(t *testing.T) {
+	// 按证据ID识别，描述很短也不会越界
+	da := NewDynamicAnalyzer()
+	da.AdjustWeights(types.VolatilityAnalysis{Available: true, Ratio: 2}, types.VolumeAnalysis{}, 20)
+	short := types.Evidence{ID: "ma.price_above_ma5", Category: "ma", Type: types.BullishEvidence, Description: "MA", Strength: 0.5}
+	if got := da.EvaluateEvidence(short, nil); got != 0.5*1.5 {
+		t.Errorf("high volatility should boost price/MA5 evidence, got %v", got)
+	}
+	if got := da.EvaluateEvidence(types.Evidence{ID: "ma.ma5_above_ma20", Category: "ma", Strength: 0.2}, nil); got != 0.2 {
+		t.Errorf("high volatility should not boost MA5/MA20 evidence, got %v", got)
+	}
+
+	da.AdjustWeights(types.VolatilityAnalysis{}, types.VolumeAnalysis{}, 40)
+	if got := da.EvaluateEvidence(types.Evidence{ID: "ma.bullish_alignment", Category: "ma", Strength: 0.5}, nil); got != 0.5*1.3 {
+		t.Errorf("trending market should boost MA alignment evidence, got %v", got)
+	}
+
+	conflicts := da.DetectConflicts([]types.Evidence{
+		{ID: "ma.ma5_above_ma20", Type: types.BullishEvidence},
+		{ID: "macd.below_signal", Type: types.BearishEvidence},
+		{ID: "volume.surge_down", Type: types.BearishEvidence},
+		{ID: "custom.x", Category: "ma", Type: types.BullishEvidence},
+	})
+	if len(conflicts) != 2 || conflicts[0] != types.MAMACDConflict || conflicts[1] != types.VolumeSurgeDownConflict {
+		t.Errorf("expected MA/MACD and volume conflicts, got %q", conflicts)
+	}
+	if conflicts := da.DetectConflicts([]types.Evidence{
+		{ID: "ma.ma5_below_ma20", Type: types.BearishEvidence},
+		{ID: "macd.below_signal", Type: types.BearishEvidence},
+	}); len(conflicts) != 0 {
+		t.Errorf("agreeing signals should not conflict, got %v", conflicts)
+	}
+
+	// 融合决策返回判断代码
+	strong := []types.Evidence{{Type: types.BullishEvidence, Strength: 1}, {Type: types.BullishEvidence, Strength: 1}}
+	if decision, p := da.FusionDecision(strong); decision != types.StrongBullishJudgment || p <= 0.7 {
+		t.Errorf("FusionDecision = %q %.2f, want STRONG_BULLISH", decision, p)
+	}
+	if decision, _ := da.FusionDecision([]types.Evidence{{Type: types.BearishEvidence, Strength: 0.5}}); decision != types.BearishJudgment {
+		t.Errorf("FusionDecision = %q, want BEARISH", decision)
+	}
+	if decision, _ := da.FusionDecision(nil); decision != types.NeutralJudgment {
+		t.Errorf("FusionDecision without evidence = %q, want NEUTRAL", decision)
+	}
+
+	// 权重来自基础权重，按市场状态调整后归一化
+	da = NewDynamicAnalyzer()
+	da.SetLearning(Learning{BaseWeights: map[string]float64{"MA": 0.4, "MACD": 0.2, "RSI": 0.2, "Volume": 0.2}})
+	if w := da.Weights(); w["MA"] != 0.4 {
+		t.Errorf("weights should start from the base weights, got %v", w)
+	}
+	da.AdjustWeights(types.VolatilityAnalysis{}, types.VolumeAnalysis{}, 40)
+	if w := da.Weights(); math.Abs(w["MA"]-0.5) > 1e-9 || math.Abs(w["RSI"]-0.1) > 1e-9 {
+		t.Errorf("trending weights = %v", w)
+	}
+	// 权重高的类别在融合决策中占比更大
+	ma, _ := da.FusionDecision([]types.Evidence{{Category: "ma", Type: types.BullishEvidence, Strength: 0.5}})
+	rsi, _ := da.FusionDecision([]types.Evidence{{Category: "rsi", Type: types.BullishEvidence, Strength: 0.5}})
+	if ma != types.BullishJudgment || rsi != types.NeutralJudgment {
+		t.Errorf("weighted fusion: ma %q rsi %q", ma, rsi)
+	}
+
+	// 自适应RSI阈值按趋势选择，缺少的市场回退到常规阈值
+	da.SetLearning(DefaultLearning())
+	if band := da.RSIBand(types.StrongUptrend); band.Overbought != 80 || band.Oversold != 35 {
+		t.Errorf("bull market band = %+v", band)
+	}
+	da.SetLearning(Learning{RSIBands: map[string]RSIBand{"normal": {Oversold: 25, Overbought: 75}}})
+	if band := da.RSIBand(types.Downtrend); band.Overbought != 75 {
+		t.Errorf("missing bear market band should fall back to normal, got %+v", band)
+	}
+}
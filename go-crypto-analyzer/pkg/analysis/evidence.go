@@ -2,6 +2,8 @@ package analysis
 
 import (
 	"fmt"
+	"math"
+	"reflect"
 
 	"github.com/zjc/go-crypto-analyzer/pkg/types"
 )
@@ -9,15 +11,25 @@ import (
 // EvidenceCollector collects and analyzes trading evidence
 type EvidenceCollector struct {
 	evidences []types.Evidence
+	// thresholds of the RSI zones and volume ratios
+	thresholds Thresholds
+	rules      *EvidenceRuleSet
 }
 
 // NewEvidenceCollector creates a new EvidenceCollector
 func NewEvidenceCollector() *EvidenceCollector {
 	return &EvidenceCollector{
-		evidences: make([]types.Evidence, 0),
+		evidences:  make([]types.Evidence, 0),
+		thresholds: DefaultThresholds(),
+		rules:      DefaultEvidenceRules(),
 	}
 }
 
+// SetRules sets the evidence rules, see LoadEvidenceRules
+func (ec *EvidenceCollector) SetRules(rs *EvidenceRuleSet) {
+	ec.rules = rs
+}
+
 // Clear clears all collected evidence
 func (ec *EvidenceCollector) Clear() {
 	ec.evidences = make([]types.Evidence, 0)
@@ -28,254 +40,98 @@ func (ec *EvidenceCollector) AddEvidence(evidence types.Evidence) {
 	ec.evidences = append(ec.evidences, evidence)
 }
 
-// AnalyzeMAEvidence analyzes moving average evidence
-func (ec *EvidenceCollector) AnalyzeMAEvidence(ma types.MAAnalysis, currentPrice float64) {
-	// Price vs MA5
-	if currentPrice > ma.MA5 {
-		ec.AddEvidence(types.Evidence{
-			Type:        types.BullishEvidence,
-			Category:    "移动平均线",
-			Description: fmt.Sprintf("价格(%.2f)高于MA5(%.2f)，短期趋势向上", currentPrice, ma.MA5),
-			Strength:    0.3,
-			Data: map[string]interface{}{
-				"price": currentPrice,
-				"ma5":   ma.MA5,
-			},
-		})
-	} else {
-		ec.AddEvidence(types.Evidence{
-			Type:        types.BearishEvidence,
-			Category:    "移动平均线",
-			Description: fmt.Sprintf("价格(%.2f)低于MA5(%.2f)，短期趋势向下", currentPrice, ma.MA5),
-			Strength:    -0.3,
-			Data: map[string]interface{}{
-				"price": currentPrice,
-				"ma5":   ma.MA5,
-			},
-		})
-	}
-
-	// MA5 vs MA20
-	if ma.MA5 > ma.MA20 {
-		ec.AddEvidence(types.Evidence{
-			Type:        types.BullishEvidence,
-			Category:    "移动平均线",
-			Description: fmt.Sprintf("MA5(%.2f)高于MA20(%.2f)，中期趋势向上", ma.MA5, ma.MA20),
-			Strength:    0.4,
-			Data: map[string]interface{}{
-				"ma5":  ma.MA5,
-				"ma20": ma.MA20,
-			},
-		})
-	} else {
-		ec.AddEvidence(types.Evidence{
-			Type:        types.BearishEvidence,
-			Category:    "移动平均线",
-			Description: fmt.Sprintf("MA5(%.2f)低于MA20(%.2f)，中期趋势向下", ma.MA5, ma.MA20),
-			Strength:    -0.4,
-			Data: map[string]interface{}{
-				"ma5":  ma.MA5,
-				"ma20": ma.MA20,
-			},
-		})
+// Collect evaluates the evidence rules on an analysis result. priceChange is
+// the change of the latest candle, which the volume rules need
+func (ec *EvidenceCollector) Collect(result *types.Analysis, priceChange float64) error {
+	env, err := ec.rules.environment(result, ec.facts(result, priceChange))
+	if err != nil {
+		return err
 	}
+	for _, block := range ec.rules.blocks {
+		if block.forEach == nil {
+			if err := block.apply(ec, env); err != nil {
+				return err
+			}
+			continue
+		}
 
-	// Perfect alignment check
-	if currentPrice > ma.MA5 && ma.MA5 > ma.MA10 && ma.MA10 > ma.MA20 && ma.MA20 > ma.MA50 {
-		ec.AddEvidence(types.Evidence{
-			Type:        types.BullishEvidence,
-			Category:    "移动平均线",
-			Description: "完美多头排列：价格>MA5>MA10>MA20>MA50",
-			Strength:    0.8,
-			Data:        map[string]interface{}{},
-		})
-	} else if currentPrice < ma.MA5 && ma.MA5 < ma.MA10 && ma.MA10 < ma.MA20 && ma.MA20 < ma.MA50 {
-		ec.AddEvidence(types.Evidence{
-			Type:        types.BearishEvidence,
-			Category:    "移动平均线",
-			Description: "完美空头排列：价格<MA5<MA10<MA20<MA50",
-			Strength:    -0.8,
-			Data:        map[string]interface{}{},
-		})
+		list, err := block.forEach.Eval(env)
+		if err != nil {
+			return fmt.Errorf("rule %s: %w", block.rules[0].rule.ID, err)
+		}
+		items := reflect.ValueOf(list)
+		if list != nil && items.Kind() != reflect.Slice && items.Kind() != reflect.Array {
+			return fmt.Errorf("rule %s: for_each is not a list", block.rules[0].rule.ID)
+		}
+		for i := 0; list != nil && i < items.Len(); i++ {
+			env["item"] = items.Index(i).Interface()
+			if err := block.apply(ec, env); err != nil {
+				return err
+			}
+		}
+		delete(env, "item")
 	}
+	return nil
 }
 
-// AnalyzeMACDEvidence analyzes MACD evidence
-func (ec *EvidenceCollector) AnalyzeMACDEvidence(macd types.MACDAnalysis) {
-	// MACD vs Signal
-	if macd.MACD > macd.Signal {
-		ec.AddEvidence(types.Evidence{
-			Type:        types.BullishEvidence,
-			Category:    "MACD",
-			Description: fmt.Sprintf("MACD(%.2f)高于Signal(%.2f)，动量向上", macd.MACD, macd.Signal),
-			Strength:    0.5,
-			Data: map[string]interface{}{
-				"macd":   macd.MACD,
-				"signal": macd.Signal,
-			},
-		})
-	} else {
-		ec.AddEvidence(types.Evidence{
-			Type:        types.BearishEvidence,
-			Category:    "MACD",
-			Description: fmt.Sprintf("MACD(%.2f)低于Signal(%.2f)，动量向下", macd.MACD, macd.Signal),
-			Strength:    -0.5,
-			Data: map[string]interface{}{
-				"macd":   macd.MACD,
-				"signal": macd.Signal,
-			},
-		})
+// facts derives the values rules use besides the analysis fields
+func (ec *EvidenceCollector) facts(result *types.Analysis, priceChange float64) EvidenceFacts {
+	sr := result.SupportResistance
+	facts := EvidenceFacts{
+		PriceChange:    priceChange,
+		Thresholds:     ec.thresholds,
+		Resistance:     sr.NearestResistance,
+		Support:        sr.NearestSupport,
+		FibConfluences: fibonacciConfluences(result.Fibonacci, sr),
 	}
-
-	// Histogram
-	if macd.Histogram > 0 && macd.Histogram > macd.MACD*0.1 {
-		ec.AddEvidence(types.Evidence{
-			Type:        types.BullishEvidence,
-			Category:    "MACD",
-			Description: fmt.Sprintf("MACD柱状图为正(%.2f)且较大，买入动量强", macd.Histogram),
-			Strength:    0.4,
-			Data:        map[string]interface{}{"histogram": macd.Histogram},
-		})
-	} else if macd.Histogram < 0 && -macd.Histogram > -macd.MACD*0.1 {
-		ec.AddEvidence(types.Evidence{
-			Type:        types.BearishEvidence,
-			Category:    "MACD",
-			Description: fmt.Sprintf("MACD柱状图为负(%.2f)且较大，卖出动量强", macd.Histogram),
-			Strength:    -0.4,
-			Data:        map[string]interface{}{"histogram": macd.Histogram},
-		})
+	// Nearest clustered levels, falling back to the classic pivots
+	if facts.Resistance.Price == 0 {
+		facts.Resistance = types.SRLevel{Price: sr.Resistance["R1"], Source: "R1", Score: 0.5}
 	}
-}
-
-// AnalyzeRSIEvidence analyzes RSI evidence
-func (ec *EvidenceCollector) AnalyzeRSIEvidence(rsi float64) {
-	if rsi > 70 {
-		ec.AddEvidence(types.Evidence{
-			Type:        types.WarningEvidence,
-			Category:    "RSI",
-			Description: fmt.Sprintf("RSI(%.2f)>70，处于超买区域，可能回调", rsi),
-			Strength:    -0.3,
-			Data:        map[string]interface{}{"rsi": rsi},
-		})
-	} else if rsi > 60 {
-		ec.AddEvidence(types.Evidence{
-			Type:        types.BullishEvidence,
-			Category:    "RSI",
-			Description: fmt.Sprintf("RSI(%.2f)处于强势区域(60-70)，上涨动能充足", rsi),
-			Strength:    0.3,
-			Data:        map[string]interface{}{"rsi": rsi},
-		})
-	} else if rsi < 30 {
-		ec.AddEvidence(types.Evidence{
-			Type:        types.WarningEvidence,
-			Category:    "RSI",
-			Description: fmt.Sprintf("RSI(%.2f)<30，处于超卖区域，可能反弹", rsi),
-			Strength:    0.3,
-			Data:        map[string]interface{}{"rsi": rsi},
-		})
-	} else if rsi < 40 {
-		ec.AddEvidence(types.Evidence{
-			Type:        types.BearishEvidence,
-			Category:    "RSI",
-			Description: fmt.Sprintf("RSI(%.2f)处于弱势区域(30-40)，下跌动能较强", rsi),
-			Strength:    -0.3,
-			Data:        map[string]interface{}{"rsi": rsi},
-		})
-	} else {
-		ec.AddEvidence(types.Evidence{
-			Type:        types.NeutralEvidence,
-			Category:    "RSI",
-			Description: fmt.Sprintf("RSI(%.2f)处于中性区域(40-60)", rsi),
-			Strength:    0,
-			Data:        map[string]interface{}{"rsi": rsi},
-		})
+	if facts.Support.Price == 0 {
+		facts.Support = types.SRLevel{Price: sr.Support["S1"], Source: "S1", Score: 0.5}
 	}
+	return facts
 }
 
-// AnalyzeVolumeEvidence analyzes volume evidence
-func (ec *EvidenceCollector) AnalyzeVolumeEvidence(volume types.VolumeAnalysis, priceChange float64) {
-	if volume.VolumeRatio > 2 && priceChange > 0 {
-		ec.AddEvidence(types.Evidence{
-			Type:        types.BullishEvidence,
-			Category:    "成交量",
-			Description: fmt.Sprintf("放量上涨：成交量是均量的%.1f倍，买入意愿强烈", volume.VolumeRatio),
-			Strength:    0.6,
-			Data:        map[string]interface{}{"volumeRatio": volume.VolumeRatio},
-		})
-	} else if volume.VolumeRatio > 2 && priceChange < 0 {
-		ec.AddEvidence(types.Evidence{
-			Type:        types.BearishEvidence,
-			Category:    "成交量",
-			Description: fmt.Sprintf("放量下跌：成交量是均量的%.1f倍，卖出压力大", volume.VolumeRatio),
-			Strength:    -0.6,
-			Data:        map[string]interface{}{"volumeRatio": volume.VolumeRatio},
-		})
-	} else if volume.VolumeRatio < 0.5 && priceChange > 0 {
-		ec.AddEvidence(types.Evidence{
-			Type:        types.WarningEvidence,
-			Category:    "成交量",
-			Description: fmt.Sprintf("缩量上涨：成交量仅为均量的%.1f倍，上涨缺乏支撑", volume.VolumeRatio),
-			Strength:    -0.2,
-			Data:        map[string]interface{}{"volumeRatio": volume.VolumeRatio},
-		})
-	} else if volume.VolumeRatio < 0.5 && priceChange < 0 {
-		ec.AddEvidence(types.Evidence{
-			Type:        types.NeutralEvidence,
-			Category:    "成交量",
-			Description: fmt.Sprintf("缩量下跌：成交量仅为均量的%.1f倍，抛压减轻", volume.VolumeRatio),
-			Strength:    0.2,
-			Data:        map[string]interface{}{"volumeRatio": volume.VolumeRatio},
-		})
+// fibonacciConfluences lists the other levels within 0.5% of the Fibonacci
+// level price is testing: the first clustered level, pivot and volume level,
+// as source codes such as SWING, PIVOT_R1 or VOLUME_NODE
+func fibonacciConfluences(fib types.FibonacciAnalysis, sr types.SRAnalysis) []string {
+	confluences := make([]string, 0)
+	if !fib.Available || fib.Testing.Ratio == 0 {
+		return confluences
 	}
-}
-
-// AnalyzeSREvidence analyzes support and resistance evidence
-func (ec *EvidenceCollector) AnalyzeSREvidence(currentPrice float64, sr types.SRAnalysis) {
-	r1 := sr.Resistance["R1"]
-	s1 := sr.Support["S1"]
-	pivot := sr.Pivot
-
-	// Distance to levels
-	distanceToR1 := (r1 - currentPrice) / currentPrice * 100
-	distanceToS1 := (currentPrice - s1) / currentPrice * 100
-
-	if distanceToR1 < 1 {
-		ec.AddEvidence(types.Evidence{
-			Type:        types.WarningEvidence,
-			Category:    "支撑阻力",
-			Description: fmt.Sprintf("接近阻力位R1(%.2f)，上涨空间有限(%.1f%%)", r1, distanceToR1),
-			Strength:    -0.3,
-			Data:        map[string]interface{}{"r1": r1, "distance": distanceToR1},
-		})
+	level := fib.Testing.Price
+	near := func(price float64) bool {
+		return price > 0 && math.Abs(price-level)/level*100 <= 0.5
 	}
-
-	if distanceToS1 < 1 {
-		ec.AddEvidence(types.Evidence{
-			Type:        types.WarningEvidence,
-			Category:    "支撑阻力",
-			Description: fmt.Sprintf("接近支撑位S1(%.2f)，下跌空间有限(%.1f%%)", s1, distanceToS1),
-			Strength:    0.3,
-			Data:        map[string]interface{}{"s1": s1, "distance": distanceToS1},
-		})
+	for _, srLevel := range sr.Levels {
+		if near(srLevel.Price) {
+			confluences = append(confluences, string(srLevel.Source))
+			break
+		}
 	}
-
-	if currentPrice > pivot {
-		ec.AddEvidence(types.Evidence{
-			Type:        types.BullishEvidence,
-			Category:    "支撑阻力",
-			Description: fmt.Sprintf("价格(%.2f)高于轴心点(%.2f)，多头占优", currentPrice, pivot),
-			Strength:    0.2,
-			Data:        map[string]interface{}{"price": currentPrice, "pivot": pivot},
-		})
-	} else {
-		ec.AddEvidence(types.Evidence{
-			Type:        types.BearishEvidence,
-			Category:    "支撑阻力",
-			Description: fmt.Sprintf("价格(%.2f)低于轴心点(%.2f)，空头占优", currentPrice, pivot),
-			Strength:    -0.2,
-			Data:        map[string]interface{}{"price": currentPrice, "pivot": pivot},
-		})
+	pivots := map[string]float64{"P": sr.Pivot}
+	for name, price := range sr.Resistance {
+		pivots[name] = price
+	}
+	for name, price := range sr.Support {
+		pivots[name] = price
+	}
+	for _, name := range []string{"R4", "R3", "R2", "R1", "P", "S1", "S2", "S3", "S4"} {
+		if near(pivots[name]) {
+			confluences = append(confluences, "PIVOT_"+name)
+			break
+		}
+	}
+	for _, volumeLevel := range append([]float64{sr.POC, sr.VAH, sr.VAL}, sr.HVN...) {
+		if near(volumeLevel) {
+			confluences = append(confluences, "VOLUME_NODE")
+			break
+		}
 	}
+	return confluences
 }
 
 // GetSummary returns a summary of all collected evidence
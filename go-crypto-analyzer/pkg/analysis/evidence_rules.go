@@ -0,0 +1,514 @@
+package analysis
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"math"
+	"os"
+	"reflect"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/zjc/go-crypto-analyzer/pkg/i18n"
+	"github.com/zjc/go-crypto-analyzer/pkg/rules"
+	"github.com/zjc/go-crypto-analyzer/pkg/types"
+)
+
+//go:embed evidence_rules.yaml
+var defaultEvidenceRulesYAML []byte
+
+// EvidenceRule is a declarative evidence rule. When its condition holds it
+// adds one piece of evidence whose strength, description and data are
+// evaluated from the analysis result
+type EvidenceRule struct {
+	// ID identifies the rule and becomes the ID of its evidence; a user rule
+	// with the same ID replaces it
+	ID string `yaml:"id"`
+	// Category is a category code such as "ma", shown through the message
+	// catalog
+	Category string `yaml:"category"`
+	// Group chains rules sharing it: only the first one whose condition holds
+	// fires, like an if/else-if chain
+	Group string `yaml:"group"`
+	// ForEach is a list expression; the rule is applied to each element,
+	// bound as item
+	ForEach string `yaml:"for_each"`
+	// When is the condition, empty means always
+	When string `yaml:"when"`
+	// Type is bullish, bearish, warning or neutral
+	Type string `yaml:"type"`
+	// Strength is a number or expression, positive favoring longs
+	Strength string `yaml:"strength"`
+	// Description is a template such as "RSI({Momentum.RSI:.2f})超买"
+	Description string `yaml:"description"`
+	// Descriptions holds the templates of other output languages keyed by
+	// language such as en-US; languages without one use Description
+	Descriptions map[string]string `yaml:"descriptions"`
+	// Data maps names to expressions stored in Evidence.Data, nil values
+	// are left out
+	Data     map[string]string `yaml:"data"`
+	Disabled bool              `yaml:"disabled"`
+}
+
+// EvidenceFacts are the values derived from an analysis that rules can use
+// besides the fields of types.Analysis
+type EvidenceFacts struct {
+	// PriceChange is the change of the latest candle
+	PriceChange float64
+	Thresholds  Thresholds
+	// Resistance and Support are the nearest clustered levels, falling back to
+	// the classic pivots R1/S1 with a score of 0.5
+	Resistance types.SRLevel
+	Support    types.SRLevel
+	// FibConfluences lists the source codes of the other levels within 0.5%
+	// of the Fibonacci level price is testing, labelled with kind "source"
+	FibConfluences []string
+}
+
+var evidenceTypes = map[string]types.EvidenceType{
+	"bullish": types.BullishEvidence,
+	"bearish": types.BearishEvidence,
+	"warning": types.WarningEvidence,
+	"neutral": types.NeutralEvidence,
+}
+
+// evidenceRuleFile is the layout of a rule file
+type evidenceRuleFile struct {
+	// Define holds named expressions usable by the rules, evaluated in order
+	Define yaml.Node      `yaml:"define"`
+	Rules  []EvidenceRule `yaml:"rules"`
+}
+
+type namedExpression struct {
+	name string
+	expr *rules.Expression
+}
+
+type compiledRule struct {
+	rule         EvidenceRule
+	evidenceType types.EvidenceType
+	when         *rules.Expression
+	strength     *rules.Expression
+	description  *rules.Template
+	descriptions map[string]*rules.Template
+	data         map[string]*rules.Expression
+	dataTypes    map[string]reflect.Type // static Go types the data values are restored to
+}
+
+// ruleBlock is a group of rules evaluated together, or a single rule
+type ruleBlock struct {
+	group   string
+	forEach *rules.Expression
+	rules   []*compiledRule
+}
+
+// EvidenceRuleSet is a compiled set of evidence rules
+type EvidenceRuleSet struct {
+	defines []namedExpression
+	rules   []EvidenceRule
+	blocks  []*ruleBlock
+}
+
+var (
+	defaultEvidenceRules     *EvidenceRuleSet
+	defaultEvidenceRulesOnce sync.Once
+)
+
+// DefaultEvidenceRules returns the built-in rules shipped in evidence_rules.yaml
+func DefaultEvidenceRules() *EvidenceRuleSet {
+	defaultEvidenceRulesOnce.Do(func() {
+		rs, err := parseEvidenceRules(nil, defaultEvidenceRulesYAML)
+		if err != nil {
+			panic(fmt.Sprintf("default evidence rules: %v", err))
+		}
+		defaultEvidenceRules = rs
+	})
+	return defaultEvidenceRules
+}
+
+// LoadEvidenceRules loads the default rules extended by the given rule files
+// in order. A rule whose ID already exists replaces it in place (disabled:
+// true removes it), new rules are appended; defines work the same by name
+func LoadEvidenceRules(paths ...string) (*EvidenceRuleSet, error) {
+	rs := DefaultEvidenceRules()
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read rule file: %w", err)
+		}
+		if rs, err = parseEvidenceRules(rs, data); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return rs, nil
+}
+
+// parseEvidenceRules merges a rule file into base and compiles the result
+func parseEvidenceRules(base *EvidenceRuleSet, data []byte) (*EvidenceRuleSet, error) {
+	var file evidenceRuleFile
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("failed to parse rule file: %w", err)
+	}
+
+	rs := &EvidenceRuleSet{}
+	if base != nil {
+		rs.defines = append(rs.defines, base.defines...)
+		rs.rules = append(rs.rules, base.rules...)
+	}
+
+	if file.Define.Kind != 0 {
+		if file.Define.Kind != yaml.MappingNode {
+			return nil, fmt.Errorf("define must be a mapping of names to expressions")
+		}
+		for i := 0; i+1 < len(file.Define.Content); i += 2 {
+			name, text := file.Define.Content[i].Value, file.Define.Content[i+1].Value
+			e, err := rules.Parse(text)
+			if err != nil {
+				return nil, fmt.Errorf("define %s: %w", name, err)
+			}
+			define, replaced := namedExpression{name: name, expr: e}, false
+			for j := range rs.defines {
+				if rs.defines[j].name == name {
+					rs.defines[j], replaced = define, true
+					break
+				}
+			}
+			if !replaced {
+				rs.defines = append(rs.defines, define)
+			}
+		}
+	}
+
+	for _, rule := range file.Rules {
+		if rule.ID == "" {
+			return nil, fmt.Errorf("rule without id")
+		}
+		replaced := false
+		for i := range rs.rules {
+			if rs.rules[i].ID == rule.ID {
+				rs.rules[i], replaced = rule, true
+				break
+			}
+		}
+		if !replaced {
+			rs.rules = append(rs.rules, rule)
+		}
+	}
+
+	if err := rs.compile(); err != nil {
+		return nil, err
+	}
+	return rs, nil
+}
+
+// compile parses the enabled rules into blocks and checks every expression
+// against the fields of the analysis
+func (rs *EvidenceRuleSet) compile() error {
+	scope := evidenceScope()
+	for _, d := range rs.defines {
+		if err := d.expr.Check(scope); err != nil {
+			return fmt.Errorf("define %s: %w", d.name, err)
+		}
+		scope[d.name] = nil
+	}
+
+	rs.blocks = nil
+	groups := make(map[string]*ruleBlock)
+	for _, rule := range rs.rules {
+		if rule.Disabled {
+			continue
+		}
+		block := groups[rule.Group]
+		if rule.Group == "" || block == nil {
+			block = &ruleBlock{group: rule.Group}
+			if rule.ForEach != "" {
+				e, err := rules.Parse(rule.ForEach)
+				if err != nil {
+					return fmt.Errorf("rule %s: for_each: %w", rule.ID, err)
+				}
+				block.forEach = e
+			}
+			if rule.Group != "" {
+				groups[rule.Group] = block
+			}
+			rs.blocks = append(rs.blocks, block)
+		} else if (block.forEach == nil && rule.ForEach != "") || (block.forEach != nil && block.forEach.Text() != rule.ForEach) {
+			return fmt.Errorf("rule %s: rules of group %s must share for_each", rule.ID, rule.Group)
+		}
+
+		ruleScope := scope
+		if block.forEach != nil {
+			listType, err := block.forEach.TypeOf(scope)
+			if err != nil {
+				return fmt.Errorf("rule %s: for_each: %w", rule.ID, err)
+			}
+			var itemType reflect.Type
+			if listType != nil && (listType.Kind() == reflect.Slice || listType.Kind() == reflect.Array) {
+				itemType = listType.Elem()
+			}
+			ruleScope = make(rules.Scope, len(scope)+1)
+			for name, t := range scope {
+				ruleScope[name] = t
+			}
+			ruleScope["item"] = itemType
+		}
+
+		compiled, err := compileRule(rule, ruleScope)
+		if err != nil {
+			return fmt.Errorf("rule %s: %w", rule.ID, err)
+		}
+		block.rules = append(block.rules, compiled)
+	}
+	return nil
+}
+
+func compileRule(rule EvidenceRule, scope rules.Scope) (*compiledRule, error) {
+	evidenceType, ok := evidenceTypes[rule.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown type %q, expected bullish, bearish, warning or neutral", rule.Type)
+	}
+	if rule.Category == "" {
+		return nil, fmt.Errorf("missing category")
+	}
+	c := &compiledRule{
+		rule:         rule,
+		evidenceType: evidenceType,
+		descriptions: make(map[string]*rules.Template),
+		data:         make(map[string]*rules.Expression),
+		dataTypes:    make(map[string]reflect.Type),
+	}
+
+	var err error
+	if rule.When != "" {
+		if c.when, err = rules.Parse(rule.When); err != nil {
+			return nil, fmt.Errorf("when: %w", err)
+		}
+		if err = c.when.Check(scope); err != nil {
+			return nil, fmt.Errorf("when: %w", err)
+		}
+	}
+	strength := rule.Strength
+	if strength == "" {
+		strength = "0"
+	}
+	if c.strength, err = rules.Parse(strength); err != nil {
+		return nil, fmt.Errorf("strength: %w", err)
+	}
+	if err = c.strength.Check(scope); err != nil {
+		return nil, fmt.Errorf("strength: %w", err)
+	}
+	if c.description, err = rules.ParseTemplate(rule.Description); err != nil {
+		return nil, fmt.Errorf("description: %w", err)
+	}
+	if err = c.description.Check(scope); err != nil {
+		return nil, fmt.Errorf("description: %w", err)
+	}
+	for lang, text := range rule.Descriptions {
+		tmpl, err := rules.ParseTemplate(text)
+		if err != nil {
+			return nil, fmt.Errorf("descriptions %s: %w", lang, err)
+		}
+		if err := tmpl.Check(scope); err != nil {
+			return nil, fmt.Errorf("descriptions %s: %w", lang, err)
+		}
+		c.descriptions[lang] = tmpl
+	}
+	for name, text := range rule.Data {
+		e, err := rules.Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("data %s: %w", name, err)
+		}
+		t, err := e.TypeOf(scope)
+		if err != nil {
+			return nil, fmt.Errorf("data %s: %w", name, err)
+		}
+		c.data[name] = e
+		c.dataTypes[name] = t
+	}
+	return c, nil
+}
+
+// evidenceScope returns the identifiers available to rules and their types
+func evidenceScope() rules.Scope {
+	scope := make(rules.Scope)
+	for _, t := range []reflect.Type{reflect.TypeOf(types.Analysis{}), reflect.TypeOf(EvidenceFacts{})} {
+		for i := 0; i < t.NumField(); i++ {
+			scope[t.Field(i).Name] = t.Field(i).Type
+		}
+	}
+	scope["expr"] = reflect.TypeOf(rules.Func(nil))
+	scope["label"] = reflect.TypeOf(rules.Func(nil))
+	return scope
+}
+
+// Rules returns the rules of the set, including disabled ones
+func (rs *EvidenceRuleSet) Rules() []EvidenceRule {
+	return append([]EvidenceRule{}, rs.rules...)
+}
+
+// Expressions returns the custom indicator expressions the rules evaluate with
+// expr('...'); they must be added to the TrendAnalyzer with AddExpression
+func (rs *EvidenceRuleSet) Expressions() []string {
+	texts := make([]string, 0)
+	seen := make(map[string]bool)
+	add := func(list []string) {
+		for _, text := range list {
+			if !seen[text] {
+				seen[text] = true
+				texts = append(texts, text)
+			}
+		}
+	}
+	for _, d := range rs.defines {
+		add(d.expr.StringArgs("expr"))
+	}
+	for _, block := range rs.blocks {
+		if block.forEach != nil {
+			add(block.forEach.StringArgs("expr"))
+		}
+		for _, c := range block.rules {
+			if c.when != nil {
+				add(c.when.StringArgs("expr"))
+			}
+			add(c.strength.StringArgs("expr"))
+			add(c.description.StringArgs("expr"))
+			for _, tmpl := range c.descriptions {
+				add(tmpl.StringArgs("expr"))
+			}
+			for _, e := range c.data {
+				add(e.StringArgs("expr"))
+			}
+		}
+	}
+	return texts
+}
+
+// environment binds the analysis fields, the facts and the defines
+func (rs *EvidenceRuleSet) environment(result *types.Analysis, facts EvidenceFacts) (rules.Env, error) {
+	env := make(rules.Env)
+	for _, v := range []reflect.Value{reflect.ValueOf(result).Elem(), reflect.ValueOf(facts)} {
+		for i := 0; i < v.NumField(); i++ {
+			env[v.Type().Field(i).Name] = v.Field(i).Interface()
+		}
+	}
+	env["expr"] = rules.Func(func(args []interface{}) (interface{}, error) {
+		text, ok := args[0].(string)
+		if len(args) != 1 || !ok {
+			return nil, fmt.Errorf("expected the expression text")
+		}
+		for _, r := range result.Expressions {
+			if r.Expression != text {
+				continue
+			}
+			if !r.Valid {
+				return nil, nil
+			}
+			if r.Boolean {
+				return r.Value != 0, nil
+			}
+			return r.Value, nil
+		}
+		return nil, fmt.Errorf("expression %q was not evaluated by the analyzer", text)
+	})
+	env["label"] = rules.Func(labelCode)
+
+	for _, d := range rs.defines {
+		value, err := d.expr.Eval(env)
+		if err != nil {
+			return nil, fmt.Errorf("define %s: %w", d.name, err)
+		}
+		env[d.name] = value
+	}
+	return env, nil
+}
+
+// apply adds the evidence of the first rule of the block whose condition holds
+func (b *ruleBlock) apply(ec *EvidenceCollector, env rules.Env) error {
+	for _, c := range b.rules {
+		if c.when != nil {
+			cond, err := c.when.Eval(env)
+			if err != nil {
+				return fmt.Errorf("rule %s: %w", c.rule.ID, err)
+			}
+			if !rules.Truthy(cond) {
+				continue
+			}
+		}
+		evidence, err := c.evidence(env)
+		if err != nil {
+			return fmt.Errorf("rule %s: %w", c.rule.ID, err)
+		}
+		ec.AddEvidence(evidence)
+		return nil
+	}
+	return nil
+}
+
+func (c *compiledRule) evidence(env rules.Env) (types.Evidence, error) {
+	value, err := c.strength.Eval(env)
+	if err != nil {
+		return types.Evidence{}, err
+	}
+	strength, ok := value.(float64)
+	if !ok || math.IsNaN(strength) {
+		return types.Evidence{}, fmt.Errorf("strength %v is not a number", value)
+	}
+	tmpl := c.description
+	if localized, ok := c.descriptions[i18n.Language()]; ok {
+		tmpl = localized
+	}
+	description, err := tmpl.Execute(env)
+	if err != nil {
+		return types.Evidence{}, err
+	}
+	data := make(map[string]interface{}, len(c.data))
+	for name, e := range c.data {
+		value, err := e.Eval(env)
+		if err != nil {
+			return types.Evidence{}, err
+		}
+		if value != nil {
+			// Keep the field's own type (int bar counts, TrendDirection...)
+			// rather than the evaluator's float64/string
+			data[name] = rules.Restore(value, c.dataTypes[name])
+		}
+	}
+	return types.Evidence{
+		ID:          c.rule.ID,
+		Type:        c.evidenceType,
+		Category:    c.rule.Category,
+		Description: description,
+		Strength:    strength,
+		Data:        data,
+	}, nil
+}
+
+// labelCode implements label(kind, x) for description templates: it returns
+// the display label of a code produced by the indicators (see i18n.Label), or
+// of each element of a list
+func labelCode(args []interface{}) (interface{}, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("label expects two arguments")
+	}
+	kind, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("label kind must be a string, got %T", args[0])
+	}
+	switch value := args[1].(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return i18n.Label(kind, value), nil
+	case []string:
+		labels := make([]string, len(value))
+		for i, code := range value {
+			labels[i] = i18n.Label(kind, code)
+		}
+		return labels, nil
+	}
+	return nil, fmt.Errorf("label of %T", args[1])
+}
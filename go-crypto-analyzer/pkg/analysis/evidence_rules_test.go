@@ -0,0 +1,179 @@
+package analysis
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zjc/go-crypto-analyzer/pkg/i18n"
+	"github.com/zjc/go-crypto-analyzer/pkg/types"
+)
+
+func TestEvidenceRules(t *testing.T) {
+	// 多头排列、RSI超买、放量上涨，价格接近聚类阻力位
+	result := &types.Analysis{
+		CurrentPrice: 100,
+		MAAnalysis:   types.MAAnalysis{MA5: 99, MA10: 98, MA20: 97, MA50: 95},
+		MACDAnalysis: types.MACDAnalysis{MACD: 1, Signal: 0.5, Histogram: 0.5},
+		Momentum:     types.MomentumAnalysis{RSI: 75, StochRSIK: 50, StochRSID: 50},
+		Volume:       types.VolumeAnalysis{VolumeRatio: 2.5},
+		SupportResistance: types.SRAnalysis{
+			Pivot:             98,
+			NearestResistance: types.SRLevel{Price: 100.5, Source: types.SwingSource, Score: 0.8},
+			Support:           map[string]float64{"S1": 90},
+		},
+		CandlePatterns: []types.CandlePattern{
+			{Name: types.BullishEngulfing, Direction: 1, Reliability: 0.6, BarsAgo: 1},
+			{Name: types.Doji, Direction: 0},
+		},
+	}
+
+	ec := NewEvidenceCollector()
+	if err := ec.Collect(result, 0.01); err != nil {
+		t.Fatal(err)
+	}
+	byCategory := make(map[string][]types.Evidence)
+	for _, e := range ec.evidences {
+		byCategory[e.Category] = append(byCategory[e.Category], e)
+	}
+
+	ma := byCategory["ma"]
+	if len(ma) != 3 || ma[0].ID != "ma.price_above_ma5" || ma[0].Description != "价格(100.00)高于MA5(99.00)，短期趋势向上" || ma[2].Strength != 0.8 {
+		t.Errorf("moving average evidence: %+v", ma)
+	}
+	// 类别和类型为代码，显示文本查消息目录
+	if ma[0].CategoryLabel() != "移动平均线" || ma[0].Type != "BULLISH" || ma[0].Type.String() != "看涨证据" {
+		t.Errorf("evidence codes and labels: %q %q %q", ma[0].CategoryLabel(), ma[0].Type, ma[0].Type.String())
+	}
+	if rsi := byCategory["rsi"]; len(rsi) != 1 || rsi[0].Type != types.WarningEvidence || rsi[0].Description != "RSI(75.00)>70，处于超买区域，可能回调" {
+		t.Errorf("RSI evidence: %+v", rsi)
+	}
+	if volume := byCategory["volume"]; len(volume) != 1 || volume[0].Strength != 0.6 || volume[0].Data["volumeRatio"] != 2.5 {
+		t.Errorf("volume evidence: %+v", volume)
+	}
+	sr := byCategory["sr"]
+	if len(sr) != 2 || sr[0].Description != "接近阻力位100.50(摆动，评分0.80)，上涨空间有限(0.5%)" || sr[0].Strength != -0.15-0.3*0.8 {
+		t.Errorf("support/resistance evidence: %+v", sr)
+	}
+	// 每个形态一条证据，强度随K线数衰减，缺少趋势背景时减半
+	candles := byCategory["candlestick"]
+	if len(candles) != 2 || candles[0].Strength != 0.6*0.6/2/2 || candles[0].Description != "看涨吞没（1根K线前，可靠性60%，缺少趋势背景）" ||
+		candles[1].Description != "十字星（当前K线），多空犹豫" {
+		t.Errorf("candlestick evidence: %+v", candles)
+	}
+	// 数据保留字段原有的Go类型
+	if candles[0].Data["barsAgo"] != 1 || candles[0].Data["reliability"] != 0.6 {
+		t.Errorf("candlestick data should keep int values: %#v", candles[0].Data)
+	}
+	if len(byCategory["ichimoku"]) != 0 || len(byCategory["fibonacci"]) != 0 {
+		t.Error("unavailable indicators should not add evidence")
+	}
+
+	// 英文输出使用 descriptions 中的模板，代码经 label() 显示
+	if err := i18n.SetLanguage(i18n.English); err != nil {
+		t.Fatal(err)
+	}
+	defer i18n.SetLanguage(i18n.Chinese)
+	ec.Clear()
+	if err := ec.Collect(result, 0.01); err != nil {
+		t.Fatal(err)
+	}
+	var english []types.Evidence
+	for _, e := range ec.evidences {
+		if e.ID == "ma.price_above_ma5" || e.Category == "candlestick" {
+			english = append(english, e)
+		}
+	}
+	if len(english) != 3 || english[0].Description != "Price (100.00) above MA5 (99.00), short-term trend up" ||
+		english[1].Description != "Bullish engulfing (1 bars ago, reliability 60%, lacking trend context)" || english[0].CategoryLabel() != "Moving averages" {
+		t.Errorf("English evidence: %+v", english)
+	}
+
+	// 用户规则：覆盖、停用和新增
+	dir := t.TempDir()
+	path := filepath.Join(dir, "rules.yaml")
+	userRules := `
+define:
+  stretched: "(CurrentPrice - MAAnalysis.MA20) / MAAnalysis.MA20 * 100"
+rules:
+  - id: rsi.overbought
+    category: rsi
+    group: rsi.zone
+    when: "Momentum.RSI > 80"
+    type: warning
+    strength: -0.5
+    description: "RSI({Momentum.RSI:.1f})>80"
+  - id: ma.price_above_ma5
+    disabled: true
+  - id: ma.price_below_ma5
+    disabled: true
+  - id: custom.stretched
+    category: custom
+    when: "stretched > 2 and expr('close > 0')"
+    type: warning
+    strength: "-0.1 * stretched"
+    description: "价格偏离MA20 {stretched:.1f}%"
+    data: {deviation: stretched}
+`
+	if err := os.WriteFile(path, []byte(userRules), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rs, err := LoadEvidenceRules(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exprs := rs.Expressions(); len(exprs) != 1 || exprs[0] != "close > 0" {
+		t.Errorf("rule expressions: %v", exprs)
+	}
+
+	ec.SetRules(rs)
+	ec.Clear()
+	result.Expressions = []types.ExpressionResult{{Expression: "close > 0", Value: 1, Boolean: true, Valid: true}}
+	if err := ec.Collect(result, 0.01); err != nil {
+		t.Fatal(err)
+	}
+	var rsi, custom []types.Evidence
+	ma = nil
+	for _, e := range ec.evidences {
+		switch e.Category {
+		case "rsi":
+			rsi = append(rsi, e)
+		case "custom":
+			custom = append(custom, e)
+		case "ma":
+			ma = append(ma, e)
+		}
+	}
+	// RSI 75不再超买，落到同组的下一条规则
+	if len(rsi) != 1 || rsi[0].Type != types.BullishEvidence {
+		t.Errorf("overridden RSI rule: %+v", rsi)
+	}
+	if len(ma) != 2 {
+		t.Errorf("disabled price/MA5 rules should leave 2 MA evidences, got %+v", ma)
+	}
+	if len(custom) != 1 || custom[0].Description != "价格偏离MA20 3.1%" || custom[0].Data["deviation"] == nil || ec.evidences[len(ec.evidences)-1].ID != "custom.stretched" || custom[0].CategoryLabel() != "custom" {
+		t.Errorf("appended rule: %+v", custom)
+	}
+
+	// 默认规则不受用户规则影响
+	if len(DefaultEvidenceRules().Rules()) == len(rs.Rules()) {
+		t.Error("user rules should not modify the default rule set")
+	}
+
+	// 加载时检查字段、类型和表达式
+	for _, bad := range []string{
+		"rules:\n  - id: x\n    category: c\n    type: bullish\n    when: \"Momentum.RSII > 70\"\n",
+		"rules:\n  - id: x\n    category: c\n    type: bullsh\n",
+		"rules:\n  - id: x\n    category: c\n    type: bullish\n    descripton: typo\n",
+		"rules:\n  - id: x\n    category: c\n    type: bullish\n    description: \"{CurrentPrice:.2f\"\n",
+		"rules:\n  - id: x\n    category: c\n    type: bullish\n    for_each: CandlePatterns\n    when: \"item.Nmae == ''\"\n",
+	} {
+		if err := os.WriteFile(path, []byte(bad), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadEvidenceRules(path); err == nil || !strings.Contains(err.Error(), path) {
+			t.Errorf("expected rule file to fail: %q (%v)", bad, err)
+		}
+	}
+}
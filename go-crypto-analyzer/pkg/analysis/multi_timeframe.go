@@ -0,0 +1,170 @@
+package analysis
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/zjc/go-crypto-analyzer/pkg/types"
+)
+
+// MultiTimeframeAnalyzer runs the trend analysis and evidence collection on
+// several timeframes of a symbol and scores how well they line up
+type MultiTimeframeAnalyzer struct {
+	analyzer  *TrendAnalyzer
+	collector *EvidenceCollector
+}
+
+// NewMultiTimeframeAnalyzer creates a MultiTimeframeAnalyzer sharing the
+// settings of the given analyzer and the thresholds and rules of the given
+// collector. Evidence is collected into a collector of its own, so the
+// evidence already in the given collector is left untouched
+func NewMultiTimeframeAnalyzer(analyzer *TrendAnalyzer, collector *EvidenceCollector) *MultiTimeframeAnalyzer {
+	own := NewEvidenceCollector()
+	own.SetThresholds(collector.thresholds)
+	own.SetRules(collector.rules)
+	return &MultiTimeframeAnalyzer{analyzer: analyzer, collector: own}
+}
+
+// Analyze analyzes every timeframe in series, keyed by interval such as "4h".
+// Timeframes the analyzer rejects (e.g. too few candles) are left out of the
+// matrix; the i-th shortest remaining timeframe gets weight i
+func (mta *MultiTimeframeAnalyzer) Analyze(symbol string, series map[string][]types.OHLCV) (*types.MultiTimeframeAnalysis, error) {
+	intervals := make([]string, 0, len(series))
+	durations := make(map[string]time.Duration, len(series))
+	for interval := range series {
+		duration, err := intervalDuration(interval)
+		if err != nil {
+			return nil, err
+		}
+		intervals = append(intervals, interval)
+		durations[interval] = duration
+	}
+	sort.Slice(intervals, func(i, j int) bool {
+		return durations[intervals[i]] < durations[intervals[j]]
+	})
+
+	result := &types.MultiTimeframeAnalysis{Symbol: symbol}
+	for _, interval := range intervals {
+		data := series[interval]
+		analysis, err := mta.analyzer.AnalyzeComprehensive(data)
+		if err != nil {
+			continue
+		}
+		priceChange := 0.0
+		if n := len(data); n > 1 && data[n-2].Close != 0 {
+			priceChange = (data[n-1].Close - data[n-2].Close) / data[n-2].Close
+		}
+		mta.collector.Clear()
+		if err := mta.collector.Collect(analysis, priceChange); err != nil {
+			return nil, fmt.Errorf("%s: %w", interval, err)
+		}
+		strength := mta.collector.GetSummary()["totalStrength"].(float64)
+
+		result.Timeframes = append(result.Timeframes, types.TimeframeAnalysis{
+			Interval:         interval,
+			Weight:           float64(len(result.Timeframes) + 1),
+			Trend:            analysis.OverallTrend,
+			TrendScore:       analysis.TrendScore,
+			RSI:              analysis.Momentum.RSI,
+			Momentum:         analysis.Momentum.Momentum,
+			MACDTrend:        analysis.MACDAnalysis.Trend,
+			EvidenceStrength: strength,
+			Bias:             trendBias(analysis.OverallTrend),
+			// The trend score spans -4..4; evidence beyond ±4 counts as full
+			Score: (analysis.TrendScore/4 + math.Max(-1, math.Min(1, strength/4))) / 2,
+		})
+	}
+	if len(result.Timeframes) == 0 {
+		return nil, fmt.Errorf("no timeframe of %s could be analyzed", symbol)
+	}
+
+	totalWeight, weighted := 0.0, 0.0
+	for _, tf := range result.Timeframes {
+		totalWeight += tf.Weight
+		weighted += tf.Weight * tf.Score
+	}
+	result.Confluence = weighted / totalWeight * 100
+	result.Direction = classifyConfluence(result.Confluence)
+
+	direction := trendBias(result.Direction)
+	agreeing := 0.0
+	for _, tf := range result.Timeframes {
+		if tf.Bias == direction {
+			agreeing += tf.Weight
+		}
+	}
+	result.Alignment = agreeing / totalWeight
+
+	for i, lower := range result.Timeframes {
+		for _, higher := range result.Timeframes[i+1:] {
+			if lower.Bias*higher.Bias < 0 {
+				result.Conflicts = append(result.Conflicts, types.TimeframeConflict{
+					Lower:       lower.Interval,
+					Higher:      higher.Interval,
+					LowerTrend:  lower.Trend,
+					HigherTrend: higher.Trend,
+				})
+			}
+		}
+	}
+	return result, nil
+}
+
+// classifyConfluence maps a confluence score in percent to a direction
+func classifyConfluence(confluence float64) types.TrendDirection {
+	switch {
+	case confluence >= 50:
+		return types.StrongUptrend
+	case confluence >= 15:
+		return types.Uptrend
+	case confluence > -15:
+		return types.Sideways
+	case confluence > -50:
+		return types.Downtrend
+	default:
+		return types.StrongDowntrend
+	}
+}
+
+// trendBias is 1 for up, -1 for down and 0 for sideways trends
+func trendBias(trend types.TrendDirection) int {
+	switch trend {
+	case types.StrongUptrend, types.Uptrend:
+		return 1
+	case types.StrongDowntrend, types.Downtrend:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// intervalDuration parses kline intervals such as 15m, 4h, 1d, 1w and 1M
+// (taken as 30 days)
+func intervalDuration(interval string) (time.Duration, error) {
+	if len(interval) < 2 {
+		return 0, fmt.Errorf("invalid interval %q", interval)
+	}
+	n, err := strconv.Atoi(interval[:len(interval)-1])
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid interval %q", interval)
+	}
+	var unit time.Duration
+	switch interval[len(interval)-1] {
+	case 'm':
+		unit = time.Minute
+	case 'h':
+		unit = time.Hour
+	case 'd':
+		unit = 24 * time.Hour
+	case 'w':
+		unit = 7 * 24 * time.Hour
+	case 'M':
+		unit = 30 * 24 * time.Hour
+	default:
+		return 0, fmt.Errorf("invalid interval %q", interval)
+	}
+	return time.Duration(n) * unit, nil
+}
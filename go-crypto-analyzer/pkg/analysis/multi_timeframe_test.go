@@ -0,0 +1,76 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/zjc/go-crypto-analyzer/pkg/types"
+)
+
+func TestMultiTimeframe(t *testing.T) {
+	// 带小幅波动的单边行情：1h、4h上涨，1d下跌
+	build := func(step time.Duration, drift float64) []types.OHLCV {
+		start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		ohlcv := make([]types.OHLCV, 220)
+		price := 100.0
+		for i := range ohlcv {
+			open := price
+			price *= 1 + drift + 0.004*math.Sin(float64(i))
+			ohlcv[i] = types.OHLCV{
+				Time:   start.Add(time.Duration(i) * step),
+				Open:   open,
+				High:   math.Max(open, price) * 1.002,
+				Low:    math.Min(open, price) * 0.998,
+				Close:  price,
+				Volume: 1000 + 100*math.Cos(float64(i)),
+			}
+		}
+		return ohlcv
+	}
+	series := map[string][]types.OHLCV{
+		"1d": build(24*time.Hour, -0.01),
+		"1h": build(time.Hour, 0.01),
+		"4h": build(4*time.Hour, 0.01),
+	}
+
+	// 多周期分析使用自己的证据收集器，不清空调用方已收集的证据
+	shared := NewEvidenceCollector()
+	shared.AddEvidence(types.Evidence{ID: "test.kept", Strength: 1})
+	mta := NewMultiTimeframeAnalyzer(NewTrendAnalyzer(), shared)
+	result, err := mta.Analyze("BTCUSDT", series)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strength := shared.GetSummary()["totalStrength"].(float64); strength != 1 {
+		t.Errorf("shared collector was modified, total strength %.2f", strength)
+	}
+	if len(result.Timeframes) != 3 || result.Timeframes[0].Interval != "1h" || result.Timeframes[2].Interval != "1d" {
+		t.Fatalf("timeframes should be ordered by interval: %+v", result.Timeframes)
+	}
+	for i, tf := range result.Timeframes {
+		if tf.Weight != float64(i+1) {
+			t.Errorf("%s weight %.0f", tf.Interval, tf.Weight)
+		}
+	}
+	if result.Timeframes[0].Bias != 1 || result.Timeframes[2].Bias != -1 {
+		t.Fatalf("biases: 1h %d 1d %d", result.Timeframes[0].Bias, result.Timeframes[2].Bias)
+	}
+	if len(result.Conflicts) != 2 || result.Conflicts[0].Lower != "1h" || result.Conflicts[0].Higher != "1d" ||
+		trendBias(result.Conflicts[0].LowerTrend) != 1 || trendBias(result.Conflicts[0].HigherTrend) != -1 {
+		t.Errorf("expected 1h and 4h against 1d, got %+v", result.Conflicts)
+	}
+	if result.Alignment <= 0 || result.Alignment >= 1 {
+		t.Errorf("alignment %.2f", result.Alignment)
+	}
+
+	// 全部同向时无冲突且共振得分为正
+	aligned, err := mta.Analyze("BTCUSDT", map[string][]types.OHLCV{"1h": series["1h"], "4h": series["4h"]})
+	if err != nil || len(aligned.Conflicts) != 0 || aligned.Confluence <= 0 || aligned.Alignment != 1 {
+		t.Errorf("aligned uptrend: %v %+v", err, aligned)
+	}
+
+	if _, err := mta.Analyze("BTCUSDT", map[string][]types.OHLCV{"4x": series["4h"]}); err == nil {
+		t.Error("expected invalid interval to fail")
+	}
+}
@@ -0,0 +1,298 @@
+package analysis
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zjc/go-crypto-analyzer/pkg/types"
+)
+
+// RelativeStrengthLookback is a performance lookback and its weight in the
+// composite score
+type RelativeStrengthLookback struct {
+	Label    string
+	Duration time.Duration
+	Weight   float64
+}
+
+// ParseRelativeStrengthLookbacks parses a list such as "1d,7d,30d" or
+// "1d:0.2,7d:0.3,30d:0.5"; lookbacks without a weight are weighted equally
+func ParseRelativeStrengthLookbacks(text string) ([]RelativeStrengthLookback, error) {
+	lookbacks := make([]RelativeStrengthLookback, 0)
+	for _, part := range strings.Split(text, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		label, weightText, hasWeight := strings.Cut(part, ":")
+		duration, err := parseLookbackDuration(label)
+		if err != nil {
+			return nil, err
+		}
+		weight := 1.0
+		if hasWeight {
+			if weight, err = strconv.ParseFloat(weightText, 64); err != nil || weight <= 0 {
+				return nil, fmt.Errorf("invalid weight %q for lookback %s", weightText, label)
+			}
+		}
+		lookbacks = append(lookbacks, RelativeStrengthLookback{Label: label, Duration: duration, Weight: weight})
+	}
+	if len(lookbacks) == 0 {
+		return nil, fmt.Errorf("no relative strength lookbacks in %q", text)
+	}
+	sort.SliceStable(lookbacks, func(i, j int) bool { return lookbacks[i].Duration < lookbacks[j].Duration })
+	return lookbacks, nil
+}
+
+// parseLookbackDuration accepts Go durations plus day and week suffixes
+func parseLookbackDuration(label string) (time.Duration, error) {
+	unit := time.Duration(0)
+	switch {
+	case strings.HasSuffix(label, "d"):
+		unit = 24 * time.Hour
+	case strings.HasSuffix(label, "w"):
+		unit = 7 * 24 * time.Hour
+	}
+	if unit > 0 {
+		n, err := strconv.Atoi(strings.TrimSuffix(label, label[len(label)-1:]))
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid lookback %q", label)
+		}
+		return time.Duration(n) * unit, nil
+	}
+	duration, err := time.ParseDuration(label)
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("invalid lookback %q", label)
+	}
+	return duration, nil
+}
+
+// RelativeStrengthAnalyzer ranks symbols by performance relative to the
+// benchmark and to an equal-weight basket of the watchlist
+type RelativeStrengthAnalyzer struct {
+	lookbacks []RelativeStrengthLookback
+	benchmark string
+}
+
+// NewRelativeStrengthAnalyzer creates a RelativeStrengthAnalyzer over 1d/7d/30d,
+// weighting the longer lookbacks more
+func NewRelativeStrengthAnalyzer() *RelativeStrengthAnalyzer {
+	return &RelativeStrengthAnalyzer{
+		lookbacks: []RelativeStrengthLookback{
+			{Label: "1d", Duration: 24 * time.Hour, Weight: 0.2},
+			{Label: "7d", Duration: 7 * 24 * time.Hour, Weight: 0.3},
+			{Label: "30d", Duration: 30 * 24 * time.Hour, Weight: 0.5},
+		},
+		benchmark: "BTCUSDT",
+	}
+}
+
+// SetLookbacks sets the lookbacks of the ranking
+func (ra *RelativeStrengthAnalyzer) SetLookbacks(lookbacks []RelativeStrengthLookback) {
+	ra.lookbacks = lookbacks
+}
+
+// SetBenchmark sets the symbol the RS lines are measured against
+func (ra *RelativeStrengthAnalyzer) SetBenchmark(symbol string) {
+	ra.benchmark = symbol
+}
+
+// Benchmark returns the symbol the RS lines are measured against
+func (ra *RelativeStrengthAnalyzer) Benchmark() string {
+	return ra.benchmark
+}
+
+// LongestLookback returns the longest lookback, which bounds the history needed
+func (ra *RelativeStrengthAnalyzer) LongestLookback() time.Duration {
+	longest := time.Duration(0)
+	for _, lookback := range ra.lookbacks {
+		if lookback.Duration > longest {
+			longest = lookback.Duration
+		}
+	}
+	return longest
+}
+
+// Analyze ranks the symbols as of the latest timestamp all of them have reached
+func (ra *RelativeStrengthAnalyzer) Analyze(symbols []string, series map[string][]types.OHLCV) (*types.RelativeStrengthAnalysis, error) {
+	asOf := time.Time{}
+	for _, symbol := range symbols {
+		ohlcv := series[symbol]
+		if len(ohlcv) == 0 {
+			continue
+		}
+		if last := ohlcv[len(ohlcv)-1].Time; asOf.IsZero() || last.Before(asOf) {
+			asOf = last
+		}
+	}
+	if asOf.IsZero() {
+		return nil, fmt.Errorf("no data for relative strength")
+	}
+	return ra.AnalyzeAt(symbols, series, asOf)
+}
+
+// AnalyzeAt ranks the symbols using only candles up to asOf, so that
+// backtests can score each bar without lookahead. series may hold the
+// benchmark even when it is not one of the symbols
+func (ra *RelativeStrengthAnalyzer) AnalyzeAt(symbols []string, series map[string][]types.OHLCV, asOf time.Time) (*types.RelativeStrengthAnalysis, error) {
+	if len(ra.lookbacks) == 0 {
+		return nil, fmt.Errorf("no relative strength lookbacks")
+	}
+	result := &types.RelativeStrengthAnalysis{
+		AsOf:             asOf,
+		Lookbacks:        make([]string, len(ra.lookbacks)),
+		BenchmarkReturns: make([]float64, len(ra.lookbacks)),
+		BasketReturns:    make([]float64, len(ra.lookbacks)),
+	}
+	for k, lookback := range ra.lookbacks {
+		result.Lookbacks[k] = lookback.Label
+		result.BenchmarkReturns[k] = math.NaN()
+	}
+	benchmark, hasBenchmark := series[ra.benchmark]
+	if hasBenchmark {
+		result.Benchmark = ra.benchmark
+		for k, lookback := range ra.lookbacks {
+			result.BenchmarkReturns[k] = lookbackReturn(benchmark, asOf, lookback.Duration)
+		}
+	}
+
+	scores := make([]types.RelativeStrengthScore, 0, len(symbols))
+	for _, symbol := range symbols {
+		if _, ok := closeAt(series[symbol], asOf); !ok {
+			continue
+		}
+		score := types.RelativeStrengthScore{
+			Symbol:      symbol,
+			Returns:     make([]float64, len(ra.lookbacks)),
+			VsBenchmark: make([]float64, len(ra.lookbacks)),
+			VsBasket:    make([]float64, len(ra.lookbacks)),
+		}
+		for k, lookback := range ra.lookbacks {
+			score.Returns[k] = lookbackReturn(series[symbol], asOf, lookback.Duration)
+			score.VsBenchmark[k] = relativeReturn(score.Returns[k], result.BenchmarkReturns[k])
+		}
+		if hasBenchmark && symbol != ra.benchmark {
+			score.RSLineNewHigh = ra.ratioLineAtHigh(series[symbol], benchmark, asOf)
+		}
+		scores = append(scores, score)
+	}
+	if len(scores) == 0 {
+		return nil, fmt.Errorf("no symbol has data at %s", asOf.Format("2006-01-02 15:04"))
+	}
+
+	// 等权篮子：覆盖该周期的币种收益的平均
+	for k := range ra.lookbacks {
+		sum, count := 0.0, 0
+		for _, score := range scores {
+			if !math.IsNaN(score.Returns[k]) {
+				sum += score.Returns[k]
+				count++
+			}
+		}
+		result.BasketReturns[k] = math.NaN()
+		if count > 0 {
+			result.BasketReturns[k] = sum / float64(count)
+		}
+	}
+	for i := range scores {
+		weighted, weights := 0.0, 0.0
+		for k, lookback := range ra.lookbacks {
+			scores[i].VsBasket[k] = relativeReturn(scores[i].Returns[k], result.BasketReturns[k])
+			if !math.IsNaN(scores[i].VsBasket[k]) {
+				weighted += lookback.Weight * math.Log1p(scores[i].VsBasket[k])
+				weights += lookback.Weight
+			}
+		}
+		scores[i].Composite = math.NaN()
+		if weights > 0 {
+			scores[i].Composite = weighted / weights * 100
+		}
+	}
+
+	rankRelativeStrength(scores)
+	result.Leaderboard = scores
+	return result, nil
+}
+
+// ratioLineAtHigh reports whether the ratio of symbol to benchmark closes is at
+// its high over the longest lookback the symbol covers
+func (ra *RelativeStrengthAnalyzer) ratioLineAtHigh(ohlcv, benchmark []types.OHLCV, asOf time.Time) bool {
+	start := asOf.Add(-ra.LongestLookback())
+	if len(ohlcv) > 0 && ohlcv[0].Time.After(start) {
+		start = ohlcv[0].Time
+	}
+	high, last, points := math.Inf(-1), math.NaN(), 0
+	for _, candle := range ohlcv {
+		if candle.Time.Before(start) || candle.Time.After(asOf) {
+			continue
+		}
+		reference, ok := closeAt(benchmark, candle.Time)
+		if !ok || reference <= 0 {
+			continue
+		}
+		last = candle.Close / reference
+		high = math.Max(high, last)
+		points++
+	}
+	return points > 1 && last >= high
+}
+
+// rankRelativeStrength sorts by composite score and assigns ranks and
+// percentiles; symbols without a score keep Rank 0
+func rankRelativeStrength(scores []types.RelativeStrengthScore) {
+	sort.SliceStable(scores, func(i, j int) bool {
+		a, b := scores[i].Composite, scores[j].Composite
+		if math.IsNaN(a) || math.IsNaN(b) {
+			return !math.IsNaN(a) && math.IsNaN(b)
+		}
+		return a > b
+	})
+	ranked := 0
+	for _, score := range scores {
+		if !math.IsNaN(score.Composite) {
+			ranked++
+		}
+	}
+	for i := 0; i < ranked; i++ {
+		scores[i].Rank = i + 1
+		scores[i].Percentile = 100
+		if ranked > 1 {
+			scores[i].Percentile = float64(ranked-1-i) / float64(ranked-1) * 100
+		}
+	}
+}
+
+// lookbackReturn is the simple return from the last close at or before
+// asOf-duration to the last close at or before asOf, NaN when not covered
+func lookbackReturn(ohlcv []types.OHLCV, asOf time.Time, duration time.Duration) float64 {
+	current, ok := closeAt(ohlcv, asOf)
+	if !ok {
+		return math.NaN()
+	}
+	past, ok := closeAt(ohlcv, asOf.Add(-duration))
+	if !ok || past <= 0 {
+		return math.NaN()
+	}
+	return current/past - 1
+}
+
+// relativeReturn is the change of the ratio line, NaN when either is undefined
+func relativeReturn(r, reference float64) float64 {
+	if math.IsNaN(r) || math.IsNaN(reference) {
+		return math.NaN()
+	}
+	return (1+r)/(1+reference) - 1
+}
+
+// closeAt returns the close of the last candle at or before t
+func closeAt(ohlcv []types.OHLCV, t time.Time) (float64, bool) {
+	i := sort.Search(len(ohlcv), func(i int) bool { return ohlcv[i].Time.After(t) })
+	if i == 0 {
+		return 0, false
+	}
+	return ohlcv[i-1].Close, true
+}
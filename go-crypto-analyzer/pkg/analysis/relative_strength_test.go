@@ -0,0 +1,77 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/zjc/go-crypto-analyzer/pkg/types"
+)
+
+func TestRelativeStrength(t *testing.T) {
+	// 每日K线：BTC每天+1%，ETH每天+2%，SOL每天-1%，共40天
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	build := func(daily float64) []types.OHLCV {
+		ohlcv := make([]types.OHLCV, 40)
+		price := 100.0
+		for i := range ohlcv {
+			ohlcv[i] = types.OHLCV{Time: start.AddDate(0, 0, i), Open: price, High: price, Low: price, Close: price}
+			price *= 1 + daily
+		}
+		return ohlcv
+	}
+	series := map[string][]types.OHLCV{
+		"BTCUSDT": build(0.01),
+		"ETHUSDT": build(0.02),
+		"SOLUSDT": build(-0.01),
+	}
+
+	lookbacks, err := ParseRelativeStrengthLookbacks("7d:1,1d:1,60d:2")
+	if err != nil || lookbacks[0].Label != "1d" || lookbacks[2].Duration != 60*24*time.Hour {
+		t.Fatalf("unexpected lookbacks %v (%v)", lookbacks, err)
+	}
+	if _, err := ParseRelativeStrengthLookbacks("7x"); err == nil {
+		t.Error("expected invalid lookback to fail")
+	}
+
+	ra := NewRelativeStrengthAnalyzer()
+	ra.SetLookbacks(lookbacks)
+	result, err := ra.Analyze([]string{"SOLUSDT", "BTCUSDT", "ETHUSDT"}, series)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	order := []string{"ETHUSDT", "BTCUSDT", "SOLUSDT"}
+	for i, score := range result.Leaderboard {
+		if score.Symbol != order[i] || score.Rank != i+1 {
+			t.Errorf("rank %d: got %s (rank %d)", i+1, score.Symbol, score.Rank)
+		}
+	}
+	eth, _ := result.Score("ETHUSDT")
+	if eth.Percentile != 100 || !eth.RSLineNewHigh {
+		t.Errorf("ETH: percentile %.0f new high %v", eth.Percentile, eth.RSLineNewHigh)
+	}
+	if math.Abs(eth.Returns[1]-(math.Pow(1.02, 7)-1)) > 1e-9 {
+		t.Errorf("ETH 7d return: got %v", eth.Returns[1])
+	}
+	if expected := math.Pow(1.02/1.01, 7) - 1; math.Abs(eth.VsBenchmark[1]-expected) > 1e-9 {
+		t.Errorf("ETH 7d vs BTC: got %v, expected %v", eth.VsBenchmark[1], expected)
+	}
+	// 60天周期超出数据范围，不计入综合评分
+	if !math.IsNaN(eth.Returns[2]) || !math.IsNaN(result.BasketReturns[2]) || math.IsNaN(eth.Composite) {
+		t.Errorf("uncovered lookback: return %v basket %v composite %v", eth.Returns[2], result.BasketReturns[2], eth.Composite)
+	}
+	sol, _ := result.Score("SOLUSDT")
+	if sol.Percentile != 0 || sol.RSLineNewHigh || sol.Composite >= 0 {
+		t.Errorf("SOL: percentile %.0f composite %.2f", sol.Percentile, sol.Composite)
+	}
+
+	// 截止时间之后的数据不参与计算
+	earlier, err := ra.AnalyzeAt([]string{"ETHUSDT", "SOLUSDT"}, series, start.AddDate(0, 0, 3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if score, _ := earlier.Score("ETHUSDT"); math.Abs(score.Returns[0]-0.02) > 1e-9 || !math.IsNaN(score.Returns[1]) {
+		t.Errorf("as of day 3: got %v", score.Returns)
+	}
+}
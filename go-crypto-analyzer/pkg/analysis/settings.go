@@ -0,0 +1,168 @@
+package analysis
+
+import "fmt"
+
+// Settings are the indicator periods and alert thresholds of the analysis,
+// normally loaded from configs/*.yaml by internal/config
+type Settings struct {
+	// MAPeriods are the moving averages whose alignment scores the MA trend,
+	// shortest first
+	MAPeriods       []int
+	MACDFast        int
+	MACDSlow        int
+	MACDSignal      int
+	RSIPeriod       int
+	ADXPeriod       int
+	BollingerPeriod int
+	BollingerStdDev float64
+	// Ichimoku conversion, base and leading span B periods and the cloud
+	// displacement
+	IchimokuTenkan       int
+	IchimokuKijun        int
+	IchimokuSenkouB      int
+	IchimokuDisplacement int
+	Thresholds           Thresholds
+}
+
+// Thresholds are the alert thresholds shared by the trend analyzer, the
+// evidence collector and the backtest strategies
+type Thresholds struct {
+	// RSI zones: oversold < weak < neutral < strong < overbought
+	RSIOversold   float64
+	RSIWeak       float64
+	RSIStrong     float64
+	RSIOverbought float64
+	// RSIMidline separates bullish from bearish momentum
+	RSIMidline float64
+	// RSIChaseLimit is the RSI above which trend entries would chase the move
+	RSIChaseLimit float64
+	// RSIExtreme is the extremely overbought RSI at which momentum trades exit
+	RSIExtreme float64
+	// Volume ratios to the average above/below which volume is high/low
+	VolumeHigh float64
+	VolumeLow  float64
+	// SignificantChange is the single-candle price change (fraction) that is
+	// reported as significant
+	SignificantChange float64
+	// ADX levels above which a trend is weak < moderate < strong < very strong
+	ADXWeak       float64
+	ADXModerate   float64
+	ADXStrong     float64
+	ADXVeryStrong float64
+	// Strategy thresholds of the improved backtest: the evidence strength
+	// opening longs (positive) and shorts (negative), the volume ratio
+	// confirming an entry and the opposite strength reversing a position
+	LongSignal         float64
+	ShortSignal        float64
+	VolumeConfirmation float64
+	ReversalStrength   float64
+	// Volatility ratios (short- to long-term) below/above which the market
+	// counts as calm/volatile
+	VolatilityLow  float64
+	VolatilityHigh float64
+	// TakeProfit is the profit (fraction) at which a position exits on a
+	// fading trend
+	TakeProfit float64
+}
+
+// DefaultSettings returns the built-in settings, matching configs/default.yaml
+func DefaultSettings() Settings {
+	return Settings{
+		MAPeriods:            []int{5, 10, 20, 50, 200},
+		MACDFast:             12,
+		MACDSlow:             26,
+		MACDSignal:           9,
+		RSIPeriod:            14,
+		ADXPeriod:            14,
+		BollingerPeriod:      20,
+		BollingerStdDev:      2.0,
+		IchimokuTenkan:       9,
+		IchimokuKijun:        26,
+		IchimokuSenkouB:      52,
+		IchimokuDisplacement: 26,
+		Thresholds:           DefaultThresholds(),
+	}
+}
+
+// DefaultThresholds returns the built-in alert thresholds
+func DefaultThresholds() Thresholds {
+	return Thresholds{
+		RSIOversold:        30,
+		RSIWeak:            40,
+		RSIStrong:          60,
+		RSIOverbought:      70,
+		RSIMidline:         50,
+		RSIChaseLimit:      75,
+		RSIExtreme:         80,
+		VolumeHigh:         2.0,
+		VolumeLow:          0.5,
+		SignificantChange:  0.05,
+		ADXWeak:            10,
+		ADXModerate:        20,
+		ADXStrong:          35,
+		ADXVeryStrong:      50,
+		LongSignal:         0.6,
+		ShortSignal:        -0.6,
+		VolumeConfirmation: 1.5,
+		ReversalStrength:   0.8,
+		VolatilityLow:      0.8,
+		VolatilityHigh:     1.5,
+		TakeProfit:         0.05,
+	}
+}
+
+// ApplySettings sets the periods and thresholds of the analyzer
+func (ta *TrendAnalyzer) ApplySettings(settings Settings) error {
+	if len(settings.MAPeriods) == 0 {
+		return fmt.Errorf("no moving average periods")
+	}
+	specs := []string{
+		fmt.Sprintf("rsi(period=%d)", settings.RSIPeriod),
+		fmt.Sprintf("macd(fast=%d,slow=%d,signal=%d)", settings.MACDFast, settings.MACDSlow, settings.MACDSignal),
+		fmt.Sprintf("dmi(period=%d)", settings.ADXPeriod),
+	}
+	for _, spec := range specs {
+		if err := ta.SetCoreIndicator(spec); err != nil {
+			return err
+		}
+	}
+	ta.maPeriods = append([]int{}, settings.MAPeriods...)
+	ta.SetBollingerParams(settings.BollingerPeriod, settings.BollingerStdDev)
+	ta.SetIchimokuParams(settings.IchimokuTenkan, settings.IchimokuKijun, settings.IchimokuSenkouB, settings.IchimokuDisplacement)
+	ta.thresholds = settings.Thresholds
+	return nil
+}
+
+// SetThresholds sets the alert thresholds of the RSI and volume evidence
+func (ec *EvidenceCollector) SetThresholds(thresholds Thresholds) {
+	ec.thresholds = thresholds
+}
+
+// Learning holds the indicator weights and adaptive RSI thresholds of the
+// dynamic analyzer, normally loaded from configs/ml_config.yaml
+type Learning struct {
+	// BaseWeights weigh the evidence categories (MA, MACD, RSI, Volume) in
+	// the fusion decision before the market condition shifts them; they sum to 1
+	BaseWeights map[string]float64
+	// RSIBands are the RSI zones by market: bull_market, bear_market and normal
+	RSIBands map[string]RSIBand
+}
+
+// RSIBand is an oversold/overbought RSI pair
+type RSIBand struct {
+	Oversold   float64
+	Overbought float64
+}
+
+// DefaultLearning returns the built-in learning settings, matching
+// configs/ml_config.yaml
+func DefaultLearning() Learning {
+	return Learning{
+		BaseWeights: map[string]float64{"MA": 0.25, "MACD": 0.25, "RSI": 0.25, "Volume": 0.25},
+		RSIBands: map[string]RSIBand{
+			"bull_market": {Oversold: 35, Overbought: 80},
+			"bear_market": {Oversold: 20, Overbought: 65},
+			"normal":      {Oversold: 30, Overbought: 70},
+		},
+	}
+}
@@ -2,21 +2,329 @@ package analysis
 
 import (
 	"fmt"
+	"math"
+	"sort"
+	"time"
 
+	"github.com/zjc/go-crypto-analyzer/pkg/expr"
 	"github.com/zjc/go-crypto-analyzer/pkg/indicators"
+	"github.com/zjc/go-crypto-analyzer/pkg/stats"
 	"github.com/zjc/go-crypto-analyzer/pkg/types"
 )
 
 // TrendAnalyzer analyzes market trends
 type TrendAnalyzer struct {
 	indicators *indicators.TechnicalIndicators
+	// registry resolves indicator specs; rsiSpec, macdSpec, dmiSpec and
+	// stochRSISpec drive the core momentum/trend analysis, superTrendSpec and
+	// psarSpec the trailing stops, and requested holds extra indicators
+	// reported in Analysis.Indicators
+	registry       *indicators.Registry
+	rsiSpec        indicators.Spec
+	macdSpec       indicators.Spec
+	dmiSpec        indicators.Spec
+	stochRSISpec   indicators.Spec
+	superTrendSpec indicators.Spec
+	psarSpec       indicators.Spec
+	requested      []indicators.Spec
+	// expressions are custom indicator expressions reported in
+	// Analysis.Expressions
+	expressions []*expr.Expression
+	// vwapSessionOffset shifts the daily VWAP session reset from UTC midnight
+	vwapSessionOffset time.Duration
+	// vwapAnchor is an optional user supplied anchor for an extra anchored VWAP
+	vwapAnchor time.Time
+	// Bollinger Band parameters used for channel and squeeze analysis
+	bollingerPeriod int
+	bollingerStdDev float64
+	donchianPeriod  int
+	// Ichimoku conversion, base and leading span B periods and the cloud
+	// displacement
+	ichimokuTenkan       int
+	ichimokuKijun        int
+	ichimokuSenkouB      int
+	ichimokuDisplacement int
+	// Volume profile lookback (candles) and number of price bins
+	volumeProfileLookback int
+	volumeProfileBins     int
+	// Swing strengths clustered into S/R levels, the clustering tolerance (% of
+	// price) and the configured key levels of the analyzed symbol
+	srSwingStrengths []int
+	srTolerance      float64
+	keyLevels        types.KeyLevels
+	// Pivot formula and the completed period (day/week/month) it is computed on
+	pivotMethod indicators.PivotMethod
+	pivotPeriod indicators.PivotPeriod
+	// Lookback (candles) and ZigZag reversal threshold (%) of the dominant
+	// swing used for Fibonacci levels
+	fibLookback  int
+	fibDeviation float64
+	// Candles of the short- and long-term volatility estimates, the Hurst
+	// exponent window and its maximum lag, and the variance ratio horizon
+	volWindow     int
+	volLongWindow int
+	hurstWindow   int
+	hurstMaxLag   int
+	varianceLag   int
+	// Swing confirmation candles of the market structure analysis
+	structureStrength int
+	// maPeriods are the moving averages whose alignment scores the MA trend
+	maPeriods []int
+	// thresholds classify RSI momentum and ADX trend strength
+	thresholds Thresholds
 }
 
 // NewTrendAnalyzer creates a new TrendAnalyzer
 func NewTrendAnalyzer() *TrendAnalyzer {
-	return &TrendAnalyzer{
-		indicators: indicators.NewTechnicalIndicators(),
+	ta := &TrendAnalyzer{
+		indicators:            indicators.NewTechnicalIndicators(),
+		registry:              indicators.DefaultRegistry(),
+		bollingerPeriod:       20,
+		bollingerStdDev:       2.0,
+		donchianPeriod:        20,
+		ichimokuTenkan:        9,
+		ichimokuKijun:         26,
+		ichimokuSenkouB:       52,
+		ichimokuDisplacement:  26,
+		volumeProfileLookback: 100,
+		volumeProfileBins:     30,
+		srSwingStrengths:      []int{2, 5, 10},
+		srTolerance:           0.5,
+		pivotMethod:           indicators.PivotFloor,
+		pivotPeriod:           indicators.PivotDaily,
+		fibLookback:           100,
+		fibDeviation:          2.0,
+		volWindow:             20,
+		volLongWindow:         100,
+		hurstWindow:           100,
+		hurstMaxLag:           20,
+		varianceLag:           4,
+		structureStrength:     3,
+		maPeriods:             DefaultSettings().MAPeriods,
+		thresholds:            DefaultThresholds(),
 	}
+	for _, spec := range []string{"rsi(period=14)", "macd(fast=12,slow=26,signal=9)", "dmi(period=14)", "stochrsi(rsi=14,stoch=14,k=3,d=3)", "supertrend(period=10,multiplier=3)", "psar(step=0.02,max=0.2)"} {
+		if err := ta.SetCoreIndicator(spec); err != nil {
+			panic(err)
+		}
+	}
+	return ta
+}
+
+// SetRegistry sets the registry indicator specs are resolved against
+func (ta *TrendAnalyzer) SetRegistry(registry *indicators.Registry) {
+	ta.registry = registry
+}
+
+// SetCoreIndicator overrides the parameters of one of the indicators driving
+// the core analysis by spec, e.g. "rsi(period=9)" or "macd(8,21,5)". rsi,
+// macd, dmi, stochrsi, supertrend, psar, bbands and donchian are accepted;
+// bbands and donchian set the channel and squeeze parameters. The others
+// (moving averages, atr, obv, keltner) drive no configurable analysis and are
+// requested with RequestIndicators
+func (ta *TrendAnalyzer) SetCoreIndicator(text string) error {
+	spec, err := indicators.ParseSpec(text)
+	if err != nil {
+		return err
+	}
+	resolved, _, err := ta.registry.Resolve(spec)
+	if err != nil {
+		return err
+	}
+	if resolved.Source != "" || resolved.Output != "" {
+		return fmt.Errorf("core indicator %s cannot change source or output", resolved)
+	}
+
+	switch resolved.Name {
+	case "rsi":
+		ta.rsiSpec = resolved
+	case "macd":
+		ta.macdSpec = resolved
+	case "dmi":
+		ta.dmiSpec = resolved
+	case "stochrsi":
+		ta.stochRSISpec = resolved
+	case "supertrend":
+		ta.superTrendSpec = resolved
+	case "psar":
+		ta.psarSpec = resolved
+	case "bbands":
+		params := resolved.Params()
+		ta.SetBollingerParams(params.Int("period"), params["stddev"])
+	case "donchian":
+		ta.SetDonchianPeriod(resolved.Params().Int("period"))
+	default:
+		return fmt.Errorf("%s is not a core indicator (rsi|macd|dmi|stochrsi|supertrend|psar|bbands|donchian), request it with RequestIndicators", resolved.Name)
+	}
+	return nil
+}
+
+// TrailingStopSpecs returns the resolved SuperTrend and Parabolic SAR specs
+// driving Analysis.SuperTrend and Analysis.ParabolicSAR
+func (ta *TrendAnalyzer) TrailingStopSpecs() (superTrend, psar indicators.Spec) {
+	return ta.superTrendSpec, ta.psarSpec
+}
+
+// warmUp returns the index at which the output of a resolved core spec
+// becomes valid, as declared by its registry definition
+func (ta *TrendAnalyzer) warmUp(spec indicators.Spec) int {
+	def, ok := ta.registry.Lookup(spec.Name)
+	if !ok || def.WarmUp == nil {
+		return 0
+	}
+	return def.WarmUp(spec.Params())
+}
+
+// RequestIndicators adds indicators by spec whose latest values are reported
+// in Analysis.Indicators, keyed by the returned canonical specs
+func (ta *TrendAnalyzer) RequestIndicators(texts ...string) ([]string, error) {
+	keys := make([]string, 0, len(texts))
+	for _, text := range texts {
+		spec, err := indicators.ParseSpec(text)
+		if err != nil {
+			return nil, err
+		}
+		resolved, _, err := ta.registry.Resolve(spec)
+		if err != nil {
+			return nil, err
+		}
+
+		key := resolved.String()
+		keys = append(keys, key)
+		duplicate := false
+		for _, existing := range ta.requested {
+			duplicate = duplicate || existing.String() == key
+		}
+		if !duplicate {
+			ta.requested = append(ta.requested, resolved)
+		}
+	}
+	return keys, nil
+}
+
+// AddExpression parses a custom indicator expression such as
+// "ema(close,20) > ema(close,50) and rsi(14) < 40" whose latest value is
+// reported in Analysis.Expressions
+func (ta *TrendAnalyzer) AddExpression(text string) error {
+	e, err := expr.ParseWithRegistry(text, ta.registry)
+	if err != nil {
+		return err
+	}
+	ta.expressions = append(ta.expressions, e)
+	return nil
+}
+
+// SetVWAPSessionOffset sets the time of day (offset from UTC midnight) at which
+// the session VWAP resets
+func (ta *TrendAnalyzer) SetVWAPSessionOffset(offset time.Duration) {
+	ta.vwapSessionOffset = offset
+}
+
+// SetVWAPAnchor sets a custom anchor time for an additional anchored VWAP,
+// a zero time disables it
+func (ta *TrendAnalyzer) SetVWAPAnchor(anchor time.Time) {
+	ta.vwapAnchor = anchor
+}
+
+// SetBollingerParams sets the Bollinger Band period and standard deviation
+// multiplier used for channel and squeeze analysis
+func (ta *TrendAnalyzer) SetBollingerParams(period int, stdDev float64) {
+	ta.bollingerPeriod = period
+	ta.bollingerStdDev = stdDev
+}
+
+// SetIchimokuParams sets the Ichimoku conversion, base and leading span B
+// periods and the cloud displacement
+func (ta *TrendAnalyzer) SetIchimokuParams(tenkan, kijun, senkouB, displacement int) {
+	ta.ichimokuTenkan = tenkan
+	ta.ichimokuKijun = kijun
+	ta.ichimokuSenkouB = senkouB
+	ta.ichimokuDisplacement = displacement
+}
+
+// SetDonchianPeriod sets the lookback of the Donchian breakout channel
+func (ta *TrendAnalyzer) SetDonchianPeriod(period int) {
+	ta.donchianPeriod = period
+}
+
+// SetVolumeProfileParams sets the lookback (in candles) and number of price
+// bins of the volume profile
+func (ta *TrendAnalyzer) SetVolumeProfileParams(lookback, bins int) {
+	ta.volumeProfileLookback = lookback
+	ta.volumeProfileBins = bins
+}
+
+// SetKeyLevels sets the configured key levels merged into the swing-based
+// support/resistance levels
+func (ta *TrendAnalyzer) SetKeyLevels(keyLevels types.KeyLevels) {
+	ta.keyLevels = keyLevels
+}
+
+// SetSRClusterParams sets the swing strengths clustered into support/resistance
+// levels and the clustering tolerance in % of price
+func (ta *TrendAnalyzer) SetSRClusterParams(strengths []int, tolerancePct float64) {
+	ta.srSwingStrengths = strengths
+	ta.srTolerance = tolerancePct
+}
+
+// SetPivotParams sets the pivot formula and the period whose previous
+// completed candle the pivots are computed from
+func (ta *TrendAnalyzer) SetPivotParams(method indicators.PivotMethod, period indicators.PivotPeriod) {
+	ta.pivotMethod = method
+	ta.pivotPeriod = period
+}
+
+// SetFibonacciParams sets the lookback and the ZigZag deviation (%) used to find
+// the dominant swing for Fibonacci levels
+func (ta *TrendAnalyzer) SetFibonacciParams(lookback int, deviationPct float64) {
+	ta.fibLookback = lookback
+	ta.fibDeviation = deviationPct
+}
+
+// SetVolatilityParams sets the short- and long-term volatility windows, the
+// Hurst exponent window and its maximum lag, and the variance ratio horizon
+// (candles)
+func (ta *TrendAnalyzer) SetVolatilityParams(window, longWindow, hurstWindow, hurstMaxLag, varianceLag int) {
+	ta.volWindow = window
+	ta.volLongWindow = longWindow
+	ta.hurstWindow = hurstWindow
+	ta.hurstMaxLag = hurstMaxLag
+	ta.varianceLag = varianceLag
+}
+
+// SetStructureStrength sets the number of candles on each side confirming a
+// swing point of the market structure
+func (ta *TrendAnalyzer) SetStructureStrength(strength int) {
+	ta.structureStrength = strength
+}
+
+// AnalyzeTransformed runs the comprehensive analysis on a Heikin-Ashi or Renko
+// transform of the data, keeping only the last window transformed candles
+// when window > 0. CurrentPrice and Timestamp stay those of the last real
+// candle so consumers never act on synthetic prices
+func (ta *TrendAnalyzer) AnalyzeTransformed(data []types.OHLCV, transform indicators.Transform, window int) (*types.Analysis, error) {
+	if !transform.Active() {
+		if window > 0 && window < len(data) {
+			data = data[len(data)-window:]
+		}
+		return ta.AnalyzeComprehensive(data)
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("no data to transform")
+	}
+
+	series := transform.Apply(data)
+	if window > 0 && window < len(series) {
+		series = series[len(series)-window:]
+	}
+	result, err := ta.AnalyzeComprehensive(series)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %d transformed candles: %w", transform, len(series), err)
+	}
+	last := data[len(data)-1]
+	result.CurrentPrice = last.Close
+	result.Timestamp = last.Time
+	return result, nil
 }
 
 // AnalyzeComprehensive performs comprehensive analysis on OHLCV data
@@ -35,26 +343,87 @@ func (ta *TrendAnalyzer) AnalyzeComprehensive(data []types.OHLCV) (*types.Analys
 	maAnalysis := ta.analyzeMovingAverages(closes)
 
 	// MACD Analysis
-	macdAnalysis := ta.indicators.MACD(closes, 12, 26, 9)
+	macdParams := ta.macdSpec.Params()
+	macdAnalysis := ta.indicators.MACD(closes, macdParams.Int("fast"), macdParams.Int("slow"), macdParams.Int("signal"))
+
+	// Divergence Analysis
+	divergences := ta.analyzeDivergences(data, closes, highs, lows, volumes)
+	for _, div := range divergences {
+		if div.Indicator == "MACD" {
+			macdAnalysis.Divergence = div.Type
+		}
+	}
 
 	// Momentum Analysis
-	rsi := ta.indicators.RSI(closes, 14)
+	rsi := ta.indicators.RSI(closes, ta.rsiSpec.Params().Int("period"))
 	momentumAnalysis := ta.analyzeMomentum(rsi)
+	ta.analyzeStochRSI(closes, &momentumAnalysis)
 
 	// Trend Strength Analysis
-	adx := ta.indicators.ADX(highs, lows, closes, 14)
-	trendStrength := ta.analyzeTrendStrength(adx)
+	dmi := ta.indicators.DMI(highs, lows, closes, ta.dmiSpec.Params().Int("period"))
+	trendStrength := ta.analyzeTrendStrength(dmi)
+
+	// Ichimoku Analysis
+	ichimokuAnalysis := ta.analyzeIchimoku(highs, lows, closes)
+
+	// VWAP Analysis
+	vwapAnalysis := ta.analyzeVWAP(data)
+
+	// Trailing stop indicators
+	stParams, sarParams := ta.superTrendSpec.Params(), ta.psarSpec.Params()
+	superTrend := ta.analyzeTrailingStop(ta.indicators.SuperTrend(highs, lows, closes, stParams.Int("period"), stParams["multiplier"]), closes)
+	parabolicSAR := ta.analyzeTrailingStop(ta.indicators.ParabolicSAR(highs, lows, sarParams["step"], sarParams["max"]), closes)
+
+	// Channels and volatility squeeze
+	channelAnalysis := ta.analyzeChannels(highs, lows, closes)
+
+	// Candlestick patterns completed in the last 3 candles
+	candlePatterns := ta.indicators.DetectCandlePatterns(data, len(data)-3)
+
+	// Chart patterns from swing points
+	chartPatterns := ta.indicators.DetectChartPatterns(highs, lows, closes)
+
+	// Fibonacci levels of the dominant swing
+	fibonacci := ta.analyzeFibonacci(data, highs, lows)
+
+	// Swing structure (HH/HL/LH/LL) and its breaks, an independent trend
+	// classification next to the MA trend
+	structure := ta.indicators.MarketStructure(data, ta.structureStrength)
+
+	// Realized volatility and trending/mean-reverting regime
+	volatility := ta.analyzeVolatility(data, closes)
 
 	// Volume Analysis
 	volumeAnalysis := ta.indicators.VolumeAnalysis(volumes, 20)
+	// Relabel with the configured high/low volume ratios
+	if volumeAnalysis.VolumeMA > 0 {
+		volumeAnalysis.VolumeTrend = types.NormalVolume
+		if volumeAnalysis.VolumeRatio > ta.thresholds.VolumeHigh {
+			volumeAnalysis.VolumeTrend = types.HighVolume
+		} else if volumeAnalysis.VolumeRatio < ta.thresholds.VolumeLow {
+			volumeAnalysis.VolumeTrend = types.LowVolume
+		}
+	}
 
 	// Support and Resistance
-	lastCandle := data[len(data)-1]
-	srAnalysis := ta.indicators.PivotPoints(lastCandle.High, lastCandle.Low, lastCandle.Close)
+	srAnalysis := ta.analyzePivots(data)
+	profile := ta.indicators.VolumeProfile(data, ta.volumeProfileLookback, ta.volumeProfileBins, 0.7)
+	srAnalysis.POC = profile.POC
+	srAnalysis.VAH = profile.VAH
+	srAnalysis.VAL = profile.VAL
+	srAnalysis.HVN = profile.HVN
+	srAnalysis.LVN = profile.LVN
+	ta.analyzeSRLevels(data, &srAnalysis)
 
 	// Overall trend determination
 	overallTrend, trendScore := ta.determineOverallTrend(maAnalysis, macdAnalysis, momentumAnalysis)
 
+	// Custom expressions
+	expressions, err := ta.evaluateExpressions(data)
+	if err != nil {
+		return nil, err
+	}
+
 	return &types.Analysis{
 		Symbol:            "", // Will be set by caller
 		CurrentPrice:      closes[len(closes)-1],
@@ -67,9 +436,187 @@ func (ta *TrendAnalyzer) AnalyzeComprehensive(data []types.OHLCV) (*types.Analys
 		TrendStrength:     trendStrength,
 		Volume:            volumeAnalysis,
 		SupportResistance: srAnalysis,
+		Divergences:       divergences,
+		Ichimoku:          ichimokuAnalysis,
+		VWAP:              vwapAnalysis,
+		SuperTrend:        superTrend,
+		ParabolicSAR:      parabolicSAR,
+		Channels:          channelAnalysis,
+		CandlePatterns:    candlePatterns,
+		ChartPatterns:     chartPatterns,
+		Fibonacci:         fibonacci,
+		Volatility:        volatility,
+		Structure:         structure,
+		Indicators:        ta.computeRequested(data),
+		Expressions:       expressions,
 	}, nil
 }
 
+// evaluateExpressions evaluates the custom expressions on the latest candle.
+// Expressions undefined there, e.g. conditions on an indicator still in its
+// warm-up period, are reported as not valid
+func (ta *TrendAnalyzer) evaluateExpressions(data []types.OHLCV) ([]types.ExpressionResult, error) {
+	if len(ta.expressions) == 0 {
+		return nil, nil
+	}
+
+	results := make([]types.ExpressionResult, 0, len(ta.expressions))
+	for _, e := range ta.expressions {
+		value, ok, err := e.Last(data)
+		if err != nil {
+			return nil, fmt.Errorf("expression %s: %w", e.Text(), err)
+		}
+		results = append(results, types.ExpressionResult{
+			Expression: e.Text(),
+			Value:      value,
+			Boolean:    e.IsBoolean(),
+			Valid:      ok,
+		})
+	}
+	return results, nil
+}
+
+// computeRequested evaluates the requested indicators on the data, skipping
+// those still in their warm-up period
+func (ta *TrendAnalyzer) computeRequested(data []types.OHLCV) map[string]float64 {
+	if len(ta.requested) == 0 {
+		return nil
+	}
+
+	inputs := indicators.InputsFromOHLCV(data)
+	values := make(map[string]float64, len(ta.requested))
+	for _, spec := range ta.requested {
+		result, err := ta.registry.ComputeSpec(spec, inputs)
+		if err != nil {
+			continue
+		}
+		if value, ok := result.Last(); ok {
+			values[spec.String()] = value
+		}
+	}
+	return values
+}
+
+// analyzePivots computes pivots from the previous completed day/week/month,
+// falling back to the last candle when the data holds no completed period
+func (ta *TrendAnalyzer) analyzePivots(data []types.OHLCV) types.SRAnalysis {
+	sr, periodStart, partial, ok := ta.indicators.PeriodPivots(data, ta.pivotMethod, ta.pivotPeriod)
+	if !ok {
+		last := data[len(data)-1]
+		sr = ta.indicators.PivotLevels(ta.pivotMethod, last.Open, last.High, last.Low, last.Close, last.Close)
+	} else {
+		sr.PivotPeriod = string(ta.pivotPeriod)
+		sr.PivotPeriodStart = periodStart
+		sr.PivotPartial = partial
+	}
+	sr.PivotMethod = string(ta.pivotMethod)
+	return sr
+}
+
+// analyzeFibonacci computes retracements and extensions of the dominant ZigZag
+// swing and the level price is testing (within 0.3%)
+func (ta *TrendAnalyzer) analyzeFibonacci(data []types.OHLCV, highs, lows []float64) types.FibonacciAnalysis {
+	high, low, ok := ta.indicators.DominantSwing(highs, lows, ta.fibLookback, ta.fibDeviation)
+	if !ok {
+		return types.FibonacciAnalysis{}
+	}
+
+	fib := types.FibonacciAnalysis{
+		Available:     true,
+		Upswing:       low.Index < high.Index,
+		SwingHigh:     high.Price,
+		SwingLow:      low.Price,
+		SwingHighTime: data[high.Index].Time,
+		SwingLowTime:  data[low.Index].Time,
+	}
+	fib.Retracements, fib.Extensions = indicators.FibonacciLevels(high.Price, low.Price, fib.Upswing)
+
+	price := data[len(data)-1].Close
+	for _, level := range fib.Levels() {
+		distance := (price - level.Price) / price * 100
+		if math.Abs(distance) <= 0.3 && (fib.Testing.Ratio == 0 || math.Abs(distance) < math.Abs(fib.TestingDistance)) {
+			fib.Testing = level
+			fib.TestingDistance = distance
+		}
+	}
+	return fib
+}
+
+// analyzeVolatility estimates realized volatility with every estimator,
+// annualized by the inferred candle interval, and classifies the regime from
+// the Hurst exponent and variance ratio. Windows longer than the data are
+// shrunk to fit
+func (ta *TrendAnalyzer) analyzeVolatility(data []types.OHLCV, closes []float64) types.VolatilityAnalysis {
+	interval, ok := stats.InferInterval(data)
+	if !ok {
+		return types.VolatilityAnalysis{}
+	}
+	window := ta.volWindow
+	longWindow := ta.volLongWindow
+	if longWindow > len(data)-1 {
+		longWindow = len(data) - 1
+	}
+	if window > longWindow {
+		window = longWindow
+	}
+
+	vol := types.VolatilityAnalysis{
+		Interval:       interval,
+		PeriodsPerYear: stats.PeriodsPerYear(interval),
+		Window:         window,
+		LongWindow:     longWindow,
+		Regime:         stats.RegimeRandomWalk,
+	}
+	annualize := math.Sqrt(vol.PeriodsPerYear)
+	estimates := map[stats.Estimator]*float64{
+		stats.CloseToClose:   &vol.CloseToClose,
+		stats.Parkinson:      &vol.Parkinson,
+		stats.GarmanKlass:    &vol.GarmanKlass,
+		stats.RogersSatchell: &vol.RogersSatchell,
+		stats.YangZhang:      &vol.YangZhang,
+	}
+	for estimator, target := range estimates {
+		if sigma, ok := stats.Volatility(data, estimator, window); ok {
+			*target = sigma * annualize
+		}
+	}
+	if sigma, ok := stats.Volatility(data, stats.YangZhang, longWindow); ok {
+		vol.LongTerm = sigma * annualize
+	}
+	if vol.LongTerm > 0 {
+		vol.Ratio = vol.YangZhang / vol.LongTerm
+	}
+	vol.Available = vol.YangZhang > 0
+
+	hurstCloses := closes
+	if ta.hurstWindow > 0 && ta.hurstWindow < len(closes) {
+		hurstCloses = closes[len(closes)-ta.hurstWindow:]
+	}
+	vol.Hurst, vol.HurstAvailable = stats.Hurst(hurstCloses, ta.hurstMaxLag)
+	vr, z, vrOK := stats.VarianceRatio(hurstCloses, ta.varianceLag)
+	vol.VarianceRatio, vol.VarianceRatioZ = vr, z
+	if vol.HurstAvailable && vrOK {
+		vol.Regime = stats.ClassifyRegime(vol.Hurst, vol.VarianceRatio)
+	}
+	return vol
+}
+
+// analyzeSRLevels clusters swing points into support/resistance levels, merges
+// the configured key levels and picks the nearest levels around the price
+func (ta *TrendAnalyzer) analyzeSRLevels(data []types.OHLCV, sr *types.SRAnalysis) {
+	levels := ta.indicators.SwingSRLevels(data, ta.srSwingStrengths, ta.srTolerance)
+	sr.Levels = indicators.MergeKeyLevels(levels, ta.keyLevels, ta.srTolerance)
+
+	price := data[len(data)-1].Close
+	sr.NearestResistance, sr.NearestSupport = indicators.NearestSRLevels(sr.Levels, price)
+	if sr.NearestResistance.Price > 0 {
+		sr.ResistanceDistance = (sr.NearestResistance.Price - price) / price * 100
+	}
+	if sr.NearestSupport.Price > 0 {
+		sr.SupportDistance = (price - sr.NearestSupport.Price) / price * 100
+	}
+}
+
 // analyzeMovingAverages analyzes moving average trends
 func (ta *TrendAnalyzer) analyzeMovingAverages(closes []float64) types.MAAnalysis {
 	ma5 := ta.indicators.SMA(closes, 5)
@@ -87,33 +634,27 @@ func (ta *TrendAnalyzer) analyzeMovingAverages(closes []float64) types.MAAnalysi
 	lastMA50 := getLastValue(ma50, 50)
 	lastMA200 := getLastValue(ma200, 200)
 
-	// Calculate MA signals
-	signals := 0.0
-	if currentPrice > lastMA5 {
-		signals += 1
-	} else {
-		signals -= 1
-	}
-
-	if lastMA5 > lastMA10 {
-		signals += 1
-	} else {
-		signals -= 1
+	// Score the alignment of price and the configured MAs that have enough
+	// data: price above the shortest MA and each MA above the next longer one
+	chain := []float64{currentPrice}
+	for _, period := range ta.maPeriods {
+		if len(closes) >= period {
+			chain = append(chain, getLastValue(ta.indicators.SMA(closes, period), period))
+		}
 	}
-
-	if lastMA10 > lastMA20 {
-		signals += 1
-	} else {
-		signals -= 1
+	signals := 0.0
+	for i := 1; i < len(chain); i++ {
+		if chain[i-1] > chain[i] {
+			signals += 1
+		} else {
+			signals -= 1
+		}
 	}
 
-	if lastMA20 > lastMA50 {
-		signals += 1
-	} else {
-		signals -= 1
+	score := 0.0
+	if len(chain) > 1 {
+		score = signals / float64(len(chain)-1)
 	}
-
-	score := signals / 4.0
 	trend := ta.classifyMAScore(score)
 
 	return types.MAAnalysis{
@@ -128,6 +669,39 @@ func (ta *TrendAnalyzer) analyzeMovingAverages(closes []float64) types.MAAnalysi
 	}
 }
 
+// analyzeDivergences detects price/oscillator divergences against the MACD
+// histogram, RSI and OBV, keeping only those whose later swing is recent
+func (ta *TrendAnalyzer) analyzeDivergences(data []types.OHLCV, closes, highs, lows, volumes []float64) []types.Divergence {
+	const swingStrength = 3
+	const maxBarsAgo = 20
+
+	macdParams := ta.macdSpec.Params()
+	_, _, histogram := ta.indicators.MACDSeries(closes, macdParams.Int("fast"), macdParams.Int("slow"), macdParams.Int("signal"))
+	rsi := ta.indicators.RSISeries(closes, ta.rsiSpec.Params().Int("period"))
+	obv := ta.indicators.OBV(closes, volumes)
+
+	candidates := ta.indicators.DetectDivergences("MACD", highs, lows, histogram, swingStrength, ta.warmUp(ta.macdSpec))
+	candidates = append(candidates, ta.indicators.DetectDivergences("RSI", highs, lows, rsi, swingStrength, ta.warmUp(ta.rsiSpec))...)
+	candidates = append(candidates, ta.indicators.DetectDivergences("OBV", highs, lows, obv, swingStrength, 1)...)
+
+	divergences := make([]types.Divergence, 0)
+	for _, div := range candidates {
+		if div.BarsAgo > maxBarsAgo {
+			continue
+		}
+		div.StartTime = data[div.StartIndex].Time
+		div.EndTime = data[div.EndIndex].Time
+		divergences = append(divergences, div)
+	}
+
+	// Oldest first so the latest divergence of each indicator wins
+	sort.SliceStable(divergences, func(i, j int) bool {
+		return divergences[i].EndIndex < divergences[j].EndIndex
+	})
+
+	return divergences
+}
+
 // classifyMAScore classifies the MA score into a trend
 func (ta *TrendAnalyzer) classifyMAScore(score float64) types.TrendDirection {
 	if score > 0.75 {
@@ -144,15 +718,15 @@ func (ta *TrendAnalyzer) classifyMAScore(score float64) types.TrendDirection {
 
 // analyzeMomentum analyzes momentum indicators
 func (ta *TrendAnalyzer) analyzeMomentum(rsi float64) types.MomentumAnalysis {
-	momentum := "中性"
-	if rsi > 70 {
-		momentum = "超买"
-	} else if rsi > 60 {
-		momentum = "强势"
-	} else if rsi < 30 {
-		momentum = "超卖"
-	} else if rsi < 40 {
-		momentum = "弱势"
+	momentum := types.MomentumNeutral
+	if rsi > ta.thresholds.RSIOverbought {
+		momentum = types.MomentumOverbought
+	} else if rsi > ta.thresholds.RSIStrong {
+		momentum = types.MomentumStrong
+	} else if rsi < ta.thresholds.RSIOversold {
+		momentum = types.MomentumOversold
+	} else if rsi < ta.thresholds.RSIWeak {
+		momentum = types.MomentumWeak
 	}
 
 	return types.MomentumAnalysis{
@@ -161,22 +735,287 @@ func (ta *TrendAnalyzer) analyzeMomentum(rsi float64) types.MomentumAnalysis {
 	}
 }
 
+// analyzeStochRSI adds Stochastic RSI values and recent extreme-zone K/D crosses
+func (ta *TrendAnalyzer) analyzeStochRSI(closes []float64, momentum *types.MomentumAnalysis) {
+	params := ta.stochRSISpec.Params()
+	kLine, dLine := ta.indicators.StochasticRSISeries(closes, params.Int("rsi"), params.Int("stoch"), params.Int("k"), params.Int("d"))
+	last := len(kLine) - 1
+	momentum.StochRSIK = kLine[last]
+	momentum.StochRSID = dLine[last]
+
+	// Only crosses in the last 3 candles are actionable
+	events := indicators.StochCrossEvents(kLine, dLine, ta.warmUp(ta.stochRSISpec)+1, 80, 20)
+	if len(events) > 0 {
+		latest := events[len(events)-1]
+		if barsAgo := last - latest.Index; barsAgo < 3 {
+			if latest.Direction > 0 {
+				momentum.StochRSICross = types.OversoldGoldenCross
+			} else {
+				momentum.StochRSICross = types.OverboughtDeathCross
+			}
+			momentum.StochRSICrossBarsAgo = barsAgo
+		}
+	}
+}
+
+// analyzeIchimoku analyzes the Ichimoku cloud, TK cross and Chikou span
+func (ta *TrendAnalyzer) analyzeIchimoku(highs, lows, closes []float64) types.IchimokuAnalysis {
+	tenkanPeriod, kijunPeriod, senkouBPeriod, displacement := ta.ichimokuTenkan, ta.ichimokuKijun, ta.ichimokuSenkouB, ta.ichimokuDisplacement
+
+	// The current cloud needs senkouBPeriod candles plus the displacement
+	n := len(closes)
+	if n < senkouBPeriod+displacement {
+		return types.IchimokuAnalysis{}
+	}
+
+	ich := ta.indicators.Ichimoku(highs, lows, closes, tenkanPeriod, kijunPeriod, senkouBPeriod, displacement)
+	last := n - 1
+	price := closes[last]
+
+	result := types.IchimokuAnalysis{
+		Available:     true,
+		Tenkan:        ich.Tenkan[last],
+		Kijun:         ich.Kijun[last],
+		SenkouA:       ich.SenkouA[last],
+		SenkouB:       ich.SenkouB[last],
+		FutureSenkouA: ich.SenkouA[last+displacement],
+		FutureSenkouB: ich.SenkouB[last+displacement],
+		Chikou:        price,
+	}
+	result.CloudTop = math.Max(result.SenkouA, result.SenkouB)
+	result.CloudBottom = math.Min(result.SenkouA, result.SenkouB)
+	result.CloudThickness = (result.CloudTop - result.CloudBottom) / price * 100
+
+	result.CloudColor = types.BullishCloud
+	if result.FutureSenkouA < result.FutureSenkouB {
+		result.CloudColor = types.BearishCloud
+	}
+	result.PricePosition = cloudPosition(price, result.CloudTop, result.CloudBottom)
+
+	// TK cross and where it happened relative to the cloud
+	direction, barsAgo := indicators.LastCross(ich.Tenkan, ich.Kijun, 5)
+	if direction != 0 {
+		result.TKCross = types.GoldenCross
+		if direction < 0 {
+			result.TKCross = types.DeathCross
+		}
+		idx := last - barsAgo
+		crossLevel := (ich.Tenkan[idx] + ich.Kijun[idx]) / 2
+		top := math.Max(ich.SenkouA[idx], ich.SenkouB[idx])
+		bottom := math.Min(ich.SenkouA[idx], ich.SenkouB[idx])
+		result.TKCrossPosition = cloudPosition(crossLevel, top, bottom)
+		result.TKCrossBarsAgo = barsAgo
+	}
+
+	// Chikou confirmation against the candle it is plotted next to
+	past := last - displacement
+	if price > highs[past] {
+		result.ChikouStatus = types.ChikouAbove
+	} else if price < lows[past] {
+		result.ChikouStatus = types.ChikouBelow
+	} else {
+		result.ChikouStatus = types.ChikouTangled
+	}
+
+	// Next twist in the projected cloud
+	for _, idx := range indicators.KumoTwists(ich.SenkouA, ich.SenkouB, last+1) {
+		result.TwistBarsAhead = idx - last
+		break
+	}
+
+	return result
+}
+
+func cloudPosition(value, top, bottom float64) types.CloudPosition {
+	if value > top {
+		return types.AboveCloud
+	} else if value < bottom {
+		return types.BelowCloud
+	}
+	return types.InsideCloud
+}
+
+// analyzeVWAP analyzes the session VWAP bands and VWAPs anchored at the last
+// major swing points
+func (ta *TrendAnalyzer) analyzeVWAP(data []types.OHLCV) types.VWAPAnalysis {
+	// A swing needs 10 lower/higher candles on each side to count as major
+	const majorSwingStrength = 10
+
+	last := len(data) - 1
+	price := data[last].Close
+	session := ta.indicators.SessionVWAP(data, ta.vwapSessionOffset)
+
+	result := types.VWAPAnalysis{
+		SessionStart: indicators.SessionStart(data[last].Time, ta.vwapSessionOffset),
+		SessionVWAP:  session.VWAP[last],
+		StdDev:       session.StdDev[last],
+		Upper1:       session.Upper1[last],
+		Lower1:       session.Lower1[last],
+		Upper2:       session.Upper2[last],
+		Lower2:       session.Lower2[last],
+	}
+
+	switch {
+	case price > result.Upper2:
+		result.BandPosition = types.AboveUpper2
+	case price > result.Upper1:
+		result.BandPosition = types.Upper1To2
+	case price >= result.SessionVWAP:
+		result.BandPosition = types.VWAPToUpper1
+	case price >= result.Lower1:
+		result.BandPosition = types.Lower1ToVWAP
+	case price >= result.Lower2:
+		result.BandPosition = types.Lower1To2
+	default:
+		result.BandPosition = types.BelowLower2
+	}
+
+	if idx := indicators.LastMajorSwing(data, majorSwingStrength, false); idx >= 0 {
+		result.AnchoredLow = ta.indicators.AnchoredVWAP(data, idx).VWAP[last]
+		result.AnchoredLowTime = data[idx].Time
+	}
+	if idx := indicators.LastMajorSwing(data, majorSwingStrength, true); idx >= 0 {
+		result.AnchoredHigh = ta.indicators.AnchoredVWAP(data, idx).VWAP[last]
+		result.AnchoredHighTime = data[idx].Time
+	}
+	if !ta.vwapAnchor.IsZero() {
+		if idx := indicators.AnchorIndex(data, ta.vwapAnchor); idx >= 0 {
+			result.AnchoredCustom = ta.indicators.AnchoredVWAP(data, idx).VWAP[last]
+			result.AnchoredCustomTime = data[idx].Time
+		}
+	}
+
+	return result
+}
+
+// analyzeTrailingStop summarizes the current state of a SuperTrend or
+// Parabolic SAR series
+func (ta *TrendAnalyzer) analyzeTrailingStop(series indicators.TrailingResult, closes []float64) types.TrailingStopAnalysis {
+	last := len(closes) - 1
+	if series.Direction[last] == 0 {
+		return types.TrailingStopAnalysis{}
+	}
+
+	price := closes[last]
+	result := types.TrailingStopAnalysis{
+		Available:   true,
+		Level:       series.Level[last],
+		Direction:   types.LongSide,
+		Distance:    math.Abs(price-series.Level[last]) / price * 100,
+		FlipBarsAgo: -1,
+	}
+	if series.Direction[last] < 0 {
+		result.Direction = types.ShortSide
+	}
+
+	if flips := series.FlipEvents(1); len(flips) > 0 {
+		result.FlipBarsAgo = last - flips[len(flips)-1].Index
+	}
+
+	return result
+}
+
+// analyzeChannels analyzes Bollinger %B/bandwidth, Keltner and Donchian
+// channels and the TTM squeeze
+func (ta *TrendAnalyzer) analyzeChannels(highs, lows, closes []float64) types.ChannelAnalysis {
+	const keltnerMultiplier = 1.5
+
+	period, stdDev, donchianPeriod := ta.bollingerPeriod, ta.bollingerStdDev, ta.donchianPeriod
+	n := len(closes)
+	if n < 2*period || n < donchianPeriod+1 {
+		return types.ChannelAnalysis{}
+	}
+	last := n - 1
+	price := closes[last]
+
+	bbUpper, bbMiddle, bbLower := ta.indicators.BollingerBands(closes, period, stdDev)
+	percentB, bandwidth := ta.indicators.BollingerStats(closes, period, stdDev)
+	kcUpper, kcMiddle, kcLower := ta.indicators.KeltnerChannels(highs, lows, closes, period, period, keltnerMultiplier)
+	dcUpper, _, dcLower := ta.indicators.DonchianChannels(highs, lows, donchianPeriod)
+	squeeze := ta.indicators.TTMSqueeze(highs, lows, closes, period, stdDev, keltnerMultiplier)
+
+	result := types.ChannelAnalysis{
+		Available:       true,
+		BollingerPeriod: period,
+		BollingerStdDev: stdDev,
+		BBUpper:         bbUpper[last],
+		BBMiddle:        bbMiddle[last],
+		BBLower:         bbLower[last],
+		PercentB:        percentB[last],
+		Bandwidth:       bandwidth[last],
+		KCUpper:         kcUpper[last],
+		KCMiddle:        kcMiddle[last],
+		KCLower:         kcLower[last],
+		DonchianPeriod:  donchianPeriod,
+		DonchianUpper:   dcUpper[last],
+		DonchianLower:   dcLower[last],
+		Squeeze:         squeeze.On[last],
+		SqueezeMomentum: squeeze.Momentum[last],
+	}
+
+	// Bandwidth percentile over the analyzed candles
+	below, total := 0, 0
+	for i := period - 1; i < n; i++ {
+		total++
+		if bandwidth[i] < bandwidth[last] {
+			below++
+		}
+	}
+	result.BandwidthRank = float64(below) / float64(total) * 100
+
+	// Donchian breakout against the previous candle's channel
+	if price > dcUpper[last-1] {
+		result.DonchianBreakout = types.BreakoutUp
+	} else if price < dcLower[last-1] {
+		result.DonchianBreakout = types.BreakoutDown
+	}
+
+	for i := last; i >= 0 && squeeze.On[i]; i-- {
+		result.SqueezeBars++
+	}
+	if releases := squeeze.Releases(last - 2); len(releases) > 0 {
+		release := releases[len(releases)-1]
+		result.SqueezeFired = types.SqueezeFiredUp
+		if release.Direction < 0 {
+			result.SqueezeFired = types.SqueezeFiredDown
+		}
+		result.SqueezeFiredBarsAgo = last - release.Index
+	}
+
+	return result
+}
+
 // analyzeTrendStrength analyzes trend strength
-func (ta *TrendAnalyzer) analyzeTrendStrength(adx float64) types.TrendStrengthAnalysis {
+func (ta *TrendAnalyzer) analyzeTrendStrength(dmi indicators.DMIResult) types.TrendStrengthAnalysis {
+	last := len(dmi.ADX) - 1
+	adx := dmi.ADX[last]
 	strength := types.NoTrend
-	if adx > 50 {
+	if adx > ta.thresholds.ADXVeryStrong {
 		strength = types.VeryStrong
-	} else if adx > 35 {
+	} else if adx > ta.thresholds.ADXStrong {
 		strength = types.Strong
-	} else if adx > 20 {
+	} else if adx > ta.thresholds.ADXModerate {
 		strength = types.Moderate
-	} else if adx > 10 {
+	} else if adx > ta.thresholds.ADXWeak {
 		strength = types.Weak
 	}
 
+	// Most recent DI crossover within the last 5 candles
+	var diCross types.CrossType
+	direction, barsAgo := indicators.LastCross(dmi.PlusDI, dmi.MinusDI, 5)
+	if direction > 0 {
+		diCross = types.GoldenCross
+	} else if direction < 0 {
+		diCross = types.DeathCross
+	}
+
 	return types.TrendStrengthAnalysis{
-		ADX:      adx,
-		Strength: strength,
+		ADX:            adx,
+		PlusDI:         dmi.PlusDI[last],
+		MinusDI:        dmi.MinusDI[last],
+		Strength:       strength,
+		DICross:        diCross,
+		DICrossBarsAgo: barsAgo,
 	}
 }
 
@@ -194,16 +1033,16 @@ func (ta *TrendAnalyzer) determineOverallTrend(ma types.MAAnalysis, macd types.M
 	}
 
 	// MACD contribution
-	if macd.Trend == "看涨" {
+	if macd.Trend == types.MACDBullish {
 		trendScore += 1
-	} else if macd.Trend == "看跌" {
+	} else if macd.Trend == types.MACDBearish {
 		trendScore -= 1
 	}
 
 	// Momentum contribution
-	if momentum.Momentum == "超买" || momentum.Momentum == "强势" {
+	if momentum.Momentum == types.MomentumOverbought || momentum.Momentum == types.MomentumStrong {
 		trendScore += 1
-	} else if momentum.Momentum == "超卖" || momentum.Momentum == "弱势" {
+	} else if momentum.Momentum == types.MomentumOversold || momentum.Momentum == types.MomentumWeak {
 		trendScore -= 1
 	}
 
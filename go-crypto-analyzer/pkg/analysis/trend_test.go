@@ -0,0 +1,54 @@
+package analysis
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/zjc/go-crypto-analyzer/pkg/indicators"
+	"github.com/zjc/go-crypto-analyzer/pkg/types"
+)
+
+func TestSetCoreIndicator(t *testing.T) {
+	ta := NewTrendAnalyzer()
+	for _, spec := range []string{"rsi(9)", "supertrend(period=7,multiplier=2)", "psar(step=0.01)", "bbands(period=14,stddev=2.5)", "donchian(30)"} {
+		if err := ta.SetCoreIndicator(spec); err != nil {
+			t.Fatalf("%s: %v", spec, err)
+		}
+	}
+	if superTrend, psar := ta.TrailingStopSpecs(); superTrend.String() != "supertrend(period=7,multiplier=2)" || psar.String() != "psar(step=0.01,max=0.2)" {
+		t.Errorf("trailing specs: %s %s", superTrend, psar)
+	}
+	if ta.bollingerPeriod != 14 || ta.bollingerStdDev != 2.5 || ta.donchianPeriod != 30 {
+		t.Errorf("channel params: %d %g %d", ta.bollingerPeriod, ta.bollingerStdDev, ta.donchianPeriod)
+	}
+
+	// 没有可配置分析的指标要用 RequestIndicators 请求
+	if err := ta.SetCoreIndicator("ema(21)"); err == nil || !strings.Contains(err.Error(), "RequestIndicators") {
+		t.Errorf("expected ema to be rejected as a core indicator, got %v", err)
+	}
+	if err := ta.SetCoreIndicator("macd(8,21,5).signal"); err == nil {
+		t.Error("expected an output selector to be rejected")
+	}
+}
+
+func TestApplySettingsIchimokuAndADX(t *testing.T) {
+	ta := NewTrendAnalyzer()
+	settings := DefaultSettings()
+	settings.IchimokuTenkan, settings.IchimokuKijun, settings.IchimokuSenkouB, settings.IchimokuDisplacement = 7, 22, 44, 22
+	settings.Thresholds.ADXStrong, settings.Thresholds.ADXVeryStrong = 30, 40
+	if err := ta.ApplySettings(settings); err != nil {
+		t.Fatal(err)
+	}
+	if ta.ichimokuTenkan != 7 || ta.ichimokuKijun != 22 || ta.ichimokuSenkouB != 44 || ta.ichimokuDisplacement != 22 {
+		t.Errorf("ichimoku params: %d %d %d %d", ta.ichimokuTenkan, ta.ichimokuKijun, ta.ichimokuSenkouB, ta.ichimokuDisplacement)
+	}
+
+	// ADX 45 is very strong under the configured cut-offs but only strong by default
+	dmi := indicators.DMIResult{ADX: []float64{45}, PlusDI: []float64{30}, MinusDI: []float64{10}}
+	if got := ta.analyzeTrendStrength(dmi).Strength; got != types.VeryStrong {
+		t.Errorf("configured strength %v, want %v", got, types.VeryStrong)
+	}
+	if got := NewTrendAnalyzer().analyzeTrendStrength(dmi).Strength; got != types.Strong {
+		t.Errorf("default strength %v, want %v", got, types.Strong)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/zjc/go-crypto-analyzer/pkg/analysis"
+	"github.com/zjc/go-crypto-analyzer/pkg/i18n"
+	"github.com/zjc/go-crypto-analyzer/pkg/indicators"
 	"github.com/zjc/go-crypto-analyzer/pkg/types"
 )
 
@@ -34,15 +36,35 @@ type BacktestResult struct {
 type Trade struct {
 	EntryTime   time.Time
 	EntryPrice  float64
-	EntrySignal string
+	EntrySignal Reason
 	ExitTime    time.Time
 	ExitPrice   float64
-	ExitSignal  string
+	ExitSignal  Reason
 	Profit      float64
 	ProfitPct   float64
 	Holding     float64 // 持仓数量
 }
 
+// Reason 入场/出场原因：消息目录中 reason.<Code> 的代码及其格式化参数，
+// 显示时按当前语言渲染
+type Reason struct {
+	Code string
+	Args []interface{}
+}
+
+// NewReason 创建入场/出场原因
+func NewReason(code string, args ...interface{}) Reason {
+	return Reason{Code: code, Args: args}
+}
+
+// String 以当前语言渲染原因，参数中的Reason（如组合策略的子策略原因）同样渲染
+func (r Reason) String() string {
+	if r.Code == "" {
+		return ""
+	}
+	return i18n.T("reason."+r.Code, r.Args...)
+}
+
 // Backtester 回测器
 type Backtester struct {
 	analyzer          *analysis.TrendAnalyzer
@@ -62,6 +84,15 @@ type Backtester struct {
 	// 新增：策略接口
 	strategy        TradingStrategy
 	useStrategy     bool
+	
+	// 分析所用的K线变换，成交仍按真实价格
+	transform       indicators.Transform
+	
+	// 观察列表相对强度，nil表示不计算
+	relativeStrength *relativeStrengthFilter
+	
+	// 警报阈值，设置策略时传给使用RSI阈值的策略
+	thresholds analysis.Thresholds
 }
 
 // NewBacktester 创建回测器
@@ -76,6 +107,7 @@ func NewBacktester(initialCapital float64) *Backtester {
 		exitThreshold:     -0.2,   // 反向信号平仓
 		stopLoss:          0.05,   // 5%止损
 		takeProfit:        0.10,   // 10%止盈
+		thresholds:        analysis.DefaultThresholds(),
 	}
 }
 
@@ -98,7 +130,7 @@ func (bt *Backtester) RunBacktest(symbol string, data []types.OHLCV) (*BacktestR
 	position := 0.0          // 当前持仓
 	entryPrice := 0.0        // 入场价格
 	entryTime := time.Time{} // 入场时间
-	entrySignal := ""        // 入场信号
+	entrySignal := Reason{}  // 入场信号
 	maxCapital := capital    // 最高资金
 	
 	// 滑动窗口分析
@@ -108,18 +140,19 @@ func (bt *Backtester) RunBacktest(symbol string, data []types.OHLCV) (*BacktestR
 		currentPrice := window[len(window)-1].Close
 		currentTime := window[len(window)-1].Time
 		
-		// 执行技术分析
-		analysisResult, err := bt.analyzer.AnalyzeComprehensive(window)
+		// 执行技术分析（变换序列只用于分析，不影响成交价格）
+		analysisResult, err := bt.analyzer.AnalyzeTransformed(data[:i+1], bt.transform, len(window))
 		if err != nil {
 			continue
 		}
+		bt.relativeStrength.score(symbol, data[:i+1], analysisResult)
 		
 		// 收集证据
 		bt.evidenceCollector.Clear()
-		bt.evidenceCollector.AnalyzeMAEvidence(analysisResult.MAAnalysis, currentPrice)
-		bt.evidenceCollector.AnalyzeMACDEvidence(analysisResult.MACDAnalysis)
-		bt.evidenceCollector.AnalyzeRSIEvidence(analysisResult.Momentum.RSI)
-		bt.evidenceCollector.AnalyzeSREvidence(currentPrice, analysisResult.SupportResistance)
+		// 第一版回测从未统计成交量证据，价格变化传0保持原有行为（成交量规则要求涨跌非零）
+		if err := bt.evidenceCollector.Collect(analysisResult, 0); err != nil {
+			return nil, err
+		}
 		
 		// 获取信号强度
 		summary := bt.evidenceCollector.GetSummary()
@@ -141,7 +174,7 @@ func (bt *Backtester) RunBacktest(symbol string, data []types.OHLCV) (*BacktestR
 					EntrySignal: entrySignal,
 					ExitTime:    currentTime,
 					ExitPrice:   exitPrice,
-					ExitSignal:  "止损",
+					ExitSignal:  NewReason("stop_loss"),
 					Profit:      profit,
 					ProfitPct:   profit / (position * entryPrice),
 					Holding:     position,
@@ -164,7 +197,7 @@ func (bt *Backtester) RunBacktest(symbol string, data []types.OHLCV) (*BacktestR
 					EntrySignal: entrySignal,
 					ExitTime:    currentTime,
 					ExitPrice:   exitPrice,
-					ExitSignal:  "止盈",
+					ExitSignal:  NewReason("take_profit"),
 					Profit:      profit,
 					ProfitPct:   profit / (position * entryPrice),
 					Holding:     position,
@@ -179,7 +212,7 @@ func (bt *Backtester) RunBacktest(symbol string, data []types.OHLCV) (*BacktestR
 		// 交易信号
 		if bt.useStrategy && bt.strategy != nil {
 			// 使用策略接口
-			if shouldEnter, reason := bt.strategy.ShouldEnter(analysisResult, summary, position); shouldEnter {
+			if shouldEnter, reason := bt.strategy.ShouldEnter(analysisResult, summary, position); shouldEnter && bt.relativeStrength.allowsLong(analysisResult) {
 				entryPrice = currentPrice * (1 + bt.slippage + bt.feeRate)
 				position = capital / entryPrice
 				capital = 0
@@ -213,13 +246,13 @@ func (bt *Backtester) RunBacktest(symbol string, data []types.OHLCV) (*BacktestR
 			}
 		} else {
 			// 使用原始逻辑
-			if position == 0 && totalStrength > bt.entryThreshold {
+			if position == 0 && totalStrength > bt.entryThreshold && bt.relativeStrength.allowsLong(analysisResult) {
 				// 做多信号
 				entryPrice = currentPrice * (1 + bt.slippage + bt.feeRate)
 				position = capital / entryPrice
 				capital = 0
 				entryTime = currentTime
-				entrySignal = fmt.Sprintf("做多(强度:%.2f)", totalStrength)
+				entrySignal = NewReason("long", totalStrength)
 				
 			} else if position > 0 && totalStrength < bt.exitThreshold {
 				// 平仓信号
@@ -233,7 +266,7 @@ func (bt *Backtester) RunBacktest(symbol string, data []types.OHLCV) (*BacktestR
 					EntrySignal: entrySignal,
 					ExitTime:    currentTime,
 					ExitPrice:   exitPrice,
-					ExitSignal:  fmt.Sprintf("平仓(强度:%.2f)", totalStrength),
+					ExitSignal:  NewReason("close", totalStrength),
 					Profit:      profit,
 					ProfitPct:   profit / (position * entryPrice),
 					Holding:     position,
@@ -273,7 +306,7 @@ func (bt *Backtester) RunBacktest(symbol string, data []types.OHLCV) (*BacktestR
 			EntrySignal: entrySignal,
 			ExitTime:    data[len(data)-1].Time,
 			ExitPrice:   exitPrice,
-			ExitSignal:  "回测结束平仓",
+			ExitSignal:  NewReason("end_of_backtest"),
 			Profit:      profit,
 			ProfitPct:   profit / (position * entryPrice),
 			Holding:     position,
@@ -359,8 +392,51 @@ func (bt *Backtester) SetFees(feeRate, slippage float64) {
 	bt.slippage = slippage
 }
 
+// SetKeyLevels 设置交易对的关键价位，参与支撑阻力聚类
+func (bt *Backtester) SetKeyLevels(keyLevels types.KeyLevels) {
+	bt.analyzer.SetKeyLevels(keyLevels)
+}
+
+// SetTransform 设置分析所用的Heikin-Ashi/Renko变换
+// 变换作用于截至当前K线的全部历史，只用于生成信号，成交价格始终取真实K线收盘价
+func (bt *Backtester) SetTransform(transform indicators.Transform) {
+	bt.transform = transform
+}
+
+// SetAnalysisSettings 设置分析的指标周期和警报阈值（通常来自配置文件），需在设置策略之前调用
+func (bt *Backtester) SetAnalysisSettings(settings analysis.Settings) error {
+	bt.thresholds = settings.Thresholds
+	bt.evidenceCollector.SetThresholds(settings.Thresholds)
+	return bt.analyzer.ApplySettings(settings)
+}
+
+// SetEvidenceRules 设置证据规则，规则中 expr('...') 引用的指标表达式加入分析器
+func (bt *Backtester) SetEvidenceRules(rs *analysis.EvidenceRuleSet) error {
+	for _, text := range rs.Expressions() {
+		if err := bt.analyzer.AddExpression(text); err != nil {
+			return fmt.Errorf("evidence rule expression %q: %w", text, err)
+		}
+	}
+	bt.evidenceCollector.SetRules(rs)
+	return nil
+}
+
+// SetRelativeStrength 设置相对强度观察列表（不含回测币种也可），逐根K线计算回测币种的排名写入分析结果；
+// minPercentile>0时只在百分位不低于它时做多
+func (bt *Backtester) SetRelativeStrength(analyzer *analysis.RelativeStrengthAnalyzer, universe map[string][]types.OHLCV, minPercentile float64) {
+	bt.relativeStrength = newRelativeStrengthFilter(analyzer, universe)
+	bt.relativeStrength.minPercentile = minPercentile
+}
+
 // SetTradingStrategy 设置交易策略
 func (bt *Backtester) SetTradingStrategy(strategy TradingStrategy) {
 	bt.strategy = strategy
 	bt.useStrategy = true
+	
+	if configurer, ok := strategy.(AnalyzerConfigurer); ok {
+		configurer.ConfigureAnalyzer(bt.analyzer)
+	}
+	if configurer, ok := strategy.(ThresholdConfigurer); ok {
+		configurer.SetThresholds(bt.thresholds)
+	}
 }
\ No newline at end of file
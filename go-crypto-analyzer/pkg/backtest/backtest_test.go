@@ -0,0 +1,94 @@
+package backtest
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/zjc/go-crypto-analyzer/pkg/indicators"
+	"github.com/zjc/go-crypto-analyzer/pkg/types"
+)
+
+// testCandles 带上下影线的震荡上涨行情，开盘价与收盘价不同，
+// Heikin-Ashi和Renko的收盘价因此与真实收盘价不同
+func testCandles(n int) []types.OHLCV {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	data := make([]types.OHLCV, n)
+	price := 100.0
+	for i := range data {
+		open := price
+		price *= 1 + 0.002 + 0.03*math.Sin(float64(i)/6)
+		data[i] = types.OHLCV{
+			Time:   start.Add(time.Duration(i) * time.Hour),
+			Open:   open,
+			High:   math.Max(open, price) * 1.01,
+			Low:    math.Min(open, price) * 0.99,
+			Close:  price,
+			Volume: 1000 + 500*math.Cos(float64(i)/3),
+		}
+	}
+	return data
+}
+
+// closeAt 返回时间对应的真实收盘价
+func closeAt(t *testing.T, data []types.OHLCV, at time.Time) float64 {
+	t.Helper()
+	for _, candle := range data {
+		if candle.Time.Equal(at) {
+			return candle.Close
+		}
+	}
+	t.Fatalf("no candle at %v", at)
+	return 0
+}
+
+// checkFill 成交价应为真实收盘价加减滑点和手续费
+func checkFill(t *testing.T, what string, price, close, cost float64) {
+	t.Helper()
+	if math.Abs(price-close*(1+cost)) > 1e-9 && math.Abs(price-close*(1-cost)) > 1e-9 {
+		t.Errorf("%s price %.6f does not derive from the real close %.6f", what, price, close)
+	}
+}
+
+func TestTransformedBacktestFillsAtRealPrices(t *testing.T) {
+	data := testCandles(300)
+	transforms := map[string]indicators.Transform{
+		"heikin-ashi": {Kind: indicators.TransformHeikinAshi},
+		"renko":       {Kind: indicators.TransformRenko, ATRPeriod: 14},
+	}
+
+	for name, transform := range transforms {
+		// 阈值放宽到每根K线都开仓或平仓，保证产生足够多的成交
+		bt := NewBacktester(10000)
+		bt.SetTransform(transform)
+		bt.SetStrategy(math.Inf(-1), math.Inf(1), 0.05, 0.10)
+		result, err := bt.RunBacktest("TEST", data)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if len(result.Trades) == 0 {
+			t.Fatalf("%s: expected trades", name)
+		}
+		cost := bt.slippage + bt.feeRate
+		for _, trade := range result.Trades {
+			checkFill(t, name+" entry", trade.EntryPrice, closeAt(t, data, trade.EntryTime), cost)
+			checkFill(t, name+" exit", trade.ExitPrice, closeAt(t, data, trade.ExitTime), cost)
+		}
+
+		v2 := NewBacktesterV2(10000)
+		v2.SetTransform(transform)
+		v2.SetThresholds(0, 0, 0)
+		resultV2, err := v2.RunBacktestV2("TEST", data)
+		if err != nil {
+			t.Fatalf("%s v2: %v", name, err)
+		}
+		if len(resultV2.Trades) == 0 {
+			t.Fatalf("%s v2: expected trades", name)
+		}
+		cost = v2.slippage + v2.feeRate
+		for _, trade := range resultV2.Trades {
+			checkFill(t, name+" v2 entry", trade.EntryPrice, closeAt(t, data, trade.EntryTime), cost)
+			checkFill(t, name+" v2 exit", trade.ExitPrice, closeAt(t, data, trade.ExitTime), cost)
+		}
+	}
+}
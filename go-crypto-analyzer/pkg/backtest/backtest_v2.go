@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/zjc/go-crypto-analyzer/pkg/analysis"
+	"github.com/zjc/go-crypto-analyzer/pkg/indicators"
 	"github.com/zjc/go-crypto-analyzer/pkg/types"
 )
 
@@ -18,6 +19,24 @@ const (
 	ShortPosition
 )
 
+// StopMode 改进策略的止损/跟踪止损方式
+type StopMode string
+
+const (
+	StopModeATR        StopMode = "atr"        // ATR倍数动态止损
+	StopModeSuperTrend StopMode = "supertrend" // SuperTrend跟踪止损
+	StopModeSAR        StopMode = "psar"       // 抛物线SAR跟踪止损
+)
+
+// ParseStopMode 解析止损方式
+func ParseStopMode(mode string) (StopMode, error) {
+	switch StopMode(mode) {
+	case StopModeATR, StopModeSuperTrend, StopModeSAR:
+		return StopMode(mode), nil
+	}
+	return "", fmt.Errorf("unknown stop mode %q (atr|supertrend|psar)", mode)
+}
+
 // BacktesterV2 支持做空的回测器
 type BacktesterV2 struct {
 	analyzer          *analysis.TrendAnalyzer
@@ -44,16 +63,30 @@ type BacktesterV2 struct {
 	improvedStrategy *ImprovedBidirectionalStrategy
 	useImproved      bool
 	currentStopLoss  float64  // 当前止损价
+	
+	// 跟踪止损方式
+	indicators           *indicators.TechnicalIndicators
+	stopMode             StopMode
+	superTrendPeriod     int      // SuperTrend ATR周期
+	superTrendMultiplier float64  // SuperTrend ATR倍数
+	sarStep              float64  // SAR加速因子步长
+	sarMax               float64  // SAR最大加速因子
+	
+	// 分析所用的K线变换，成交和止损仍按真实价格
+	transform            indicators.Transform
+	
+	// 观察列表相对强度，nil表示不计算
+	relativeStrength     *relativeStrengthFilter
 }
 
 // TradeV2 交易记录（支持做空）
 type TradeV2 struct {
 	EntryTime    time.Time
 	EntryPrice   float64
-	EntrySignal  string
+	EntrySignal  Reason
 	ExitTime     time.Time
 	ExitPrice    float64
-	ExitSignal   string
+	ExitSignal   Reason
 	Direction    string     // "LONG" or "SHORT"
 	Profit       float64
 	ProfitPct    float64
@@ -87,21 +120,27 @@ type BacktestResultV2 struct {
 // NewBacktesterV2 创建支持做空的回测器
 func NewBacktesterV2(initialCapital float64) *BacktesterV2 {
 	return &BacktesterV2{
-		analyzer:          analysis.NewTrendAnalyzer(),
-		evidenceCollector: analysis.NewEvidenceCollector(),
-		initialCapital:    initialCapital,
-		feeRate:           0.001,
-		slippage:          0.0005,
-		longThreshold:     0.5,
-		shortThreshold:    -0.5,
-		closeThreshold:    0.0,
-		stopLoss:          0.03,
-		takeProfit:        0.06,
-		allowShort:        true,
-		positionType:      NoPosition,
-		maxLeverage:       2.0,
-		improvedStrategy:  NewImprovedBidirectionalStrategy(),
-		useImproved:       false,
+		analyzer:             analysis.NewTrendAnalyzer(),
+		evidenceCollector:    analysis.NewEvidenceCollector(),
+		initialCapital:       initialCapital,
+		feeRate:              0.001,
+		slippage:             0.0005,
+		longThreshold:        0.5,
+		shortThreshold:       -0.5,
+		closeThreshold:       0.0,
+		stopLoss:             0.03,
+		takeProfit:           0.06,
+		allowShort:           true,
+		positionType:         NoPosition,
+		maxLeverage:          2.0,
+		improvedStrategy:     NewImprovedBidirectionalStrategy(),
+		useImproved:          false,
+		indicators:           indicators.NewTechnicalIndicators(),
+		stopMode:             StopModeATR,
+		superTrendPeriod:     10,
+		superTrendMultiplier: 3.0,
+		sarStep:              0.02,
+		sarMax:               0.2,
 	}
 }
 
@@ -122,6 +161,70 @@ func (bt *BacktesterV2) UseImprovedStrategy(use bool) {
 	bt.useImproved = use
 }
 
+// UseAnchoredVWAPStop 改进策略使用锚定VWAP收紧止损
+func (bt *BacktesterV2) UseAnchoredVWAPStop(use bool) {
+	bt.improvedStrategy.UseAnchoredVWAPStop(use)
+}
+
+// SetStopMode 设置改进策略的止损方式
+func (bt *BacktesterV2) SetStopMode(mode StopMode) {
+	bt.stopMode = mode
+}
+
+// SetSuperTrendParams 设置SuperTrend止损参数
+func (bt *BacktesterV2) SetSuperTrendParams(period int, multiplier float64) {
+	bt.superTrendPeriod = period
+	bt.superTrendMultiplier = multiplier
+}
+
+// SetSARParams 设置抛物线SAR止损参数
+func (bt *BacktesterV2) SetSARParams(step, max float64) {
+	bt.sarStep = step
+	bt.sarMax = max
+}
+
+// SetKeyLevels 设置交易对的关键价位，参与支撑阻力聚类
+func (bt *BacktesterV2) SetKeyLevels(keyLevels types.KeyLevels) {
+	bt.analyzer.SetKeyLevels(keyLevels)
+}
+
+// SetTransform 设置分析所用的Heikin-Ashi/Renko变换
+// 变换作用于截至当前K线的全部历史，只用于生成信号，成交价格始终取真实K线收盘价
+func (bt *BacktesterV2) SetTransform(transform indicators.Transform) {
+	bt.transform = transform
+}
+
+// SetAnalysisSettings 设置分析的指标周期和警报阈值（通常来自配置文件），需在设置策略之前调用
+func (bt *BacktesterV2) SetAnalysisSettings(settings analysis.Settings) error {
+	bt.improvedStrategy.SetThresholds(settings.Thresholds)
+	bt.improvedStrategy.SetBollingerParams(settings.BollingerPeriod, settings.BollingerStdDev)
+	bt.evidenceCollector.SetThresholds(settings.Thresholds)
+	return bt.analyzer.ApplySettings(settings)
+}
+
+// SetLearning 设置改进策略动态分析的基础权重和自适应RSI阈值（通常来自 configs/ml_config.yaml）
+func (bt *BacktesterV2) SetLearning(learning analysis.Learning) {
+	bt.improvedStrategy.SetLearning(learning)
+}
+
+// SetEvidenceRules 设置证据规则，规则中 expr('...') 引用的指标表达式加入分析器
+func (bt *BacktesterV2) SetEvidenceRules(rs *analysis.EvidenceRuleSet) error {
+	for _, text := range rs.Expressions() {
+		if err := bt.analyzer.AddExpression(text); err != nil {
+			return fmt.Errorf("evidence rule expression %q: %w", text, err)
+		}
+	}
+	bt.evidenceCollector.SetRules(rs)
+	return nil
+}
+
+// SetRelativeStrength 设置相对强度观察列表（不含回测币种也可），逐根K线计算回测币种的排名写入分析结果；
+// minPercentile>0时只在百分位不低于它时做多、不高于100-minPercentile时做空
+func (bt *BacktesterV2) SetRelativeStrength(analyzer *analysis.RelativeStrengthAnalyzer, universe map[string][]types.OHLCV, minPercentile float64) {
+	bt.relativeStrength = newRelativeStrengthFilter(analyzer, universe)
+	bt.relativeStrength.minPercentile = minPercentile
+}
+
 // RunBacktestV2 运行支持做空的回测
 func (bt *BacktesterV2) RunBacktestV2(symbol string, data []types.OHLCV) (*BacktestResultV2, error) {
 	if len(data) < 200 {
@@ -141,7 +244,7 @@ func (bt *BacktesterV2) RunBacktestV2(symbol string, data []types.OHLCV) (*Backt
 	position := 0.0
 	entryPrice := 0.0
 	entryTime := time.Time{}
-	entrySignal := ""
+	entrySignal := Reason{}
 	maxCapital := capital
 	bt.positionType = NoPosition
 	
@@ -151,25 +254,23 @@ func (bt *BacktesterV2) RunBacktestV2(symbol string, data []types.OHLCV) (*Backt
 		currentPrice := window[len(window)-1].Close
 		currentTime := window[len(window)-1].Time
 		
-		// 执行技术分析
-		analysisResult, err := bt.analyzer.AnalyzeComprehensive(window)
+		// 执行技术分析（变换序列只用于分析，不影响成交价格）
+		analysisResult, err := bt.analyzer.AnalyzeTransformed(data[:i+1], bt.transform, len(window))
 		if err != nil {
 			continue
 		}
+		bt.relativeStrength.score(symbol, data[:i+1], analysisResult)
 		
 		// 收集证据
-		bt.evidenceCollector.Clear()
-		bt.evidenceCollector.AnalyzeMAEvidence(analysisResult.MAAnalysis, currentPrice)
-		bt.evidenceCollector.AnalyzeMACDEvidence(analysisResult.MACDAnalysis)
-		bt.evidenceCollector.AnalyzeRSIEvidence(analysisResult.Momentum.RSI)
-		bt.evidenceCollector.AnalyzeSREvidence(currentPrice, analysisResult.SupportResistance)
-		
 		// 计算价格变化率（用于成交量分析）
 		priceChange := 0.0
 		if i > 0 {
 			priceChange = (currentPrice - data[i-1].Close) / data[i-1].Close
 		}
-		bt.evidenceCollector.AnalyzeVolumeEvidence(analysisResult.Volume, priceChange)
+		bt.evidenceCollector.Clear()
+		if err := bt.evidenceCollector.Collect(analysisResult, priceChange); err != nil {
+			return nil, err
+		}
 		
 		// 获取信号强度
 		summary := bt.evidenceCollector.GetSummary()
@@ -205,7 +306,7 @@ func (bt *BacktesterV2) RunBacktestV2(symbol string, data []types.OHLCV) (*Backt
 					EntrySignal: entrySignal,
 					ExitTime:    currentTime,
 					ExitPrice:   exitPrice,
-					ExitSignal:  "止损",
+					ExitSignal:  NewReason("stop_loss"),
 					Direction:   bt.getPositionString(),
 					Profit:      profit,
 					ProfitPct:   profitPct,
@@ -238,7 +339,7 @@ func (bt *BacktesterV2) RunBacktestV2(symbol string, data []types.OHLCV) (*Backt
 					EntrySignal: entrySignal,
 					ExitTime:    currentTime,
 					ExitPrice:   exitPrice,
-					ExitSignal:  "止盈",
+					ExitSignal:  NewReason("take_profit"),
 					Direction:   bt.getPositionString(),
 					Profit:      profit,
 					ProfitPct:   profitPct,
@@ -256,10 +357,10 @@ func (bt *BacktesterV2) RunBacktestV2(symbol string, data []types.OHLCV) (*Backt
 		if bt.positionType == NoPosition {
 			if bt.useImproved {
 				// 使用改进策略
-				marketRegime := bt.improvedStrategy.AnalyzeMarketRegime(analysisResult, window)
+				marketRegime := bt.improvedStrategy.AnalyzeMarketRegime(analysisResult)
 				
 				// 做多信号
-				if shouldLong, reason := bt.improvedStrategy.ShouldOpenLong(analysisResult, summary, marketRegime, window); shouldLong {
+				if shouldLong, reason := bt.improvedStrategy.ShouldOpenLong(analysisResult, summary, marketRegime, window); shouldLong && bt.relativeStrength.allowsLong(analysisResult) {
 					entryPrice = currentPrice * (1 + bt.slippage + bt.feeRate)
 					position = capital / entryPrice
 					capital = 0
@@ -268,12 +369,11 @@ func (bt *BacktesterV2) RunBacktestV2(symbol string, data []types.OHLCV) (*Backt
 					bt.positionType = LongPosition
 					
 					// 计算动态止损
-					atr := bt.calculateATR(window, 14)
-					bt.currentStopLoss = bt.improvedStrategy.GetDynamicStopLoss(entryPrice, currentPrice, LongPosition, atr)
+					bt.currentStopLoss = bt.calculateStopLoss(entryPrice, currentPrice, LongPosition, window, analysisResult, true)
 					
 				// 做空信号
 				} else if bt.allowShort {
-					if shouldShort, reason := bt.improvedStrategy.ShouldOpenShort(analysisResult, summary, marketRegime, window); shouldShort {
+					if shouldShort, reason := bt.improvedStrategy.ShouldOpenShort(analysisResult, summary, marketRegime, window); shouldShort && bt.relativeStrength.allowsShort(analysisResult) {
 						entryPrice = currentPrice * (1 - bt.slippage - bt.feeRate)
 						position = capital / entryPrice
 						capital = 0
@@ -282,28 +382,27 @@ func (bt *BacktesterV2) RunBacktestV2(symbol string, data []types.OHLCV) (*Backt
 						bt.positionType = ShortPosition
 						
 						// 计算动态止损
-						atr := bt.calculateATR(window, 14)
-						bt.currentStopLoss = bt.improvedStrategy.GetDynamicStopLoss(entryPrice, currentPrice, ShortPosition, atr)
+						bt.currentStopLoss = bt.calculateStopLoss(entryPrice, currentPrice, ShortPosition, window, analysisResult, true)
 					}
 				}
 			} else {
 				// 使用原始策略
 				// 做多信号
-				if totalStrength > bt.longThreshold {
+				if totalStrength > bt.longThreshold && bt.relativeStrength.allowsLong(analysisResult) {
 					entryPrice = currentPrice * (1 + bt.slippage + bt.feeRate)
 					position = capital / entryPrice
 					capital = 0
 					entryTime = currentTime
-					entrySignal = fmt.Sprintf("做多(强度:%.2f)", totalStrength)
+					entrySignal = NewReason("long", totalStrength)
 					bt.positionType = LongPosition
 					
 				// 做空信号
-				} else if bt.allowShort && totalStrength < bt.shortThreshold {
+				} else if bt.allowShort && totalStrength < bt.shortThreshold && bt.relativeStrength.allowsShort(analysisResult) {
 					entryPrice = currentPrice * (1 - bt.slippage - bt.feeRate)
 					position = capital / entryPrice
 					capital = 0
 					entryTime = currentTime
-					entrySignal = fmt.Sprintf("做空(强度:%.2f)", totalStrength)
+					entrySignal = NewReason("short", totalStrength)
 					bt.positionType = ShortPosition
 				}
 			}
@@ -311,17 +410,16 @@ func (bt *BacktesterV2) RunBacktestV2(symbol string, data []types.OHLCV) (*Backt
 		} else if bt.positionType == LongPosition {
 			// 多头平仓信号
 			shouldExit := false
-			exitReason := ""
+			exitReason := Reason{}
 			
 			if bt.useImproved {
 				// 使用改进策略的出场逻辑
-				marketRegime := bt.improvedStrategy.AnalyzeMarketRegime(analysisResult, window)
+				marketRegime := bt.improvedStrategy.AnalyzeMarketRegime(analysisResult)
 				shouldExit, exitReason = bt.improvedStrategy.ShouldCloseLong(analysisResult, summary, entryPrice, currentPrice, marketRegime)
 				
 				// 更新动态止损
 				if !shouldExit && bt.improvedStrategy.dynamicStopLoss {
-					atr := bt.calculateATR(window, 14)
-					newStopLoss := bt.improvedStrategy.GetDynamicStopLoss(entryPrice, currentPrice, LongPosition, atr)
+					newStopLoss := bt.calculateStopLoss(entryPrice, currentPrice, LongPosition, window, analysisResult, false)
 					if newStopLoss > bt.currentStopLoss {
 						bt.currentStopLoss = newStopLoss
 					}
@@ -329,14 +427,14 @@ func (bt *BacktesterV2) RunBacktestV2(symbol string, data []types.OHLCV) (*Backt
 					// 检查动态止损
 					if currentPrice <= bt.currentStopLoss {
 						shouldExit = true
-						exitReason = fmt.Sprintf("动态止损(%.2f)", bt.currentStopLoss)
+						exitReason = bt.stopReason()
 					}
 				}
 			} else {
 				// 原始策略逻辑
 				if totalStrength < bt.closeThreshold {
 					shouldExit = true
-					exitReason = fmt.Sprintf("平多(强度:%.2f)", totalStrength)
+					exitReason = NewReason("close_long", totalStrength)
 				}
 			}
 			
@@ -363,12 +461,12 @@ func (bt *BacktesterV2) RunBacktestV2(symbol string, data []types.OHLCV) (*Backt
 				bt.positionType = NoPosition
 				
 				// 立即检查是否可以反向开仓
-				if bt.allowShort && totalStrength < bt.shortThreshold {
+				if bt.allowShort && totalStrength < bt.shortThreshold && bt.relativeStrength.allowsShort(analysisResult) {
 					entryPrice = currentPrice * (1 - bt.slippage - bt.feeRate)
 					position = capital / entryPrice
 					capital = 0
 					entryTime = currentTime
-					entrySignal = fmt.Sprintf("反手做空(强度:%.2f)", totalStrength)
+					entrySignal = NewReason("reverse_short", totalStrength)
 					bt.positionType = ShortPosition
 				}
 			}
@@ -376,17 +474,16 @@ func (bt *BacktesterV2) RunBacktestV2(symbol string, data []types.OHLCV) (*Backt
 		} else if bt.positionType == ShortPosition {
 			// 空头平仓信号
 			shouldExit := false
-			exitReason := ""
+			exitReason := Reason{}
 			
 			if bt.useImproved {
 				// 使用改进策略的出场逻辑
-				marketRegime := bt.improvedStrategy.AnalyzeMarketRegime(analysisResult, window)
+				marketRegime := bt.improvedStrategy.AnalyzeMarketRegime(analysisResult)
 				shouldExit, exitReason = bt.improvedStrategy.ShouldCloseShort(analysisResult, summary, entryPrice, currentPrice, marketRegime)
 				
 				// 更新动态止损
 				if !shouldExit && bt.improvedStrategy.dynamicStopLoss {
-					atr := bt.calculateATR(window, 14)
-					newStopLoss := bt.improvedStrategy.GetDynamicStopLoss(entryPrice, currentPrice, ShortPosition, atr)
+					newStopLoss := bt.calculateStopLoss(entryPrice, currentPrice, ShortPosition, window, analysisResult, false)
 					if newStopLoss < bt.currentStopLoss {
 						bt.currentStopLoss = newStopLoss
 					}
@@ -394,14 +491,14 @@ func (bt *BacktesterV2) RunBacktestV2(symbol string, data []types.OHLCV) (*Backt
 					// 检查动态止损
 					if currentPrice >= bt.currentStopLoss {
 						shouldExit = true
-						exitReason = fmt.Sprintf("动态止损(%.2f)", bt.currentStopLoss)
+						exitReason = bt.stopReason()
 					}
 				}
 			} else {
 				// 原始策略逻辑
 				if totalStrength > -bt.closeThreshold {
 					shouldExit = true
-					exitReason = fmt.Sprintf("平空(强度:%.2f)", totalStrength)
+					exitReason = NewReason("close_short", totalStrength)
 				}
 			}
 			
@@ -428,12 +525,12 @@ func (bt *BacktesterV2) RunBacktestV2(symbol string, data []types.OHLCV) (*Backt
 				bt.positionType = NoPosition
 				
 				// 立即检查是否可以反向开仓
-				if totalStrength > bt.longThreshold {
+				if totalStrength > bt.longThreshold && bt.relativeStrength.allowsLong(analysisResult) {
 					entryPrice = currentPrice * (1 + bt.slippage + bt.feeRate)
 					position = capital / entryPrice
 					capital = 0
 					entryTime = currentTime
-					entrySignal = fmt.Sprintf("反手做多(强度:%.2f)", totalStrength)
+					entrySignal = NewReason("reverse_long", totalStrength)
 					bt.positionType = LongPosition
 				}
 			}
@@ -481,7 +578,7 @@ func (bt *BacktesterV2) RunBacktestV2(symbol string, data []types.OHLCV) (*Backt
 			EntrySignal: entrySignal,
 			ExitTime:    data[len(data)-1].Time,
 			ExitPrice:   exitPrice,
-			ExitSignal:  "回测结束平仓",
+			ExitSignal:  NewReason("end_of_backtest"),
 			Direction:   bt.getPositionString(),
 			Profit:      profit,
 			ProfitPct:   profit / (position * entryPrice),
@@ -577,37 +674,52 @@ func (bt *BacktesterV2) getPositionString() string {
 	}
 }
 
-// calculateATR 计算ATR（平均真实波幅）
-func (bt *BacktesterV2) calculateATR(data []types.OHLCV, period int) float64 {
-	if len(data) < period+1 {
-		return 0
+// calculateStopLoss 按止损方式计算止损价
+// SuperTrend/SAR方向与持仓一致时以其止损位为止损（最大亏损5%）；开仓时方向不一致则退回ATR止损，
+// 持仓中方向翻转则止损位越过现价，下一次检查即出场
+func (bt *BacktesterV2) calculateStopLoss(entryPrice, currentPrice float64, positionType PositionType, window []types.OHLCV, analysisResult *types.Analysis, opening bool) float64 {
+	highs := make([]float64, len(window))
+	lows := make([]float64, len(window))
+	closes := make([]float64, len(window))
+	for i, candle := range window {
+		highs[i], lows[i], closes[i] = candle.High, candle.Low, candle.Close
 	}
 	
-	// 计算真实波幅
-	trueRanges := make([]float64, 0)
-	for i := len(data) - period; i < len(data); i++ {
-		if i == 0 {
-			continue
+	// 与分析器和SuperTrend相同的Wilder ATR
+	atr := bt.indicators.ATR(highs, lows, closes, 14)
+	stopLoss := bt.improvedStrategy.GetDynamicStopLoss(entryPrice, currentPrice, positionType, atr)
+	
+	if bt.stopMode != StopModeATR {
+		var trailing indicators.TrailingResult
+		if bt.stopMode == StopModeSuperTrend {
+			trailing = bt.indicators.SuperTrend(highs, lows, closes, bt.superTrendPeriod, bt.superTrendMultiplier)
+		} else {
+			trailing = bt.indicators.ParabolicSAR(highs, lows, bt.sarStep, bt.sarMax)
 		}
 		
-		// TR = max(H-L, abs(H-PC), abs(L-PC))
-		highLow := data[i].High - data[i].Low
-		highPrevClose := math.Abs(data[i].High - data[i-1].Close)
-		lowPrevClose := math.Abs(data[i].Low - data[i-1].Close)
-		
-		tr := math.Max(highLow, math.Max(highPrevClose, lowPrevClose))
-		trueRanges = append(trueRanges, tr)
-	}
-	
-	// 计算ATR
-	if len(trueRanges) == 0 {
-		return 0
+		last := len(window) - 1
+		level, direction := trailing.Level[last], trailing.Direction[last]
+		aligned := (positionType == LongPosition && direction == 1) || (positionType == ShortPosition && direction == -1)
+		if direction != 0 && (aligned || !opening) {
+			if positionType == LongPosition {
+				stopLoss = math.Max(level, entryPrice*0.95)
+			} else {
+				stopLoss = math.Min(level, entryPrice*1.05)
+			}
+		}
 	}
 	
-	sum := 0.0
-	for _, tr := range trueRanges {
-		sum += tr
+	return bt.improvedStrategy.ApplyAnchoredVWAPStop(stopLoss, currentPrice, positionType, analysisResult)
+}
+
+// stopReason 止损出场原因，附当前止损价
+func (bt *BacktesterV2) stopReason() Reason {
+	switch bt.stopMode {
+	case StopModeSuperTrend:
+		return NewReason("stop_supertrend", bt.currentStopLoss)
+	case StopModeSAR:
+		return NewReason("stop_sar", bt.currentStopLoss)
+	default:
+		return NewReason("stop_dynamic", bt.currentStopLoss)
 	}
-	
-	return sum / float64(len(trueRanges))
-}
\ No newline at end of file
+}
@@ -0,0 +1,120 @@
+package backtest
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/zjc/go-crypto-analyzer/pkg/types"
+)
+
+// trendWindow 单边行情，drift为每根K线的涨跌幅
+func trendWindow(drift float64) []types.OHLCV {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	window := make([]types.OHLCV, 101)
+	price := 100.0
+	for i := range window {
+		open := price
+		price *= 1 + drift + 0.002*math.Sin(float64(i))
+		window[i] = types.OHLCV{
+			Time:  start.Add(time.Duration(i) * time.Hour),
+			Open:  open,
+			High:  math.Max(open, price) * 1.003,
+			Low:   math.Min(open, price) * 0.997,
+			Close: price,
+		}
+	}
+	return window
+}
+
+func TestCalculateStopLoss(t *testing.T) {
+	up, down := trendWindow(0.005), trendWindow(-0.005)
+
+	tests := []struct {
+		name     string
+		mode     StopMode
+		window   []types.OHLCV
+		position PositionType
+		// entry 为入场价相对现价的倍数
+		entry   float64
+		opening bool
+		// want 为期望止损：atr 为ATR止损，level 为跟踪指标止损位，clamp 为5%最大亏损
+		want string
+	}{
+		{"atr long", StopModeATR, up, LongPosition, 1, true, "atr"},
+		{"atr short", StopModeATR, down, ShortPosition, 1, true, "atr"},
+		{"supertrend long aligned", StopModeSuperTrend, up, LongPosition, 1, true, "level"},
+		{"supertrend long against at entry", StopModeSuperTrend, down, LongPosition, 1, true, "atr"},
+		{"supertrend long flipped while holding", StopModeSuperTrend, down, LongPosition, 1, false, "level"},
+		{"supertrend long clamped", StopModeSuperTrend, up, LongPosition, 1.2, false, "clamp"},
+		{"sar short aligned", StopModeSAR, down, ShortPosition, 1, true, "level"},
+		{"sar short against at entry", StopModeSAR, up, ShortPosition, 1, true, "atr"},
+		{"sar short flipped while holding", StopModeSAR, up, ShortPosition, 1, false, "level"},
+		{"sar short clamped", StopModeSAR, down, ShortPosition, 0.8, false, "clamp"},
+	}
+
+	for _, tt := range tests {
+		bt := NewBacktesterV2(10000)
+		bt.SetStopMode(tt.mode)
+
+		highs := make([]float64, len(tt.window))
+		lows := make([]float64, len(tt.window))
+		closes := make([]float64, len(tt.window))
+		for i, candle := range tt.window {
+			highs[i], lows[i], closes[i] = candle.High, candle.Low, candle.Close
+		}
+		last := len(tt.window) - 1
+		currentPrice := closes[last]
+		entryPrice := currentPrice * tt.entry
+
+		var want float64
+		switch tt.want {
+		case "atr":
+			atr := bt.indicators.ATR(highs, lows, closes, 14)
+			want = bt.improvedStrategy.GetDynamicStopLoss(entryPrice, currentPrice, tt.position, atr)
+		case "level":
+			trailing := bt.indicators.SuperTrend(highs, lows, closes, bt.superTrendPeriod, bt.superTrendMultiplier)
+			if tt.mode == StopModeSAR {
+				trailing = bt.indicators.ParabolicSAR(highs, lows, bt.sarStep, bt.sarMax)
+			}
+			want = trailing.Level[last]
+		case "clamp":
+			want = entryPrice * 0.95
+			if tt.position == ShortPosition {
+				want = entryPrice * 1.05
+			}
+		}
+
+		got := bt.calculateStopLoss(entryPrice, currentPrice, tt.position, tt.window, &types.Analysis{}, tt.opening)
+		if math.Abs(got-want) > 1e-9 {
+			t.Errorf("%s: stop %.4f, want %s stop %.4f", tt.name, got, tt.want, want)
+		}
+
+		// 持仓中方向翻转时止损位越过现价，下一次检查即出场
+		if !tt.opening && tt.want == "level" {
+			if (tt.position == LongPosition && got < currentPrice) || (tt.position == ShortPosition && got > currentPrice) {
+				t.Errorf("%s: stop %.4f should cross the price %.4f", tt.name, got, currentPrice)
+			}
+		}
+	}
+}
+
+func TestStopReason(t *testing.T) {
+	tests := []struct {
+		mode StopMode
+		want Reason
+	}{
+		{StopModeATR, NewReason("stop_dynamic", 95.0)},
+		{StopModeSuperTrend, NewReason("stop_supertrend", 95.0)},
+		{StopModeSAR, NewReason("stop_sar", 95.0)},
+	}
+	for _, tt := range tests {
+		bt := NewBacktesterV2(10000)
+		bt.SetStopMode(tt.mode)
+		bt.currentStopLoss = 95
+		if got := bt.stopReason(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: stop reason %+v, want %+v", tt.mode, got, tt.want)
+		}
+	}
+}
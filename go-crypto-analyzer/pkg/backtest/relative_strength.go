@@ -0,0 +1,111 @@
+package backtest
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/zjc/go-crypto-analyzer/pkg/analysis"
+	"github.com/zjc/go-crypto-analyzer/pkg/data"
+	"github.com/zjc/go-crypto-analyzer/pkg/i18n"
+	"github.com/zjc/go-crypto-analyzer/pkg/types"
+	"github.com/zjc/go-crypto-analyzer/pkg/utils"
+)
+
+// RelativeStrengthTarget 可设置相对强度观察列表的回测器，Backtester和BacktesterV2都满足
+type RelativeStrengthTarget interface {
+	SetRelativeStrength(analyzer *analysis.RelativeStrengthAnalyzer, universe map[string][]types.OHLCV, minPercentile float64)
+}
+
+// RelativeStrengthConfig 相对强度观察列表、周期权重（如 "1d:0.2,7d:0.3,30d:0.5"）和开仓百分位过滤
+type RelativeStrengthConfig struct {
+	Universe      []string
+	Lookbacks     string
+	MinPercentile float64
+}
+
+// SetupRelativeStrength 获取观察列表和基准的K线（比回测币种多取最长周期所需的K线，
+// 使第一根K线就能计算排名）并设置到回测器；回测币种本身不重复获取
+func SetupRelativeStrength(target RelativeStrengthTarget, fetcher data.Fetcher, symbol, interval string, limit int, config RelativeStrengthConfig) error {
+	lookbacks, err := analysis.ParseRelativeStrengthLookbacks(config.Lookbacks)
+	if err != nil {
+		return err
+	}
+	rsAnalyzer := analysis.NewRelativeStrengthAnalyzer()
+	rsAnalyzer.SetLookbacks(lookbacks)
+
+	extra := utils.CalculateKlineLimit(interval, int(math.Ceil(rsAnalyzer.LongestLookback().Hours()/24)))
+	universe := make(map[string][]types.OHLCV, len(config.Universe)+1)
+	for _, other := range append(append([]string{}, config.Universe...), rsAnalyzer.Benchmark()) {
+		if other == symbol || universe[other] != nil {
+			continue
+		}
+		fmt.Println(i18n.T("backtest.fetching_rs", other))
+		ohlcv, err := fetcher.FetchOHLCV(other, interval, limit+extra)
+		if err != nil {
+			return fmt.Errorf(i18n.T("backtest.fetch_rs_failed"), other, err)
+		}
+		universe[other] = ohlcv
+	}
+	target.SetRelativeStrength(rsAnalyzer, universe, config.MinPercentile)
+	return nil
+}
+
+// relativeStrengthFilter 回测中逐根K线计算回测币种在观察列表中的相对强度，
+// 写入分析结果供策略使用，并可按百分位过滤开仓
+type relativeStrengthFilter struct {
+	analyzer *analysis.RelativeStrengthAnalyzer
+	universe map[string][]types.OHLCV
+	symbols  []string
+	// minPercentile 做多要求的最低百分位，做空要求不高于100-minPercentile，0表示不过滤
+	minPercentile float64
+}
+
+func newRelativeStrengthFilter(analyzer *analysis.RelativeStrengthAnalyzer, universe map[string][]types.OHLCV) *relativeStrengthFilter {
+	symbols := make([]string, 0, len(universe))
+	for symbol := range universe {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	return &relativeStrengthFilter{analyzer: analyzer, universe: universe, symbols: symbols}
+}
+
+// score 以当前K线时间为截止计算相对强度（不使用未来数据）
+func (f *relativeStrengthFilter) score(symbol string, data []types.OHLCV, result *types.Analysis) {
+	if f == nil || len(data) == 0 {
+		return
+	}
+	series := make(map[string][]types.OHLCV, len(f.universe)+1)
+	symbols := []string{symbol}
+	for _, other := range f.symbols {
+		series[other] = f.universe[other]
+		if other != symbol {
+			symbols = append(symbols, other)
+		}
+	}
+	series[symbol] = data
+
+	ranking, err := f.analyzer.AnalyzeAt(symbols, series, data[len(data)-1].Time)
+	if err != nil {
+		return
+	}
+	if score, ok := ranking.Score(symbol); ok && score.Rank > 0 {
+		result.RelativeStrength = &score
+	}
+}
+
+// allowsLong 相对强度是否允许做多；尚无排名时不过滤
+func (f *relativeStrengthFilter) allowsLong(result *types.Analysis) bool {
+	if f == nil || f.minPercentile <= 0 || result.RelativeStrength == nil {
+		return true
+	}
+	return result.RelativeStrength.Percentile >= f.minPercentile
+}
+
+// allowsShort 相对强度是否允许做空：只做空排名靠后的币种
+func (f *relativeStrengthFilter) allowsShort(result *types.Analysis) bool {
+	if f == nil || f.minPercentile <= 0 || result.RelativeStrength == nil {
+		return true
+	}
+	return result.RelativeStrength.Percentile <= 100-f.minPercentile
+}
@@ -3,6 +3,8 @@ package backtest
 import (
 	"fmt"
 	
+	"github.com/zjc/go-crypto-analyzer/pkg/analysis"
+	"github.com/zjc/go-crypto-analyzer/pkg/indicators"
 	"github.com/zjc/go-crypto-analyzer/pkg/types"
 )
 
@@ -20,10 +22,10 @@ const (
 // TradingStrategy 交易策略接口
 type TradingStrategy interface {
 	// ShouldEnter 判断是否应该入场
-	ShouldEnter(analysis *types.Analysis, evidenceSummary map[string]interface{}, position float64) (bool, string)
+	ShouldEnter(analysis *types.Analysis, evidenceSummary map[string]interface{}, position float64) (bool, Reason)
 	
 	// ShouldExit 判断是否应该出场
-	ShouldExit(analysis *types.Analysis, evidenceSummary map[string]interface{}, position float64, entryPrice float64) (bool, string)
+	ShouldExit(analysis *types.Analysis, evidenceSummary map[string]interface{}, position float64, entryPrice float64) (bool, Reason)
 	
 	// GetStopLoss 获取止损价格
 	GetStopLoss(entryPrice float64, analysis *types.Analysis) float64
@@ -32,6 +34,17 @@ type TradingStrategy interface {
 	GetTakeProfit(entryPrice float64, analysis *types.Analysis) float64
 }
 
+// AnalyzerConfigurer 需要自定义分析参数的策略（如布林带、唐奇安周期），
+// 回测器设置策略时会用它配置分析器
+type AnalyzerConfigurer interface {
+	ConfigureAnalyzer(analyzer *analysis.TrendAnalyzer)
+}
+
+// ThresholdConfigurer 使用RSI等警报阈值的策略，回测器设置策略时传入配置文件中的阈值
+type ThresholdConfigurer interface {
+	SetThresholds(thresholds analysis.Thresholds)
+}
+
 // TrendFollowingStrategy 趋势跟踪策略
 type TrendFollowingStrategy struct {
 	minADX          float64  // 最小ADX值
@@ -40,6 +53,8 @@ type TrendFollowingStrategy struct {
 	exitThreshold   float64  // 出场阈值
 	useATRStop      bool     // 使用ATR止损
 	atrMultiplier   float64  // ATR乘数
+	useAVWAPStop    bool     // 使用前低锚定VWAP止损
+	thresholds      analysis.Thresholds // RSI阈值
 }
 
 // NewTrendFollowingStrategy 创建趋势跟踪策略
@@ -51,82 +66,90 @@ func NewTrendFollowingStrategy() *TrendFollowingStrategy {
 		exitThreshold:  -0.3,
 		useATRStop:     true,
 		atrMultiplier:  2.0,
+		thresholds:     analysis.DefaultThresholds(),
 	}
 }
 
+// SetThresholds 设置RSI阈值
+func (s *TrendFollowingStrategy) SetThresholds(thresholds analysis.Thresholds) {
+	s.thresholds = thresholds
+}
+
+// UseAnchoredVWAPStop 使用前一个主要低点锚定的VWAP作为止损
+func (s *TrendFollowingStrategy) UseAnchoredVWAPStop(use bool) {
+	s.useAVWAPStop = use
+}
+
 // ShouldEnter 趋势策略入场条件
-func (s *TrendFollowingStrategy) ShouldEnter(analysis *types.Analysis, evidenceSummary map[string]interface{}, position float64) (bool, string) {
+func (s *TrendFollowingStrategy) ShouldEnter(analysis *types.Analysis, evidenceSummary map[string]interface{}, position float64) (bool, Reason) {
 	if position > 0 {
-		return false, ""
+		return false, Reason{}
 	}
 	
 	totalStrength := evidenceSummary["totalStrength"].(float64)
 	
 	// 基本条件检查
 	if totalStrength <= s.entryThreshold {
-		return false, ""
+		return false, Reason{}
 	}
 	
 	// ADX过滤 - 只在趋势市场交易
 	if analysis.TrendStrength.ADX < s.minADX {
-		return false, ""
+		return false, Reason{}
 	}
 	
 	// 成交量确认
 	if analysis.Volume.VolumeRatio < s.minVolumeRatio {
-		return false, ""
+		return false, Reason{}
 	}
 	
 	// 价格位置检查 - 必须在中期均线上方
 	if analysis.CurrentPrice < analysis.MAAnalysis.MA20 {
-		return false, ""
+		return false, Reason{}
 	}
 	
 	// RSI过滤 - 避免追高
-	if analysis.Momentum.RSI > 75 {
-		return false, ""
+	if analysis.Momentum.RSI > s.thresholds.RSIChaseLimit {
+		return false, Reason{}
 	}
 	
 	// MACD确认
-	if analysis.MACDAnalysis.Trend != "看涨" {
-		return false, ""
+	if analysis.MACDAnalysis.Trend != types.MACDBullish {
+		return false, Reason{}
 	}
 	
-	reason := fmt.Sprintf("趋势买入(ADX:%.1f,强度:%.2f)", 
-		analysis.TrendStrength.ADX, totalStrength)
-	
-	return true, reason
+	return true, NewReason("trend_entry", analysis.TrendStrength.ADX, totalStrength)
 }
 
 // ShouldExit 趋势策略出场条件
-func (s *TrendFollowingStrategy) ShouldExit(analysis *types.Analysis, evidenceSummary map[string]interface{}, position float64, entryPrice float64) (bool, string) {
+func (s *TrendFollowingStrategy) ShouldExit(analysis *types.Analysis, evidenceSummary map[string]interface{}, position float64, entryPrice float64) (bool, Reason) {
 	if position <= 0 {
-		return false, ""
+		return false, Reason{}
 	}
 	
 	totalStrength := evidenceSummary["totalStrength"].(float64)
 	
 	// 趋势反转信号
 	if totalStrength < s.exitThreshold {
-		return true, fmt.Sprintf("趋势反转(强度:%.2f)", totalStrength)
+		return true, NewReason("trend_reversal", totalStrength)
 	}
 	
 	// 跌破关键均线
 	if analysis.CurrentPrice < analysis.MAAnalysis.MA20 {
-		return true, "跌破MA20"
+		return true, NewReason("below_ma20")
 	}
 	
 	// MACD死叉
-	if analysis.MACDAnalysis.Trend == "看跌" && analysis.MACDAnalysis.Histogram < 0 {
-		return true, "MACD死叉"
+	if analysis.MACDAnalysis.Trend == types.MACDBearish && analysis.MACDAnalysis.Histogram < 0 {
+		return true, NewReason("macd_death_cross")
 	}
 	
 	// 成交量异常
 	if analysis.Volume.VolumeRatio > 3 && analysis.CurrentPrice < entryPrice {
-		return true, "放量下跌"
+		return true, NewReason("volume_selloff")
 	}
 	
-	return false, ""
+	return false, Reason{}
 }
 
 // GetStopLoss 计算止损价
@@ -134,6 +157,12 @@ func (s *TrendFollowingStrategy) GetStopLoss(entryPrice float64, analysis *types
 	// 使用MA20作为止损参考
 	stopLoss := analysis.MAAnalysis.MA20
 	
+	// 启用时改用前低锚定VWAP：跌破说明低点以来的买方整体转为亏损
+	avwap := analysis.VWAP.AnchoredLow
+	if s.useAVWAPStop && avwap > 0 && avwap < entryPrice {
+		stopLoss = avwap
+	}
+	
 	// 但不能超过5%
 	maxLoss := entryPrice * 0.95
 	if stopLoss < maxLoss {
@@ -145,11 +174,20 @@ func (s *TrendFollowingStrategy) GetStopLoss(entryPrice float64, analysis *types
 
 // GetTakeProfit 计算止盈价
 func (s *TrendFollowingStrategy) GetTakeProfit(entryPrice float64, analysis *types.Analysis) float64 {
-	// 使用阻力位作为止盈目标
-	r1 := analysis.SupportResistance.Resistance["R1"]
+	// 使用最近的阻力位作为止盈目标
+	r1 := analysis.SupportResistance.ResistanceTarget()
 	
 	// 但至少要有5%的利润
 	minProfit := entryPrice * 1.05
+	
+	// 满足最低利润的第一个成交密集区或斐波那契位更容易到达
+	if level := analysis.SupportResistance.NextVolumeLevelAbove(minProfit); level > 0 && level < r1 {
+		r1 = level
+	}
+	if level := analysis.Fibonacci.NextLevelAbove(minProfit); level > 0 && level < r1 {
+		r1 = level
+	}
+	
 	if r1 < minProfit {
 		return minProfit
 	}
@@ -159,81 +197,107 @@ func (s *TrendFollowingStrategy) GetTakeProfit(entryPrice float64, analysis *typ
 
 // MomentumBreakoutStrategy 动量突破策略
 type MomentumBreakoutStrategy struct {
-	rsiThreshold    float64
 	volumeThreshold float64
 	breakoutPeriod  int
+	thresholds      analysis.Thresholds // RSI高于强势区下沿入场、极度超买离场
 }
 
 // NewMomentumBreakoutStrategy 创建动量突破策略
 func NewMomentumBreakoutStrategy() *MomentumBreakoutStrategy {
 	return &MomentumBreakoutStrategy{
-		rsiThreshold:    60.0,
 		volumeThreshold: 2.0,
 		breakoutPeriod:  20,
+		thresholds:      analysis.DefaultThresholds(),
 	}
 }
 
+// SetThresholds 设置RSI阈值
+func (s *MomentumBreakoutStrategy) SetThresholds(thresholds analysis.Thresholds) {
+	s.thresholds = thresholds
+}
+
+// ConfigureAnalyzer 使用突破周期作为唐奇安通道周期
+func (s *MomentumBreakoutStrategy) ConfigureAnalyzer(analyzer *analysis.TrendAnalyzer) {
+	analyzer.SetDonchianPeriod(s.breakoutPeriod)
+}
+
 // ShouldEnter 动量策略入场
-func (s *MomentumBreakoutStrategy) ShouldEnter(analysis *types.Analysis, evidenceSummary map[string]interface{}, position float64) (bool, string) {
+func (s *MomentumBreakoutStrategy) ShouldEnter(analysis *types.Analysis, evidenceSummary map[string]interface{}, position float64) (bool, Reason) {
 	if position > 0 {
-		return false, ""
+		return false, Reason{}
 	}
 	
 	// RSI动量确认
-	if analysis.Momentum.RSI < s.rsiThreshold || analysis.Momentum.RSI > 80 {
-		return false, ""
+	if analysis.Momentum.RSI < s.thresholds.RSIStrong || analysis.Momentum.RSI > s.thresholds.RSIExtreme {
+		return false, Reason{}
 	}
 	
 	// 成交量突破
 	if analysis.Volume.VolumeRatio < s.volumeThreshold {
-		return false, ""
+		return false, Reason{}
 	}
 	
 	// MACD柱状图必须为正且增长
 	if analysis.MACDAnalysis.Histogram <= 0 {
-		return false, ""
+		return false, Reason{}
 	}
 	
 	// 价格必须突破所有短期均线
 	if analysis.CurrentPrice <= analysis.MAAnalysis.MA5 ||
 	   analysis.CurrentPrice <= analysis.MAAnalysis.MA10 ||
 	   analysis.CurrentPrice <= analysis.MAAnalysis.MA20 {
-		return false, ""
+		return false, Reason{}
+	}
+	
+	// 波动率挤压中不追突破，需突破唐奇安上轨或挤压向上释放
+	channels := analysis.Channels
+	if channels.Squeeze {
+		return false, Reason{}
+	}
+	if channels.DonchianBreakout != types.BreakoutUp && channels.SqueezeFired != types.SqueezeFiredUp {
+		return false, Reason{}
 	}
 	
-	reason := fmt.Sprintf("动量突破(RSI:%.1f,Vol:%.1fx)", 
-		analysis.Momentum.RSI, analysis.Volume.VolumeRatio)
+	reason := NewReason("momentum_breakout", analysis.Momentum.RSI, analysis.Volume.VolumeRatio)
+	if channels.SqueezeFired == types.SqueezeFiredUp {
+		reason = NewReason("squeeze_breakout", analysis.Momentum.RSI, analysis.Volume.VolumeRatio)
+	}
 	
 	return true, reason
 }
 
 // ShouldExit 动量策略出场
-func (s *MomentumBreakoutStrategy) ShouldExit(analysis *types.Analysis, evidenceSummary map[string]interface{}, position float64, entryPrice float64) (bool, string) {
+func (s *MomentumBreakoutStrategy) ShouldExit(analysis *types.Analysis, evidenceSummary map[string]interface{}, position float64, entryPrice float64) (bool, Reason) {
 	if position <= 0 {
-		return false, ""
+		return false, Reason{}
 	}
 	
 	// RSI超买
-	if analysis.Momentum.RSI > 80 {
-		return true, "RSI超买"
+	if analysis.Momentum.RSI > s.thresholds.RSIExtreme {
+		return true, NewReason("rsi_overbought")
 	}
 	
 	// 动量衰竭
-	if analysis.Momentum.RSI < 50 {
-		return true, "动量衰竭"
+	if analysis.Momentum.RSI < s.thresholds.RSIMidline {
+		return true, NewReason("momentum_exhausted")
 	}
 	
 	// MACD柱状图转负
 	if analysis.MACDAnalysis.Histogram < 0 {
-		return true, "MACD转负"
+		return true, NewReason("macd_negative")
 	}
 	
 	// 跌破MA5
 	if analysis.CurrentPrice < analysis.MAAnalysis.MA5 {
-		return true, "跌破MA5"
+		return true, NewReason("below_ma5")
 	}
 	
-	return false, ""
+	// 挤压向下释放
+	if analysis.Channels.SqueezeFired == types.SqueezeFiredDown {
+		return true, NewReason("squeeze_fired_down")
+	}
+	
+	return false, Reason{}
 }
 
 // GetStopLoss 动量策略止损
@@ -252,101 +316,165 @@ func (s *MomentumBreakoutStrategy) GetStopLoss(entryPrice float64, analysis *typ
 
 // GetTakeProfit 动量策略止盈
 func (s *MomentumBreakoutStrategy) GetTakeProfit(entryPrice float64, analysis *types.Analysis) float64 {
-	// 动量策略使用较小的止盈目标
-	return entryPrice * 1.06
+	// 动量策略使用较小的止盈目标，突破后上方第一个成交密集区或斐波那契扩展位更近时以其为目标
+	target := entryPrice * 1.06
+	if level := analysis.SupportResistance.NextVolumeLevelAbove(entryPrice * 1.03); level > 0 && level < target {
+		target = level
+	}
+	if level := analysis.Fibonacci.NextLevelAbove(entryPrice * 1.03); level > 0 && level < target {
+		target = level
+	}
+	return target
 }
 
 // MeanReversionStrategy 均值回归策略
 type MeanReversionStrategy struct {
-	oversoldRSI     float64
-	overboughtRSI   float64
+	thresholds      analysis.Thresholds // RSI超卖入场、回到多空分界离场
 	bollingerPeriod int
 	bollingerStdDev float64
+	rsiSpec         string // 自定义RSI规格，为空时使用分析器的RSI
+	rsiKey          string // 分析器返回的规范规格
 }
 
 // NewMeanReversionStrategy 创建均值回归策略
 func NewMeanReversionStrategy() *MeanReversionStrategy {
 	return &MeanReversionStrategy{
-		oversoldRSI:     30.0,
-		overboughtRSI:   70.0,
+		thresholds:      analysis.DefaultThresholds(),
 		bollingerPeriod: 20,
 		bollingerStdDev: 2.0,
 	}
 }
 
+// SetThresholds 设置RSI阈值
+func (s *MeanReversionStrategy) SetThresholds(thresholds analysis.Thresholds) {
+	s.thresholds = thresholds
+}
+
+// SetRSISpec 设置超卖判断使用的RSI规格，如 rsi(period=9)
+func (s *MeanReversionStrategy) SetRSISpec(spec string) error {
+	parsed, err := indicators.ParseSpec(spec)
+	if err != nil {
+		return err
+	}
+	resolved, def, err := indicators.DefaultRegistry().Resolve(parsed)
+	if err != nil {
+		return err
+	}
+	if def.Name != "rsi" && def.Name != "stochrsi" {
+		return fmt.Errorf("%s is not an RSI indicator", resolved)
+	}
+	s.rsiSpec = resolved.String()
+	return nil
+}
+
+// ConfigureAnalyzer 使用策略的布林带参数，并请求自定义RSI
+func (s *MeanReversionStrategy) ConfigureAnalyzer(analyzer *analysis.TrendAnalyzer) {
+	analyzer.SetBollingerParams(s.bollingerPeriod, s.bollingerStdDev)
+	if s.rsiSpec != "" {
+		if keys, err := analyzer.RequestIndicators(s.rsiSpec); err == nil {
+			s.rsiKey = keys[0]
+		}
+	}
+}
+
+// rsi 返回策略使用的RSI值
+func (s *MeanReversionStrategy) rsi(analysis *types.Analysis) float64 {
+	if s.rsiKey != "" {
+		if value, ok := analysis.Indicators[s.rsiKey]; ok {
+			return value
+		}
+	}
+	return analysis.Momentum.RSI
+}
+
 // ShouldEnter 均值回归入场
-func (s *MeanReversionStrategy) ShouldEnter(analysis *types.Analysis, evidenceSummary map[string]interface{}, position float64) (bool, string) {
+func (s *MeanReversionStrategy) ShouldEnter(analysis *types.Analysis, evidenceSummary map[string]interface{}, position float64) (bool, Reason) {
 	if position > 0 {
-		return false, ""
+		return false, Reason{}
 	}
 	
 	// RSI超卖
-	if analysis.Momentum.RSI >= s.oversoldRSI {
-		return false, ""
+	rsi := s.rsi(analysis)
+	if rsi >= s.thresholds.RSIOversold {
+		return false, Reason{}
 	}
 	
 	// 价格必须远离均线（超卖）
 	deviation := (analysis.CurrentPrice - analysis.MAAnalysis.MA20) / analysis.MAAnalysis.MA20
 	if deviation > -0.03 { // 必须低于MA20至少3%
-		return false, ""
+		return false, Reason{}
+	}
+	
+	// 价格必须触及布林下轨
+	if analysis.Channels.Available && analysis.Channels.PercentB > 0 {
+		return false, Reason{}
 	}
 	
 	// ADX低于25，表示没有强趋势
 	if analysis.TrendStrength.ADX > 25 {
-		return false, ""
+		return false, Reason{}
 	}
 	
 	// 价格接近支撑位
-	s1 := analysis.SupportResistance.Support["S1"]
-	if analysis.CurrentPrice > s1*1.01 { // 必须接近S1（1%以内）
-		return false, ""
+	s1 := analysis.SupportResistance.SupportTarget()
+	if analysis.CurrentPrice > s1*1.01 { // 必须接近最近支撑（1%以内）
+		return false, Reason{}
 	}
 	
-	reason := fmt.Sprintf("超卖反弹(RSI:%.1f,偏离:%.1f%%)", 
-		analysis.Momentum.RSI, deviation*100)
-	
-	return true, reason
+	return true, NewReason("oversold_bounce", rsi, deviation*100, analysis.Channels.PercentB)
 }
 
 // ShouldExit 均值回归出场
-func (s *MeanReversionStrategy) ShouldExit(analysis *types.Analysis, evidenceSummary map[string]interface{}, position float64, entryPrice float64) (bool, string) {
+func (s *MeanReversionStrategy) ShouldExit(analysis *types.Analysis, evidenceSummary map[string]interface{}, position float64, entryPrice float64) (bool, Reason) {
 	if position <= 0 {
-		return false, ""
+		return false, Reason{}
 	}
 	
-	// 回归均值
+	// 回归均值（布林中轨）
+	if analysis.Channels.Available && analysis.CurrentPrice >= analysis.Channels.BBMiddle {
+		return true, NewReason("bollinger_middle")
+	}
 	if analysis.CurrentPrice >= analysis.MAAnalysis.MA20 {
-		return true, "回归MA20"
+		return true, NewReason("back_to_ma20")
 	}
 	
 	// RSI恢复正常
-	if analysis.Momentum.RSI > 50 {
-		return true, "RSI恢复"
+	if s.rsi(analysis) > s.thresholds.RSIMidline {
+		return true, NewReason("rsi_recovered")
 	}
 	
 	// 达到阻力位
-	if analysis.CurrentPrice >= analysis.SupportResistance.Resistance["R1"]*0.99 {
-		return true, "接近阻力"
+	if analysis.CurrentPrice >= analysis.SupportResistance.ResistanceTarget()*0.99 {
+		return true, NewReason("near_resistance")
 	}
 	
 	// 止盈3%
 	if analysis.CurrentPrice >= entryPrice*1.03 {
-		return true, "达到止盈"
+		return true, NewReason("target_reached")
 	}
 	
-	return false, ""
+	return false, Reason{}
 }
 
 // GetStopLoss 均值回归止损
 func (s *MeanReversionStrategy) GetStopLoss(entryPrice float64, analysis *types.Analysis) float64 {
-	// 使用S2作为止损
-	return analysis.SupportResistance.Support["S2"]
+	// 有效跌破入场所依托的支撑位即止损，没有结构支撑时使用S2（DeMark轴心点没有S2，用S1）
+	if support := analysis.SupportResistance.NearestSupport.Price; support > 0 && support < entryPrice {
+		return support * 0.99
+	}
+	if s2, ok := analysis.SupportResistance.Support["S2"]; ok {
+		return s2
+	}
+	return analysis.SupportResistance.Support["S1"] * 0.99
 }
 
 // GetTakeProfit 均值回归止盈
 func (s *MeanReversionStrategy) GetTakeProfit(entryPrice float64, analysis *types.Analysis) float64 {
-	// 目标是回到MA20
+	// 目标是回到MA20，价格低于价值区时以POC为价值回归目标
 	target := analysis.MAAnalysis.MA20
+	if sr := analysis.SupportResistance; sr.POC > 0 && entryPrice < sr.VAL {
+		target = sr.POC
+	}
 	
 	// 但至少要有3%利润
 	minProfit := entryPrice * 1.03
@@ -363,6 +491,7 @@ type ComboAdaptiveStrategy struct {
 	momentumStrategy  *MomentumBreakoutStrategy
 	reversionStrategy *MeanReversionStrategy
 	currentMode       string
+	thresholds        analysis.Thresholds // 市场状态判断的RSI阈值
 }
 
 // NewComboAdaptiveStrategy 创建自适应组合策略
@@ -372,9 +501,24 @@ func NewComboAdaptiveStrategy() *ComboAdaptiveStrategy {
 		momentumStrategy:  NewMomentumBreakoutStrategy(),
 		reversionStrategy: NewMeanReversionStrategy(),
 		currentMode:       "detecting",
+		thresholds:        analysis.DefaultThresholds(),
 	}
 }
 
+// SetThresholds 设置自身和子策略的RSI阈值
+func (s *ComboAdaptiveStrategy) SetThresholds(thresholds analysis.Thresholds) {
+	s.thresholds = thresholds
+	s.trendStrategy.SetThresholds(thresholds)
+	s.momentumStrategy.SetThresholds(thresholds)
+	s.reversionStrategy.SetThresholds(thresholds)
+}
+
+// ConfigureAnalyzer 应用子策略的分析参数
+func (s *ComboAdaptiveStrategy) ConfigureAnalyzer(analyzer *analysis.TrendAnalyzer) {
+	s.momentumStrategy.ConfigureAnalyzer(analyzer)
+	s.reversionStrategy.ConfigureAnalyzer(analyzer)
+}
+
 // DetectMarketCondition 检测市场状态
 func (s *ComboAdaptiveStrategy) DetectMarketCondition(analysis *types.Analysis) string {
 	adx := analysis.TrendStrength.ADX
@@ -388,12 +532,12 @@ func (s *ComboAdaptiveStrategy) DetectMarketCondition(analysis *types.Analysis)
 	}
 	
 	// 动量市场
-	if adx > 20 && adx <= 35 && rsi > 50 && rsi < 70 {
+	if adx > 20 && adx <= 35 && rsi > s.thresholds.RSIMidline && rsi < s.thresholds.RSIOverbought {
 		return "momentum"
 	}
 	
 	// 超卖反弹机会
-	if adx < 25 && rsi < 30 {
+	if adx < 25 && rsi < s.thresholds.RSIOversold {
 		return "reversion"
 	}
 	
@@ -402,9 +546,9 @@ func (s *ComboAdaptiveStrategy) DetectMarketCondition(analysis *types.Analysis)
 }
 
 // ShouldEnter 自适应策略入场
-func (s *ComboAdaptiveStrategy) ShouldEnter(analysis *types.Analysis, evidenceSummary map[string]interface{}, position float64) (bool, string) {
+func (s *ComboAdaptiveStrategy) ShouldEnter(analysis *types.Analysis, evidenceSummary map[string]interface{}, position float64) (bool, Reason) {
 	if position > 0 {
-		return false, ""
+		return false, Reason{}
 	}
 	
 	// 检测市场状态
@@ -414,25 +558,25 @@ func (s *ComboAdaptiveStrategy) ShouldEnter(analysis *types.Analysis, evidenceSu
 	switch marketCondition {
 	case "trending":
 		if enter, reason := s.trendStrategy.ShouldEnter(analysis, evidenceSummary, position); enter {
-			return true, "[趋势模式] " + reason
+			return true, NewReason("mode_trend", reason)
 		}
 	case "momentum":
 		if enter, reason := s.momentumStrategy.ShouldEnter(analysis, evidenceSummary, position); enter {
-			return true, "[动量模式] " + reason
+			return true, NewReason("mode_momentum", reason)
 		}
 	case "reversion":
 		if enter, reason := s.reversionStrategy.ShouldEnter(analysis, evidenceSummary, position); enter {
-			return true, "[反转模式] " + reason
+			return true, NewReason("mode_reversion", reason)
 		}
 	}
 	
-	return false, ""
+	return false, Reason{}
 }
 
 // ShouldExit 自适应策略出场
-func (s *ComboAdaptiveStrategy) ShouldExit(analysis *types.Analysis, evidenceSummary map[string]interface{}, position float64, entryPrice float64) (bool, string) {
+func (s *ComboAdaptiveStrategy) ShouldExit(analysis *types.Analysis, evidenceSummary map[string]interface{}, position float64, entryPrice float64) (bool, Reason) {
 	if position <= 0 {
-		return false, ""
+		return false, Reason{}
 	}
 	
 	// 根据入场模式选择出场策略
@@ -447,10 +591,10 @@ func (s *ComboAdaptiveStrategy) ShouldExit(analysis *types.Analysis, evidenceSum
 	
 	// 默认止损
 	if analysis.CurrentPrice < entryPrice*0.95 {
-		return true, "默认止损5%"
+		return true, NewReason("default_stop")
 	}
 	
-	return false, ""
+	return false, Reason{}
 }
 
 // GetStopLoss 自适应策略止损
@@ -1,9 +1,10 @@
 package backtest
 
 import (
-	"fmt"
 	"math"
 
+	"github.com/zjc/go-crypto-analyzer/pkg/analysis"
+	"github.com/zjc/go-crypto-analyzer/pkg/stats"
 	"github.com/zjc/go-crypto-analyzer/pkg/types"
 )
 
@@ -11,17 +12,18 @@ import (
 type ImprovedBidirectionalStrategy struct {
 	// 市场状态检测
 	trendStrengthThreshold float64  // ADX阈值
-	volatilityPeriod       int      // 波动率计算周期
+	dynamic                *analysis.DynamicAnalyzer // 按市场状态调整证据权重和RSI阈值
 	
 	// 入场条件
-	longSignalThreshold    float64  // 做多信号阈值
-	shortSignalThreshold   float64  // 做空信号阈值
-	volumeConfirmation     float64  // 成交量确认倍数
+	bollingerPeriod        int      // 布林带周期
+	bollingerStdDev        float64  // 布林带标准差倍数
 	
 	// 风险管理
 	dynamicStopLoss        bool     // 是否使用动态止损
 	atrMultiplier          float64  // ATR止损倍数
 	trailingStop           bool     // 是否使用移动止损
+	avwapStop              bool     // 是否用锚定VWAP收紧止损
+	thresholds             analysis.Thresholds // RSI、入场信号、波动率和止盈反转阈值
 	
 	// 市场状态
 	currentMarketRegime    string   // trending/ranging/volatile
@@ -32,25 +34,40 @@ type ImprovedBidirectionalStrategy struct {
 func NewImprovedBidirectionalStrategy() *ImprovedBidirectionalStrategy {
 	return &ImprovedBidirectionalStrategy{
 		trendStrengthThreshold: 25.0,
-		volatilityPeriod:       20,
-		longSignalThreshold:    0.6,
-		shortSignalThreshold:   -0.6,
-		volumeConfirmation:     1.5,
+		dynamic:                analysis.NewDynamicAnalyzer(),
+		bollingerPeriod:        20,
+		bollingerStdDev:        2.0,
 		dynamicStopLoss:        true,
 		atrMultiplier:          2.0,
 		trailingStop:           true,
+		thresholds:             analysis.DefaultThresholds(),
 		currentMarketRegime:    "unknown",
 		positionBias:           "neutral",
 	}
 }
 
+// SetThresholds 设置RSI、入场信号、波动率和止盈反转阈值
+func (s *ImprovedBidirectionalStrategy) SetThresholds(thresholds analysis.Thresholds) {
+	s.thresholds = thresholds
+}
+
+// SetBollingerParams 设置入场判断所用布林带的周期和标准差倍数
+func (s *ImprovedBidirectionalStrategy) SetBollingerParams(period int, stdDev float64) {
+	s.bollingerPeriod = period
+	s.bollingerStdDev = stdDev
+}
+
+// SetLearning 设置动态分析的基础权重和自适应RSI阈值（通常来自 configs/ml_config.yaml）
+func (s *ImprovedBidirectionalStrategy) SetLearning(learning analysis.Learning) {
+	s.dynamic.SetLearning(learning)
+}
+
 // AnalyzeMarketRegime 分析市场状态
-func (s *ImprovedBidirectionalStrategy) AnalyzeMarketRegime(analysis *types.Analysis, data []types.OHLCV) string {
+// 波动率和Hurst/方差比状态取分析结果，与分析器和动态权重使用同一组数值
+func (s *ImprovedBidirectionalStrategy) AnalyzeMarketRegime(analysis *types.Analysis) string {
 	adx := analysis.TrendStrength.ADX
-	
-	// 计算最近的波动率
-	volatility := s.calculateVolatility(data, s.volatilityPeriod)
-	avgVolatility := s.calculateVolatility(data, 50)
+	vol := analysis.Volatility
+	s.dynamic.AdjustWeights(vol, analysis.Volume, adx)
 	
 	// 趋势强度分析
 	if adx > 40 {
@@ -63,14 +80,15 @@ func (s *ImprovedBidirectionalStrategy) AnalyzeMarketRegime(analysis *types.Anal
 		}
 	}
 	
-	// 区间震荡市场
-	if adx < 20 && volatility < avgVolatility*0.8 {
+	// 区间震荡市场：低波动或Hurst/方差比显示均值回归
+	// 短期Yang–Zhang波动率相对长期基准的比值低于 VolatilityLow 视为低波动
+	if adx < 20 && ((vol.Available && vol.Ratio < s.thresholds.VolatilityLow) || vol.Regime == stats.RegimeMeanReverting) {
 		s.positionBias = "neutral"
 		return "ranging"
 	}
 	
 	// 高波动市场
-	if volatility > avgVolatility*1.5 {
+	if vol.Available && vol.Ratio > s.thresholds.VolatilityHigh {
 		return "volatile"
 	}
 	
@@ -88,31 +106,18 @@ func (s *ImprovedBidirectionalStrategy) AnalyzeMarketRegime(analysis *types.Anal
 	return "neutral"
 }
 
-// calculateVolatility 计算波动率
-func (s *ImprovedBidirectionalStrategy) calculateVolatility(data []types.OHLCV, period int) float64 {
-	if len(data) < period {
-		return 0
-	}
-	
-	returns := make([]float64, period-1)
-	for i := len(data)-period+1; i < len(data); i++ {
-		returns[i-(len(data)-period+1)] = math.Log(data[i].Close / data[i-1].Close)
-	}
-	
-	// 计算标准差
-	mean := 0.0
-	for _, r := range returns {
-		mean += r
-	}
-	mean /= float64(len(returns))
-	
-	variance := 0.0
-	for _, r := range returns {
-		variance += math.Pow(r-mean, 2)
-	}
-	variance /= float64(len(returns))
-	
-	return math.Sqrt(variance) * math.Sqrt(252*24) // 年化波动率（小时数据）
+// weightedStrength 按当前市场状态评估的证据总强度（见 DynamicAnalyzer.EvaluateEvidence）
+func (s *ImprovedBidirectionalStrategy) weightedStrength(analysis *types.Analysis, evidenceSummary map[string]interface{}) float64 {
+	evidences, ok := evidenceSummary["allEvidences"].([]types.Evidence)
+	if !ok {
+		return evidenceSummary["totalStrength"].(float64)
+	}
+	context := map[string]interface{}{"volumeRatio": analysis.Volume.VolumeRatio}
+	total := 0.0
+	for _, evidence := range evidences {
+		total += s.dynamic.EvaluateEvidence(evidence, context)
+	}
+	return total
 }
 
 // ShouldOpenLong 判断是否开多
@@ -121,35 +126,36 @@ func (s *ImprovedBidirectionalStrategy) ShouldOpenLong(
 	evidenceSummary map[string]interface{},
 	marketRegime string,
 	data []types.OHLCV,
-) (bool, string) {
+) (bool, Reason) {
 	
-	totalStrength := evidenceSummary["totalStrength"].(float64)
+	totalStrength := s.weightedStrength(analysis, evidenceSummary)
+	rsiBand := s.dynamic.RSIBand(analysis.MAAnalysis.Trend)
 	
 	// 市场状态过滤
 	switch marketRegime {
 	case "strong_downtrend", "downtrend":
 		// 下跌趋势中不做多
-		return false, ""
+		return false, Reason{}
 	case "ranging":
 		// 区间震荡需要更强的信号
-		if totalStrength < s.longSignalThreshold*1.2 {
-			return false, ""
+		if totalStrength < s.thresholds.LongSignal*1.2 {
+			return false, Reason{}
 		}
 	case "volatile":
 		// 高波动市场谨慎做多
-		if analysis.Momentum.RSI > 60 {
-			return false, ""
+		if analysis.Momentum.RSI > s.thresholds.RSIStrong {
+			return false, Reason{}
 		}
 	}
 	
 	// 基本信号强度检查
-	if totalStrength < s.longSignalThreshold {
-		return false, ""
+	if totalStrength < s.thresholds.LongSignal {
+		return false, Reason{}
 	}
 	
 	// 成交量确认
-	if analysis.Volume.VolumeRatio < s.volumeConfirmation {
-		return false, ""
+	if analysis.Volume.VolumeRatio < s.thresholds.VolumeConfirmation {
+		return false, Reason{}
 	}
 	
 	// 技术指标确认
@@ -161,8 +167,8 @@ func (s *ImprovedBidirectionalStrategy) ShouldOpenLong(
 		confirmations++
 	}
 	
-	// RSI确认（不能超买）
-	if analysis.Momentum.RSI > 30 && analysis.Momentum.RSI < 70 {
+	// RSI确认（不能超买，阈值随趋势自适应）
+	if analysis.Momentum.RSI > rsiBand.Oversold && analysis.Momentum.RSI < rsiBand.Overbought {
 		confirmations++
 	}
 	
@@ -173,18 +179,17 @@ func (s *ImprovedBidirectionalStrategy) ShouldOpenLong(
 	}
 	
 	// 布林带确认
-	bb := s.calculateBollingerBands(data, 20, 2)
+	bb := s.calculateBollingerBands(data, s.bollingerPeriod, s.bollingerStdDev)
 	if analysis.CurrentPrice > bb.lower && analysis.CurrentPrice < bb.middle {
 		confirmations++
 	}
 	
 	// 需要至少3个确认信号
 	if confirmations < 3 {
-		return false, ""
+		return false, Reason{}
 	}
 	
-	reason := fmt.Sprintf("做多信号(强度:%.2f,确认:%d,市场:%s)", 
-		totalStrength, confirmations, marketRegime)
+	reason := NewReason("long_signal", totalStrength, confirmations, marketRegime)
 	
 	return true, reason
 }
@@ -195,35 +200,36 @@ func (s *ImprovedBidirectionalStrategy) ShouldOpenShort(
 	evidenceSummary map[string]interface{},
 	marketRegime string,
 	data []types.OHLCV,
-) (bool, string) {
+) (bool, Reason) {
 	
-	totalStrength := evidenceSummary["totalStrength"].(float64)
+	totalStrength := s.weightedStrength(analysis, evidenceSummary)
+	rsiBand := s.dynamic.RSIBand(analysis.MAAnalysis.Trend)
 	
 	// 市场状态过滤
 	switch marketRegime {
 	case "strong_uptrend", "uptrend":
 		// 上涨趋势中不做空
-		return false, ""
+		return false, Reason{}
 	case "ranging":
 		// 区间震荡需要更强的信号
-		if totalStrength > s.shortSignalThreshold*1.2 {
-			return false, ""
+		if totalStrength > s.thresholds.ShortSignal*1.2 {
+			return false, Reason{}
 		}
 	case "volatile":
 		// 高波动市场谨慎做空
-		if analysis.Momentum.RSI < 40 {
-			return false, ""
+		if analysis.Momentum.RSI < s.thresholds.RSIWeak {
+			return false, Reason{}
 		}
 	}
 	
 	// 基本信号强度检查
-	if totalStrength > s.shortSignalThreshold {
-		return false, ""
+	if totalStrength > s.thresholds.ShortSignal {
+		return false, Reason{}
 	}
 	
 	// 成交量确认
-	if analysis.Volume.VolumeRatio < s.volumeConfirmation {
-		return false, ""
+	if analysis.Volume.VolumeRatio < s.thresholds.VolumeConfirmation {
+		return false, Reason{}
 	}
 	
 	// 技术指标确认
@@ -235,8 +241,8 @@ func (s *ImprovedBidirectionalStrategy) ShouldOpenShort(
 		confirmations++
 	}
 	
-	// RSI确认（不能超卖）
-	if analysis.Momentum.RSI < 70 && analysis.Momentum.RSI > 30 {
+	// RSI确认（不能超卖，阈值随趋势自适应）
+	if analysis.Momentum.RSI < rsiBand.Overbought && analysis.Momentum.RSI > rsiBand.Oversold {
 		confirmations++
 	}
 	
@@ -247,18 +253,17 @@ func (s *ImprovedBidirectionalStrategy) ShouldOpenShort(
 	}
 	
 	// 布林带确认
-	bb := s.calculateBollingerBands(data, 20, 2)
+	bb := s.calculateBollingerBands(data, s.bollingerPeriod, s.bollingerStdDev)
 	if analysis.CurrentPrice < bb.upper && analysis.CurrentPrice > bb.middle {
 		confirmations++
 	}
 	
 	// 需要至少3个确认信号
 	if confirmations < 3 {
-		return false, ""
+		return false, Reason{}
 	}
 	
-	reason := fmt.Sprintf("做空信号(强度:%.2f,确认:%d,市场:%s)", 
-		totalStrength, confirmations, marketRegime)
+	reason := NewReason("short_signal", totalStrength, confirmations, marketRegime)
 	
 	return true, reason
 }
@@ -270,37 +275,37 @@ func (s *ImprovedBidirectionalStrategy) ShouldCloseLong(
 	entryPrice float64,
 	currentPrice float64,
 	marketRegime string,
-) (bool, string) {
+) (bool, Reason) {
 	
-	totalStrength := evidenceSummary["totalStrength"].(float64)
+	totalStrength := s.weightedStrength(analysis, evidenceSummary)
 	profitPct := (currentPrice - entryPrice) / entryPrice
 	
 	// 止盈条件
-	if profitPct > 0.05 && totalStrength < 0 {
-		return true, fmt.Sprintf("止盈平多(收益:%.2f%%)", profitPct*100)
+	if profitPct > s.thresholds.TakeProfit && totalStrength < 0 {
+		return true, NewReason("take_profit_long", profitPct*100)
 	}
 	
 	// 趋势反转
 	if marketRegime == "downtrend" || marketRegime == "strong_downtrend" {
-		return true, "趋势反转平多"
+		return true, NewReason("trend_reversal_long")
 	}
 	
 	// 技术指标背离
-	if analysis.MACDAnalysis.Histogram < 0 && analysis.Momentum.RSI > 70 {
-		return true, "技术背离平多"
+	if analysis.MACDAnalysis.Histogram < 0 && analysis.Momentum.RSI > s.thresholds.RSIOverbought {
+		return true, NewReason("divergence_long")
 	}
 	
 	// 跌破关键支撑
 	if currentPrice < analysis.MAAnalysis.MA20*0.98 {
-		return true, "跌破MA20平多"
+		return true, NewReason("below_ma20_long")
 	}
 	
 	// 强烈看跌信号
-	if totalStrength < -0.8 {
-		return true, fmt.Sprintf("强烈看跌平多(强度:%.2f)", totalStrength)
+	if totalStrength < -s.thresholds.ReversalStrength {
+		return true, NewReason("strong_bearish_long", totalStrength)
 	}
 	
-	return false, ""
+	return false, Reason{}
 }
 
 // ShouldCloseShort 判断是否平空
@@ -310,37 +315,37 @@ func (s *ImprovedBidirectionalStrategy) ShouldCloseShort(
 	entryPrice float64,
 	currentPrice float64,
 	marketRegime string,
-) (bool, string) {
+) (bool, Reason) {
 	
-	totalStrength := evidenceSummary["totalStrength"].(float64)
+	totalStrength := s.weightedStrength(analysis, evidenceSummary)
 	profitPct := (entryPrice - currentPrice) / entryPrice
 	
 	// 止盈条件
-	if profitPct > 0.05 && totalStrength > 0 {
-		return true, fmt.Sprintf("止盈平空(收益:%.2f%%)", profitPct*100)
+	if profitPct > s.thresholds.TakeProfit && totalStrength > 0 {
+		return true, NewReason("take_profit_short", profitPct*100)
 	}
 	
 	// 趋势反转
 	if marketRegime == "uptrend" || marketRegime == "strong_uptrend" {
-		return true, "趋势反转平空"
+		return true, NewReason("trend_reversal_short")
 	}
 	
 	// 技术指标背离
-	if analysis.MACDAnalysis.Histogram > 0 && analysis.Momentum.RSI < 30 {
-		return true, "技术背离平空"
+	if analysis.MACDAnalysis.Histogram > 0 && analysis.Momentum.RSI < s.thresholds.RSIOversold {
+		return true, NewReason("divergence_short")
 	}
 	
 	// 突破关键阻力
 	if currentPrice > analysis.MAAnalysis.MA20*1.02 {
-		return true, "突破MA20平空"
+		return true, NewReason("above_ma20_short")
 	}
 	
 	// 强烈看涨信号
-	if totalStrength > 0.8 {
-		return true, fmt.Sprintf("强烈看涨平空(强度:%.2f)", totalStrength)
+	if totalStrength > s.thresholds.ReversalStrength {
+		return true, NewReason("strong_bullish_short", totalStrength)
 	}
 	
-	return false, ""
+	return false, Reason{}
 }
 
 // GetDynamicStopLoss 获取动态止损价格
@@ -380,6 +385,36 @@ func (s *ImprovedBidirectionalStrategy) GetDynamicStopLoss(
 	}
 }
 
+// UseAnchoredVWAPStop 使用前低/前高锚定的VWAP收紧动态止损
+func (s *ImprovedBidirectionalStrategy) UseAnchoredVWAPStop(use bool) {
+	s.avwapStop = use
+}
+
+// ApplyAnchoredVWAPStop 用锚定VWAP收紧止损：多头取前低AVWAP，空头取前高AVWAP
+func (s *ImprovedBidirectionalStrategy) ApplyAnchoredVWAPStop(
+	stopLoss float64,
+	currentPrice float64,
+	positionType PositionType,
+	analysis *types.Analysis,
+) float64 {
+	if !s.avwapStop {
+		return stopLoss
+	}
+	
+	if positionType == LongPosition {
+		avwap := analysis.VWAP.AnchoredLow
+		if avwap > 0 && avwap < currentPrice {
+			return math.Max(stopLoss, avwap)
+		}
+	} else {
+		avwap := analysis.VWAP.AnchoredHigh
+		if avwap > currentPrice {
+			return math.Min(stopLoss, avwap)
+		}
+	}
+	return stopLoss
+}
+
 // BollingerBands 布林带
 type BollingerBands struct {
 	upper  float64
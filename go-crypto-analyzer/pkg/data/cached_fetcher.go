@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/zjc/go-crypto-analyzer/pkg/cache"
+	"github.com/zjc/go-crypto-analyzer/pkg/i18n"
 	"github.com/zjc/go-crypto-analyzer/pkg/types"
 )
 
@@ -39,7 +40,7 @@ func (cf *CachedFetcher) FetchOHLCV(symbol string, interval string, limit int) (
 	// 缓存不存在或数据不够，需要获取新数据
 	if !exists || len(cachedData) == 0 {
 		// 完全没有缓存，获取全部数据
-		fmt.Printf("  📥 首次获取数据，请求 %d 根K线...\n", limit)
+		fmt.Println(i18n.T("cache.first_fetch", limit))
 		newData, err := cf.fetcher.FetchOHLCV(symbol, interval, limit)
 		if err != nil {
 			return nil, err
@@ -47,7 +48,7 @@ func (cf *CachedFetcher) FetchOHLCV(symbol string, interval string, limit int) (
 		
 		// 保存到缓存
 		cf.cache.Set(symbol, interval, newData)
-		fmt.Printf("  💾 已缓存 %d 根K线数据\n", len(newData))
+		fmt.Println(i18n.T("cache.saved", len(newData)))
 		
 		return newData, nil
 	}
@@ -68,7 +69,7 @@ func (cf *CachedFetcher) fetchIncremental(symbol string, interval string, limit
 	
 	// 如果预期新数据很少，且缓存数据足够，直接使用缓存
 	if expectedNewBars < 5 && len(cachedData) >= limit {
-		fmt.Printf("  ⚡ 使用缓存数据（最新: %s）\n", latestTime.Format("01-02 15:04"))
+		fmt.Println(i18n.T("cache.hit", latestTime.Format("01-02 15:04")))
 		start := len(cachedData) - limit
 		if start < 0 {
 			start = 0
@@ -82,11 +83,11 @@ func (cf *CachedFetcher) fetchIncremental(symbol string, interval string, limit
 		fetchLimit = 50 // 至少获取50根
 	}
 	
-	fmt.Printf("  🔄 增量更新：获取最新 %d 根K线...\n", fetchLimit)
+	fmt.Println(i18n.T("cache.incremental", fetchLimit))
 	newData, err := cf.fetcher.FetchOHLCV(symbol, interval, fetchLimit)
 	if err != nil {
 		// 如果获取失败，返回缓存数据
-		fmt.Printf("  ⚠️  获取新数据失败，使用缓存数据\n")
+		fmt.Println(i18n.T("cache.fetch_failed"))
 		if len(cachedData) >= limit {
 			start := len(cachedData) - limit
 			return cachedData[start:], nil
@@ -96,7 +97,7 @@ func (cf *CachedFetcher) fetchIncremental(symbol string, interval string, limit
 	
 	// 更新缓存
 	cf.cache.Update(symbol, interval, newData)
-	fmt.Printf("  ✅ 更新成功，新增 %d 根K线\n", len(newData))
+	fmt.Println(i18n.T("cache.updated", len(newData)))
 	
 	// 重新获取更新后的缓存
 	updatedData, _ := cf.cache.Get(symbol, interval)
@@ -129,5 +130,5 @@ func (cf *CachedFetcher) calculateExpectedBars(interval string, duration time.Du
 // ClearCache 清除缓存
 func (cf *CachedFetcher) ClearCache(symbol, interval string) {
 	cf.cache.Clear(symbol, interval)
-	fmt.Printf("  🗑️  已清除 %s %s 的缓存\n", symbol, interval)
+	fmt.Println(i18n.T("cache.cleared", symbol, interval))
 }
\ No newline at end of file
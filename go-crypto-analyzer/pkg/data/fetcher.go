@@ -253,17 +253,17 @@ func (fg *FearGreedFetcher) Fetch() (*types.FearGreedIndex, error) {
 	value, _ := strconv.Atoi(result.Data[0].Value)
 	timestamp, _ := strconv.ParseInt(result.Data[0].Timestamp, 10, 64)
 
-	sentiment := ""
+	sentiment := types.NeutralMood
 	if value < 25 {
-		sentiment = "极度恐慌 - 可能是买入机会"
+		sentiment = types.ExtremeFear
 	} else if value < 45 {
-		sentiment = "恐慌 - 市场偏空"
+		sentiment = types.Fear
 	} else if value < 55 {
-		sentiment = "中性 - 观望为主"
+		sentiment = types.NeutralMood
 	} else if value < 75 {
-		sentiment = "贪婪 - 市场偏多"
+		sentiment = types.Greed
 	} else {
-		sentiment = "极度贪婪 - 注意风险"
+		sentiment = types.ExtremeGreed
 	}
 
 	return &types.FearGreedIndex{
@@ -5,8 +5,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
 	"time"
 	
+	"github.com/zjc/go-crypto-analyzer/pkg/i18n"
+	"github.com/zjc/go-crypto-analyzer/pkg/indicators"
 	"github.com/zjc/go-crypto-analyzer/pkg/types"
 )
 
@@ -68,11 +71,14 @@ func (e *Exporter) exportCSV(filename string, analysis *types.Analysis, evidence
 	defer writer.Flush()
 	
 	// 写入头部
-	headers := []string{
-		"时间", "交易对", "价格", "趋势", "趋势得分",
-		"RSI", "MACD", "ADX", "成交量比",
-		"MA5", "MA20", "MA50",
+	headers := i18n.List("export.analysis_header")
+	// 按规格请求的自定义指标
+	specs := make([]string, 0, len(analysis.Indicators))
+	for spec := range analysis.Indicators {
+		specs = append(specs, spec)
 	}
+	sort.Strings(specs)
+	headers = append(headers, specs...)
 	if err := writer.Write(headers); err != nil {
 		return err
 	}
@@ -82,7 +88,7 @@ func (e *Exporter) exportCSV(filename string, analysis *types.Analysis, evidence
 		analysis.Timestamp.Format("2006-01-02 15:04:05"),
 		analysis.Symbol,
 		fmt.Sprintf("%.2f", analysis.CurrentPrice),
-		string(analysis.OverallTrend),
+		analysis.OverallTrend.String(),
 		fmt.Sprintf("%.2f", analysis.TrendScore),
 		fmt.Sprintf("%.1f", analysis.Momentum.RSI),
 		fmt.Sprintf("%.2f", analysis.MACDAnalysis.MACD),
@@ -92,10 +98,68 @@ func (e *Exporter) exportCSV(filename string, analysis *types.Analysis, evidence
 		fmt.Sprintf("%.2f", analysis.MAAnalysis.MA20),
 		fmt.Sprintf("%.2f", analysis.MAAnalysis.MA50),
 	}
+	for _, spec := range specs {
+		row = append(row, fmt.Sprintf("%.4f", analysis.Indicators[spec]))
+	}
 	
 	return writer.Write(row)
 }
 
+// ExportIndicators 按规格计算指标序列并与K线时间一起导出为CSV，
+// 预热期内的值留空，返回导出的文件名
+func (e *Exporter) ExportIndicators(symbol string, data []types.OHLCV, specs []string) (string, error) {
+	registry := indicators.DefaultRegistry()
+	results := make([]*indicators.Result, 0, len(specs))
+	for _, spec := range specs {
+		result, err := registry.Compute(spec, data)
+		if err != nil {
+			return "", err
+		}
+		results = append(results, result)
+	}
+	
+	filename := fmt.Sprintf("indicators_%s_%s.csv", 
+		symbol, 
+		time.Now().Format("20060102_150405"))
+	
+	file, err := os.Create(filename)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+	
+	headers := i18n.List("export.indicators_header")
+	for _, result := range results {
+		headers = append(headers, result.Spec.String())
+	}
+	if err := writer.Write(headers); err != nil {
+		return "", err
+	}
+	
+	for i, candle := range data {
+		row := []string{
+			candle.Time.Format("2006-01-02 15:04:05"),
+			fmt.Sprintf("%.2f", candle.Close),
+		}
+		for _, result := range results {
+			value := ""
+			if i >= result.WarmUp {
+				value = fmt.Sprintf("%.4f", result.Series()[i])
+			}
+			row = append(row, value)
+		}
+		if err := writer.Write(row); err != nil {
+			return "", err
+		}
+	}
+	
+	writer.Flush()
+	return filename, writer.Error()
+}
+
 // ExportOHLCV 导出K线数据
 func (e *Exporter) ExportOHLCV(symbol string, data []types.OHLCV) error {
 	filename := fmt.Sprintf("ohlcv_%s_%s.csv", 
@@ -112,7 +176,7 @@ func (e *Exporter) ExportOHLCV(symbol string, data []types.OHLCV) error {
 	defer writer.Flush()
 	
 	// 写入头部
-	headers := []string{"时间", "开盘", "最高", "最低", "收盘", "成交量"}
+	headers := i18n.List("export.ohlcv_header")
 	if err := writer.Write(headers); err != nil {
 		return err
 	}
@@ -0,0 +1,326 @@
+package expr
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/zjc/go-crypto-analyzer/pkg/indicators"
+)
+
+// sourceInput 非行情序列的源表达式求值后在输入表中的键
+const sourceInput indicators.Input = "expr"
+
+// evalContext 求值上下文，所有序列与K线对齐
+type evalContext struct {
+	inputs   indicators.Inputs
+	length   int
+	registry *indicators.Registry
+}
+
+// node 语法树节点，求值结果为与K线等长的序列
+// 布尔值以1/0表示，NaN表示该位置无定义（预热期或偏移越界）；逻辑运算按三值逻辑处理，
+// 结果取决于无定义的操作数时仍为NaN，如 false and NaN 为假、true and NaN 无定义
+type node interface {
+	eval(ctx *evalContext) ([]float64, error)
+	boolean() bool
+	String() string
+}
+
+type numberNode struct {
+	value  float64
+	isBool bool
+}
+
+func (n *numberNode) eval(ctx *evalContext) ([]float64, error) {
+	out := make([]float64, ctx.length)
+	for i := range out {
+		out[i] = n.value
+	}
+	return out, nil
+}
+
+func (n *numberNode) boolean() bool { return n.isBool }
+
+func (n *numberNode) String() string {
+	if n.isBool {
+		if n.value != 0 {
+			return "true"
+		}
+		return "false"
+	}
+	return formatNumber(n.value)
+}
+
+type seriesNode struct {
+	input indicators.Input
+}
+
+func (n *seriesNode) eval(ctx *evalContext) ([]float64, error) {
+	return ctx.inputs[n.input], nil
+}
+
+func (n *seriesNode) boolean() bool { return false }
+
+func (n *seriesNode) String() string { return string(n.input) }
+
+type notNode struct {
+	operand node
+}
+
+func (n *notNode) eval(ctx *evalContext) ([]float64, error) {
+	values, err := n.operand.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]float64, len(values))
+	for i, v := range values {
+		out[i] = math.NaN()
+		if !math.IsNaN(v) {
+			out[i] = boolValue(v == 0)
+		}
+	}
+	return out, nil
+}
+
+func (n *notNode) boolean() bool { return true }
+
+func (n *notNode) String() string { return "not " + wrap(n.operand) }
+
+type binaryNode struct {
+	op          string
+	left, right node
+}
+
+func (n *binaryNode) eval(ctx *evalContext) ([]float64, error) {
+	left, err := n.left.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]float64, len(left))
+	for i := range out {
+		a, b := left[i], right[i]
+		switch n.op {
+		case "and":
+			out[i] = logical(a, b, false)
+			continue
+		case "or":
+			out[i] = logical(a, b, true)
+			continue
+		}
+		if math.IsNaN(a) || math.IsNaN(b) {
+			out[i] = math.NaN()
+			continue
+		}
+		switch n.op {
+		case "+":
+			out[i] = a + b
+		case "-":
+			out[i] = a - b
+		case "*":
+			out[i] = a * b
+		case "/":
+			out[i] = math.NaN()
+			if b != 0 {
+				out[i] = a / b
+			}
+		case ">":
+			out[i] = boolValue(a > b)
+		case "<":
+			out[i] = boolValue(a < b)
+		case ">=":
+			out[i] = boolValue(a >= b)
+		case "<=":
+			out[i] = boolValue(a <= b)
+		case "==":
+			out[i] = boolValue(a == b)
+		case "!=":
+			out[i] = boolValue(a != b)
+		}
+	}
+	return out, nil
+}
+
+func (n *binaryNode) boolean() bool {
+	switch n.op {
+	case "+", "-", "*", "/":
+		return false
+	}
+	return true
+}
+
+func (n *binaryNode) String() string {
+	return wrap(n.left) + " " + n.op + " " + wrap(n.right)
+}
+
+// offsetNode x[n] 取n根K线之前的值
+type offsetNode struct {
+	operand node
+	offset  int
+}
+
+func (n *offsetNode) eval(ctx *evalContext) ([]float64, error) {
+	values, err := n.operand.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]float64, len(values))
+	for i := range out {
+		out[i] = math.NaN()
+		if i >= n.offset {
+			out[i] = values[i-n.offset]
+		}
+	}
+	return out, nil
+}
+
+func (n *offsetNode) boolean() bool { return n.operand.boolean() }
+
+func (n *offsetNode) String() string {
+	return fmt.Sprintf("%s[%d]", wrap(n.operand), n.offset)
+}
+
+type funcNode struct {
+	name string
+	args []node
+}
+
+func (n *funcNode) eval(ctx *evalContext) ([]float64, error) {
+	args := make([][]float64, len(n.args))
+	for i, arg := range n.args {
+		values, err := arg.eval(ctx)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = values
+	}
+
+	out := make([]float64, ctx.length)
+	for i := range out {
+		switch n.name {
+		case "cross_above", "cross_below":
+			// 上一根K线未越过、当前K线越过
+			if i == 0 {
+				out[i] = math.NaN()
+				continue
+			}
+			a0, b0, a1, b1 := args[0][i-1], args[1][i-1], args[0][i], args[1][i]
+			if math.IsNaN(a0) || math.IsNaN(b0) || math.IsNaN(a1) || math.IsNaN(b1) {
+				out[i] = math.NaN()
+			} else if n.name == "cross_above" {
+				out[i] = boolValue(a0 <= b0 && a1 > b1)
+			} else {
+				out[i] = boolValue(a0 >= b0 && a1 < b1)
+			}
+		case "abs":
+			out[i] = math.Abs(args[0][i])
+		case "min":
+			out[i] = math.Min(args[0][i], args[1][i])
+		case "max":
+			out[i] = math.Max(args[0][i], args[1][i])
+		}
+	}
+	return out, nil
+}
+
+func (n *funcNode) boolean() bool {
+	return n.name == "cross_above" || n.name == "cross_below"
+}
+
+func (n *funcNode) String() string {
+	parts := make([]string, len(n.args))
+	for i, arg := range n.args {
+		parts[i] = arg.String()
+	}
+	return n.name + "(" + strings.Join(parts, ", ") + ")"
+}
+
+// indicatorNode 注册表指标调用，source非空时以表达式结果作为源序列
+type indicatorNode struct {
+	spec   indicators.Spec
+	source node
+}
+
+func (n *indicatorNode) eval(ctx *evalContext) ([]float64, error) {
+	inputs := ctx.inputs
+	start := 0
+	if n.source != nil {
+		values, err := n.source.eval(ctx)
+		if err != nil {
+			return nil, err
+		}
+		// 源序列的前导NaN会污染递推计算，从首个有效值开始计算
+		for start < len(values) && math.IsNaN(values[start]) {
+			start++
+		}
+		inputs = make(indicators.Inputs, len(ctx.inputs)+1)
+		for key, series := range ctx.inputs {
+			inputs[key] = series[start:]
+		}
+		inputs[sourceInput] = values[start:]
+	}
+
+	result, err := ctx.registry.ComputeSpec(n.spec, inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]float64, ctx.length)
+	series := result.Series()
+	for i := range out {
+		out[i] = math.NaN()
+		if j := i - start; j >= 0 && j < len(series) && j >= result.WarmUp {
+			out[i] = series[j]
+		}
+	}
+	return out, nil
+}
+
+func (n *indicatorNode) boolean() bool { return false }
+
+func (n *indicatorNode) String() string {
+	text := n.spec.String()
+	if n.source == nil {
+		return text
+	}
+	return strings.Replace(text, "source="+string(sourceInput), "source="+n.source.String(), 1)
+}
+
+// logical 三值逻辑的and（dominant为false）和or（dominant为true）：
+// 任一操作数为dominant时结果为dominant，否则有无定义的操作数时结果无定义
+func logical(a, b float64, dominant bool) float64 {
+	for _, v := range []float64{a, b} {
+		if !math.IsNaN(v) && (v != 0) == dominant {
+			return boolValue(dominant)
+		}
+	}
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return math.NaN()
+	}
+	return boolValue(!dominant)
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// wrap 复合表达式加括号
+func wrap(n node) string {
+	switch n.(type) {
+	case *binaryNode, *notNode:
+		return "(" + n.String() + ")"
+	}
+	return n.String()
+}
+
+func formatNumber(value float64) string {
+	return strings.TrimRight(strings.TrimRight(fmt.Sprintf("%.6f", value), "0"), ".")
+}
@@ -0,0 +1,98 @@
+// Package expr 实现基于指标库的条件表达式，用于筛选和策略条件，例如
+//
+//	ema(close,20) > ema(close,50) and rsi(14) < 40 and volume > 2*sma(volume,20)
+//	cross_above(macd(), macd().signal) or close < close[1] * 0.95
+//
+// 支持四则运算、比较、and/or/not、cross_above/cross_below、abs/min/max、
+// x[n] 回看偏移，以及注册表中的全部指标（数值参数按声明顺序对应，序列参数作为源）。
+package expr
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/zjc/go-crypto-analyzer/pkg/indicators"
+	"github.com/zjc/go-crypto-analyzer/pkg/types"
+)
+
+// Expression 已解析的表达式
+type Expression struct {
+	text     string
+	root     node
+	registry *indicators.Registry
+}
+
+// Parse 使用默认指标注册表解析表达式
+func Parse(text string) (*Expression, error) {
+	return ParseWithRegistry(text, indicators.DefaultRegistry())
+}
+
+// ParseWithRegistry 使用指定注册表解析表达式，指标名称和参数在解析时校验
+func ParseWithRegistry(text string, registry *indicators.Registry) (*Expression, error) {
+	tokens, err := tokenize(text)
+	if err != nil {
+		return nil, fmt.Errorf("expression %q: %w", text, err)
+	}
+	p := &parser{tokens: tokens, registry: registry}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("expression %q: %w", text, err)
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf("expression %q: unexpected %q at position %d", text, tok.text, tok.pos)
+	}
+	return &Expression{text: text, root: root, registry: registry}, nil
+}
+
+// MustParse 解析失败时panic，用于固定表达式
+func MustParse(text string) *Expression {
+	e, err := Parse(text)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// Text 返回原始表达式文本
+func (e *Expression) Text() string {
+	return e.text
+}
+
+// String 返回规范形式，指标参数全部展开
+func (e *Expression) String() string {
+	return e.root.String()
+}
+
+// IsBoolean 表达式结果是否为条件（比较、逻辑运算或交叉）
+func (e *Expression) IsBoolean() bool {
+	return e.root.boolean()
+}
+
+// Eval 在K线上求值，返回与K线对齐的序列；无定义的位置为NaN，条件结果为1/0
+func (e *Expression) Eval(data []types.OHLCV) ([]float64, error) {
+	ctx := &evalContext{
+		inputs:   indicators.InputsFromOHLCV(data),
+		length:   len(data),
+		registry: e.registry,
+	}
+	return e.root.eval(ctx)
+}
+
+// Last 返回最新K线上的值，数据不足导致无定义时ok为false
+func (e *Expression) Last(data []types.OHLCV) (value float64, ok bool, err error) {
+	series, err := e.Eval(data)
+	if err != nil || len(series) == 0 {
+		return 0, false, err
+	}
+	value = series[len(series)-1]
+	if math.IsNaN(value) {
+		return 0, false, nil
+	}
+	return value, true, nil
+}
+
+// Matches 条件表达式在最新K线上是否成立
+func (e *Expression) Matches(data []types.OHLCV) (bool, error) {
+	value, ok, err := e.Last(data)
+	return ok && value != 0, err
+}
@@ -0,0 +1,95 @@
+package expr
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/zjc/go-crypto-analyzer/pkg/types"
+)
+
+func TestExpression(t *testing.T) {
+	// 先跌后涨的V形走势，第31根K线放量
+	data := make([]types.OHLCV, 60)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range data {
+		price := 100.0 - float64(i)
+		if i >= 30 {
+			price = 70.0 + 2*float64(i-30)
+		}
+		volume := 1000.0
+		if i == 31 {
+			volume = 5000
+		}
+		data[i] = types.OHLCV{Time: start.Add(time.Duration(i) * time.Hour), Open: price, High: price + 1, Low: price - 1, Close: price, Volume: volume}
+	}
+
+	// 算术、偏移和优先级
+	e, err := Parse("close - close[1] + 2 * 3")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	series, err := e.Eval(data)
+	if err != nil {
+		t.Fatalf("eval: %v", err)
+	}
+	if !math.IsNaN(series[0]) || series[59] != 8 || e.IsBoolean() {
+		t.Errorf("arithmetic: got %v at 0 and %v at 59", series[0], series[59])
+	}
+
+	// 成交量放大条件只在第31根成立
+	e = MustParse("volume > 2*sma(volume,20) and close > close[1]")
+	series, _ = e.Eval(data)
+	for i, v := range series {
+		if (v == 1) != (i == 31) {
+			t.Errorf("volume condition at %d: got %v", i, v)
+		}
+	}
+
+	// 均线交叉只在一根K线上触发
+	e = MustParse("cross_above(ema(close,5), sma(close,20))")
+	series, _ = e.Eval(data)
+	crosses := 0
+	for _, v := range series {
+		if v == 1 {
+			crosses++
+		}
+	}
+	if crosses != 1 || !e.IsBoolean() {
+		t.Errorf("expected exactly one cross above, got %d", crosses)
+	}
+
+	// 嵌套指标：源序列的预热期不应污染结果
+	e = MustParse("sma(rsi(14), 5)")
+	if value, ok, err := e.Last(data); err != nil || !ok || value <= 50 {
+		t.Errorf("nested indicator: got %v ok=%v err=%v", value, ok, err)
+	}
+	if e.String() != "sma(source=rsi(period=14),period=5)" {
+		t.Errorf("unexpected canonical form %q", e.String())
+	}
+
+	// 预热不足时无定义
+	if _, ok, _ := MustParse("sma(close,100)").Last(data); ok {
+		t.Error("expected undefined value within warm-up")
+	}
+	// 条件同样无定义，除非另一操作数已决定结果
+	for text, want := range map[string]float64{
+		"close > 0 and sma(close,100) > 0":      math.NaN(),
+		"not sma(close,100) > 0":                math.NaN(),
+		"close < 0 and sma(close,100) > 0":      0,
+		"close > 0 or sma(close,100) > 0":       1,
+		"cross_above(close, sma(close,100))":    math.NaN(),
+		"close < 0 or cross_below(close, open)": 0,
+	} {
+		value, ok, err := MustParse(text).Last(data)
+		if err != nil || ok != !math.IsNaN(want) || (ok && value != want) {
+			t.Errorf("%s: got %v ok=%v err=%v", text, value, ok, err)
+		}
+	}
+
+	for _, bad := range []string{"foo(1)", "rsi(1,2,3)", "close >", "close[-1]", "macd().nope", "(close"} {
+		if _, err := Parse(bad); err == nil {
+			t.Errorf("expected parse error for %q", bad)
+		}
+	}
+}
@@ -0,0 +1,350 @@
+package expr
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/zjc/go-crypto-analyzer/pkg/indicators"
+)
+
+// 可直接引用的行情序列
+var seriesInputs = map[string]indicators.Input{
+	"open":   indicators.InputOpen,
+	"high":   indicators.InputHigh,
+	"low":    indicators.InputLow,
+	"close":  indicators.InputClose,
+	"volume": indicators.InputVolume,
+	"hl2":    indicators.InputHL2,
+	"hlc3":   indicators.InputHLC3,
+	"ohlc4":  indicators.InputOHLC4,
+}
+
+// 内置函数及参数个数
+var builtinFuncs = map[string]int{
+	"cross_above": 2,
+	"cross_below": 2,
+	"abs":         1,
+	"min":         2,
+	"max":         2,
+}
+
+// parser 递归下降解析器，优先级从低到高：or、and、not、比较、加减、乘除、负号、偏移
+type parser struct {
+	tokens   []token
+	pos      int
+	registry *indicators.Registry
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// accept 当前词法单元为给定运算符或关键字时前进
+func (p *parser) accept(texts ...string) (string, bool) {
+	tok := p.peek()
+	if tok.kind != tokenOp && tok.kind != tokenIdent {
+		return "", false
+	}
+	for _, text := range texts {
+		if tok.text == text {
+			p.pos++
+			return text, true
+		}
+	}
+	return "", false
+}
+
+func (p *parser) expect(text string) error {
+	if _, ok := p.accept(text); !ok {
+		tok := p.peek()
+		return fmt.Errorf("expected %q at position %d, got %q", text, tok.pos, tok.text)
+	}
+	return nil
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("or", "||"); !ok {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: "or", left: left, right: right}
+	}
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("and", "&&"); !ok {
+			return left, nil
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: "and", left: left, right: right}
+	}
+}
+
+func (p *parser) parseNot() (node, error) {
+	if _, ok := p.accept("not", "!"); ok {
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.accept(">", "<", ">=", "<=", "==", "!=")
+	if !ok {
+		return left, nil
+	}
+	right, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	return &binaryNode{op: op, left: left, right: right}, nil
+}
+
+func (p *parser) parseAdditive() (node, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept("+", "-")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *parser) parseMultiplicative() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept("*", "/")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if _, ok := p.accept("-"); ok {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		// 常量折叠，使负数可以作为指标参数
+		if number, isNumber := operand.(*numberNode); isNumber {
+			return &numberNode{value: -number.value}, nil
+		}
+		return &binaryNode{op: "-", left: &numberNode{value: 0}, right: operand}, nil
+	}
+	return p.parsePostfix()
+}
+
+// parsePostfix 解析 x[n] 回看偏移
+func (p *parser) parsePostfix() (node, error) {
+	operand, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("["); !ok {
+			return operand, nil
+		}
+		tok := p.next()
+		offset, err := strconv.Atoi(tok.text)
+		if tok.kind != tokenNumber || err != nil || offset < 0 {
+			return nil, fmt.Errorf("offset must be a non-negative integer at position %d", tok.pos)
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		operand = &offsetNode{operand: operand, offset: offset}
+	}
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenNumber:
+		value, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("bad number %q at position %d", tok.text, tok.pos)
+		}
+		return &numberNode{value: value}, nil
+	case tokenIdent:
+		if _, ok := p.accept("("); ok {
+			return p.parseCall(tok)
+		}
+		switch tok.text {
+		case "true":
+			return &numberNode{value: 1, isBool: true}, nil
+		case "false":
+			return &numberNode{value: 0, isBool: true}, nil
+		}
+		if input, ok := seriesInputs[tok.text]; ok {
+			return &seriesNode{input: input}, nil
+		}
+		// 无参数的指标可以省略括号，如 obv
+		if _, ok := p.registry.Lookup(tok.text); ok {
+			return p.newIndicator(tok, nil)
+		}
+		return nil, fmt.Errorf("unknown identifier %q at position %d", tok.text, tok.pos)
+	case tokenOp:
+		if tok.text == "(" {
+			inner, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			return inner, p.expect(")")
+		}
+	}
+	if tok.kind == tokenEOF {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+}
+
+// callArg 函数调用参数：命名参数只允许数值
+type callArg struct {
+	name  string
+	value node
+}
+
+func (p *parser) parseCall(name token) (node, error) {
+	args := make([]callArg, 0)
+	if _, ok := p.accept(")"); !ok {
+		for {
+			arg := callArg{}
+			if p.peek().kind == tokenIdent && p.tokens[p.pos+1].text == "=" {
+				arg.name = p.next().text
+				p.next()
+			}
+			value, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			arg.value = value
+			args = append(args, arg)
+
+			if _, ok := p.accept(")"); ok {
+				break
+			}
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if arity, ok := builtinFuncs[name.text]; ok {
+		if len(args) != arity {
+			return nil, fmt.Errorf("%s takes %d arguments, got %d", name.text, arity, len(args))
+		}
+		operands := make([]node, len(args))
+		for i, arg := range args {
+			if arg.name != "" {
+				return nil, fmt.Errorf("%s does not take named arguments", name.text)
+			}
+			operands[i] = arg.value
+		}
+		return &funcNode{name: name.text, args: operands}, nil
+	}
+
+	return p.newIndicator(name, args)
+}
+
+// newIndicator 将调用参数映射为指标规格：数值按参数声明顺序对应，序列表达式作为源
+func (p *parser) newIndicator(name token, args []callArg) (node, error) {
+	def, ok := p.registry.Lookup(name.text)
+	if !ok {
+		return nil, fmt.Errorf("unknown function %q at position %d", name.text, name.pos)
+	}
+
+	spec := indicators.Spec{Name: def.Name}
+	var source node
+	positional := 0
+	for _, arg := range args {
+		number, isNumber := arg.value.(*numberNode)
+		switch {
+		case arg.name != "" && arg.name != "source":
+			if !isNumber {
+				return nil, fmt.Errorf("%s: parameter %s must be a number", def.Name, arg.name)
+			}
+			spec.Args = append(spec.Args, indicators.Arg{Name: arg.name, Value: number.value})
+		case isNumber && arg.name == "":
+			if positional >= len(def.Params) {
+				return nil, fmt.Errorf("%s takes at most %d parameters", def.Name, len(def.Params))
+			}
+			spec.Args = append(spec.Args, indicators.Arg{Name: def.Params[positional].Name, Value: number.value})
+			positional++
+		default:
+			if source != nil {
+				return nil, fmt.Errorf("%s: only one source series is allowed", def.Name)
+			}
+			source = arg.value
+		}
+	}
+
+	// 行情序列直接作为源，其他表达式先求值再作为源
+	if series, ok := source.(*seriesNode); ok {
+		spec.Source = series.input
+		source = nil
+	} else if source != nil {
+		spec.Source = sourceInput
+	}
+
+	if _, ok := p.accept("."); ok {
+		tok := p.next()
+		if tok.kind != tokenIdent {
+			return nil, fmt.Errorf("expected output name after '.' at position %d", tok.pos)
+		}
+		spec.Output = tok.text
+	}
+
+	resolved, _, err := p.registry.Resolve(spec)
+	if err != nil {
+		return nil, err
+	}
+	return &indicatorNode{spec: resolved, source: source}, nil
+}
@@ -0,0 +1,680 @@
+package i18n
+
+// enUS 英文消息目录
+var enUS = map[string]string{
+	// 趋势方向
+	"trend.STRONG_UPTREND":   "Strong uptrend",
+	"trend.UPTREND":          "Uptrend",
+	"trend.SIDEWAYS":         "Sideways",
+	"trend.DOWNTREND":        "Downtrend",
+	"trend.STRONG_DOWNTREND": "Strong downtrend",
+
+	// 趋势强度
+	"strength.VERY_STRONG": "Very strong",
+	"strength.STRONG":      "Strong",
+	"strength.MODERATE":    "Moderate",
+	"strength.WEAK":        "Weak",
+	"strength.NO_TREND":    "No trend",
+
+	// 证据类型
+	"evidence.BULLISH": "Bullish evidence",
+	"evidence.BEARISH": "Bearish evidence",
+	"evidence.NEUTRAL": "Neutral evidence",
+	"evidence.WARNING": "Warning",
+
+	// 背离类型
+	"divergence.NONE":            "No divergence",
+	"divergence.REGULAR_BULLISH": "Regular bullish divergence",
+	"divergence.REGULAR_BEARISH": "Regular bearish divergence",
+	"divergence.HIDDEN_BULLISH":  "Hidden bullish divergence",
+	"divergence.HIDDEN_BEARISH":  "Hidden bearish divergence",
+
+	// MACD趋势
+	"macd.BULLISH": "Bullish",
+	"macd.NEUTRAL": "Neutral",
+	"macd.BEARISH": "Bearish",
+
+	// RSI动量
+	"momentum.OVERBOUGHT": "Overbought",
+	"momentum.STRONG":     "Strong",
+	"momentum.NEUTRAL":    "Neutral",
+	"momentum.WEAK":       "Weak",
+	"momentum.OVERSOLD":   "Oversold",
+
+	// 成交量
+	"volume.HIGH":              "High volume",
+	"volume.NORMAL":            "Normal volume",
+	"volume.LOW":               "Low volume",
+	"volume.INSUFFICIENT_DATA": "Insufficient data",
+
+	// 交叉
+	"cross.GOLDEN":           "Golden cross",
+	"cross.DEATH":            "Death cross",
+	"cross.OVERSOLD_GOLDEN":  "Oversold golden cross",
+	"cross.OVERBOUGHT_DEATH": "Overbought death cross",
+
+	// 一目均衡表
+	"cloud_position.ABOVE":  "above cloud",
+	"cloud_position.INSIDE": "in cloud",
+	"cloud_position.BELOW":  "below cloud",
+	"cloud.BULLISH":         "Bullish cloud",
+	"cloud.BEARISH":         "Bearish cloud",
+	"chikou.ABOVE":          "Above past price",
+	"chikou.BELOW":          "Below past price",
+	"chikou.TANGLED":        "Tangled with past price",
+
+	// VWAP标准差带
+	"vwap_band.ABOVE_UPPER_2": "Above upper 2σ",
+	"vwap_band.UPPER_1_2":     "Upper 1σ-2σ",
+	"vwap_band.VWAP_UPPER_1":  "VWAP-upper 1σ",
+	"vwap_band.LOWER_1_VWAP":  "Lower 1σ-VWAP",
+	"vwap_band.LOWER_1_2":     "Lower 1σ-2σ",
+	"vwap_band.BELOW_LOWER_2": "Below lower 2σ",
+
+	// 跟踪止损方向（flip用于"翻多/翻空"）与通道
+	"side.LONG":     "Bullish",
+	"side.SHORT":    "Bearish",
+	"flip.LONG":     "bullish",
+	"flip.SHORT":    "bearish",
+	"breakout.UP":   "Breakout up",
+	"breakout.DOWN": "Breakout down",
+	"squeeze.UP":    "Fired up",
+	"squeeze.DOWN":  "Fired down",
+
+	// 斐波那契与价位来源
+	"fib.RETRACEMENT":              "Retracement",
+	"fib.EXTENSION":                "Extension",
+	"source.SWING":                 "Swing",
+	"source.PSYCHOLOGICAL":         "Psychological",
+	"source.HISTORICAL_SUPPORT":    "Historical support",
+	"source.HISTORICAL_RESISTANCE": "Historical resistance",
+	"source.PIVOT_P":               "Pivot P",
+	"source.PIVOT_R1":              "Pivot R1",
+	"source.PIVOT_R2":              "Pivot R2",
+	"source.PIVOT_R3":              "Pivot R3",
+	"source.PIVOT_R4":              "Pivot R4",
+	"source.PIVOT_S1":              "Pivot S1",
+	"source.PIVOT_S2":              "Pivot S2",
+	"source.PIVOT_S3":              "Pivot S3",
+	"source.PIVOT_S4":              "Pivot S4",
+	"source.VOLUME_NODE":           "Volume node",
+
+	// K线形态
+	"candle.GRAVESTONE_DOJI":      "Gravestone doji",
+	"candle.DRAGONFLY_DOJI":       "Dragonfly doji",
+	"candle.LONG_LEGGED_DOJI":     "Long-legged doji",
+	"candle.DOJI":                 "Doji",
+	"candle.HAMMER":               "Hammer",
+	"candle.SHOOTING_STAR":        "Shooting star",
+	"candle.BULLISH_ENGULFING":    "Bullish engulfing",
+	"candle.BEARISH_ENGULFING":    "Bearish engulfing",
+	"candle.BULLISH_HARAMI":       "Bullish harami",
+	"candle.BEARISH_HARAMI":       "Bearish harami",
+	"candle.PIERCING_LINE":        "Piercing line",
+	"candle.DARK_CLOUD_COVER":     "Dark cloud cover",
+	"candle.INSIDE_BAR":           "Inside bar",
+	"candle.OUTSIDE_BAR":          "Outside bar",
+	"candle.MORNING_STAR":         "Morning star",
+	"candle.EVENING_STAR":         "Evening star",
+	"candle.THREE_WHITE_SOLDIERS": "Three white soldiers",
+	"candle.THREE_BLACK_CROWS":    "Three black crows",
+
+	// 图表形态及状态
+	"chart.DOUBLE_TOP":                 "Double top",
+	"chart.DOUBLE_BOTTOM":              "Double bottom",
+	"chart.TRIPLE_TOP":                 "Triple top",
+	"chart.TRIPLE_BOTTOM":              "Triple bottom",
+	"chart.HEAD_AND_SHOULDERS":         "Head and shoulders",
+	"chart.INVERSE_HEAD_AND_SHOULDERS": "Inverse head and shoulders",
+	"chart.ASCENDING_TRIANGLE":         "Ascending triangle",
+	"chart.DESCENDING_TRIANGLE":        "Descending triangle",
+	"chart.SYMMETRICAL_TRIANGLE":       "Symmetrical triangle",
+	"chart.BULL_FLAG":                  "Bull flag",
+	"chart.BEAR_FLAG":                  "Bear flag",
+	"chart.BULLISH_PENNANT":            "Bullish pennant",
+	"chart.BEARISH_PENNANT":            "Bearish pennant",
+	"pattern_status.FORMING":           "Forming",
+	"pattern_status.BROKE_OUT":         "Broke out",
+	"pattern_status.BROKE_DOWN":        "Broke down",
+	// 形态的突破价位：头肩和多重顶底为颈线，三角形和旗形为突破位
+	"breakout_level.DOUBLE_TOP":                 "the neckline",
+	"breakout_level.DOUBLE_BOTTOM":              "the neckline",
+	"breakout_level.TRIPLE_TOP":                 "the neckline",
+	"breakout_level.TRIPLE_BOTTOM":              "the neckline",
+	"breakout_level.HEAD_AND_SHOULDERS":         "the neckline",
+	"breakout_level.INVERSE_HEAD_AND_SHOULDERS": "the neckline",
+	"breakout_level.ASCENDING_TRIANGLE":         "the breakout level",
+	"breakout_level.DESCENDING_TRIANGLE":        "the breakout level",
+	"breakout_level.SYMMETRICAL_TRIANGLE":       "the breakout level",
+	"breakout_level.BULL_FLAG":                  "the breakout level",
+	"breakout_level.BEAR_FLAG":                  "the breakout level",
+	"breakout_level.BULLISH_PENNANT":            "the breakout level",
+	"breakout_level.BEARISH_PENNANT":            "the breakout level",
+
+	// 证据类别
+	"category.ma":             "Moving averages",
+	"category.macd":           "MACD",
+	"category.divergence":     "Divergence",
+	"category.rsi":            "RSI",
+	"category.stochrsi":       "StochRSI",
+	"category.dmi":            "DMI",
+	"category.ichimoku":       "Ichimoku",
+	"category.vwap":           "VWAP",
+	"category.supertrend":     "SuperTrend",
+	"category.sar":            "Parabolic SAR",
+	"category.squeeze":        "Volatility squeeze",
+	"category.channel":        "Channels",
+	"category.candlestick":    "Candlesticks",
+	"category.chart_pattern":  "Chart patterns",
+	"category.fibonacci":      "Fibonacci",
+	"category.structure":      "Market structure",
+	"category.sr":             "Support/resistance",
+	"category.volume_profile": "Volume profile",
+	"category.volume":         "Volume",
+
+	// 轴心点算法和周期
+	"pivot.floor":         "Floor",
+	"pivot.fibonacci":     "Fibonacci",
+	"pivot.camarilla":     "Camarilla",
+	"pivot.woodie":        "Woodie",
+	"pivot.demark":        "DeMark",
+	"pivot_period.day":    "previous day",
+	"pivot_period.week":   "previous week",
+	"pivot_period.month":  "previous month",
+	"pivot_period.latest": "latest candle",
+
+	// 恐慌贪婪指数
+	"sentiment.EXTREME_FEAR":  "Extreme fear - possible buying opportunity",
+	"sentiment.FEAR":          "Fear - market leaning bearish",
+	"sentiment.NEUTRAL":       "Neutral - wait and see",
+	"sentiment.GREED":         "Greed - market leaning bullish",
+	"sentiment.EXTREME_GREED": "Extreme greed - mind the risk",
+	"feargreed.title":         "😱 Fear & Greed Index: ",
+
+	// 系统判断
+	"judgment.STRONG_BULLISH": "Strong bullish signal",
+	"judgment.BULLISH":        "Leaning bullish",
+	"judgment.STRONG_BEARISH": "Strong bearish signal",
+	"judgment.BEARISH":        "Leaning bearish",
+	"judgment.NEUTRAL":        "No clear signal",
+
+	// 信号冲突
+	"conflict.MA_MACD":           "MA and MACD signals conflict, trade with caution",
+	"conflict.VOLUME_SURGE_DOWN": "Heavy-volume selloff, strong selling pressure",
+
+	// K线变换
+	"transform.none":        "Raw candles",
+	"transform.heikin_ashi": "Heikin-Ashi",
+	"transform.renko":       "Renko (brick %.4g)",
+	"transform.renko_atr":   "Renko (ATR%d bricks)",
+
+	// 通用
+	"common.none":              "None",
+	"common.yes":               "Yes",
+	"common.no":                "No",
+	"common.insufficient_data": "Insufficient data",
+	"common.bars_ago":          "%s (%d bars ago)",
+	"common.item_header":       "Item|Value|Status",
+	"common.source_yahoo":      "Using Yahoo Finance data source",
+	"common.source_binance":    "Using Binance data source",
+	"common.fetch_failed":      "❌ Failed to fetch data: %v",
+	"common.rules_load_failed": "❌ Failed to load evidence rules: %v",
+
+	// 分析运行
+	"run.clear_cache_failed":   "Failed to clear cache: %v",
+	"run.cache_cleared":        "✅ All cached data cleared",
+	"run.cache_enabled":        "✅ Cache enabled (dir: %s, TTL: %d min)",
+	"run.cache_disabled":       "⚠️  Cache disabled",
+	"run.invalid_vwap_session": "Invalid VWAP session time %q: %v",
+	"run.invalid_avwap_from":   "Invalid anchored VWAP start %q: %v",
+	"run.rule_expression":      "❌ Evidence rule expression %q: %v",
+	"run.title":                "🚀 Crypto Market Analysis - %s",
+	"run.timeframe":            "📊 Interval: %s | Data points: %d",
+	"run.profile":              "⚙️  Sensitivity profile: %s (%s)",
+	"run.transform":            "🔁 Candle transform: %s (indicators use the transformed series, prices are real)",
+	"run.next_update":          "⏰ Next update in %d seconds",
+
+	// 数据缓存与计时
+	"cache.first_fetch":  "  📥 First fetch, requesting %d candles...",
+	"cache.saved":        "  💾 Cached %d candles",
+	"cache.hit":          "  ⚡ Using cached data (latest: %s)",
+	"cache.incremental":  "  🔄 Incremental update: fetching the latest %d candles...",
+	"cache.fetch_failed": "  ⚠️  Failed to fetch new data, using cached data",
+	"cache.updated":      "  ✅ Updated, %d new candles",
+	"cache.cleared":      "  🗑️  Cleared the cache of %s %s",
+	"timer.seconds":      "⏱️  %s took %.2fs",
+	"timer.milliseconds": "⏱️  %s took %dms",
+
+	// 单个交易对
+	"symbol.title":              "📊 Analyzing %s",
+	"symbol.adjust_limit":       "  ℹ️  Data points adjusted: %d → %d (for historical signal tracking)",
+	"symbol.rate_limited":       "  ❌ API access restricted, retry later or switch to Yahoo with -y",
+	"symbol.network_failed":     "  ❌ Network connection failed, please check your connection",
+	"symbol.fetch_failed":       "  ❌ Failed to fetch data: %v",
+	"symbol.hint":               "  💡 Hint: you can try the following:",
+	"symbol.hint_yahoo":         "     1. Switch to Yahoo Finance with -y",
+	"symbol.hint_rate":          "     2. Reduce the request frequency or data points",
+	"symbol.hint_symbol":        "     3. Check that the trading pair name is correct",
+	"symbol.insufficient_data":  "  ❌ Insufficient data (at least 50 candles required)",
+	"symbol.analysis_failed":    "  ❌ Analysis failed: %v",
+	"symbol.renko_hint":         "  💡 Hint: not enough Renko bricks, increase the data points (-l) or reduce the brick size (--brick-size)",
+	"symbol.rules_failed":       "  ❌ Evidence rules failed: %v",
+	"symbol.significant_change": "  ⚡ Latest candle moved %+.2f%%, above the significant move threshold of %.1f%%",
+
+	// 多周期共振
+	"mtf.fetch_failed":         "  ⚠️  Failed to fetch %s data: %v",
+	"mtf.skipped":              "⚠️  Multi-timeframe confluence skipped: %v",
+	"mtf.title":                "🧭 %s multi-timeframe confluence",
+	"mtf.header":               "Interval|Weight|Trend|Trend score|RSI|MACD|Evidence|Score",
+	"mtf.confluence":           "  Confluence score: %+.0f (%s), aligned weight %.0f%%, higher timeframes weigh more",
+	"mtf.conflict":             "  ⚠️  Timeframe conflict: %s",
+	"mtf.conflict_description": "%s %s, but within a %s %s",
+
+	// 相对强度
+	"rs.skipped":       "⚠️  Relative strength leaderboard skipped: %v",
+	"rs.title":         "🏆 Relative Strength Leaderboard (as of %s)",
+	"rs.header":        "Rank|Symbol",
+	"rs.basket":        "Basket %s",
+	"rs.vs_benchmark":  "vs %s %s",
+	"rs.score_header":  "Composite|Percentile",
+	"rs.leading":       "%s leading",
+	"rs.lagging":       "%s lagging",
+	"rs.note":          "  Basket columns show strength vs the equal-weight basket, composite is the weighted log excess return (%%), ⭐ marks a new high of the RS line vs %s",
+	"rs.partial_cover": "  ⚠️  Data only covers up to %s, longer lookbacks are left out of the composite",
+
+	// 跨资产相关性
+	"cross.skipped":           "⚠️  Cross-asset correlation skipped: %v",
+	"cross.title":             "🔗 Cross-Asset Correlation (%d returns, %s to %s)",
+	"cross.legend":            "  Upper: Pearson  Lower: Spearman  (red≥0.8 yellow≥0.5 green<0.5 cyan<0)",
+	"cross.summary":           "  Average correlation: %.2f  Effective independent bets: %.1f / %d",
+	"cross.rolling_title":     "📈 Rolling correlation (%d returns each):",
+	"cross.rolling_header":    "Pair|Rolling correlation (oldest to latest)|Min|Max",
+	"cross.beta_title":        "📐 Beta vs %s:",
+	"cross.beta_header":       "Symbol|Beta|Correlation|Previous window|Change",
+	"cross.cluster":           "  🧩 Cluster %d: %s",
+	"cross.warning_single":    "The %d symbols of the watchlist amount to only %.1f effective independent bets, essentially a single bet",
+	"cross.warning_cluster":   "%s are highly correlated (ρ≥%.2f), %d/%d of the watchlist, limited diversification",
+	"cross.warning_systemic":  "Average pairwise correlation %.2f, systemic risk is concentrated",
+	"cross.cluster_separator": ", ",
+
+	// 分析结果
+	"analysis.price":              "💰 Current price: %s",
+	"analysis.trend":              "📈 Overall trend: %s",
+	"analysis.trend_score":        "📊 Trend score: %.1f",
+	"analysis.ma_structure":       "🧭 MA trend: %s  Structure trend: %s",
+	"analysis.indicator_header":   "Indicator|Value|Reference|Status",
+	"analysis.rsi_ref":            "Overbought>%.0f, Oversold<%.0f",
+	"analysis.overbought":         "Overbought",
+	"analysis.oversold":           "Oversold",
+	"analysis.stoch_ref":          "Overbought>80, Oversold<20",
+	"analysis.macd_histogram":     "MACD histogram",
+	"analysis.macd_histogram_ref": ">0 bullish, <0 bearish",
+	"analysis.adx_ref":            "Strong>35, Weak<20",
+	"analysis.plus_di_dominant":   "+DI dominant",
+	"analysis.minus_di_dominant":  "-DI dominant",
+	"analysis.di_ref":             "+DI>-DI bullish",
+	"analysis.supertrend_ref":     "Bullish above the line",
+	"analysis.sar_ref":            "Bullish above SAR",
+	"analysis.trailing_flip":      "Flipped %s (%d bars ago)",
+	"analysis.bb_inside":          "Inside bands",
+	"analysis.bb_above":           "Above upper band",
+	"analysis.bb_below":           "Below lower band",
+	"analysis.percent_b":          "Bollinger %%B(%d,%.1f)",
+	"analysis.bb_ref":             "<0 oversold, >1 overbought",
+	"analysis.bandwidth":          "Bollinger bandwidth",
+	"analysis.bandwidth_rank":     "Percentile: %.0f%%",
+	"analysis.no_squeeze":         "No squeeze",
+	"analysis.squeezing":          "Squeezing (%d bars)",
+	"analysis.ttm_squeeze":        "TTM squeeze",
+	"analysis.squeeze_ref":        "Squeeze while BB inside KC",
+	"analysis.donchian":           "Donchian(%d)",
+	"analysis.donchian_ref":       "Breakout sets direction",
+	"analysis.volume_ref":         "High>%gx, Low<%gx",
+	"analysis.volume_ratio":       "Volume ratio",
+	"analysis.current_volume":     "Current volume",
+	"analysis.volume_ma":          "Average: %.0f",
+	"analysis.indicators_title":   "📊 Technical indicators:",
+	"analysis.ma_title":           "📉 Moving averages:",
+	"analysis.ma_header":          "MA|Price|Position|Deviation",
+	"analysis.pivot_partial":      ", incomplete data",
+	"analysis.pivot_title":        "🎯 Key levels (%s pivots, based on the %s):",
+	"analysis.pivot_header":       "Type|Level|Distance|Strength",
+	"analysis.level_strength":     "Medium|Strong|Very strong|Extreme",
+	"analysis.resistance_level":   "Resistance R%d",
+	"analysis.support_level":      "Support S%d",
+	"analysis.pivot":              "Pivot",
+	"analysis.pivot_strength":     "Reference",
+
+	// 证据汇总与建议
+	"analysis.evidence_summary":  "🔍 Evidence summary:",
+	"analysis.count":             "%d",
+	"analysis.bullish_evidence":  "  Bullish evidence: %s",
+	"analysis.bearish_evidence":  "  Bearish evidence: %s",
+	"analysis.warning_evidence":  "  Warnings: %s",
+	"analysis.total_strength":    "  Total strength: %.2f",
+	"analysis.evidence_title":    "📋 Evidence details:",
+	"analysis.evidence_header":   "Type|Category|Description|Weight",
+	"analysis.evidence_bullish":  "✅ Bullish",
+	"analysis.evidence_bearish":  "❌ Bearish",
+	"analysis.evidence_warning":  "⚠️ Warning",
+	"analysis.evidence_neutral":  "➖ Neutral",
+	"analysis.evidence_hidden":   "  … %d weaker evidence items not shown (display.max_evidences)",
+	"analysis.consistency_title": "🔍 Indicator consistency:",
+	"analysis.bullish_signals":   "  Bullish signals: %d",
+	"analysis.bearish_signals":   "  Bearish signals: %d",
+	"analysis.warning_signals":   "  Warning signals: %d",
+	"analysis.consistency":       "  Consistency: %.1f%%",
+	"analysis.suggestion_title":  "💡 Suggestion (for reference only, consider your own situation):",
+	"analysis.overall_score":     "  Overall score: %.2f",
+	"analysis.judgment":          "  System verdict: %s",
+	"analysis.disclaimer":        "⚠️  Note: the above are technical indicator readings; investment decisions should weigh many other factors",
+
+	// 一目均衡表
+	"ichimoku.title":          "☁️  Ichimoku:",
+	"ichimoku.no_cross":       "No cross",
+	"ichimoku.tk_cross":       "%s (%s, %d bars ago)",
+	"ichimoku.twist":          "Twists in %d bars",
+	"ichimoku.tenkan_kijun":   "Tenkan/Kijun",
+	"ichimoku.cloud":          "Current cloud",
+	"ichimoku.price_position": "Price %s",
+	"ichimoku.thickness":      "Cloud thickness",
+	"ichimoku.future_cloud":   "Future cloud",
+	"ichimoku.cloud_twist":    "Cloud twist",
+	"ichimoku.chikou":         "Chikou span",
+
+	// 图表形态与市场结构
+	"pattern.title":       "🔺 Chart patterns:",
+	"pattern.header":      "Pattern|Status|Breakout|Invalidation|Target|Confidence",
+	"structure.title":     "🏗️  Market structure:",
+	"structure.swings":    "  Swings: %s",
+	"structure.up":        "upward",
+	"structure.down":      "downward",
+	"structure.event":     "  Last event: %s %s break of $%.2f (%s, %d bars ago)",
+	"structure.protected": "  Protected level: $%.2f",
+
+	// 指标与表达式
+	"indicators.title":   "🧮 Available indicators:",
+	"indicators.header":  "Name|Description|Params|Inputs|Outputs",
+	"indicators.sources": "  source options: open, high, low, close, volume, hl2, hlc3, ohlc4; pick an output of multi-output indicators with .name, e.g. macd().signal",
+	"custom.title":       "🧮 Custom indicators:",
+	"custom.header":      "Indicator|Value",
+	"expr.title":         "🧾 Custom expressions:",
+	"expr.header":        "Expression|Result",
+
+	// 波动率
+	"volatility.title":              "🌡️  Realized volatility (interval %s, annualization factor √%.0f):",
+	"volatility.estimator":          "Estimator",
+	"volatility.annualized":         "Annualized volatility (%d bars)",
+	"volatility.close_to_close":     "Close-to-Close",
+	"volatility.expanding":          "%s (expanding)",
+	"volatility.contracting":        "%s (contracting)",
+	"volatility.long_term":          "  Long-term volatility (%d bars): %.2f%%  Short/long: %s",
+	"volatility.hurst_insufficient": "  Hurst exponent: insufficient data",
+	"volatility.hurst":              "  Hurst exponent: %.2f  Variance ratio: %.2f (z=%.2f)  Regime: %s",
+	"regime.random_walk":            "Random walk",
+	"regime.trending":               "Trending",
+	"regime.mean_reverting":         "Mean reverting",
+
+	// 斐波那契、支撑阻力、成交量分布、VWAP
+	"fibonacci.downswing":   "Downswing $%.2f(%s) → $%.2f(%s)",
+	"fibonacci.upswing":     "Upswing $%.2f(%s) → $%.2f(%s)",
+	"fibonacci.title":       "🌀 Fibonacci (%s):",
+	"fibonacci.header":      "Type|Ratio|Level|Distance|Status",
+	"fibonacci.testing":     "Testing",
+	"sr.title":              "🧱 Structural support/resistance:",
+	"sr.header":             "Type|Level|Distance|Source|Touches|Score",
+	"sr.touches":            "%d",
+	"sr.touches_ago":        " (%d bars ago)",
+	"sr.untested":           "Untested",
+	"sr.resistance":         "Resistance",
+	"sr.support":            "Support",
+	"volume_profile.title":  "📦 Volume profile:",
+	"volume_profile.header": "Type|Level|Distance",
+	"volume_profile.vah":    "Value area high VAH",
+	"volume_profile.poc":    "Point of control POC",
+	"volume_profile.val":    "Value area low VAL",
+	"volume_profile.hvn":    "High-volume nodes",
+	"volume_profile.lvn":    "Low-volume nodes",
+	"vwap.title":            "📐 VWAP:",
+	"vwap.session":          "Session VWAP",
+	"vwap.since":            "Since %s",
+	"vwap.anchored_low":     "Swing-low AVWAP",
+	"vwap.anchored_high":    "Swing-high AVWAP",
+	"vwap.anchored_custom":  "Custom AVWAP",
+	"vwap.anchor":           "Anchored %s",
+
+	// 价格走势图
+	"chart.price_range": "Price range: $%.2f - $%.2f",
+	"chart.title":       "📈 Price chart:",
+	"chart.hours":       "%.0fh",
+	"chart.days_hours":  "%dd %dh",
+	"chart.days":        "%dd",
+	"chart.summary":     "Span: %s  High: $%.2f  Low: $%.2f  Change: %.2f%%",
+
+	// 历史信号追踪
+	"history.title":            "📊 Historical signal tracking (last 12 hours)",
+	"history.no_history":       "  ⚠️  Not enough history to track signals",
+	"history.limited":          "  ℹ️  Limited data, showing the last %d hours",
+	"history.header":           "Time|Price|Score|Verdict|RSI|MACD|Volume|Candles",
+	"history.insufficient":     "  ⚠️  Insufficient data for a full signal history",
+	"history.candles":          "  ℹ️  Only %d candles available, not enough for both the analysis and the history",
+	"history.nothing":          "  ⚠️  Not enough history to display",
+	"history.range":            "  ℹ️  Analysis range: %s to %s",
+	"history.points":           "  ℹ️  Data points: %d in total, showing every %d",
+	"history.change_title":     "🔄 Signal change analysis:",
+	"history.average":          "  Average score: %.2f",
+	"history.highest":          "  Highest score: %.2f (%s)",
+	"history.lowest":           "  Lowest score: %.2f (%s)",
+	"history.trend":            "  Signal trend: ",
+	"history.strengthening":    "Strengthening ↗",
+	"history.weakening":        "Weakening ↘",
+	"history.flat":             "Flat →",
+	"history.position":         "  Current position: ",
+	"history.maybe_overbought": "Possibly overbought",
+	"history.maybe_oversold":   "Possibly oversold",
+	"history.normal":           "Normal range",
+
+	// 回测
+	"backtest.title":             "📊 Backtest - %s",
+	"backtest.title_v2":          "📊 Long/Short Backtest - %s",
+	"backtest.fetching":          "⏳ Fetching history: %s, %s, %d candles...",
+	"backtest.fetched":           "✅ Fetched %d candles",
+	"backtest.strategy_trend":    "📊 Using the trend following strategy",
+	"backtest.strategy_momentum": "📊 Using the momentum breakout strategy",
+	"backtest.strategy_reversal": "📊 Using the mean reversion strategy",
+	"backtest.strategy_combo":    "📊 Using the adaptive combo strategy",
+	"backtest.strategy_simple":   "📊 Using the simple threshold strategy",
+	"backtest.params":            "📈 Backtest parameters:",
+	"backtest.initial_capital":   "  Initial capital: $%.2f",
+	"backtest.entry_threshold":   "  Entry threshold: %.2f",
+	"backtest.exit_threshold":    "  Exit threshold: %.2f",
+	"backtest.long_threshold":    "  Long threshold: %.2f",
+	"backtest.short_threshold":   "  Short threshold: %.2f",
+	"backtest.close_threshold":   "  Close threshold: %.2f",
+	"backtest.stop_loss":         "  Stop loss: %.1f%%",
+	"backtest.take_profit":       "  Take profit: %.1f%%",
+	"backtest.profile":           "  Sensitivity profile: %s (%s)",
+	"backtest.transform":         "  Candle transform: %s (fills at real prices)",
+	"backtest.rs":                "  Relative strength: %s (lookbacks %s",
+	"backtest.rs_min":            ", entry percentile≥%.0f",
+	"backtest.rs_end":            ")",
+	"backtest.short_enabled":     "  ✅ Shorting enabled",
+	"backtest.short_disabled":    "  ❌ Shorting disabled",
+	"backtest.improved":          "  📊 Using the improved strategy (dynamic stops, regime aware)",
+	"backtest.stop_mode":         "  Stop mode: %s",
+	"backtest.basic":             "  📊 Using the basic strategy",
+	"backtest.running":           "⚙️  Running backtest...",
+	"backtest.failed":            "❌ Backtest failed: %v",
+	"backtest.fetching_rs":       "⏳ Fetching relative strength data: %s...",
+	"backtest.fetch_rs_failed":   "failed to fetch %s data: %v",
+
+	// 回测结果
+	"backtest.result_title":     "📊 Backtest Results",
+	"backtest.result_title_v2":  "📊 Long/Short Backtest Results",
+	"backtest.capital":          "💰 Capital:",
+	"backtest.final_capital":    "  Final capital: ",
+	"backtest.total_return":     "  Total return: ",
+	"backtest.max_drawdown":     "  Max drawdown: ",
+	"backtest.trade_stats":      "📈 Trade statistics:",
+	"backtest.total_trades":     "  Total trades: %d",
+	"backtest.long_trades":      "  Long trades: %s",
+	"backtest.short_trades":     "  Short trades: %s",
+	"backtest.winning_trades":   "  Winning trades: %s",
+	"backtest.losing_trades":    "  Losing trades: %s",
+	"backtest.win_rate":         "  Win rate: %.1f%%",
+	"backtest.pnl":              "💵 Profit and loss:",
+	"backtest.average_win":      "  Average win: $%.2f",
+	"backtest.average_loss":     "  Average loss: $%.2f",
+	"backtest.profit_factor":    "  Profit factor: %.2f",
+	"backtest.risk":             "📊 Risk metrics:",
+	"backtest.sharpe":           "  Sharpe ratio: %.2f",
+	"backtest.calmar":           "  Calmar ratio: %.2f",
+	"backtest.direction_stats":  "📊 By direction:",
+	"backtest.long_win_rate":    "  Long win rate: %.1f%% (P&L: ",
+	"backtest.short_win_rate":   "  Short win rate: %.1f%% (P&L: ",
+	"backtest.trades_title":     "📋 Trades (last %d):",
+	"backtest.trades_header":    "Entry time|Entry|Exit time|Exit|Profit|Return|Signal",
+	"backtest.trades_header_v2": "Time|Side|Entry|Exit|Profit|Return|Signal",
+	"backtest.long":             "Long",
+	"backtest.short":            "Short",
+	"backtest.trades_shown":     "%d trades in total, showing the last %d",
+	"backtest.evaluation":       "💡 Strategy evaluation:",
+	"backtest.excellent":        "  ✅ Excellent performance with a solid return",
+	"backtest.profitable":       "  ⚠️  Profitable, but the return is modest",
+	"backtest.losing":           "  ❌ The strategy lost money, tune the parameters or improve the strategy",
+	"backtest.high_drawdown":    "  ⚠️  Large max drawdown, risk control needs work",
+	"backtest.low_win_rate":     "  ⚠️  Low win rate, consider tightening the entry conditions",
+	"backtest.low_sharpe":       "  ⚠️  Low Sharpe ratio, the return/risk ratio needs work",
+	"backtest.high_sharpe":      "  ✅ Excellent Sharpe ratio, good risk-adjusted return",
+	"backtest.high_calmar":      "  ✅ Excellent Calmar ratio, good return/drawdown ratio",
+	"backtest.low_calmar":       "  ⚠️  Low Calmar ratio, drawdown control needs work",
+
+	// CSV导出表头
+	"export.analysis_header":   "Time|Symbol|Price|Trend|Trend score|RSI|MACD|ADX|Volume ratio|MA5|MA20|MA50",
+	"export.indicators_header": "Time|Close",
+	"export.ohlcv_header":      "Time|Open|High|Low|Close|Volume",
+	"export.indicators_done":   "📁 Indicator series exported to %s",
+	"export.indicators_failed": "❌ Failed to export indicators: %v",
+
+	// 回测入场/出场原因
+	"reason.stop_loss":       "Stop loss",
+	"reason.take_profit":     "Take profit",
+	"reason.end_of_backtest": "Closed at backtest end",
+	"reason.long":            "Long (strength: %.2f)",
+	"reason.short":           "Short (strength: %.2f)",
+	"reason.close":           "Close (strength: %.2f)",
+	"reason.close_long":      "Close long (strength: %.2f)",
+	"reason.close_short":     "Close short (strength: %.2f)",
+	"reason.reverse_long":    "Reverse to long (strength: %.2f)",
+	"reason.reverse_short":   "Reverse to short (strength: %.2f)",
+	"reason.stop_supertrend": "SuperTrend stop (%.2f)",
+	"reason.stop_sar":        "SAR stop (%.2f)",
+	"reason.stop_dynamic":    "Trailing stop (%.2f)",
+	"reason.default_stop":    "Default 5% stop",
+
+	"reason.trend_entry":        "Trend entry (ADX: %.1f, strength: %.2f)",
+	"reason.trend_reversal":     "Trend reversal (strength: %.2f)",
+	"reason.below_ma20":         "Below MA20",
+	"reason.macd_death_cross":   "MACD death cross",
+	"reason.volume_selloff":     "High-volume selloff",
+	"reason.momentum_breakout":  "Momentum breakout (RSI: %.1f, Vol: %.1fx)",
+	"reason.squeeze_breakout":   "Squeeze breakout (RSI: %.1f, Vol: %.1fx)",
+	"reason.rsi_overbought":     "RSI overbought",
+	"reason.momentum_exhausted": "Momentum exhausted",
+	"reason.macd_negative":      "MACD turned negative",
+	"reason.below_ma5":          "Below MA5",
+	"reason.squeeze_fired_down": "Squeeze fired down",
+	"reason.oversold_bounce":    "Oversold bounce (RSI: %.1f, deviation: %.1f%%, %%B: %.2f)",
+	"reason.bollinger_middle":   "Back to Bollinger middle",
+	"reason.back_to_ma20":       "Back to MA20",
+	"reason.rsi_recovered":      "RSI recovered",
+	"reason.near_resistance":    "Near resistance",
+	"reason.target_reached":     "Target reached",
+	"reason.mode_trend":         "[Trend mode] %s",
+	"reason.mode_momentum":      "[Momentum mode] %s",
+	"reason.mode_reversion":     "[Reversion mode] %s",
+
+	"reason.long_signal":          "Long signal (strength: %.2f, confirmations: %d, market: %s)",
+	"reason.short_signal":         "Short signal (strength: %.2f, confirmations: %d, market: %s)",
+	"reason.take_profit_long":     "Take profit on long (return: %.2f%%)",
+	"reason.take_profit_short":    "Take profit on short (return: %.2f%%)",
+	"reason.trend_reversal_long":  "Trend reversal, close long",
+	"reason.trend_reversal_short": "Trend reversal, close short",
+	"reason.divergence_long":      "Divergence, close long",
+	"reason.divergence_short":     "Divergence, close short",
+	"reason.below_ma20_long":      "Below MA20, close long",
+	"reason.above_ma20_short":     "Above MA20, close short",
+	"reason.strong_bearish_long":  "Strongly bearish, close long (strength: %.2f)",
+	"reason.strong_bullish_short": "Strongly bullish, close short (strength: %.2f)",
+
+	// 命令行帮助
+	"cli.analyzer_short":    "Cryptocurrency market trend analysis tool",
+	"cli.analyzer_long":     "A cryptocurrency market technical analysis tool written in Go, supporting many technical indicators and live monitoring.",
+	"cli.backtest_short":    "Backtest trading strategies",
+	"cli.backtest_long":     "Backtesting tool for trading strategies based on technical indicators",
+	"cli.backtest_v2_short": "Backtest long/short trading strategies",
+	"cli.backtest_v2_long":  "Backtesting tool for strategies that trade both long and short",
+
+	"flag.symbols":            "Trading pairs to analyze",
+	"flag.watchlist":          "Use a preset watchlist",
+	"flag.analyzer_interval":  "Kline interval (15m/30m/1h/4h/1d)",
+	"flag.limit":              "Number of klines to fetch",
+	"flag.continuous":         "Continuous monitoring mode",
+	"flag.delay":              "Monitoring interval (seconds)",
+	"flag.cache":              "Enable the data cache (enabled by default)",
+	"flag.clear_cache":        "Clear all cached data",
+	"flag.cache_dir":          "Cache directory",
+	"flag.cache_ttl":          "Cache lifetime (minutes)",
+	"flag.vwap_session":       "VWAP session reset time (UTC, HH:MM)",
+	"flag.avwap_from":         "Anchored VWAP start (UTC, 2006-01-02 15:04)",
+	"flag.pivot_method":       "Pivot point method (floor/fibonacci/camarilla/woodie/demark)",
+	"flag.pivot_period":       "Pivot point period, using the previous complete one (day/week/month)",
+	"flag.analyzer_transform": "Candle transform used for analysis: heikin-ashi|renko (prices are still shown as real prices)",
+	"flag.no_correlation":     "Do not print the cross-asset correlation for multiple symbols",
+	"flag.corr_window":        "Correlation window (number of aligned returns)",
+	"flag.corr_threshold":     "Correlation clustering threshold",
+	"flag.corr_rolling":       "Rolling correlation window (number of returns)",
+	"flag.no_rs":              "Do not print the relative strength leaderboard for multiple symbols",
+	"flag.mtf":                "Multi-timeframe confluence analysis (timeframes from the symbol config or analysis.timeframes in the config file)",
+	"flag.timeframes":         "Timeframes of the multi-timeframe analysis, e.g. 15m,1h,4h,1d",
+	"flag.indicator":          "Extra indicator spec to compute, repeatable, e.g. --indicator 'rsi(period=9)'",
+	"flag.list_indicators":    "List the available indicators and their parameters",
+	"flag.export_indicators":  "Export indicator series by spec to CSV, comma separated, e.g. --export-indicators 'rsi(period=9),ema(period=21)'",
+	"flag.expr":               "Custom indicator expression, repeatable, e.g. --expr 'ema(close,20) > ema(close,50) and rsi(14) < 40'",
+
+	"flag.symbol":              "Trading pair",
+	"flag.interval":            "Kline interval",
+	"flag.days":                "Number of days to backtest",
+	"flag.capital":             "Initial capital",
+	"flag.entry":               "Entry threshold",
+	"flag.exit":                "Exit threshold",
+	"flag.long":                "Long threshold",
+	"flag.short":               "Short threshold",
+	"flag.close":               "Close threshold",
+	"flag.stoploss":            "Stop loss percentage",
+	"flag.takeprofit":          "Take profit percentage",
+	"flag.strategy":            "Strategy type: simple|trend|momentum|reversal|combo",
+	"flag.trend_avwap_stop":    "Trend strategy stops at the VWAP anchored to the prior low",
+	"flag.rsi_spec":            "RSI spec of the mean reversion strategy, e.g. rsi(period=9)",
+	"flag.enable_short":        "Enable short selling",
+	"flag.improved":            "Use the improved strategy",
+	"flag.improved_avwap_stop": "Improved strategy tightens stops with the anchored VWAP",
+	"flag.stop_mode":           "Trailing stop of the improved strategy: atr|supertrend|psar",
+	"flag.st_period":           "SuperTrend ATR period",
+	"flag.st_mult":             "SuperTrend ATR multiplier",
+	"flag.sar_step":            "Parabolic SAR acceleration step",
+	"flag.sar_max":             "Parabolic SAR maximum acceleration",
+	"flag.transform":           "Candle transform used for analysis: heikin-ashi|renko (fills still use real prices)",
+	"flag.rs_universe":         "Relative strength watchlist, e.g. ETHUSDT,SOLUSDT (the BTCUSDT benchmark is added automatically)",
+	"flag.min_rs":              "Relative strength percentile required to go long (short requires at most 100 minus it), 0 disables the filter",
+
+	"flag.yahoo":        "Use the Yahoo Finance data source",
+	"flag.brick_size":   "Fixed Renko brick size, 0 for ATR bricks",
+	"flag.brick_atr":    "ATR period of Renko ATR bricks",
+	"flag.rs_lookbacks": "Relative strength lookbacks and composite score weights",
+	"flag.config":       "Override config file (same format as configs/default.yaml)",
+	"flag.config_dir":   "Config directory (configs by default, built-in defaults when missing)",
+	"flag.profile":      "Sensitivity profile: sensitive|balanced|stable",
+	"flag.rules":        "Custom evidence rule file, repeatable; rules override defaults with the same id (same format as pkg/analysis/evidence_rules.yaml)",
+	"flag.lang":         "Output language zh-CN|en-US (defaults to the system locale)",
+}
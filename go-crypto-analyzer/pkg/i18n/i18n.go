@@ -0,0 +1,131 @@
+// Package i18n 是显示文本的消息目录。程序逻辑只比较代码（如趋势 UPTREND、
+// 证据 ma.price_above_ma5），显示时再按消息键查找当前语言的文本，枚举的消息键为
+// "类别.代码"，如 trend.UPTREND、category.ma。
+//
+// 支持 zh-CN 和 en-US，默认中文；其他语言缺少的消息回退到中文。
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// 支持的语言
+const (
+	Chinese = "zh-CN"
+	English = "en-US"
+)
+
+// catalogs 各语言的消息目录
+var catalogs = map[string]map[string]string{
+	Chinese: zhCN,
+	English: enUS,
+}
+
+// current 当前语言，在程序启动时设置
+var current = Chinese
+
+// SetLanguage 设置输出语言，接受 zh-CN、en-US 及 zh、en_US.UTF-8 等语言环境写法
+func SetLanguage(lang string) error {
+	tag, ok := normalize(lang)
+	if !ok {
+		return fmt.Errorf("unsupported language %q (supported: %s, %s)", lang, Chinese, English)
+	}
+	current = tag
+	return nil
+}
+
+// Language 返回当前语言
+func Language() string {
+	return current
+}
+
+// DetectLanguage 按 LC_ALL、LC_MESSAGES、LANG 的顺序取第一个设置了的系统语言环境，
+// 未设置或不受支持（如 C、POSIX）时为中文
+func DetectLanguage() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if value := os.Getenv(name); value != "" {
+			if tag, ok := normalize(value); ok {
+				return tag
+			}
+			break
+		}
+	}
+	return Chinese
+}
+
+// ArgsLanguage 返回命令行参数中 --lang 的取值（--lang en-US 或 --lang=en-US），
+// 未指定时为系统语言环境。帮助文本在解析参数之前生成，据此提前设置语言
+func ArgsLanguage(args []string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if value, ok := strings.CutPrefix(arg, "--lang="); ok {
+			return value
+		}
+		if arg == "--lang" && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return DetectLanguage()
+}
+
+// normalize 把语言环境写法映射到支持的语言
+func normalize(lang string) (string, bool) {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if i := strings.IndexAny(lang, ".@"); i >= 0 {
+		lang = lang[:i]
+	}
+	lang = strings.ReplaceAll(lang, "_", "-")
+	switch {
+	case lang == "zh" || strings.HasPrefix(lang, "zh-"):
+		return Chinese, true
+	case lang == "en" || strings.HasPrefix(lang, "en-"):
+		return English, true
+	}
+	return "", false
+}
+
+// lookup 查找当前语言的消息，缺少时回退到中文
+func lookup(key string) (string, bool) {
+	if text, ok := catalogs[current][key]; ok {
+		return text, true
+	}
+	text, ok := zhCN[key]
+	return text, ok
+}
+
+// T 返回消息键对应的文本，目录中没有时返回键本身；有参数时文本作为格式串
+func T(key string, args ...interface{}) string {
+	text, ok := lookup(key)
+	if !ok {
+		text = key
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(text, args...)
+	}
+	return text
+}
+
+// List 返回以"|"分隔的消息，如表头
+func List(key string) []string {
+	return strings.Split(T(key), "|")
+}
+
+// Label 返回枚举代码的显示文本，目录中没有时返回代码本身（如自定义规则的类别）；
+// 以"+"连接的组合代码（如价位来源 SWING+PSYCHOLOGICAL）逐项查找
+func Label(kind, code string) string {
+	if text, ok := lookup(kind + "." + code); ok {
+		return text
+	}
+	if strings.Contains(code, "+") {
+		parts := strings.Split(code, "+")
+		for i, part := range parts {
+			parts[i] = Label(kind, part)
+		}
+		return strings.Join(parts, "+")
+	}
+	return code
+}
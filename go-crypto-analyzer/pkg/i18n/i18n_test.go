@@ -0,0 +1,70 @@
+package i18n
+
+import "testing"
+
+func TestLanguage(t *testing.T) {
+	defer SetLanguage(Chinese)
+
+	// 语言环境写法归一化
+	for lang, want := range map[string]string{"zh": Chinese, "zh_CN.UTF-8": Chinese, "en": English, "en_US.UTF-8": English, "EN-gb": English} {
+		if err := SetLanguage(lang); err != nil || Language() != want {
+			t.Errorf("SetLanguage(%q) = %q, %v; want %q", lang, Language(), err, want)
+		}
+	}
+	SetLanguage(English)
+	if err := SetLanguage("fr-FR"); err == nil || Language() != English {
+		t.Errorf("unsupported language should fail and keep the current one: %v %q", err, Language())
+	}
+
+	// 系统语言环境：LC_ALL 优先，C 等不受支持的取值为中文
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "en_US.UTF-8")
+	if lang := DetectLanguage(); lang != English {
+		t.Errorf("DetectLanguage from LANG = %q", lang)
+	}
+	t.Setenv("LC_ALL", "C")
+	if lang := DetectLanguage(); lang != Chinese {
+		t.Errorf("DetectLanguage with LC_ALL=C = %q", lang)
+	}
+
+	// 帮助文本用的语言取自 --lang，未指定时取系统语言环境
+	for want, args := range map[string][]string{
+		"en-US": {"-s", "BTCUSDT", "--lang", "en-US"},
+		"en":    {"--lang=en", "--help"},
+		Chinese: {"--help"},
+		"zh-TW": {"--lang", "zh-TW", "--", "--lang", "en"},
+	} {
+		if lang := ArgsLanguage(args); lang != want {
+			t.Errorf("ArgsLanguage(%q) = %q, want %q", args, lang, want)
+		}
+	}
+
+	// 消息和枚举
+	SetLanguage(English)
+	if T("trend.UPTREND") != "Uptrend" || Label("category", "ma") != "Moving averages" || Label("category", "custom") != "custom" {
+		t.Errorf("English messages: %q %q %q", T("trend.UPTREND"), Label("category", "ma"), Label("category", "custom"))
+	}
+	if T("no.such.key") != "no.such.key" {
+		t.Error("missing key should return the key itself")
+	}
+	if Label("source", "SWING+PSYCHOLOGICAL") != "Swing+Psychological" || Label("source", "UNKNOWN") != "UNKNOWN" {
+		t.Errorf("labels: %q %q", Label("source", "SWING+PSYCHOLOGICAL"), Label("source", "UNKNOWN"))
+	}
+	SetLanguage(Chinese)
+	if T("trend.UPTREND") != "上涨趋势" || T("common.bars_ago", "MA5", 3) != "MA5(3根前)" || Label("source", "SWING+PSYCHOLOGICAL") != "摆动+心理关口" || len(List("export.ohlcv_header")) < 2 {
+		t.Errorf("Chinese messages: %q %q", T("trend.UPTREND"), Label("source", "SWING+PSYCHOLOGICAL"))
+	}
+
+	// 两种语言的消息键一致：只加在中文目录的键会让英文输出回退到中文
+	for key := range enUS {
+		if _, ok := zhCN[key]; !ok {
+			t.Errorf("key %q missing from the Chinese catalog", key)
+		}
+	}
+	for key := range zhCN {
+		if _, ok := enUS[key]; !ok {
+			t.Errorf("key %q missing from the English catalog", key)
+		}
+	}
+}
@@ -0,0 +1,680 @@
+package i18n
+
+// zhCN 中文消息目录，也是其他语言缺少消息时的回退
+var zhCN = map[string]string{
+	// 趋势方向
+	"trend.STRONG_UPTREND":   "强劲上涨趋势",
+	"trend.UPTREND":          "上涨趋势",
+	"trend.SIDEWAYS":         "横盘震荡",
+	"trend.DOWNTREND":        "下跌趋势",
+	"trend.STRONG_DOWNTREND": "强劲下跌趋势",
+
+	// 趋势强度
+	"strength.VERY_STRONG": "非常强",
+	"strength.STRONG":      "强",
+	"strength.MODERATE":    "中等",
+	"strength.WEAK":        "弱",
+	"strength.NO_TREND":    "无趋势",
+
+	// 证据类型
+	"evidence.BULLISH": "看涨证据",
+	"evidence.BEARISH": "看跌证据",
+	"evidence.NEUTRAL": "中性证据",
+	"evidence.WARNING": "警告信号",
+
+	// 背离类型
+	"divergence.NONE":            "无背离",
+	"divergence.REGULAR_BULLISH": "常规看涨背离",
+	"divergence.REGULAR_BEARISH": "常规看跌背离",
+	"divergence.HIDDEN_BULLISH":  "隐藏看涨背离",
+	"divergence.HIDDEN_BEARISH":  "隐藏看跌背离",
+
+	// MACD趋势
+	"macd.BULLISH": "看涨",
+	"macd.NEUTRAL": "中性",
+	"macd.BEARISH": "看跌",
+
+	// RSI动量
+	"momentum.OVERBOUGHT": "超买",
+	"momentum.STRONG":     "强势",
+	"momentum.NEUTRAL":    "中性",
+	"momentum.WEAK":       "弱势",
+	"momentum.OVERSOLD":   "超卖",
+
+	// 成交量
+	"volume.HIGH":              "放量",
+	"volume.NORMAL":            "正常量能",
+	"volume.LOW":               "缩量",
+	"volume.INSUFFICIENT_DATA": "数据不足",
+
+	// 交叉
+	"cross.GOLDEN":           "金叉",
+	"cross.DEATH":            "死叉",
+	"cross.OVERSOLD_GOLDEN":  "超卖金叉",
+	"cross.OVERBOUGHT_DEATH": "超买死叉",
+
+	// 一目均衡表
+	"cloud_position.ABOVE":  "云上",
+	"cloud_position.INSIDE": "云中",
+	"cloud_position.BELOW":  "云下",
+	"cloud.BULLISH":         "看涨云",
+	"cloud.BEARISH":         "看跌云",
+	"chikou.ABOVE":          "高于历史价格",
+	"chikou.BELOW":          "低于历史价格",
+	"chikou.TANGLED":        "与历史价格交织",
+
+	// VWAP标准差带
+	"vwap_band.ABOVE_UPPER_2": "上轨2上方",
+	"vwap_band.UPPER_1_2":     "上轨1-2",
+	"vwap_band.VWAP_UPPER_1":  "VWAP-上轨1",
+	"vwap_band.LOWER_1_VWAP":  "下轨1-VWAP",
+	"vwap_band.LOWER_1_2":     "下轨1-2",
+	"vwap_band.BELOW_LOWER_2": "下轨2下方",
+
+	// 跟踪止损方向（flip用于"翻多/翻空"）与通道
+	"side.LONG":     "多头",
+	"side.SHORT":    "空头",
+	"flip.LONG":     "多",
+	"flip.SHORT":    "空",
+	"breakout.UP":   "向上突破",
+	"breakout.DOWN": "向下突破",
+	"squeeze.UP":    "向上释放",
+	"squeeze.DOWN":  "向下释放",
+
+	// 斐波那契与价位来源
+	"fib.RETRACEMENT":              "回撤",
+	"fib.EXTENSION":                "扩展",
+	"source.SWING":                 "摆动",
+	"source.PSYCHOLOGICAL":         "心理关口",
+	"source.HISTORICAL_SUPPORT":    "历史支撑",
+	"source.HISTORICAL_RESISTANCE": "历史阻力",
+	"source.PIVOT_P":               "轴心点P",
+	"source.PIVOT_R1":              "轴心点R1",
+	"source.PIVOT_R2":              "轴心点R2",
+	"source.PIVOT_R3":              "轴心点R3",
+	"source.PIVOT_R4":              "轴心点R4",
+	"source.PIVOT_S1":              "轴心点S1",
+	"source.PIVOT_S2":              "轴心点S2",
+	"source.PIVOT_S3":              "轴心点S3",
+	"source.PIVOT_S4":              "轴心点S4",
+	"source.VOLUME_NODE":           "成交密集区",
+
+	// K线形态
+	"candle.GRAVESTONE_DOJI":      "墓碑十字",
+	"candle.DRAGONFLY_DOJI":       "蜻蜓十字",
+	"candle.LONG_LEGGED_DOJI":     "长腿十字",
+	"candle.DOJI":                 "十字星",
+	"candle.HAMMER":               "锤子线",
+	"candle.SHOOTING_STAR":        "射击之星",
+	"candle.BULLISH_ENGULFING":    "看涨吞没",
+	"candle.BEARISH_ENGULFING":    "看跌吞没",
+	"candle.BULLISH_HARAMI":       "看涨孕线",
+	"candle.BEARISH_HARAMI":       "看跌孕线",
+	"candle.PIERCING_LINE":        "刺透形态",
+	"candle.DARK_CLOUD_COVER":     "乌云盖顶",
+	"candle.INSIDE_BAR":           "内包线",
+	"candle.OUTSIDE_BAR":          "外包线",
+	"candle.MORNING_STAR":         "启明星",
+	"candle.EVENING_STAR":         "黄昏星",
+	"candle.THREE_WHITE_SOLDIERS": "红三兵",
+	"candle.THREE_BLACK_CROWS":    "三只乌鸦",
+
+	// 图表形态及状态
+	"chart.DOUBLE_TOP":                 "双顶",
+	"chart.DOUBLE_BOTTOM":              "双底",
+	"chart.TRIPLE_TOP":                 "三重顶",
+	"chart.TRIPLE_BOTTOM":              "三重底",
+	"chart.HEAD_AND_SHOULDERS":         "头肩顶",
+	"chart.INVERSE_HEAD_AND_SHOULDERS": "头肩底",
+	"chart.ASCENDING_TRIANGLE":         "上升三角形",
+	"chart.DESCENDING_TRIANGLE":        "下降三角形",
+	"chart.SYMMETRICAL_TRIANGLE":       "对称三角形",
+	"chart.BULL_FLAG":                  "牛旗",
+	"chart.BEAR_FLAG":                  "熊旗",
+	"chart.BULLISH_PENNANT":            "牛市三角旗",
+	"chart.BEARISH_PENNANT":            "熊市三角旗",
+	"pattern_status.FORMING":           "形成中",
+	"pattern_status.BROKE_OUT":         "已突破",
+	"pattern_status.BROKE_DOWN":        "已跌破",
+	// 形态的突破价位：头肩和多重顶底为颈线，三角形和旗形为突破位
+	"breakout_level.DOUBLE_TOP":                 "颈线",
+	"breakout_level.DOUBLE_BOTTOM":              "颈线",
+	"breakout_level.TRIPLE_TOP":                 "颈线",
+	"breakout_level.TRIPLE_BOTTOM":              "颈线",
+	"breakout_level.HEAD_AND_SHOULDERS":         "颈线",
+	"breakout_level.INVERSE_HEAD_AND_SHOULDERS": "颈线",
+	"breakout_level.ASCENDING_TRIANGLE":         "突破位",
+	"breakout_level.DESCENDING_TRIANGLE":        "突破位",
+	"breakout_level.SYMMETRICAL_TRIANGLE":       "突破位",
+	"breakout_level.BULL_FLAG":                  "突破位",
+	"breakout_level.BEAR_FLAG":                  "突破位",
+	"breakout_level.BULLISH_PENNANT":            "突破位",
+	"breakout_level.BEARISH_PENNANT":            "突破位",
+
+	// 证据类别
+	"category.ma":             "移动平均线",
+	"category.macd":           "MACD",
+	"category.divergence":     "背离",
+	"category.rsi":            "RSI",
+	"category.stochrsi":       "StochRSI",
+	"category.dmi":            "DMI",
+	"category.ichimoku":       "一目均衡表",
+	"category.vwap":           "VWAP",
+	"category.supertrend":     "SuperTrend",
+	"category.sar":            "抛物线SAR",
+	"category.squeeze":        "波动率挤压",
+	"category.channel":        "通道",
+	"category.candlestick":    "K线形态",
+	"category.chart_pattern":  "图表形态",
+	"category.fibonacci":      "斐波那契",
+	"category.structure":      "市场结构",
+	"category.sr":             "支撑阻力",
+	"category.volume_profile": "成交量分布",
+	"category.volume":         "成交量",
+
+	// 轴心点算法和周期
+	"pivot.floor":         "经典",
+	"pivot.fibonacci":     "斐波那契",
+	"pivot.camarilla":     "Camarilla",
+	"pivot.woodie":        "Woodie",
+	"pivot.demark":        "DeMark",
+	"pivot_period.day":    "前一日",
+	"pivot_period.week":   "前一周",
+	"pivot_period.month":  "前一月",
+	"pivot_period.latest": "最新K线",
+
+	// 恐慌贪婪指数
+	"sentiment.EXTREME_FEAR":  "极度恐慌 - 可能是买入机会",
+	"sentiment.FEAR":          "恐慌 - 市场偏空",
+	"sentiment.NEUTRAL":       "中性 - 观望为主",
+	"sentiment.GREED":         "贪婪 - 市场偏多",
+	"sentiment.EXTREME_GREED": "极度贪婪 - 注意风险",
+	"feargreed.title":         "😱 恐慌贪婪指数: ",
+
+	// 系统判断
+	"judgment.STRONG_BULLISH": "强烈看涨信号",
+	"judgment.BULLISH":        "偏多信号",
+	"judgment.STRONG_BEARISH": "强烈看跌信号",
+	"judgment.BEARISH":        "偏空信号",
+	"judgment.NEUTRAL":        "信号不明确",
+
+	// 信号冲突
+	"conflict.MA_MACD":           "MA和MACD信号冲突，谨慎操作",
+	"conflict.VOLUME_SURGE_DOWN": "放量下跌，卖压沉重",
+
+	// K线变换
+	"transform.none":        "原始K线",
+	"transform.heikin_ashi": "Heikin-Ashi",
+	"transform.renko":       "Renko(砖块%.4g)",
+	"transform.renko_atr":   "Renko(砖块ATR%d)",
+
+	// 通用
+	"common.none":              "无",
+	"common.yes":               "是",
+	"common.no":                "否",
+	"common.insufficient_data": "数据不足",
+	"common.bars_ago":          "%s(%d根前)",
+	"common.item_header":       "项目|数值|状态",
+	"common.source_yahoo":      "使用Yahoo Finance数据源",
+	"common.source_binance":    "使用Binance数据源",
+	"common.fetch_failed":      "❌ 获取数据失败: %v",
+	"common.rules_load_failed": "❌ 加载证据规则失败: %v",
+
+	// 分析运行
+	"run.clear_cache_failed":   "清除缓存失败: %v",
+	"run.cache_cleared":        "✅ 已清除所有缓存数据",
+	"run.cache_enabled":        "✅ 缓存已启用 (目录: %s, TTL: %d分钟)",
+	"run.cache_disabled":       "⚠️  缓存已禁用",
+	"run.invalid_vwap_session": "无效的VWAP会话时间 %q: %v",
+	"run.invalid_avwap_from":   "无效的锚定VWAP起点 %q: %v",
+	"run.rule_expression":      "❌ 证据规则表达式 %q: %v",
+	"run.title":                "🚀 加密货币市场分析 - %s",
+	"run.timeframe":            "📊 时间周期: %s | 数据点: %d",
+	"run.profile":              "⚙️  灵敏度配置: %s（%s）",
+	"run.transform":            "🔁 K线变换: %s（指标基于变换序列，价格为真实价格）",
+	"run.next_update":          "⏰ 下次更新: %d秒后",
+
+	// 数据缓存与计时
+	"cache.first_fetch":  "  📥 首次获取数据，请求 %d 根K线...",
+	"cache.saved":        "  💾 已缓存 %d 根K线数据",
+	"cache.hit":          "  ⚡ 使用缓存数据（最新: %s）",
+	"cache.incremental":  "  🔄 增量更新：获取最新 %d 根K线...",
+	"cache.fetch_failed": "  ⚠️  获取新数据失败，使用缓存数据",
+	"cache.updated":      "  ✅ 更新成功，新增 %d 根K线",
+	"cache.cleared":      "  🗑️  已清除 %s %s 的缓存",
+	"timer.seconds":      "⏱️  %s 耗时: %.2f秒",
+	"timer.milliseconds": "⏱️  %s 耗时: %dms",
+
+	// 单个交易对
+	"symbol.title":              "📊 分析 %s",
+	"symbol.adjust_limit":       "  ℹ️  自动调整数据量: %d → %d (确保历史信号追踪)",
+	"symbol.rate_limited":       "  ❌ API访问被限制，请稍后再试或使用 -y 参数切换到Yahoo数据源",
+	"symbol.network_failed":     "  ❌ 网络连接失败，请检查网络连接",
+	"symbol.fetch_failed":       "  ❌ 获取数据失败: %v",
+	"symbol.hint":               "  💡 提示: 可以尝试以下操作:",
+	"symbol.hint_yahoo":         "     1. 使用 -y 参数切换到Yahoo Finance数据源",
+	"symbol.hint_rate":          "     2. 减少请求频率或数据量",
+	"symbol.hint_symbol":        "     3. 检查交易对名称是否正确",
+	"symbol.insufficient_data":  "  ❌ 数据不足（需要至少50根K线）",
+	"symbol.analysis_failed":    "  ❌ 分析失败: %v",
+	"symbol.renko_hint":         "  💡 提示: Renko砖块数量不足，可增加数据量(-l)或减小砖块(--brick-size)",
+	"symbol.rules_failed":       "  ❌ 证据规则执行失败: %v",
+	"symbol.significant_change": "  ⚡ 最新K线涨跌%+.2f%%，超过显著变动阈值%.1f%%",
+
+	// 多周期共振
+	"mtf.fetch_failed":         "  ⚠️  %s周期数据获取失败: %v",
+	"mtf.skipped":              "⚠️  多周期共振分析跳过: %v",
+	"mtf.title":                "🧭 %s 多周期共振",
+	"mtf.header":               "周期|权重|趋势|趋势评分|RSI|MACD|证据强度|周期得分",
+	"mtf.confluence":           "  共振得分: %+.0f（%s），方向一致权重占比 %.0f%%，高周期权重更大",
+	"mtf.conflict":             "  ⚠️  周期冲突: %s",
+	"mtf.conflict_description": "%s %s，但处于 %s %s中",
+
+	// 相对强度
+	"rs.skipped":       "⚠️  相对强度排行跳过: %v",
+	"rs.title":         "🏆 相对强度排行 (截至 %s)",
+	"rs.header":        "排名|币种",
+	"rs.basket":        "篮子%s",
+	"rs.vs_benchmark":  "对%s%s",
+	"rs.score_header":  "综合评分|百分位",
+	"rs.leading":       "%s 领涨",
+	"rs.lagging":       "%s 落后",
+	"rs.note":          "  篮子列为相对等权篮子的强弱，综合评分为加权对数超额收益(%%)，⭐ 表示相对%s强度线处于周期新高",
+	"rs.partial_cover": "  ⚠️  数据仅覆盖至%s周期，更长周期未计入综合评分",
+
+	// 跨资产相关性
+	"cross.skipped":           "⚠️  跨资产相关性分析跳过: %v",
+	"cross.title":             "🔗 跨资产相关性 (%d个收益率，%s 至 %s)",
+	"cross.legend":            "  上三角: Pearson  下三角: Spearman  (红≥0.8 黄≥0.5 绿<0.5 青<0)",
+	"cross.summary":           "  平均相关系数: %.2f  有效独立押注: %.1f / %d",
+	"cross.rolling_title":     "📈 滚动相关系数 (每%d个收益率):",
+	"cross.rolling_header":    "交易对|滚动相关（由早到近）|最低|最高",
+	"cross.beta_title":        "📐 对%s的贝塔:",
+	"cross.beta_header":       "币种|贝塔|相关系数|前一窗口|变化",
+	"cross.cluster":           "  🧩 相关簇%d: %s",
+	"cross.warning_single":    "观察列表%d个币种的有效独立押注仅%.1f个，整体相当于单一押注",
+	"cross.warning_cluster":   "%s高度相关(ρ≥%.2f)，占观察列表%d/%d，分散效果有限",
+	"cross.warning_systemic":  "平均两两相关系数%.2f，系统性风险集中",
+	"cross.cluster_separator": "、",
+
+	// 分析结果
+	"analysis.price":              "💰 当前价格: %s",
+	"analysis.trend":              "📈 整体趋势: %s",
+	"analysis.trend_score":        "📊 趋势评分: %.1f",
+	"analysis.ma_structure":       "🧭 均线趋势: %s  结构趋势: %s",
+	"analysis.indicator_header":   "指标|数值|参考值|状态",
+	"analysis.rsi_ref":            "超买>%.0f, 超卖<%.0f",
+	"analysis.overbought":         "超买",
+	"analysis.oversold":           "超卖",
+	"analysis.stoch_ref":          "超买>80, 超卖<20",
+	"analysis.macd_histogram":     "MACD柱",
+	"analysis.macd_histogram_ref": ">0看涨, <0看跌",
+	"analysis.adx_ref":            "强势>35, 弱势<20",
+	"analysis.plus_di_dominant":   "+DI占优",
+	"analysis.minus_di_dominant":  "-DI占优",
+	"analysis.di_ref":             "+DI>-DI看涨",
+	"analysis.supertrend_ref":     "价格在线上看涨",
+	"analysis.sar_ref":            "价格在SAR上看涨",
+	"analysis.trailing_flip":      "翻%s(%d根前)",
+	"analysis.bb_inside":          "带内",
+	"analysis.bb_above":           "突破上轨",
+	"analysis.bb_below":           "跌破下轨",
+	"analysis.percent_b":          "布林%%B(%d,%.1f)",
+	"analysis.bb_ref":             "<0超卖, >1超买",
+	"analysis.bandwidth":          "布林带宽",
+	"analysis.bandwidth_rank":     "分位: %.0f%%",
+	"analysis.no_squeeze":         "无挤压",
+	"analysis.squeezing":          "挤压中(%d根)",
+	"analysis.ttm_squeeze":        "TTM挤压",
+	"analysis.squeeze_ref":        "BB在KC内为挤压",
+	"analysis.donchian":           "唐奇安(%d)",
+	"analysis.donchian_ref":       "突破看方向",
+	"analysis.volume_ref":         "放量>%gx, 缩量<%gx",
+	"analysis.volume_ratio":       "成交量比",
+	"analysis.current_volume":     "当前成交量",
+	"analysis.volume_ma":          "均量: %.0f",
+	"analysis.indicators_title":   "📊 技术指标详情:",
+	"analysis.ma_title":           "📉 移动平均线详情:",
+	"analysis.ma_header":          "均线|价格|相对位置|偏离度",
+	"analysis.pivot_partial":      "，数据不完整",
+	"analysis.pivot_title":        "🎯 关键价位 (%s轴心点，基于%s):",
+	"analysis.pivot_header":       "类型|价位|距离|强度",
+	"analysis.level_strength":     "中|强|很强|极强",
+	"analysis.resistance_level":   "阻力R%d",
+	"analysis.support_level":      "支撑S%d",
+	"analysis.pivot":              "轴心点",
+	"analysis.pivot_strength":     "参考",
+
+	// 证据汇总与建议
+	"analysis.evidence_summary":  "🔍 证据汇总:",
+	"analysis.count":             "%d条",
+	"analysis.bullish_evidence":  "  看涨证据: %s",
+	"analysis.bearish_evidence":  "  看跌证据: %s",
+	"analysis.warning_evidence":  "  警告信号: %s",
+	"analysis.total_strength":    "  综合强度: %.2f",
+	"analysis.evidence_title":    "📋 详细证据分析:",
+	"analysis.evidence_header":   "类型|类别|描述|权重",
+	"analysis.evidence_bullish":  "✅ 看涨",
+	"analysis.evidence_bearish":  "❌ 看跌",
+	"analysis.evidence_warning":  "⚠️ 警告",
+	"analysis.evidence_neutral":  "➖ 中性",
+	"analysis.evidence_hidden":   "  … 另有%d条较弱证据未显示（display.max_evidences）",
+	"analysis.consistency_title": "🔍 指标一致性:",
+	"analysis.bullish_signals":   "  看涨信号: %d个",
+	"analysis.bearish_signals":   "  看跌信号: %d个",
+	"analysis.warning_signals":   "  警告信号: %d个",
+	"analysis.consistency":       "  一致性: %.1f%%",
+	"analysis.suggestion_title":  "💡 参考建议（仅供参考，请结合实际情况）:",
+	"analysis.overall_score":     "  综合得分: %.2f",
+	"analysis.judgment":          "  系统判断：%s",
+	"analysis.disclaimer":        "⚠️  提醒：以上为技术指标分析结果，投资决策需要综合考虑多方面因素",
+
+	// 一目均衡表
+	"ichimoku.title":          "☁️  一目均衡表:",
+	"ichimoku.no_cross":       "无交叉",
+	"ichimoku.tk_cross":       "%s(%s, %d根前)",
+	"ichimoku.twist":          "%d根K线后扭转",
+	"ichimoku.tenkan_kijun":   "转换线/基准线",
+	"ichimoku.cloud":          "当前云层",
+	"ichimoku.price_position": "价格%s",
+	"ichimoku.thickness":      "云层厚度",
+	"ichimoku.future_cloud":   "未来云层",
+	"ichimoku.cloud_twist":    "云层扭转",
+	"ichimoku.chikou":         "迟行线",
+
+	// 图表形态与市场结构
+	"pattern.title":       "🔺 图表形态:",
+	"pattern.header":      "形态|状态|突破位|失效位|目标|置信度",
+	"structure.title":     "🏗️  市场结构:",
+	"structure.swings":    "  摆动序列: %s",
+	"structure.up":        "向上",
+	"structure.down":      "向下",
+	"structure.event":     "  最近事件: %s %s突破 $%.2f (%s，%d根K线前)",
+	"structure.protected": "  结构保护位: $%.2f",
+
+	// 指标与表达式
+	"indicators.title":   "🧮 可用指标:",
+	"indicators.header":  "名称|说明|参数|输入|输出",
+	"indicators.sources": "  source可选: open, high, low, close, volume, hl2, hlc3, ohlc4；多输出指标用 .输出名 选择，如 macd().signal",
+	"custom.title":       "🧮 自定义指标:",
+	"custom.header":      "指标|数值",
+	"expr.title":         "🧾 自定义表达式:",
+	"expr.header":        "表达式|结果",
+
+	// 波动率
+	"volatility.title":              "🌡️  已实现波动率 (K线周期 %s，年化因子 √%.0f):",
+	"volatility.estimator":          "估计量",
+	"volatility.annualized":         "年化波动率(%d根)",
+	"volatility.close_to_close":     "收盘价 Close-to-Close",
+	"volatility.expanding":          "%s (波动放大)",
+	"volatility.contracting":        "%s (波动收敛)",
+	"volatility.long_term":          "  长期波动率(%d根): %.2f%%  短期/长期: %s",
+	"volatility.hurst_insufficient": "  Hurst指数: 数据不足",
+	"volatility.hurst":              "  Hurst指数: %.2f  方差比: %.2f (z=%.2f)  市场状态: %s",
+	"regime.random_walk":            "随机游走",
+	"regime.trending":               "趋势延续",
+	"regime.mean_reverting":         "均值回归",
+
+	// 斐波那契、支撑阻力、成交量分布、VWAP
+	"fibonacci.downswing":   "下跌波段 $%.2f(%s) → $%.2f(%s)",
+	"fibonacci.upswing":     "上涨波段 $%.2f(%s) → $%.2f(%s)",
+	"fibonacci.title":       "🌀 斐波那契 (%s):",
+	"fibonacci.header":      "类型|比例|价位|距离|状态",
+	"fibonacci.testing":     "测试中",
+	"sr.title":              "🧱 结构支撑阻力:",
+	"sr.header":             "类型|价位|距离|来源|触及|评分",
+	"sr.touches":            "%d次",
+	"sr.touches_ago":        "(%d根前)",
+	"sr.untested":           "未测试",
+	"sr.resistance":         "阻力",
+	"sr.support":            "支撑",
+	"volume_profile.title":  "📦 成交量分布:",
+	"volume_profile.header": "类型|价位|距离",
+	"volume_profile.vah":    "价值区上沿VAH",
+	"volume_profile.poc":    "控制点POC",
+	"volume_profile.val":    "价值区下沿VAL",
+	"volume_profile.hvn":    "高成交量节点",
+	"volume_profile.lvn":    "低成交量节点",
+	"vwap.title":            "📐 VWAP:",
+	"vwap.session":          "会话VWAP",
+	"vwap.since":            "自%s",
+	"vwap.anchored_low":     "前低AVWAP",
+	"vwap.anchored_high":    "前高AVWAP",
+	"vwap.anchored_custom":  "自定义AVWAP",
+	"vwap.anchor":           "锚点%s",
+
+	// 价格走势图
+	"chart.price_range": "价格区间: $%.2f - $%.2f",
+	"chart.title":       "📈 价格走势图:",
+	"chart.hours":       "%.0f小时",
+	"chart.days_hours":  "%d天%d小时",
+	"chart.days":        "%d天",
+	"chart.summary":     "时间跨度: %s  最高: $%.2f  最低: $%.2f  变化: %.2f%%",
+
+	// 历史信号追踪
+	"history.title":            "📊 历史信号追踪（过去12小时）",
+	"history.no_history":       "  ⚠️  历史数据不足，无法显示信号追踪",
+	"history.limited":          "  ℹ️  数据有限，显示过去%d小时",
+	"history.header":           "时间|价格|综合得分|系统判断|RSI|MACD|成交量|K线形态",
+	"history.insufficient":     "  ⚠️  数据不足，无法显示完整的历史信号",
+	"history.candles":          "  ℹ️  当前只有 %d 根K线，无法同时进行技术分析和历史追踪",
+	"history.nothing":          "  ⚠️  没有足够的历史数据可显示",
+	"history.range":            "  ℹ️  分析时间范围: %s 至 %s",
+	"history.points":           "  ℹ️  数据点: 共%d个，每%d个显示一次",
+	"history.change_title":     "🔄 信号变化分析:",
+	"history.average":          "  平均得分: %.2f",
+	"history.highest":          "  最高得分: %.2f (%s)",
+	"history.lowest":           "  最低得分: %.2f (%s)",
+	"history.trend":            "  信号趋势: ",
+	"history.strengthening":    "转强 ↗",
+	"history.weakening":        "转弱 ↘",
+	"history.flat":             "横盘 →",
+	"history.position":         "  当前位置: ",
+	"history.maybe_overbought": "可能超买",
+	"history.maybe_oversold":   "可能超卖",
+	"history.normal":           "正常区间",
+
+	// 回测
+	"backtest.title":             "📊 回测分析 - %s",
+	"backtest.title_v2":          "📊 双向交易回测 - %s",
+	"backtest.fetching":          "⏳ 获取历史数据: %s, %s, %d根K线...",
+	"backtest.fetched":           "✅ 成功获取 %d 根K线数据",
+	"backtest.strategy_trend":    "📊 使用趋势跟踪策略",
+	"backtest.strategy_momentum": "📊 使用动量突破策略",
+	"backtest.strategy_reversal": "📊 使用均值回归策略",
+	"backtest.strategy_combo":    "📊 使用自适应组合策略",
+	"backtest.strategy_simple":   "📊 使用简单阈值策略",
+	"backtest.params":            "📈 回测参数:",
+	"backtest.initial_capital":   "  初始资金: $%.2f",
+	"backtest.entry_threshold":   "  入场阈值: %.2f",
+	"backtest.exit_threshold":    "  出场阈值: %.2f",
+	"backtest.long_threshold":    "  做多阈值: %.2f",
+	"backtest.short_threshold":   "  做空阈值: %.2f",
+	"backtest.close_threshold":   "  平仓阈值: %.2f",
+	"backtest.stop_loss":         "  止损: %.1f%%",
+	"backtest.take_profit":       "  止盈: %.1f%%",
+	"backtest.profile":           "  灵敏度配置: %s（%s）",
+	"backtest.transform":         "  K线变换: %s（成交按真实价格）",
+	"backtest.rs":                "  相对强度: %s（周期 %s",
+	"backtest.rs_min":            "，开仓百分位≥%.0f",
+	"backtest.rs_end":            "）",
+	"backtest.short_enabled":     "  ✅ 启用做空",
+	"backtest.short_disabled":    "  ❌ 禁用做空",
+	"backtest.improved":          "  📊 使用改进的策略（动态止损、市场状态适应）",
+	"backtest.stop_mode":         "  止损方式: %s",
+	"backtest.basic":             "  📊 使用基础策略",
+	"backtest.running":           "⚙️  运行回测...",
+	"backtest.failed":            "❌ 回测失败: %v",
+	"backtest.fetching_rs":       "⏳ 获取相对强度数据: %s...",
+	"backtest.fetch_rs_failed":   "获取%s数据失败: %v",
+
+	// 回测结果
+	"backtest.result_title":     "📊 回测结果",
+	"backtest.result_title_v2":  "📊 双向交易回测结果",
+	"backtest.capital":          "💰 资金变化:",
+	"backtest.final_capital":    "  最终资金: ",
+	"backtest.total_return":     "  总收益: ",
+	"backtest.max_drawdown":     "  最大回撤: ",
+	"backtest.trade_stats":      "📈 交易统计:",
+	"backtest.total_trades":     "  总交易次数: %d",
+	"backtest.long_trades":      "  做多交易: %s",
+	"backtest.short_trades":     "  做空交易: %s",
+	"backtest.winning_trades":   "  获利交易: %s",
+	"backtest.losing_trades":    "  亏损交易: %s",
+	"backtest.win_rate":         "  胜率: %.1f%%",
+	"backtest.pnl":              "💵 盈亏分析:",
+	"backtest.average_win":      "  平均盈利: $%.2f",
+	"backtest.average_loss":     "  平均亏损: $%.2f",
+	"backtest.profit_factor":    "  盈亏比: %.2f",
+	"backtest.risk":             "📊 风险指标:",
+	"backtest.sharpe":           "  夏普比率: %.2f",
+	"backtest.calmar":           "  卡尔玛比率: %.2f",
+	"backtest.direction_stats":  "📊 方向统计:",
+	"backtest.long_win_rate":    "  做多胜率: %.1f%% (盈亏: ",
+	"backtest.short_win_rate":   "  做空胜率: %.1f%% (盈亏: ",
+	"backtest.trades_title":     "📋 交易明细 (最近%d笔):",
+	"backtest.trades_header":    "入场时间|入场价|出场时间|出场价|收益|收益率|信号",
+	"backtest.trades_header_v2": "时间|方向|入场价|出场价|收益|收益率|信号",
+	"backtest.long":             "做多",
+	"backtest.short":            "做空",
+	"backtest.trades_shown":     "共 %d 笔交易，显示最近 %d 笔",
+	"backtest.evaluation":       "💡 策略评价:",
+	"backtest.excellent":        "  ✅ 策略表现优秀，年化收益可观",
+	"backtest.profitable":       "  ⚠️  策略有盈利，但收益率一般",
+	"backtest.losing":           "  ❌ 策略亏损，需要优化参数或改进策略",
+	"backtest.high_drawdown":    "  ⚠️  最大回撤较大，风险控制需要加强",
+	"backtest.low_win_rate":     "  ⚠️  胜率较低，考虑优化入场条件",
+	"backtest.low_sharpe":       "  ⚠️  夏普比率较低，收益风险比需要改善",
+	"backtest.high_sharpe":      "  ✅ 夏普比率优秀，风险调整后收益良好",
+	"backtest.high_calmar":      "  ✅ 卡尔玛比率优秀，收益回撤比良好",
+	"backtest.low_calmar":       "  ⚠️  卡尔玛比率较低，回撤控制需要改善",
+
+	// CSV导出表头
+	"export.analysis_header":   "时间|交易对|价格|趋势|趋势得分|RSI|MACD|ADX|成交量比|MA5|MA20|MA50",
+	"export.indicators_header": "时间|收盘",
+	"export.ohlcv_header":      "时间|开盘|最高|最低|收盘|成交量",
+	"export.indicators_done":   "📁 指标序列已导出: %s",
+	"export.indicators_failed": "❌ 导出指标失败: %v",
+
+	// 回测入场/出场原因
+	"reason.stop_loss":       "止损",
+	"reason.take_profit":     "止盈",
+	"reason.end_of_backtest": "回测结束平仓",
+	"reason.long":            "做多(强度:%.2f)",
+	"reason.short":           "做空(强度:%.2f)",
+	"reason.close":           "平仓(强度:%.2f)",
+	"reason.close_long":      "平多(强度:%.2f)",
+	"reason.close_short":     "平空(强度:%.2f)",
+	"reason.reverse_long":    "反手做多(强度:%.2f)",
+	"reason.reverse_short":   "反手做空(强度:%.2f)",
+	"reason.stop_supertrend": "SuperTrend止损(%.2f)",
+	"reason.stop_sar":        "SAR止损(%.2f)",
+	"reason.stop_dynamic":    "动态止损(%.2f)",
+	"reason.default_stop":    "默认止损5%",
+
+	"reason.trend_entry":        "趋势买入(ADX:%.1f,强度:%.2f)",
+	"reason.trend_reversal":     "趋势反转(强度:%.2f)",
+	"reason.below_ma20":         "跌破MA20",
+	"reason.macd_death_cross":   "MACD死叉",
+	"reason.volume_selloff":     "放量下跌",
+	"reason.momentum_breakout":  "动量突破(RSI:%.1f,Vol:%.1fx)",
+	"reason.squeeze_breakout":   "挤压释放突破(RSI:%.1f,Vol:%.1fx)",
+	"reason.rsi_overbought":     "RSI超买",
+	"reason.momentum_exhausted": "动量衰竭",
+	"reason.macd_negative":      "MACD转负",
+	"reason.below_ma5":          "跌破MA5",
+	"reason.squeeze_fired_down": "挤压向下释放",
+	"reason.oversold_bounce":    "超卖反弹(RSI:%.1f,偏离:%.1f%%,%%B:%.2f)",
+	"reason.bollinger_middle":   "回归布林中轨",
+	"reason.back_to_ma20":       "回归MA20",
+	"reason.rsi_recovered":      "RSI恢复",
+	"reason.near_resistance":    "接近阻力",
+	"reason.target_reached":     "达到止盈",
+	"reason.mode_trend":         "[趋势模式] %s",
+	"reason.mode_momentum":      "[动量模式] %s",
+	"reason.mode_reversion":     "[反转模式] %s",
+
+	"reason.long_signal":          "做多信号(强度:%.2f,确认:%d,市场:%s)",
+	"reason.short_signal":         "做空信号(强度:%.2f,确认:%d,市场:%s)",
+	"reason.take_profit_long":     "止盈平多(收益:%.2f%%)",
+	"reason.take_profit_short":    "止盈平空(收益:%.2f%%)",
+	"reason.trend_reversal_long":  "趋势反转平多",
+	"reason.trend_reversal_short": "趋势反转平空",
+	"reason.divergence_long":      "技术背离平多",
+	"reason.divergence_short":     "技术背离平空",
+	"reason.below_ma20_long":      "跌破MA20平多",
+	"reason.above_ma20_short":     "突破MA20平空",
+	"reason.strong_bearish_long":  "强烈看跌平多(强度:%.2f)",
+	"reason.strong_bullish_short": "强烈看涨平空(强度:%.2f)",
+
+	// 命令行帮助
+	"cli.analyzer_short":    "加密货币市场趋势分析工具",
+	"cli.analyzer_long":     "使用Go语言开发的加密货币市场技术分析工具，支持多种技术指标和实时监控。",
+	"cli.backtest_short":    "回测交易策略",
+	"cli.backtest_long":     "基于技术指标的交易策略回测工具",
+	"cli.backtest_v2_short": "双向交易策略回测",
+	"cli.backtest_v2_long":  "支持做多做空的交易策略回测工具",
+
+	"flag.symbols":            "要分析的交易对列表",
+	"flag.watchlist":          "使用预设的监控列表",
+	"flag.analyzer_interval":  "K线时间间隔 (15m/30m/1h/4h/1d)",
+	"flag.limit":              "获取K线数量",
+	"flag.continuous":         "持续监控模式",
+	"flag.delay":              "监控间隔（秒）",
+	"flag.cache":              "启用数据缓存（默认启用）",
+	"flag.clear_cache":        "清除所有缓存数据",
+	"flag.cache_dir":          "缓存目录",
+	"flag.cache_ttl":          "缓存有效期（分钟）",
+	"flag.vwap_session":       "VWAP会话重置时间（UTC, HH:MM）",
+	"flag.avwap_from":         "锚定VWAP起点（UTC, 2006-01-02 15:04）",
+	"flag.pivot_method":       "轴心点算法 (floor/fibonacci/camarilla/woodie/demark)",
+	"flag.pivot_period":       "轴心点周期，取上一个完整周期 (day/week/month)",
+	"flag.analyzer_transform": "分析使用的K线变换: heikin-ashi|renko（价格仍显示真实价格）",
+	"flag.no_correlation":     "多个交易对时不输出跨资产相关性",
+	"flag.corr_window":        "相关性计算窗口（对齐后的收益率数量）",
+	"flag.corr_threshold":     "相关性聚类阈值",
+	"flag.corr_rolling":       "滚动相关系数窗口（收益率数量）",
+	"flag.no_rs":              "多个交易对时不输出相对强度排行",
+	"flag.mtf":                "多周期共振分析（周期取交易对配置或配置文件的analysis.timeframes）",
+	"flag.timeframes":         "多周期共振分析的周期，如 15m,1h,4h,1d",
+	"flag.indicator":          "额外计算的指标规格，可重复，如 --indicator 'rsi(period=9)'",
+	"flag.list_indicators":    "列出可用指标及参数",
+	"flag.export_indicators":  "按规格导出指标序列到CSV，逗号分隔，如 --export-indicators 'rsi(period=9),ema(period=21)'",
+	"flag.expr":               "自定义指标表达式，可重复，如 --expr 'ema(close,20) > ema(close,50) and rsi(14) < 40'",
+
+	"flag.symbol":              "交易对",
+	"flag.interval":            "K线时间间隔",
+	"flag.days":                "回测天数",
+	"flag.capital":             "初始资金",
+	"flag.entry":               "入场阈值",
+	"flag.exit":                "出场阈值",
+	"flag.long":                "做多阈值",
+	"flag.short":               "做空阈值",
+	"flag.close":               "平仓阈值",
+	"flag.stoploss":            "止损百分比",
+	"flag.takeprofit":          "止盈百分比",
+	"flag.strategy":            "策略类型: simple|trend|momentum|reversal|combo",
+	"flag.trend_avwap_stop":    "趋势策略使用前低锚定VWAP止损",
+	"flag.rsi_spec":            "均值回归策略使用的RSI规格，如 rsi(period=9)",
+	"flag.enable_short":        "启用做空",
+	"flag.improved":            "使用改进的策略",
+	"flag.improved_avwap_stop": "改进策略使用锚定VWAP收紧止损",
+	"flag.stop_mode":           "改进策略的跟踪止损方式: atr|supertrend|psar",
+	"flag.st_period":           "SuperTrend ATR周期",
+	"flag.st_mult":             "SuperTrend ATR倍数",
+	"flag.sar_step":            "抛物线SAR加速因子步长",
+	"flag.sar_max":             "抛物线SAR最大加速因子",
+	"flag.transform":           "分析使用的K线变换: heikin-ashi|renko（成交仍按真实价格）",
+	"flag.rs_universe":         "相对强度观察列表，如 ETHUSDT,SOLUSDT（基准BTCUSDT自动加入）",
+	"flag.min_rs":              "开仓要求的相对强度百分位（做空要求不高于100-该值），0表示不过滤",
+
+	"flag.yahoo":        "使用Yahoo Finance数据源",
+	"flag.brick_size":   "Renko固定砖块大小，0表示使用ATR砖块",
+	"flag.brick_atr":    "Renko ATR砖块的ATR周期",
+	"flag.rs_lookbacks": "相对强度周期及综合评分权重",
+	"flag.config":       "覆盖配置文件（格式同configs/default.yaml）",
+	"flag.config_dir":   "配置目录（默认configs，不存在时使用内置默认值）",
+	"flag.profile":      "灵敏度配置: sensitive|balanced|stable",
+	"flag.rules":        "自定义证据规则文件，可重复，同id覆盖默认规则（格式同pkg/analysis/evidence_rules.yaml）",
+	"flag.lang":         "输出语言 zh-CN|en-US（默认取系统语言环境）",
+}
@@ -2,51 +2,103 @@ package indicators
 
 import (
 	"math"
+
+	"github.com/zjc/go-crypto-analyzer/pkg/types"
 )
 
-// StochasticRSI 计算随机RSI
-func (ti *TechnicalIndicators) StochasticRSI(data []float64, rsiPeriod, stochPeriod, k, d int) (float64, float64) {
-	if len(data) < rsiPeriod+stochPeriod {
-		return 50.0, 50.0
+// StochasticRSISeries 计算随机RSI的%K和%D序列
+// 原始StochRSI为RSI在stochPeriod期内的相对位置，%K为其k期SMA，%D为%K的d期SMA。
+// 预热期内的值为50
+func (ti *TechnicalIndicators) StochasticRSISeries(data []float64, rsiPeriod, stochPeriod, k, d int) (kLine, dLine []float64) {
+	kLine = make([]float64, len(data))
+	dLine = make([]float64, len(data))
+	for i := range data {
+		kLine[i] = 50.0
+		dLine[i] = 50.0
+	}
+	if k < 1 {
+		k = 1
+	}
+	if d < 1 {
+		d = 1
+	}
+	
+	// 原始StochRSI从rsiPeriod+stochPeriod-1开始有效
+	rawStart := rsiPeriod + stochPeriod - 1
+	if len(data) < rawStart+k+d-1 {
+		return kLine, dLine
+	}
+	
+	rsiValues := ti.RSISeries(data, rsiPeriod)
+	raw := make([]float64, 0, len(data)-rawStart)
+	for i := rawStart; i < len(data); i++ {
+		minRSI, maxRSI := rsiValues[i], rsiValues[i]
+		for j := i - stochPeriod + 1; j <= i; j++ {
+			minRSI = math.Min(minRSI, rsiValues[j])
+			maxRSI = math.Max(maxRSI, rsiValues[j])
+		}
+		
+		if maxRSI-minRSI != 0 {
+			raw = append(raw, (rsiValues[i]-minRSI)/(maxRSI-minRSI)*100)
+		} else {
+			raw = append(raw, 50.0)
+		}
 	}
 	
-	// 先计算RSI序列
-	rsiValues := make([]float64, 0)
-	for i := rsiPeriod; i <= len(data); i++ {
-		rsi := ti.RSI(data[:i], rsiPeriod)
-		rsiValues = append(rsiValues, rsi)
+	// %K平滑
+	smoothK := ti.SMA(raw, k)
+	for i := k - 1; i < len(raw); i++ {
+		kLine[rawStart+i] = smoothK[i]
 	}
 	
-	if len(rsiValues) < stochPeriod {
-		return 50.0, 50.0
+	// %D平滑
+	smoothD := ti.SMA(smoothK[k-1:], d)
+	for i := d - 1; i < len(smoothD); i++ {
+		dLine[rawStart+k-1+i] = smoothD[i]
 	}
 	
-	// 计算最近stochPeriod期的RSI最高和最低值
-	startIdx := len(rsiValues) - stochPeriod
-	minRSI := rsiValues[startIdx]
-	maxRSI := rsiValues[startIdx]
-	
-	for i := startIdx; i < len(rsiValues); i++ {
-		if rsiValues[i] < minRSI {
-			minRSI = rsiValues[i]
-		}
-		if rsiValues[i] > maxRSI {
-			maxRSI = rsiValues[i]
-		}
+	return kLine, dLine
+}
+
+// StochasticRSI 计算随机RSI，返回最新的%K和%D
+func (ti *TechnicalIndicators) StochasticRSI(data []float64, rsiPeriod, stochPeriod, k, d int) (float64, float64) {
+	kLine, dLine := ti.StochasticRSISeries(data, rsiPeriod, stochPeriod, k, d)
+	if len(kLine) == 0 {
+		return 50.0, 50.0
 	}
 	
-	currentRSI := rsiValues[len(rsiValues)-1]
-	
-	// 计算StochRSI
-	var stochRSI float64
-	if maxRSI-minRSI != 0 {
-		stochRSI = ((currentRSI - minRSI) / (maxRSI - minRSI)) * 100
-	} else {
-		stochRSI = 50.0
+	return kLine[len(kLine)-1], dLine[len(dLine)-1]
+}
+
+// StochCross 随机指标K/D交叉事件
+type StochCross struct {
+	Index     int
+	Direction int                 // 1: K上穿D（金叉），-1: K下穿D（死叉）
+	Zone      types.MomentumState // 超买或超卖
+	K         float64
+	D         float64
+}
+
+// StochCrossEvents 找出发生在超买/超卖区域内的K/D交叉
+// 金叉要求交叉前一根K线已进入超卖区，死叉要求已进入超买区；start之前的数据视为预热期
+func StochCrossEvents(kLine, dLine []float64, start int, overbought, oversold float64) []StochCross {
+	events := make([]StochCross, 0)
+	if start < 1 {
+		start = 1
+	}
+	
+	for i := start; i < len(kLine) && i < len(dLine); i++ {
+		crossUp := kLine[i-1] <= dLine[i-1] && kLine[i] > dLine[i]
+		crossDown := kLine[i-1] >= dLine[i-1] && kLine[i] < dLine[i]
+		
+		if crossUp && math.Min(kLine[i-1], dLine[i-1]) < oversold {
+			events = append(events, StochCross{Index: i, Direction: 1, Zone: types.MomentumOversold, K: kLine[i], D: dLine[i]})
+		} else if crossDown && math.Max(kLine[i-1], dLine[i-1]) > overbought {
+			events = append(events, StochCross{Index: i, Direction: -1, Zone: types.MomentumOverbought, K: kLine[i], D: dLine[i]})
+		}
 	}
 	
-	// 简单返回K值，D值需要更多历史数据
-	return stochRSI, stochRSI
+	return events
 }
 
 // WilliamsR 威廉指标
@@ -103,32 +155,11 @@ func (ti *TechnicalIndicators) OBV(close, volume []float64) []float64 {
 
 // ATR 平均真实波幅
 func (ti *TechnicalIndicators) ATR(high, low, close []float64, period int) float64 {
-	if len(high) < period+1 || len(low) < period+1 || len(close) < period+1 {
+	atr := ti.ATRSeries(high, low, close, period)
+	if len(atr) == 0 {
 		return 0.0
 	}
-	
-	// 计算真实波幅
-	tr := make([]float64, len(high))
-	for i := 1; i < len(high); i++ {
-		hl := high[i] - low[i]
-		hc := math.Abs(high[i] - close[i-1])
-		lc := math.Abs(low[i] - close[i-1])
-		tr[i] = math.Max(hl, math.Max(hc, lc))
-	}
-	
-	// 计算ATR
-	sum := 0.0
-	for i := 1; i <= period; i++ {
-		sum += tr[i]
-	}
-	atr := sum / float64(period)
-	
-	// 平滑计算
-	for i := period + 1; i < len(tr); i++ {
-		atr = (atr*float64(period-1) + tr[i]) / float64(period)
-	}
-	
-	return atr
+	return atr[len(atr)-1]
 }
 
 // CCI 商品通道指数
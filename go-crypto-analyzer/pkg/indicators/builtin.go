@@ -0,0 +1,202 @@
+package indicators
+
+// 内置指标注册：名称、参数默认值与现有计算函数一致
+func init() {
+	ti := NewTechnicalIndicators()
+	period := func(def float64) ParamSpec {
+		return ParamSpec{Name: "period", Default: def, Min: 1, Integer: true, Description: "周期"}
+	}
+	fixed := func(n int) func(Params) int {
+		return func(Params) int { return n }
+	}
+	ints := func(values []int) []float64 {
+		out := make([]float64, len(values))
+		for i, v := range values {
+			out[i] = float64(v)
+		}
+		return out
+	}
+
+	MustRegister(Definition{
+		Name:        "sma",
+		Description: "简单移动平均",
+		Params:      []ParamSpec{period(20)},
+		Inputs:      []Input{InputSource},
+		Outputs:     []string{"sma"},
+		WarmUp:      func(p Params) int { return p.Int("period") - 1 },
+		Compute: func(in Inputs, p Params) [][]float64 {
+			return [][]float64{ti.SMA(in[InputSource], p.Int("period"))}
+		},
+	})
+	MustRegister(Definition{
+		Name:        "ema",
+		Description: "指数移动平均",
+		Params:      []ParamSpec{period(20)},
+		Inputs:      []Input{InputSource},
+		Outputs:     []string{"ema"},
+		WarmUp:      func(p Params) int { return p.Int("period") - 1 },
+		Compute: func(in Inputs, p Params) [][]float64 {
+			return [][]float64{ti.EMA(in[InputSource], p.Int("period"))}
+		},
+	})
+	MustRegister(Definition{
+		Name:        "rsi",
+		Description: "相对强弱指数（Wilder平滑）",
+		Params:      []ParamSpec{period(14)},
+		Inputs:      []Input{InputSource},
+		Outputs:     []string{"rsi"},
+		WarmUp:      func(p Params) int { return p.Int("period") },
+		Compute: func(in Inputs, p Params) [][]float64 {
+			return [][]float64{ti.RSISeries(in[InputSource], p.Int("period"))}
+		},
+	})
+	MustRegister(Definition{
+		Name:        "macd",
+		Description: "MACD指标",
+		Params: []ParamSpec{
+			{Name: "fast", Default: 12, Min: 1, Integer: true, Description: "快线周期"},
+			{Name: "slow", Default: 26, Min: 1, Integer: true, Description: "慢线周期"},
+			{Name: "signal", Default: 9, Min: 1, Integer: true, Description: "信号线周期"},
+		},
+		Inputs:  []Input{InputSource},
+		Outputs: []string{"macd", "signal", "histogram"},
+		WarmUp:  func(p Params) int { return p.Int("slow") + p.Int("signal") - 2 },
+		Compute: func(in Inputs, p Params) [][]float64 {
+			macd, signal, histogram := ti.MACDSeries(in[InputSource], p.Int("fast"), p.Int("slow"), p.Int("signal"))
+			return [][]float64{macd, signal, histogram}
+		},
+	})
+	MustRegister(Definition{
+		Name:        "bbands",
+		Description: "布林带及%B、带宽",
+		Params: []ParamSpec{
+			period(20),
+			{Name: "stddev", Default: 2, Min: 0, Description: "标准差倍数"},
+		},
+		Inputs:  []Input{InputSource},
+		Outputs: []string{"middle", "upper", "lower", "percent_b", "bandwidth"},
+		WarmUp:  func(p Params) int { return p.Int("period") - 1 },
+		Compute: func(in Inputs, p Params) [][]float64 {
+			upper, middle, lower := ti.BollingerBands(in[InputSource], p.Int("period"), p["stddev"])
+			percentB, bandwidth := ti.BollingerStats(in[InputSource], p.Int("period"), p["stddev"])
+			return [][]float64{middle, upper, lower, percentB, bandwidth}
+		},
+	})
+	MustRegister(Definition{
+		Name:        "atr",
+		Description: "平均真实波幅（Wilder平滑）",
+		Params:      []ParamSpec{period(14)},
+		Inputs:      []Input{InputHigh, InputLow, InputClose},
+		Outputs:     []string{"atr"},
+		WarmUp:      func(p Params) int { return p.Int("period") },
+		Compute: func(in Inputs, p Params) [][]float64 {
+			return [][]float64{ti.ATRSeries(in[InputHigh], in[InputLow], in[InputClose], p.Int("period"))}
+		},
+	})
+	MustRegister(Definition{
+		Name:        "dmi",
+		Description: "趋向指标（ADX、+DI、-DI）",
+		Params:      []ParamSpec{period(14)},
+		Inputs:      []Input{InputHigh, InputLow, InputClose},
+		Outputs:     []string{"adx", "plus_di", "minus_di"},
+		WarmUp:      func(p Params) int { return 2*p.Int("period") - 1 },
+		Compute: func(in Inputs, p Params) [][]float64 {
+			dmi := ti.DMI(in[InputHigh], in[InputLow], in[InputClose], p.Int("period"))
+			return [][]float64{dmi.ADX, dmi.PlusDI, dmi.MinusDI}
+		},
+	})
+	MustRegister(Definition{
+		Name:        "stochrsi",
+		Description: "随机RSI（平滑%K、%D）",
+		Params: []ParamSpec{
+			{Name: "rsi", Default: 14, Min: 1, Integer: true, Description: "RSI周期"},
+			{Name: "stoch", Default: 14, Min: 1, Integer: true, Description: "随机周期"},
+			{Name: "k", Default: 3, Min: 1, Integer: true, Description: "%K平滑"},
+			{Name: "d", Default: 3, Min: 1, Integer: true, Description: "%D平滑"},
+		},
+		Inputs:  []Input{InputSource},
+		Outputs: []string{"k", "d"},
+		WarmUp: func(p Params) int {
+			return p.Int("rsi") + p.Int("stoch") + p.Int("k") + p.Int("d") - 3
+		},
+		Compute: func(in Inputs, p Params) [][]float64 {
+			k, d := ti.StochasticRSISeries(in[InputSource], p.Int("rsi"), p.Int("stoch"), p.Int("k"), p.Int("d"))
+			return [][]float64{k, d}
+		},
+	})
+	MustRegister(Definition{
+		Name:        "obv",
+		Description: "能量潮",
+		Inputs:      []Input{InputClose, InputVolume},
+		Outputs:     []string{"obv"},
+		WarmUp:      fixed(0),
+		Compute: func(in Inputs, p Params) [][]float64 {
+			return [][]float64{ti.OBV(in[InputClose], in[InputVolume])}
+		},
+	})
+	MustRegister(Definition{
+		Name:        "supertrend",
+		Description: "SuperTrend跟踪止损",
+		Params: []ParamSpec{
+			period(10),
+			{Name: "multiplier", Default: 3, Min: 0, Description: "ATR倍数"},
+		},
+		Inputs:  []Input{InputHigh, InputLow, InputClose},
+		Outputs: []string{"level", "direction"},
+		WarmUp:  func(p Params) int { return p.Int("period") },
+		Compute: func(in Inputs, p Params) [][]float64 {
+			st := ti.SuperTrend(in[InputHigh], in[InputLow], in[InputClose], p.Int("period"), p["multiplier"])
+			// direction: 1多头，-1空头
+			return [][]float64{st.Level, ints(st.Direction)}
+		},
+	})
+	MustRegister(Definition{
+		Name:        "psar",
+		Description: "抛物线SAR",
+		Params: []ParamSpec{
+			{Name: "step", Default: 0.02, Min: 0, Description: "加速因子步长"},
+			{Name: "max", Default: 0.2, Min: 0, Description: "加速因子上限"},
+		},
+		Inputs:  []Input{InputHigh, InputLow},
+		Outputs: []string{"level", "direction"},
+		WarmUp:  fixed(1),
+		Compute: func(in Inputs, p Params) [][]float64 {
+			sar := ti.ParabolicSAR(in[InputHigh], in[InputLow], p["step"], p["max"])
+			// direction: 1多头，-1空头
+			return [][]float64{sar.Level, ints(sar.Direction)}
+		},
+	})
+	MustRegister(Definition{
+		Name:        "keltner",
+		Description: "肯特纳通道",
+		Params: []ParamSpec{
+			period(20),
+			{Name: "atr_period", Default: 10, Min: 1, Integer: true, Description: "ATR周期"},
+			{Name: "multiplier", Default: 1.5, Min: 0, Description: "ATR倍数"},
+		},
+		Inputs:  []Input{InputHigh, InputLow, InputClose},
+		Outputs: []string{"middle", "upper", "lower"},
+		WarmUp: func(p Params) int {
+			if p.Int("atr_period") > p.Int("period")-1 {
+				return p.Int("atr_period")
+			}
+			return p.Int("period") - 1
+		},
+		Compute: func(in Inputs, p Params) [][]float64 {
+			upper, middle, lower := ti.KeltnerChannels(in[InputHigh], in[InputLow], in[InputClose], p.Int("period"), p.Int("atr_period"), p["multiplier"])
+			return [][]float64{middle, upper, lower}
+		},
+	})
+	MustRegister(Definition{
+		Name:        "donchian",
+		Description: "唐奇安通道",
+		Params:      []ParamSpec{period(20)},
+		Inputs:      []Input{InputHigh, InputLow},
+		Outputs:     []string{"middle", "upper", "lower"},
+		WarmUp:      func(p Params) int { return p.Int("period") - 1 },
+		Compute: func(in Inputs, p Params) [][]float64 {
+			upper, middle, lower := ti.DonchianChannels(in[InputHigh], in[InputLow], p.Int("period"))
+			return [][]float64{middle, upper, lower}
+		},
+	})
+}
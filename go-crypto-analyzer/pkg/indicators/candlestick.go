@@ -0,0 +1,173 @@
+package indicators
+
+import (
+	"math"
+
+	"github.com/zjc/go-crypto-analyzer/pkg/types"
+)
+
+// K线形态识别参数
+const (
+	candleAverageBars  = 10  // 计算平均实体/振幅的K线数
+	candleTrendBars    = 6   // 判断形态前趋势的K线数
+	candleTrendRanges  = 1.5 // 趋势成立所需的收盘价变化（以平均振幅计）
+	dojiBodyRatio      = 0.1 // 十字星实体占振幅的最大比例
+	smallBodyRatio     = 0.3 // 小实体占平均振幅的最大比例
+	longShadowMultiple = 2.0 // 长影线相对实体的最小倍数
+)
+
+// candle 单根K线的形态度量
+type candle struct {
+	open, high, low, close float64
+}
+
+func (c candle) body() float64        { return math.Abs(c.close - c.open) }
+func (c candle) rng() float64         { return c.high - c.low }
+func (c candle) upperShadow() float64 { return c.high - math.Max(c.open, c.close) }
+func (c candle) lowerShadow() float64 { return math.Min(c.open, c.close) - c.low }
+func (c candle) bullish() bool        { return c.close > c.open }
+func (c candle) bearish() bool        { return c.close < c.open }
+func (c candle) bodyTop() float64     { return math.Max(c.open, c.close) }
+func (c candle) bodyBottom() float64  { return math.Min(c.open, c.close) }
+func (c candle) midpoint() float64    { return (c.open + c.close) / 2 }
+
+// DetectCandlePatterns 识别从start开始（含）每根K线收盘时完成的K线形态
+// 每个形态带有可靠性权重和所需的前置趋势，ContextOK表示前置趋势是否满足
+func (ti *TechnicalIndicators) DetectCandlePatterns(data []types.OHLCV, start int) []types.CandlePattern {
+	if start < candleAverageBars {
+		start = candleAverageBars
+	}
+
+	candles := make([]candle, len(data))
+	for i, d := range data {
+		candles[i] = candle{d.Open, d.High, d.Low, d.Close}
+	}
+
+	var patterns []types.CandlePattern
+	for i := start; i < len(data); i++ {
+		avgBody, avgRange := 0.0, 0.0
+		for j := i - candleAverageBars; j < i; j++ {
+			avgBody += candles[j].body()
+			avgRange += candles[j].rng()
+		}
+		avgBody /= candleAverageBars
+		avgRange /= candleAverageBars
+		if avgRange == 0 {
+			continue
+		}
+
+		add := func(name types.CandlePatternType, bars, direction int, reliability float64, requiredTrend int) {
+			trend := priorTrend(candles, i-bars+1, avgRange)
+			patterns = append(patterns, types.CandlePattern{
+				Name:          name,
+				Direction:     direction,
+				Candles:       bars,
+				Index:         i,
+				BarsAgo:       len(data) - 1 - i,
+				Time:          data[i].Time,
+				Reliability:   reliability,
+				RequiredTrend: requiredTrend,
+				ContextOK:     requiredTrend == 0 || trend == requiredTrend,
+			})
+		}
+
+		c0, c1, c2 := candles[i], candles[i-1], candles[i-2]
+		longBody := func(c candle) bool { return c.body() >= avgBody && c.body() >= 0.5*c.rng() }
+		smallBody := func(c candle) bool { return c.body() <= smallBodyRatio*avgRange }
+
+		// 单根K线形态
+		if c0.rng() > 0 && c0.body() <= dojiBodyRatio*c0.rng() {
+			switch {
+			case c0.lowerShadow() <= 0.1*c0.rng() && c0.upperShadow() >= 0.6*c0.rng():
+				add(types.GravestoneDoji, 1, -1, 0.5, 1)
+			case c0.upperShadow() <= 0.1*c0.rng() && c0.lowerShadow() >= 0.6*c0.rng():
+				add(types.DragonflyDoji, 1, 1, 0.5, -1)
+			case c0.rng() >= 1.5*avgRange:
+				add(types.LongLeggedDoji, 1, 0, 0.35, 0)
+			default:
+				add(types.Doji, 1, 0, 0.3, 0)
+			}
+		} else if c0.body() > 0 {
+			if c0.lowerShadow() >= longShadowMultiple*c0.body() && c0.upperShadow() <= 0.1*c0.rng() {
+				add(types.Hammer, 1, 1, 0.6, -1)
+			}
+			if c0.upperShadow() >= longShadowMultiple*c0.body() && c0.lowerShadow() <= 0.1*c0.rng() {
+				add(types.ShootingStar, 1, -1, 0.6, 1)
+			}
+		}
+
+		// 两根K线形态
+		if c1.bearish() && c0.bullish() && c0.bodyBottom() <= c1.bodyBottom() && c0.bodyTop() >= c1.bodyTop() && c0.body() > c1.body() {
+			add(types.BullishEngulfing, 2, 1, 0.65, -1)
+		}
+		if c1.bullish() && c0.bearish() && c0.bodyBottom() <= c1.bodyBottom() && c0.bodyTop() >= c1.bodyTop() && c0.body() > c1.body() {
+			add(types.BearishEngulfing, 2, -1, 0.65, 1)
+		}
+		if longBody(c1) && c0.body() < 0.5*c1.body() && c0.bodyTop() < c1.bodyTop() && c0.bodyBottom() > c1.bodyBottom() {
+			if c1.bearish() {
+				add(types.BullishHarami, 2, 1, 0.55, -1)
+			} else {
+				add(types.BearishHarami, 2, -1, 0.55, 1)
+			}
+		}
+		if longBody(c1) && c1.bearish() && c0.bullish() && c0.open <= c1.close && c0.close > c1.midpoint() && c0.close < c1.open {
+			add(types.PiercingLine, 2, 1, 0.6, -1)
+		}
+		if longBody(c1) && c1.bullish() && c0.bearish() && c0.open >= c1.close && c0.close < c1.midpoint() && c0.close > c1.open {
+			add(types.DarkCloudCover, 2, -1, 0.6, 1)
+		}
+		if c0.high < c1.high && c0.low > c1.low {
+			add(types.InsideBar, 2, 0, 0.3, 0)
+		}
+		if c0.high > c1.high && c0.low < c1.low {
+			direction := 0
+			if c0.bullish() {
+				direction = 1
+			} else if c0.bearish() {
+				direction = -1
+			}
+			add(types.OutsideBar, 2, direction, 0.35, 0)
+		}
+
+		// 三根K线形态
+		if longBody(c2) && smallBody(c1) && longBody(c0) {
+			if c2.bearish() && c0.bullish() && c1.bodyTop() < c2.midpoint() && c0.close > c2.midpoint() {
+				add(types.MorningStar, 3, 1, 0.75, -1)
+			}
+			if c2.bullish() && c0.bearish() && c1.bodyBottom() > c2.midpoint() && c0.close < c2.midpoint() {
+				add(types.EveningStar, 3, -1, 0.75, 1)
+			}
+		}
+		if longBody(c2) && longBody(c1) && longBody(c0) {
+			if c2.bullish() && c1.bullish() && c0.bullish() &&
+				c1.close > c2.close && c0.close > c1.close &&
+				c1.open >= c2.open && c1.open <= c2.close && c0.open >= c1.open && c0.open <= c1.close {
+				add(types.ThreeWhiteSoldiers, 3, 1, 0.7, 0)
+			}
+			if c2.bearish() && c1.bearish() && c0.bearish() &&
+				c1.close < c2.close && c0.close < c1.close &&
+				c1.open <= c2.open && c1.open >= c2.close && c0.open <= c1.open && c0.open >= c1.close {
+				add(types.ThreeBlackCrows, 3, -1, 0.7, 0)
+			}
+		}
+	}
+
+	return patterns
+}
+
+// priorTrend 判断形态第一根K线之前的趋势：1上涨，-1下跌，0无明显趋势
+func priorTrend(candles []candle, first int, avgRange float64) int {
+	end := first - 1
+	begin := end - candleTrendBars
+	if begin < 0 {
+		return 0
+	}
+
+	move := candles[end].close - candles[begin].close
+	if move > candleTrendRanges*avgRange {
+		return 1
+	} else if move < -candleTrendRanges*avgRange {
+		return -1
+	}
+	return 0
+}
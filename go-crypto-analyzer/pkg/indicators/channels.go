@@ -0,0 +1,153 @@
+package indicators
+
+import "math"
+
+// KeltnerChannels 计算肯特纳通道：中轨为EMA(period)，上下轨为中轨±multiplier*ATR(atrPeriod)
+func (ti *TechnicalIndicators) KeltnerChannels(high, low, close []float64, period, atrPeriod int, multiplier float64) (upper, middle, lower []float64) {
+	middle = ti.EMA(close, period)
+	atr := ti.ATRSeries(high, low, close, atrPeriod)
+	upper = make([]float64, len(close))
+	lower = make([]float64, len(close))
+
+	start := period - 1
+	if atrPeriod > start {
+		start = atrPeriod
+	}
+	for i := start; i < len(close); i++ {
+		upper[i] = middle[i] + multiplier*atr[i]
+		lower[i] = middle[i] - multiplier*atr[i]
+	}
+
+	return upper, middle, lower
+}
+
+// DonchianChannels 计算唐奇安通道：period根K线（含当前）的最高价、最低价及其中点
+func (ti *TechnicalIndicators) DonchianChannels(high, low []float64, period int) (upper, middle, lower []float64) {
+	n := len(high)
+	upper = make([]float64, n)
+	middle = make([]float64, n)
+	lower = make([]float64, n)
+
+	for i := period - 1; i < n; i++ {
+		hh, ll := high[i], low[i]
+		for j := i - period + 1; j < i; j++ {
+			hh = math.Max(hh, high[j])
+			ll = math.Min(ll, low[j])
+		}
+		upper[i], lower[i] = hh, ll
+		middle[i] = (hh + ll) / 2
+	}
+
+	return upper, middle, lower
+}
+
+// BollingerStats 计算布林带%B（价格在带内的相对位置）和带宽（上下轨距离占中轨的百分比）
+func (ti *TechnicalIndicators) BollingerStats(data []float64, period int, stdDev float64) (percentB, bandwidth []float64) {
+	upper, middle, lower := ti.BollingerBands(data, period, stdDev)
+	percentB = make([]float64, len(data))
+	bandwidth = make([]float64, len(data))
+
+	for i := period - 1; i < len(data); i++ {
+		width := upper[i] - lower[i]
+		if width > 0 {
+			percentB[i] = (data[i] - lower[i]) / width
+		} else {
+			percentB[i] = 0.5
+		}
+		if middle[i] != 0 {
+			bandwidth[i] = width / middle[i] * 100
+		}
+	}
+
+	return percentB, bandwidth
+}
+
+// SqueezeResult TTM挤压序列
+// On表示布林带完全收缩在肯特纳通道内，Momentum为挤压动量（线性回归平滑）
+type SqueezeResult struct {
+	On       []bool
+	Momentum []float64
+}
+
+// SqueezeRelease 挤压释放事件
+type SqueezeRelease struct {
+	Index     int
+	Direction int // 1 向上释放, -1 向下释放
+	Duration  int // 释放前连续挤压的K线数
+}
+
+// TTMSqueeze 计算TTM挤压：布林带(period, bbStdDev)位于肯特纳通道(period, kcMultiplier*ATR)内即为挤压，
+// 动量为收盘价减去唐奇安中点与SMA均值后的线性回归值
+func (ti *TechnicalIndicators) TTMSqueeze(high, low, close []float64, period int, bbStdDev, kcMultiplier float64) SqueezeResult {
+	n := len(close)
+	result := SqueezeResult{
+		On:       make([]bool, n),
+		Momentum: make([]float64, n),
+	}
+	if n < period+1 {
+		return result
+	}
+
+	bbUpper, _, bbLower := ti.BollingerBands(close, period, bbStdDev)
+	kcUpper, _, kcLower := ti.KeltnerChannels(high, low, close, period, period, kcMultiplier)
+	_, dcMiddle, _ := ti.DonchianChannels(high, low, period)
+	sma := ti.SMA(close, period)
+
+	delta := make([]float64, n)
+	for i := period; i < n; i++ {
+		result.On[i] = bbUpper[i] < kcUpper[i] && bbLower[i] > kcLower[i]
+		delta[i] = close[i] - (dcMiddle[i]+sma[i])/2
+	}
+	for i := 2*period - 1; i < n; i++ {
+		result.Momentum[i] = linearRegressionEnd(delta[i-period+1 : i+1])
+	}
+
+	return result
+}
+
+// Releases 返回从start开始的挤压释放事件，方向取释放时动量的符号
+func (r SqueezeResult) Releases(start int) []SqueezeRelease {
+	var releases []SqueezeRelease
+	if start < 1 {
+		start = 1
+	}
+	for i := start; i < len(r.On); i++ {
+		if !r.On[i-1] || r.On[i] {
+			continue
+		}
+		duration := 0
+		for j := i - 1; j >= 0 && r.On[j]; j-- {
+			duration++
+		}
+		direction := 1
+		if r.Momentum[i] < 0 {
+			direction = -1
+		}
+		releases = append(releases, SqueezeRelease{Index: i, Direction: direction, Duration: duration})
+	}
+	return releases
+}
+
+// linearRegressionEnd 最小二乘拟合后在最后一个点的取值
+func linearRegressionEnd(values []float64) float64 {
+	n := float64(len(values))
+	if n == 0 {
+		return 0
+	}
+
+	var sumX, sumY, sumXY, sumXX float64
+	for i, v := range values {
+		x := float64(i)
+		sumX += x
+		sumY += v
+		sumXY += x * v
+		sumXX += x * x
+	}
+	denom := n*sumXX - sumX*sumX
+	if denom == 0 {
+		return sumY / n
+	}
+	slope := (n*sumXY - sumX*sumY) / denom
+	intercept := (sumY - slope*sumX) / n
+	return intercept + slope*(n-1)
+}
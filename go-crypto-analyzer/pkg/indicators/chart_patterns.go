@@ -0,0 +1,425 @@
+package indicators
+
+import (
+	"math"
+
+	"github.com/zjc/go-crypto-analyzer/pkg/types"
+)
+
+// 图表形态识别参数
+const (
+	chartSwingStrength    = 3      // 摆动点左右确认K线数
+	chartLookback         = 80     // 只识别最近80根K线内的形态
+	chartTriangleLookback = 50     // 三角形使用的K线数
+	chartLevelTolerance   = 0.015  // 双顶/三顶等水平价位的容差
+	chartMinHeight        = 0.01   // 形态最小高度（占价格比例）
+	chartFlatSlope        = 0.0004 // 水平趋势线的最大斜率（每根K线占价格比例）
+	chartMinSpan          = 5      // 相邻峰之间的最小K线数
+	chartBreakoutExpiry   = 10     // 突破超过该K线数后不再报告
+)
+
+// chartView 价格视图：sign为-1时将价格取反，使顶部形态的识别逻辑可直接用于底部形态，
+// 看涨旗形的识别逻辑可直接用于看跌旗形
+type chartView struct {
+	sign                float64
+	highs, lows, closes []float64
+}
+
+func newChartView(highs, lows, closes []float64, sign float64) chartView {
+	if sign > 0 {
+		return chartView{sign: 1, highs: highs, lows: lows, closes: closes}
+	}
+	negate := func(values []float64) []float64 {
+		out := make([]float64, len(values))
+		for i, v := range values {
+			out[i] = -v
+		}
+		return out
+	}
+	return chartView{sign: -1, highs: negate(lows), lows: negate(highs), closes: negate(closes)}
+}
+
+// price 将视图中的价位还原为实际价格
+func (v chartView) price(x float64) float64 {
+	return v.sign * x
+}
+
+// firstCloseBeyond 返回from之后第一根收盘价越过level(i)的K线索引（above为true时向上），没有时返回-1
+func (v chartView) firstCloseBeyond(from int, level func(i int) float64, above bool) int {
+	for i := from; i < len(v.closes); i++ {
+		if (above && v.closes[i] > level(i)) || (!above && v.closes[i] < level(i)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// DetectChartPatterns 基于摆动点识别最近的图表形态：
+// 头肩顶/底、双重/三重顶底、上升/下降/对称三角形、旗形/三角旗
+func (ti *TechnicalIndicators) DetectChartPatterns(highs, lows, closes []float64) []types.ChartPattern {
+	n := len(closes)
+	if n < 30 {
+		return nil
+	}
+
+	var patterns []types.ChartPattern
+	appendIf := func(p types.ChartPattern, ok bool) {
+		if ok {
+			patterns = append(patterns, p)
+		}
+	}
+
+	for _, sign := range []float64{1, -1} {
+		view := newChartView(highs, lows, closes, sign)
+		appendIf(detectHeadAndShoulders(view))
+		appendIf(detectMultipleTop(view))
+	}
+	appendIf(detectTriangle(highs, lows, closes))
+	atr := ti.ATRSeries(highs, lows, closes, 14)
+	for _, sign := range []float64{1, -1} {
+		appendIf(detectFlag(newChartView(highs, lows, closes, sign), atr))
+	}
+
+	return patterns
+}
+
+// recentSwings 只保留start之后的摆动点
+func recentSwings(swings []SwingPoint, start int) []SwingPoint {
+	for i, s := range swings {
+		if s.Index >= start {
+			return swings[i:]
+		}
+	}
+	return nil
+}
+
+// lowestBetween 返回(from, to)区间内的最低值及其索引
+func lowestBetween(values []float64, from, to int) (float64, int) {
+	low, idx := math.Inf(1), -1
+	for i := from + 1; i < to; i++ {
+		if values[i] < low {
+			low, idx = values[i], i
+		}
+	}
+	return low, idx
+}
+
+// finishPattern 根据突破情况填充状态和置信度，突破过久的形态返回false
+func finishPattern(p types.ChartPattern, view chartView, breakout int) (types.ChartPattern, bool) {
+	n := len(view.closes)
+	p.BreakoutBarsAgo = -1
+	p.Status = types.PatternForming
+	if breakout >= 0 {
+		if n-1-breakout > chartBreakoutExpiry {
+			return p, false
+		}
+		p.BreakoutBarsAgo = n - 1 - breakout
+		p.Confidence += 0.15
+		if p.Direction > 0 {
+			p.Status = types.PatternBrokeOut
+		} else {
+			p.Status = types.PatternBrokeDown
+		}
+	}
+	p.Confidence = math.Min(p.Confidence, 0.95)
+	return p, true
+}
+
+// detectMultipleTop 识别双顶/三重顶（视图取反时为双底/三重底）
+func detectMultipleTop(view chartView) (types.ChartPattern, bool) {
+	n := len(view.closes)
+	peaks := recentSwings(FindSwingHighs(view.highs, chartSwingStrength), n-chartLookback)
+	if len(peaks) < 2 {
+		return types.ChartPattern{}, false
+	}
+
+	withinTolerance := func(group []SwingPoint) (mean, spread float64, ok bool) {
+		lo, hi := math.Inf(1), math.Inf(-1)
+		for i, p := range group {
+			mean += p.Value
+			lo, hi = math.Min(lo, p.Value), math.Max(hi, p.Value)
+			if i > 0 && p.Index-group[i-1].Index < chartMinSpan {
+				return 0, 0, false
+			}
+		}
+		mean /= float64(len(group))
+		spread = hi - lo
+		return mean, spread, spread <= chartLevelTolerance*math.Abs(mean)
+	}
+
+	group := peaks[len(peaks)-2:]
+	name, base := types.DoubleTop, 0.6
+	if len(peaks) >= 3 {
+		if _, _, ok := withinTolerance(peaks[len(peaks)-3:]); ok {
+			group = peaks[len(peaks)-3:]
+			name, base = types.TripleTop, 0.7
+		}
+	}
+	mean, spread, ok := withinTolerance(group)
+	if !ok {
+		return types.ChartPattern{}, false
+	}
+	if view.sign < 0 {
+		name = map[types.ChartPatternType]types.ChartPatternType{types.DoubleTop: types.DoubleBottom, types.TripleTop: types.TripleBottom}[name]
+	}
+
+	first, last := group[0], group[len(group)-1]
+	neckline, _ := lowestBetween(view.lows, first.Index, last.Index)
+	height := mean - neckline
+	if height < chartMinHeight*math.Abs(mean) {
+		return types.ChartPattern{}, false
+	}
+
+	// 突破之前收盘价越过顶部则形态失效
+	top := math.Max(first.Value, last.Value)
+	flat := func(level float64) func(int) float64 { return func(int) float64 { return level } }
+	breakout := view.firstCloseBeyond(last.Index+1, flat(neckline), false)
+	failed := view.firstCloseBeyond(last.Index+1, flat(top+chartLevelTolerance*math.Abs(top)), true)
+	if failed >= 0 && (breakout < 0 || failed < breakout) {
+		return types.ChartPattern{}, false
+	}
+
+	quality := 1 - spread/(chartLevelTolerance*math.Abs(mean))
+	p := types.ChartPattern{
+		Name:              name,
+		Direction:         -int(view.sign),
+		StartIndex:        first.Index,
+		EndIndex:          last.Index,
+		BreakoutLevel:     view.price(neckline),
+		InvalidationLevel: view.price(top),
+		Target:            view.price(neckline - height),
+		Confidence:        base * (0.5 + 0.5*quality),
+	}
+	return finishPattern(p, view, breakout)
+}
+
+// detectHeadAndShoulders 识别头肩顶（视图取反时为头肩底），颈线为两个肩-头间低点的连线
+func detectHeadAndShoulders(view chartView) (types.ChartPattern, bool) {
+	n := len(view.closes)
+	peaks := recentSwings(FindSwingHighs(view.highs, chartSwingStrength), n-chartLookback)
+	if len(peaks) < 3 {
+		return types.ChartPattern{}, false
+	}
+
+	left, head, right := peaks[len(peaks)-3], peaks[len(peaks)-2], peaks[len(peaks)-1]
+	if head.Index-left.Index < chartMinSpan || right.Index-head.Index < chartMinSpan {
+		return types.ChartPattern{}, false
+	}
+	shoulders := (left.Value + right.Value) / 2
+	if math.Abs(left.Value-right.Value) > 2*chartLevelTolerance*math.Abs(shoulders) {
+		return types.ChartPattern{}, false
+	}
+
+	low1, idx1 := lowestBetween(view.lows, left.Index, head.Index)
+	low2, idx2 := lowestBetween(view.lows, head.Index, right.Index)
+	if idx1 < 0 || idx2 < 0 || idx2 == idx1 {
+		return types.ChartPattern{}, false
+	}
+	neck := func(i int) float64 {
+		return low1 + (low2-low1)*float64(i-idx1)/float64(idx2-idx1)
+	}
+
+	height := head.Value - neck(head.Index)
+	if height < chartMinHeight*math.Abs(head.Value) || head.Value-math.Max(left.Value, right.Value) < 0.25*height {
+		return types.ChartPattern{}, false
+	}
+
+	breakout := view.firstCloseBeyond(right.Index+1, neck, false)
+	failed := view.firstCloseBeyond(right.Index+1, func(int) float64 { return head.Value }, true)
+	if failed >= 0 && (breakout < 0 || failed < breakout) {
+		return types.ChartPattern{}, false
+	}
+
+	name := types.HeadAndShoulders
+	if view.sign < 0 {
+		name = types.InverseHeadAndShoulders
+	}
+	symmetry := 1 - math.Abs(left.Value-right.Value)/(2*chartLevelTolerance*math.Abs(shoulders))
+	p := types.ChartPattern{
+		Name:              name,
+		Direction:         -int(view.sign),
+		StartIndex:        left.Index,
+		EndIndex:          right.Index,
+		BreakoutLevel:     view.price(neck(n - 1)),
+		InvalidationLevel: view.price(head.Value),
+		Target:            view.price(neck(n-1) - height),
+		Confidence:        0.75 * (0.6 + 0.4*symmetry),
+	}
+	return finishPattern(p, view, breakout)
+}
+
+// fitLine 对摆动点做最小二乘直线拟合
+func fitLine(points []SwingPoint) (slope, intercept float64) {
+	var sumX, sumY, sumXY, sumXX float64
+	n := float64(len(points))
+	for _, p := range points {
+		x := float64(p.Index)
+		sumX += x
+		sumY += p.Value
+		sumXY += x * p.Value
+		sumXX += x * x
+	}
+	denom := n*sumXX - sumX*sumX
+	if denom == 0 {
+		return 0, sumY / n
+	}
+	slope = (n*sumXY - sumX*sumY) / denom
+	intercept = (sumY - slope*sumX) / n
+	return slope, intercept
+}
+
+// detectTriangle 识别上升、下降和对称三角形
+func detectTriangle(highs, lows, closes []float64) (types.ChartPattern, bool) {
+	n := len(closes)
+	start := n - chartTriangleLookback
+	peaks := recentSwings(FindSwingHighs(highs, chartSwingStrength), start)
+	troughs := recentSwings(FindSwingLows(lows, chartSwingStrength), start)
+	if len(peaks) < 2 || len(troughs) < 2 {
+		return types.ChartPattern{}, false
+	}
+	if len(peaks) > 3 {
+		peaks = peaks[len(peaks)-3:]
+	}
+	if len(troughs) > 3 {
+		troughs = troughs[len(troughs)-3:]
+	}
+
+	upperSlope, upperIntercept := fitLine(peaks)
+	lowerSlope, lowerIntercept := fitLine(troughs)
+	upper := func(i int) float64 { return upperIntercept + upperSlope*float64(i) }
+	lower := func(i int) float64 { return lowerIntercept + lowerSlope*float64(i) }
+
+	first := int(math.Min(float64(peaks[0].Index), float64(troughs[0].Index)))
+	last := int(math.Max(float64(peaks[len(peaks)-1].Index), float64(troughs[len(troughs)-1].Index)))
+	if last-first < 2*chartMinSpan {
+		return types.ChartPattern{}, false
+	}
+
+	// 两条边需收敛且形态有足够高度
+	price := closes[n-1]
+	height := upper(first) - lower(first)
+	if height < chartMinHeight*price || upper(n-1)-lower(n-1) <= 0 || upper(n-1)-lower(n-1) > 0.8*height {
+		return types.ChartPattern{}, false
+	}
+
+	flat := chartFlatSlope * price
+	var name types.ChartPatternType
+	direction := 0
+	switch {
+	case math.Abs(upperSlope) <= flat && lowerSlope > flat:
+		name, direction = types.AscendingTriangle, 1
+	case upperSlope < -flat && math.Abs(lowerSlope) <= flat:
+		name, direction = types.DescendingTriangle, -1
+	case upperSlope < -flat && lowerSlope > flat:
+		name = types.SymmetricalTriangle
+	default:
+		return types.ChartPattern{}, false
+	}
+
+	view := newChartView(highs, lows, closes, 1)
+	up := view.firstCloseBeyond(last+1, upper, true)
+	down := view.firstCloseBeyond(last+1, lower, false)
+
+	// 上升/下降三角形反向突破视为失效，对称三角形以先突破的方向为准
+	breakout := -1
+	switch {
+	case up >= 0 && (down < 0 || up < down):
+		if direction < 0 {
+			return types.ChartPattern{}, false
+		}
+		breakout, direction = up, 1
+	case down >= 0:
+		if direction > 0 {
+			return types.ChartPattern{}, false
+		}
+		breakout, direction = down, -1
+	}
+
+	p := types.ChartPattern{
+		Name:       name,
+		Direction:  direction,
+		StartIndex: first,
+		EndIndex:   last,
+		Confidence: 0.5 + 0.05*float64(len(peaks)+len(troughs)-4),
+	}
+	if direction >= 0 {
+		p.BreakoutLevel, p.InvalidationLevel = upper(n-1), lower(n-1)
+		p.Target = upper(n-1) + height
+	} else {
+		p.BreakoutLevel, p.InvalidationLevel = lower(n-1), upper(n-1)
+		p.Target = lower(n-1) - height
+	}
+	return finishPattern(p, view, breakout)
+}
+
+// detectFlag 识别牛旗/牛市三角旗（视图取反时为熊旗/熊市三角旗）：
+// 不超过10根K线的急涨旗杆（至少4倍ATR），随后3-15根K线的整理，回撤不超过旗杆的一半
+func detectFlag(view chartView, atr []float64) (types.ChartPattern, bool) {
+	n := len(view.closes)
+	bestScore, poleStart, poleEnd := 0.0, -1, -1
+	for end := n - 16; end <= n-5; end++ {
+		if end < 1 || atr[end] == 0 {
+			continue
+		}
+		for length := 3; length <= 10 && end-length >= 0; length++ {
+			score := (view.closes[end] - view.closes[end-length]) / atr[end]
+			if score > bestScore {
+				bestScore, poleStart, poleEnd = score, end-length, end
+			}
+		}
+	}
+	if bestScore < 4 {
+		return types.ChartPattern{}, false
+	}
+
+	poleTop := math.Inf(-1)
+	for i := poleStart; i <= poleEnd; i++ {
+		poleTop = math.Max(poleTop, view.highs[i])
+	}
+	poleHeight := poleTop - view.lows[poleStart]
+
+	// 整理区间不含当前K线，当前K线用于判断突破
+	consHigh, consLow := math.Inf(-1), math.Inf(1)
+	for i := poleEnd + 1; i < n-1; i++ {
+		consHigh = math.Max(consHigh, view.highs[i])
+		consLow = math.Min(consLow, view.lows[i])
+	}
+	if poleTop-consLow > 0.5*poleHeight || consHigh > poleTop+0.25*poleHeight {
+		return types.ChartPattern{}, false
+	}
+	if view.closes[n-1] < consLow {
+		return types.ChartPattern{}, false
+	}
+
+	// 后半段波动明显收窄为三角旗，否则为旗形
+	mid := (poleEnd + n) / 2
+	rangeOf := func(from, to int) float64 {
+		hi, lo := math.Inf(-1), math.Inf(1)
+		for i := from; i < to; i++ {
+			hi, lo = math.Max(hi, view.highs[i]), math.Min(lo, view.lows[i])
+		}
+		return hi - lo
+	}
+	pennant := rangeOf(mid, n-1) < 0.6*rangeOf(poleEnd+1, mid)
+
+	name := map[bool]types.ChartPatternType{false: types.BullFlag, true: types.BullishPennant}[pennant]
+	if view.sign < 0 {
+		name = map[bool]types.ChartPatternType{false: types.BearFlag, true: types.BearishPennant}[pennant]
+	}
+
+	breakout := -1
+	if view.closes[n-1] > consHigh {
+		breakout = n - 1
+	}
+	p := types.ChartPattern{
+		Name:              name,
+		Direction:         int(view.sign),
+		StartIndex:        poleStart,
+		EndIndex:          n - 2,
+		BreakoutLevel:     view.price(consHigh),
+		InvalidationLevel: view.price(consLow),
+		Target:            view.price(consHigh + poleHeight),
+		Confidence:        0.5 + 0.05*math.Min(bestScore-4, 4),
+	}
+	return finishPattern(p, view, breakout)
+}
@@ -0,0 +1,145 @@
+package indicators
+
+import (
+	"math"
+
+	"github.com/zjc/go-crypto-analyzer/pkg/types"
+)
+
+// SwingPoint 摆动高低点
+type SwingPoint struct {
+	Index int
+	Value float64
+}
+
+// 两个摆动点之间的最小/最大间隔（K线数）
+const (
+	divergenceMinSpan = 5
+	divergenceMaxSpan = 60
+)
+
+// FindSwingHighs 寻找摆动高点：左右各strength根K线内的最高点
+func FindSwingHighs(data []float64, strength int) []SwingPoint {
+	return findSwings(data, strength, func(a, b float64) bool { return a > b })
+}
+
+// FindSwingLows 寻找摆动低点：左右各strength根K线内的最低点
+func FindSwingLows(data []float64, strength int) []SwingPoint {
+	return findSwings(data, strength, func(a, b float64) bool { return a < b })
+}
+
+func findSwings(data []float64, strength int, better func(a, b float64) bool) []SwingPoint {
+	swings := make([]SwingPoint, 0)
+	if strength < 1 {
+		strength = 1
+	}
+
+	for i := strength; i < len(data)-strength; i++ {
+		isSwing := true
+		for j := i - strength; j <= i+strength; j++ {
+			if j == i {
+				continue
+			}
+			// 左侧要求严格更优，右侧允许相等，避免平顶/平底重复计数
+			if (j < i && !better(data[i], data[j])) || (j > i && better(data[j], data[i])) {
+				isSwing = false
+				break
+			}
+		}
+		if isSwing {
+			swings = append(swings, SwingPoint{Index: i, Value: data[i]})
+		}
+	}
+
+	return swings
+}
+
+// DetectDivergences 检测价格与振荡指标之间的常规/隐藏背离
+// highs/lows为价格高低点序列，osc为振荡指标序列（MACD柱、RSI、OBV等），
+// strength为摆动点左右确认K线数，warmUp之前的指标值视为无效
+func (ti *TechnicalIndicators) DetectDivergences(indicator string, highs, lows, osc []float64, strength, warmUp int) []types.Divergence {
+	divergences := make([]types.Divergence, 0)
+	if len(highs) != len(osc) || len(lows) != len(osc) {
+		return divergences
+	}
+
+	// 高点：常规看跌（价格新高、指标未新高）和隐藏看跌（价格未新高、指标新高）
+	swingHighs := FindSwingHighs(highs, strength)
+	for i := 1; i < len(swingHighs); i++ {
+		prev, cur := swingHighs[i-1], swingHighs[i]
+		if !validDivergenceSpan(prev, cur, warmUp) {
+			continue
+		}
+		oscPrev, oscCur := osc[prev.Index], osc[cur.Index]
+
+		var divType types.DivergenceType
+		if cur.Value > prev.Value && oscCur < oscPrev {
+			divType = types.RegularBearishDivergence
+		} else if cur.Value < prev.Value && oscCur > oscPrev {
+			divType = types.HiddenBearishDivergence
+		} else {
+			continue
+		}
+		divergences = append(divergences, newDivergence(indicator, divType, prev, cur, osc, len(osc)))
+	}
+
+	// 低点：常规看涨（价格新低、指标未新低）和隐藏看涨（价格未新低、指标新低）
+	swingLows := FindSwingLows(lows, strength)
+	for i := 1; i < len(swingLows); i++ {
+		prev, cur := swingLows[i-1], swingLows[i]
+		if !validDivergenceSpan(prev, cur, warmUp) {
+			continue
+		}
+		oscPrev, oscCur := osc[prev.Index], osc[cur.Index]
+
+		var divType types.DivergenceType
+		if cur.Value < prev.Value && oscCur > oscPrev {
+			divType = types.RegularBullishDivergence
+		} else if cur.Value > prev.Value && oscCur < oscPrev {
+			divType = types.HiddenBullishDivergence
+		} else {
+			continue
+		}
+		divergences = append(divergences, newDivergence(indicator, divType, prev, cur, osc, len(osc)))
+	}
+
+	return divergences
+}
+
+func validDivergenceSpan(prev, cur SwingPoint, warmUp int) bool {
+	span := cur.Index - prev.Index
+	return prev.Index >= warmUp && span >= divergenceMinSpan && span <= divergenceMaxSpan
+}
+
+// newDivergence 构建背离记录并计算强度
+// 强度综合价格变化幅度（3%视为满分）和指标变化幅度（相对区间内指标最大绝对值）
+func newDivergence(indicator string, divType types.DivergenceType, prev, cur SwingPoint, osc []float64, n int) types.Divergence {
+	priceMove := 0.0
+	if prev.Value != 0 {
+		priceMove = math.Abs(cur.Value-prev.Value) / math.Abs(prev.Value)
+	}
+
+	oscScale := 0.0
+	for i := prev.Index; i <= cur.Index; i++ {
+		oscScale = math.Max(oscScale, math.Abs(osc[i]))
+	}
+	oscMove := 0.0
+	if oscScale > 0 {
+		oscMove = math.Abs(osc[cur.Index]-osc[prev.Index]) / oscScale
+	}
+
+	strength := 0.4*math.Min(priceMove/0.03, 1) + 0.6*math.Min(oscMove, 1)
+
+	return types.Divergence{
+		Indicator:      indicator,
+		Type:           divType,
+		StartIndex:     prev.Index,
+		EndIndex:       cur.Index,
+		BarsAgo:        n - 1 - cur.Index,
+		PriceStart:     prev.Value,
+		PriceEnd:       cur.Value,
+		IndicatorStart: osc[prev.Index],
+		IndicatorEnd:   osc[cur.Index],
+		Strength:       strength,
+	}
+}
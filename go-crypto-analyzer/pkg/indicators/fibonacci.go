@@ -0,0 +1,123 @@
+package indicators
+
+import (
+	"math"
+
+	"github.com/zjc/go-crypto-analyzer/pkg/types"
+)
+
+// 斐波那契回撤与扩展比例
+var (
+	FibRetracementRatios = []float64{0.236, 0.382, 0.5, 0.618, 0.786}
+	FibExtensionRatios   = []float64{1.272, 1.618}
+)
+
+// ZigZagPivot ZigZag转折点
+type ZigZagPivot struct {
+	Index  int
+	Price  float64
+	IsHigh bool
+}
+
+// ZigZag 以deviationPct（百分比）为反转阈值提取转折点
+// 价格从当前极值反向运动超过阈值时确认该极值为转折点；最后一个转折点为尚未确认的当前极值
+func (ti *TechnicalIndicators) ZigZag(highs, lows []float64, deviationPct float64) []ZigZagPivot {
+	pivots := make([]ZigZagPivot, 0)
+	if len(highs) == 0 || len(highs) != len(lows) {
+		return pivots
+	}
+
+	// 方向未定时同时跟踪最高点和最低点，先出现足够幅度反转的一侧决定初始方向
+	direction := 0
+	hi, lo := 0, 0
+	for i := 1; i < len(highs); i++ {
+		switch direction {
+		case 0:
+			if highs[i] > highs[hi] {
+				hi = i
+			}
+			if lows[i] < lows[lo] {
+				lo = i
+			}
+			if hi > lo && (highs[hi]-lows[lo])/lows[lo]*100 >= deviationPct {
+				pivots = append(pivots, ZigZagPivot{Index: lo, Price: lows[lo], IsHigh: false})
+				direction = 1
+			} else if lo > hi && (highs[hi]-lows[lo])/highs[hi]*100 >= deviationPct {
+				pivots = append(pivots, ZigZagPivot{Index: hi, Price: highs[hi], IsHigh: true})
+				direction = -1
+			}
+		case 1:
+			if highs[i] > highs[hi] {
+				hi = i
+			} else if (highs[hi]-lows[i])/highs[hi]*100 >= deviationPct {
+				pivots = append(pivots, ZigZagPivot{Index: hi, Price: highs[hi], IsHigh: true})
+				direction = -1
+				lo = i
+			}
+		case -1:
+			if lows[i] < lows[lo] {
+				lo = i
+			} else if (highs[i]-lows[lo])/lows[lo]*100 >= deviationPct {
+				pivots = append(pivots, ZigZagPivot{Index: lo, Price: lows[lo], IsHigh: false})
+				direction = 1
+				hi = i
+			}
+		}
+	}
+
+	// 当前未确认的极值
+	switch direction {
+	case 1:
+		pivots = append(pivots, ZigZagPivot{Index: hi, Price: highs[hi], IsHigh: true})
+	case -1:
+		pivots = append(pivots, ZigZagPivot{Index: lo, Price: lows[lo], IsHigh: false})
+	}
+
+	return pivots
+}
+
+// DominantSwing 返回最近lookback根K线内ZigZag转折点中的最高点和最低点，
+// 两者构成主导波段；不足两个转折点时ok为false
+func (ti *TechnicalIndicators) DominantSwing(highs, lows []float64, lookback int, deviationPct float64) (high, low ZigZagPivot, ok bool) {
+	offset := 0
+	if lookback > 0 && lookback < len(highs) {
+		offset = len(highs) - lookback
+	}
+	pivots := ti.ZigZag(highs[offset:], lows[offset:], deviationPct)
+	if len(pivots) < 2 {
+		return high, low, false
+	}
+
+	high.Price, low.Price = math.Inf(-1), math.Inf(1)
+	for _, pivot := range pivots {
+		pivot.Index += offset
+		if pivot.IsHigh && pivot.Price > high.Price {
+			high = pivot
+		}
+		if !pivot.IsHigh && pivot.Price < low.Price {
+			low = pivot
+		}
+	}
+	return high, low, !math.IsInf(high.Price, 0) && !math.IsInf(low.Price, 0)
+}
+
+// FibonacciLevels 计算波段的回撤和扩展价位
+// 上涨波段（低点在前）的回撤自高点向下、扩展在高点上方；下跌波段相反
+func FibonacciLevels(swingHigh, swingLow float64, upswing bool) (retracements, extensions []types.FibLevel) {
+	rng := swingHigh - swingLow
+	level := func(ratio float64) float64 {
+		if upswing {
+			return swingHigh - ratio*rng
+		}
+		return swingLow + ratio*rng
+	}
+
+	for _, ratio := range FibRetracementRatios {
+		retracements = append(retracements, types.FibLevel{Ratio: ratio, Price: level(ratio), Kind: types.Retracement})
+	}
+	// 扩展从波段起点按比例投射：上涨波段为 低点+ratio×幅度
+	for _, ratio := range FibExtensionRatios {
+		extensions = append(extensions, types.FibLevel{Ratio: ratio, Price: level(1 - ratio), Kind: types.Extension})
+	}
+	return retracements, extensions
+}
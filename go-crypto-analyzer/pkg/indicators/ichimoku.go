@@ -0,0 +1,96 @@
+package indicators
+
+// IchimokuResult 一目均衡表各条线的序列
+// Tenkan/Kijun与输入等长；SenkouA/SenkouB向前平移displacement根K线，
+// 长度为len(close)+displacement，索引i对应第i根K线位置上的云层（超出部分为未来云层）；
+// Chikou[i]为第i+displacement根K线的收盘价（迟行线向后平移），最后displacement个值为0
+type IchimokuResult struct {
+	Tenkan       []float64
+	Kijun        []float64
+	SenkouA      []float64
+	SenkouB      []float64
+	Chikou       []float64
+	Displacement int
+}
+
+// Ichimoku 计算一目均衡表（转换线、基准线、先行带A/B、迟行线）
+// 常用参数为 9/26/52，平移26
+func (ti *TechnicalIndicators) Ichimoku(high, low, close []float64, tenkanPeriod, kijunPeriod, senkouBPeriod, displacement int) IchimokuResult {
+	n := len(close)
+	result := IchimokuResult{
+		Tenkan:       make([]float64, n),
+		Kijun:        make([]float64, n),
+		SenkouA:      make([]float64, n+displacement),
+		SenkouB:      make([]float64, n+displacement),
+		Chikou:       make([]float64, n),
+		Displacement: displacement,
+	}
+	if len(high) != n || len(low) != n {
+		return result
+	}
+
+	tenkan := midpointSeries(high, low, tenkanPeriod)
+	kijun := midpointSeries(high, low, kijunPeriod)
+	senkouB := midpointSeries(high, low, senkouBPeriod)
+	copy(result.Tenkan, tenkan)
+	copy(result.Kijun, kijun)
+
+	for i := 0; i < n; i++ {
+		// 先行带A：转换线与基准线的中值，向前平移
+		if i >= kijunPeriod-1 && i >= tenkanPeriod-1 {
+			result.SenkouA[i+displacement] = (tenkan[i] + kijun[i]) / 2
+		}
+		// 先行带B：senkouBPeriod期最高最低价中值，向前平移
+		if i >= senkouBPeriod-1 {
+			result.SenkouB[i+displacement] = senkouB[i]
+		}
+		// 迟行线：收盘价向后平移
+		if i >= displacement {
+			result.Chikou[i-displacement] = close[i]
+		}
+	}
+
+	return result
+}
+
+// midpointSeries 计算period期最高价与最低价的中值序列
+func midpointSeries(high, low []float64, period int) []float64 {
+	mid := make([]float64, len(high))
+	if period < 1 {
+		return mid
+	}
+
+	for i := period - 1; i < len(high); i++ {
+		highest, lowest := high[i], low[i]
+		for j := i - period + 1; j < i; j++ {
+			if high[j] > highest {
+				highest = high[j]
+			}
+			if low[j] < lowest {
+				lowest = low[j]
+			}
+		}
+		mid[i] = (highest + lowest) / 2
+	}
+
+	return mid
+}
+
+// KumoTwists 找出云层扭转（先行带A与B交叉）的位置
+// 返回发生扭转的索引，start之前的值视为预热期
+func KumoTwists(senkouA, senkouB []float64, start int) []int {
+	twists := make([]int, 0)
+	if start < 1 {
+		start = 1
+	}
+
+	for i := start; i < len(senkouA) && i < len(senkouB); i++ {
+		prevDiff := senkouA[i-1] - senkouB[i-1]
+		diff := senkouA[i] - senkouB[i]
+		if (prevDiff <= 0 && diff > 0) || (prevDiff >= 0 && diff < 0) {
+			twists = append(twists, i)
+		}
+	}
+
+	return twists
+}
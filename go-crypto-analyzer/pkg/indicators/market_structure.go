@@ -0,0 +1,152 @@
+package indicators
+
+import (
+	"sort"
+
+	"github.com/zjc/go-crypto-analyzer/pkg/types"
+)
+
+// 保留的最近结构摆动点数量
+const structureSwingsKept = 10
+
+// MarketStructure 分析市场结构：
+// 摆动高点与前一高点比较标记为HH/LH，摆动低点与前一低点比较标记为HL/LL；
+// 收盘价突破最近已确认的摆动点时，顺结构方向为BOS（结构突破），逆结构方向为CHoCH（结构转变）。
+// 摆动点需要右侧strength根K线确认，确认之前不参与突破判断
+func (ti *TechnicalIndicators) MarketStructure(data []types.OHLCV, strength int) types.MarketStructureAnalysis {
+	result := types.MarketStructureAnalysis{Trend: types.Sideways}
+	if strength < 1 {
+		strength = 1
+	}
+	if len(data) < 2*strength+1 {
+		return result
+	}
+
+	highs := make([]float64, len(data))
+	lows := make([]float64, len(data))
+	for i, candle := range data {
+		highs[i] = candle.High
+		lows[i] = candle.Low
+	}
+
+	// 合并高低摆动点并按时间排序后逐一标记
+	swings := make([]types.StructureSwing, 0)
+	for _, sp := range FindSwingHighs(highs, strength) {
+		swings = append(swings, types.StructureSwing{Index: sp.Index, Time: data[sp.Index].Time, Price: sp.Value, IsHigh: true})
+	}
+	for _, sp := range FindSwingLows(lows, strength) {
+		swings = append(swings, types.StructureSwing{Index: sp.Index, Time: data[sp.Index].Time, Price: sp.Value})
+	}
+	if len(swings) == 0 {
+		return result
+	}
+	sort.SliceStable(swings, func(i, j int) bool { return swings[i].Index < swings[j].Index })
+
+	var prevHigh, prevLow *types.StructureSwing
+	for i := range swings {
+		swing := &swings[i]
+		if swing.IsHigh {
+			if prevHigh != nil {
+				swing.Label = types.LowerHigh
+				if swing.Price > prevHigh.Price {
+					swing.Label = types.HigherHigh
+				}
+			}
+			prevHigh = swing
+		} else {
+			if prevLow != nil {
+				swing.Label = types.LowerLow
+				if swing.Price > prevLow.Price {
+					swing.Label = types.HigherLow
+				}
+			}
+			prevLow = swing
+		}
+	}
+
+	// 逐根K线跟踪最近已确认的摆动高低点及其是否已被收盘突破
+	var activeHigh, activeLow *types.StructureSwing
+	highBroken, lowBroken := false, false
+	next := 0
+	bias := 0
+	events := make([]types.StructureEvent, 0)
+	for i, candle := range data {
+		for next < len(swings) && swings[next].Index+strength <= i {
+			if swings[next].IsHigh {
+				activeHigh, highBroken = &swings[next], false
+			} else {
+				activeLow, lowBroken = &swings[next], false
+			}
+			next++
+		}
+
+		if activeHigh != nil && !highBroken && candle.Close > activeHigh.Price {
+			events = append(events, structureEvent(bias < 0, true, activeHigh, i, data))
+			bias, highBroken = 1, true
+		}
+		if activeLow != nil && !lowBroken && candle.Close < activeLow.Price {
+			events = append(events, structureEvent(bias > 0, false, activeLow, i, data))
+			bias, lowBroken = -1, true
+		}
+	}
+
+	result.Available = true
+	result.Bias = bias
+	result.Events = events
+	if len(events) > 0 {
+		result.LastEvent = &events[len(events)-1]
+	}
+	if prevHigh != nil {
+		result.LastHigh = *prevHigh
+	}
+	if prevLow != nil {
+		result.LastLow = *prevLow
+	}
+	if len(swings) > structureSwingsKept {
+		swings = swings[len(swings)-structureSwingsKept:]
+	}
+	result.Swings = swings
+
+	// 结构方向由最近一次突破决定，最新高低点同时顺势（HH+HL或LH+LL）时为强趋势
+	rising := result.LastHigh.Label == types.HigherHigh && result.LastLow.Label == types.HigherLow
+	falling := result.LastHigh.Label == types.LowerHigh && result.LastLow.Label == types.LowerLow
+	switch {
+	case bias > 0 && rising:
+		result.Trend = types.StrongUptrend
+	case bias > 0:
+		result.Trend = types.Uptrend
+	case bias < 0 && falling:
+		result.Trend = types.StrongDowntrend
+	case bias < 0:
+		result.Trend = types.Downtrend
+	case rising:
+		result.Trend = types.Uptrend
+	case falling:
+		result.Trend = types.Downtrend
+	}
+
+	switch {
+	case bias > 0 && prevLow != nil:
+		result.ProtectedLevel = prevLow.Price
+	case bias < 0 && prevHigh != nil:
+		result.ProtectedLevel = prevHigh.Price
+	}
+
+	return result
+}
+
+func structureEvent(reversal, bullish bool, swing *types.StructureSwing, index int, data []types.OHLCV) types.StructureEvent {
+	eventType := types.BreakOfStructure
+	if reversal {
+		eventType = types.ChangeOfCharacter
+	}
+	return types.StructureEvent{
+		Type:       eventType,
+		Bullish:    bullish,
+		Level:      swing.Price,
+		SwingIndex: swing.Index,
+		Index:      index,
+		Time:       data[index].Time,
+		BarsAgo:    len(data) - 1 - index,
+	}
+}
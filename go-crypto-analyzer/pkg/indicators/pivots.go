@@ -0,0 +1,164 @@
+package indicators
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/zjc/go-crypto-analyzer/pkg/types"
+)
+
+// PivotMethod 轴心点计算方法
+type PivotMethod string
+
+const (
+	PivotFloor     PivotMethod = "floor"     // 经典（场内交易员）轴心点
+	PivotFibonacci PivotMethod = "fibonacci" // 斐波那契轴心点
+	PivotCamarilla PivotMethod = "camarilla" // Camarilla轴心点
+	PivotWoodie    PivotMethod = "woodie"    // Woodie轴心点
+	PivotDeMark    PivotMethod = "demark"    // DeMark轴心点，只有R1/S1
+)
+
+// ParsePivotMethod 解析轴心点计算方法
+func ParsePivotMethod(method string) (PivotMethod, error) {
+	switch PivotMethod(method) {
+	case PivotFloor, PivotFibonacci, PivotCamarilla, PivotWoodie, PivotDeMark:
+		return PivotMethod(method), nil
+	}
+	return "", fmt.Errorf("unknown pivot method %q (floor|fibonacci|camarilla|woodie|demark)", method)
+}
+
+// PivotPeriod 计算轴心点所用的周期
+type PivotPeriod string
+
+const (
+	PivotDaily   PivotPeriod = "day"
+	PivotWeekly  PivotPeriod = "week"
+	PivotMonthly PivotPeriod = "month"
+)
+
+// ParsePivotPeriod 解析轴心点周期
+func ParsePivotPeriod(period string) (PivotPeriod, error) {
+	switch PivotPeriod(period) {
+	case PivotDaily, PivotWeekly, PivotMonthly:
+		return PivotPeriod(period), nil
+	}
+	return "", fmt.Errorf("unknown pivot period %q (day|week|month)", period)
+}
+
+// PeriodStart 返回t所在周期（UTC日、周一开始的UTC周、UTC月）的起始时间
+func PeriodStart(t time.Time, period PivotPeriod) time.Time {
+	utc := t.UTC()
+	day := time.Date(utc.Year(), utc.Month(), utc.Day(), 0, 0, 0, 0, time.UTC)
+	switch period {
+	case PivotWeekly:
+		weekday := (int(day.Weekday()) + 6) % 7 // 周一为0
+		return day.AddDate(0, 0, -weekday)
+	case PivotMonthly:
+		return time.Date(utc.Year(), utc.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return day
+}
+
+// AggregatePeriods 将任意周期的K线按UTC日/周/月聚合，时间为周期起点
+func AggregatePeriods(data []types.OHLCV, period PivotPeriod) []types.OHLCV {
+	result := make([]types.OHLCV, 0)
+	var current time.Time
+	for _, candle := range data {
+		start := PeriodStart(candle.Time, period)
+		if len(result) == 0 || !start.Equal(current) {
+			current = start
+			result = append(result, types.OHLCV{
+				Time:   start,
+				Open:   candle.Open,
+				High:   candle.High,
+				Low:    candle.Low,
+				Close:  candle.Close,
+				Volume: candle.Volume,
+			})
+			continue
+		}
+		last := &result[len(result)-1]
+		last.High = math.Max(last.High, candle.High)
+		last.Low = math.Min(last.Low, candle.Low)
+		last.Close = candle.Close
+		last.Volume += candle.Volume
+	}
+	return result
+}
+
+// PivotLevels 根据上一周期的开高低收计算轴心点及R1-R4/S1-S4
+// currentOpen为当前周期开盘价，仅Woodie使用；DeMark只定义了R1/S1
+func (ti *TechnicalIndicators) PivotLevels(method PivotMethod, open, high, low, close, currentOpen float64) types.SRAnalysis {
+	rng := high - low
+	pivot := (high + low + close) / 3
+	var r, s [4]float64
+
+	switch method {
+	case PivotFibonacci:
+		for i, ratio := range []float64{0.382, 0.618, 1.0, 1.618} {
+			r[i] = pivot + ratio*rng
+			s[i] = pivot - ratio*rng
+		}
+	case PivotCamarilla:
+		for i, divisor := range []float64{12, 6, 4, 2} {
+			r[i] = close + rng*1.1/divisor
+			s[i] = close - rng*1.1/divisor
+		}
+	case PivotDeMark:
+		var x float64
+		switch {
+		case close < open:
+			x = high + 2*low + close
+		case close > open:
+			x = 2*high + low + close
+		default:
+			x = high + low + 2*close
+		}
+		pivot = x / 4
+		return types.SRAnalysis{
+			Pivot:      pivot,
+			Resistance: map[string]float64{"R1": x/2 - low},
+			Support:    map[string]float64{"S1": x/2 - high},
+		}
+	default:
+		if method == PivotWoodie {
+			// Woodie以当前周期开盘价加权
+			pivot = (high + low + 2*currentOpen) / 4
+		}
+		r[0] = 2*pivot - low
+		s[0] = 2*pivot - high
+		r[1] = pivot + rng
+		s[1] = pivot - rng
+		r[2] = high + 2*(pivot-low)
+		s[2] = low - 2*(high-pivot)
+		r[3] = r[2] + rng
+		s[3] = s[2] - rng
+	}
+
+	sr := types.SRAnalysis{
+		Pivot:      pivot,
+		Resistance: make(map[string]float64, 4),
+		Support:    make(map[string]float64, 4),
+	}
+	for i := 0; i < 4; i++ {
+		sr.Resistance[fmt.Sprintf("R%d", i+1)] = r[i]
+		sr.Support[fmt.Sprintf("S%d", i+1)] = s[i]
+	}
+	return sr
+}
+
+// PeriodPivots 用最后一根K线之前最近一个已完成的日/周/月计算轴心点
+// ok为false表示数据中没有已完成的周期；partial表示该周期开始早于数据起点，高低点可能不完整
+func (ti *TechnicalIndicators) PeriodPivots(data []types.OHLCV, method PivotMethod, period PivotPeriod) (sr types.SRAnalysis, periodStart time.Time, partial, ok bool) {
+	periods := AggregatePeriods(data, period)
+	if len(periods) < 2 {
+		return types.SRAnalysis{}, time.Time{}, false, false
+	}
+
+	prev := periods[len(periods)-2]
+	current := periods[len(periods)-1]
+	sr = ti.PivotLevels(method, prev.Open, prev.High, prev.Low, prev.Close, current.Open)
+	partial = len(periods) == 2 && data[0].Time.After(prev.Time)
+	return sr, prev.Time, partial, true
+}
@@ -0,0 +1,460 @@
+package indicators
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/zjc/go-crypto-analyzer/pkg/types"
+)
+
+// Input 指标输入序列
+type Input string
+
+const (
+	InputOpen   Input = "open"
+	InputHigh   Input = "high"
+	InputLow    Input = "low"
+	InputClose  Input = "close"
+	InputVolume Input = "volume"
+	InputHL2    Input = "hl2"   // (最高+最低)/2
+	InputHLC3   Input = "hlc3"  // (最高+最低+收盘)/3
+	InputOHLC4  Input = "ohlc4" // (开+高+低+收)/4
+	// InputSource 表示指标作用于可选的源序列，由规格中的source参数指定，默认收盘价
+	InputSource Input = "source"
+)
+
+// Inputs 按输入名索引的序列
+type Inputs map[Input][]float64
+
+// InputsFromOHLCV 从K线构造全部基础输入序列
+func InputsFromOHLCV(data []types.OHLCV) Inputs {
+	in := Inputs{
+		InputOpen:   make([]float64, len(data)),
+		InputHigh:   make([]float64, len(data)),
+		InputLow:    make([]float64, len(data)),
+		InputClose:  make([]float64, len(data)),
+		InputVolume: make([]float64, len(data)),
+		InputHL2:    make([]float64, len(data)),
+		InputHLC3:   make([]float64, len(data)),
+		InputOHLC4:  make([]float64, len(data)),
+	}
+	for i, candle := range data {
+		in[InputOpen][i] = candle.Open
+		in[InputHigh][i] = candle.High
+		in[InputLow][i] = candle.Low
+		in[InputClose][i] = candle.Close
+		in[InputVolume][i] = candle.Volume
+		in[InputHL2][i] = (candle.High + candle.Low) / 2
+		in[InputHLC3][i] = (candle.High + candle.Low + candle.Close) / 3
+		in[InputOHLC4][i] = (candle.Open + candle.High + candle.Low + candle.Close) / 4
+	}
+	return in
+}
+
+// ParamSpec 指标参数声明
+type ParamSpec struct {
+	Name        string
+	Default     float64
+	Min         float64 // 允许的最小值
+	Integer     bool    // 是否只接受整数，如周期
+	Description string
+}
+
+// Params 解析后的参数值
+type Params map[string]float64
+
+// Int 以整数读取参数
+func (p Params) Int(name string) int {
+	return int(p[name])
+}
+
+// Definition 指标定义：名称、参数及默认值、所需输入、预热长度和输出序列
+type Definition struct {
+	Name        string
+	Description string
+	Params      []ParamSpec
+	// Inputs 为所需输入，包含InputSource时指标作用于规格选定的源序列
+	Inputs []Input
+	// Outputs 为输出序列名，第一个为默认输出
+	Outputs []string
+	// WarmUp 返回输出开始有效的K线下标，之前的值为预热填充
+	WarmUp func(p Params) int
+	// Compute 返回与输入等长、与Outputs一一对应的序列
+	Compute func(in Inputs, p Params) [][]float64
+}
+
+// hasSource 指标是否接受source参数
+func (d Definition) hasSource() bool {
+	for _, input := range d.Inputs {
+		if input == InputSource {
+			return true
+		}
+	}
+	return false
+}
+
+// Arg 规格中的一个参数
+type Arg struct {
+	Name  string
+	Value float64
+}
+
+// Spec 指标规格，如 rsi(period=9)、sma(volume,20)、macd(fast=8).signal
+type Spec struct {
+	Name   string
+	Source Input // 为空表示默认源
+	Args   []Arg
+	Output string // 为空表示默认输出
+	// positional 为未命名的数值参数，Resolve时按声明顺序对应
+	positional []float64
+}
+
+// String 返回规格的文本形式，Resolve之后的规格为规范形式
+func (s Spec) String() string {
+	parts := make([]string, 0, len(s.Args)+len(s.positional)+1)
+	if s.Source != "" {
+		parts = append(parts, "source="+string(s.Source))
+	}
+	for _, value := range s.positional {
+		parts = append(parts, formatParam(value))
+	}
+	for _, arg := range s.Args {
+		parts = append(parts, arg.Name+"="+formatParam(arg.Value))
+	}
+	text := s.Name + "(" + strings.Join(parts, ",") + ")"
+	if s.Output != "" {
+		text += "." + s.Output
+	}
+	return text
+}
+
+// Params 返回参数表
+func (s Spec) Params() Params {
+	params := make(Params, len(s.Args))
+	for _, arg := range s.Args {
+		params[arg.Name] = arg.Value
+	}
+	return params
+}
+
+func formatParam(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// SplitSpecs 按括号外的逗号拆分规格列表，如 "rsi(period=9),ema(period=21)"，
+// 忽略空项
+func SplitSpecs(text string) []string {
+	specs := make([]string, 0)
+	depth, start := 0, 0
+	for i := 0; i <= len(text); i++ {
+		if i < len(text) {
+			switch text[i] {
+			case '(':
+				depth++
+			case ')':
+				depth--
+			}
+			if text[i] != ',' || depth > 0 {
+				continue
+			}
+		}
+		if spec := strings.TrimSpace(text[start:i]); spec != "" {
+			specs = append(specs, spec)
+		}
+		start = i + 1
+	}
+	return specs
+}
+
+// ParseSpec 解析指标规格
+// 语法：name[(arg,...)][.output]，arg为 key=value、数值（按参数声明顺序）或输入名（作为source）
+func ParseSpec(text string) (Spec, error) {
+	text = strings.TrimSpace(text)
+	spec := Spec{}
+
+	open := strings.IndexByte(text, '(')
+	rest := ""
+	if open < 0 {
+		spec.Name = text
+		if dot := strings.IndexByte(text, '.'); dot >= 0 {
+			spec.Name, rest = text[:dot], text[dot:]
+		}
+	} else {
+		closeIdx := strings.IndexByte(text, ')')
+		if closeIdx < open {
+			return Spec{}, fmt.Errorf("invalid indicator spec %q: missing ')'", text)
+		}
+		spec.Name = strings.TrimSpace(text[:open])
+		rest = text[closeIdx+1:]
+
+		body := strings.TrimSpace(text[open+1 : closeIdx])
+		if body != "" {
+			for _, raw := range strings.Split(body, ",") {
+				if err := spec.parseArg(strings.TrimSpace(raw)); err != nil {
+					return Spec{}, fmt.Errorf("invalid indicator spec %q: %v", text, err)
+				}
+			}
+		}
+	}
+
+	if rest != "" {
+		if !strings.HasPrefix(rest, ".") || !isIdentifier(rest[1:]) {
+			return Spec{}, fmt.Errorf("invalid indicator spec %q: bad output selector %q", text, rest)
+		}
+		spec.Output = strings.ToLower(rest[1:])
+	}
+	spec.Name = strings.ToLower(spec.Name)
+	if !isIdentifier(spec.Name) {
+		return Spec{}, fmt.Errorf("invalid indicator name %q", spec.Name)
+	}
+	return spec, nil
+}
+
+func (s *Spec) parseArg(raw string) error {
+	name, value := "", raw
+	if eq := strings.IndexByte(raw, '='); eq >= 0 {
+		name, value = strings.ToLower(strings.TrimSpace(raw[:eq])), strings.TrimSpace(raw[eq+1:])
+		if !isIdentifier(name) {
+			return fmt.Errorf("bad parameter name %q", name)
+		}
+	}
+
+	if number, err := strconv.ParseFloat(value, 64); err == nil {
+		if name == "" {
+			s.positional = append(s.positional, number)
+		} else {
+			s.Args = append(s.Args, Arg{Name: name, Value: number})
+		}
+		return nil
+	}
+
+	// 非数值参数只能是源序列
+	if (name == "" || name == "source") && isIdentifier(value) {
+		if s.Source != "" {
+			return fmt.Errorf("duplicate source %q", value)
+		}
+		s.Source = Input(strings.ToLower(value))
+		return nil
+	}
+	return fmt.Errorf("bad value %q", raw)
+}
+
+func isIdentifier(text string) bool {
+	if text == "" {
+		return false
+	}
+	for i, r := range text {
+		if !(r == '_' || unicode.IsLetter(r) || (i > 0 && unicode.IsDigit(r))) {
+			return false
+		}
+	}
+	return true
+}
+
+// Registry 指标注册表
+type Registry struct {
+	mu          sync.RWMutex
+	definitions map[string]Definition
+}
+
+// NewRegistry 创建空的指标注册表
+func NewRegistry() *Registry {
+	return &Registry{definitions: make(map[string]Definition)}
+}
+
+var defaultRegistry = NewRegistry()
+
+// DefaultRegistry 返回内置指标所在的默认注册表
+func DefaultRegistry() *Registry {
+	return defaultRegistry
+}
+
+// Register 向默认注册表注册指标，供第三方包在init中调用
+func Register(def Definition) error {
+	return defaultRegistry.Register(def)
+}
+
+// MustRegister 注册失败时panic
+func MustRegister(def Definition) {
+	if err := defaultRegistry.Register(def); err != nil {
+		panic(err)
+	}
+}
+
+// Register 注册指标，名称不能重复
+func (r *Registry) Register(def Definition) error {
+	def.Name = strings.ToLower(def.Name)
+	if !isIdentifier(def.Name) {
+		return fmt.Errorf("invalid indicator name %q", def.Name)
+	}
+	if def.Compute == nil || len(def.Outputs) == 0 {
+		return fmt.Errorf("indicator %q must declare outputs and a compute function", def.Name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.definitions[def.Name]; exists {
+		return fmt.Errorf("indicator %q already registered", def.Name)
+	}
+	r.definitions[def.Name] = def
+	return nil
+}
+
+// Lookup 按名称查找指标定义
+func (r *Registry) Lookup(name string) (Definition, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	def, ok := r.definitions[strings.ToLower(name)]
+	return def, ok
+}
+
+// Definitions 返回按名称排序的全部指标定义
+func (r *Registry) Definitions() []Definition {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	defs := make([]Definition, 0, len(r.definitions))
+	for _, def := range r.definitions {
+		defs = append(defs, def)
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Name < defs[j].Name })
+	return defs
+}
+
+// Resolve 校验规格并补全默认参数，返回按声明顺序排列参数的规范规格
+func (r *Registry) Resolve(spec Spec) (Spec, Definition, error) {
+	def, ok := r.Lookup(spec.Name)
+	if !ok {
+		return Spec{}, Definition{}, fmt.Errorf("unknown indicator %q", spec.Name)
+	}
+	if len(spec.positional) > len(def.Params) {
+		return Spec{}, Definition{}, fmt.Errorf("%s takes at most %d parameters", def.Name, len(def.Params))
+	}
+
+	values := make(Params, len(def.Params))
+	for i, param := range def.Params {
+		values[param.Name] = param.Default
+		if i < len(spec.positional) {
+			values[param.Name] = spec.positional[i]
+		}
+	}
+	for _, arg := range spec.Args {
+		if _, known := values[arg.Name]; !known {
+			return Spec{}, Definition{}, fmt.Errorf("%s has no parameter %q", def.Name, arg.Name)
+		}
+		values[arg.Name] = arg.Value
+	}
+	for _, param := range def.Params {
+		if values[param.Name] < param.Min {
+			return Spec{}, Definition{}, fmt.Errorf("%s: %s must be at least %s", def.Name, param.Name, formatParam(param.Min))
+		}
+		if param.Integer && values[param.Name] != math.Trunc(values[param.Name]) {
+			return Spec{}, Definition{}, fmt.Errorf("%s: %s must be an integer, got %s", def.Name, param.Name, formatParam(values[param.Name]))
+		}
+	}
+
+	resolved := Spec{Name: def.Name}
+	for _, param := range def.Params {
+		resolved.Args = append(resolved.Args, Arg{Name: param.Name, Value: values[param.Name]})
+	}
+
+	if spec.Source != "" {
+		if !def.hasSource() {
+			return Spec{}, Definition{}, fmt.Errorf("%s does not take a source", def.Name)
+		}
+		// 默认源不写入规范形式
+		if spec.Source != InputClose {
+			resolved.Source = spec.Source
+		}
+	}
+
+	if spec.Output != "" && spec.Output != def.Outputs[0] {
+		found := false
+		for _, output := range def.Outputs {
+			found = found || output == spec.Output
+		}
+		if !found {
+			return Spec{}, Definition{}, fmt.Errorf("%s has no output %q (%s)", def.Name, spec.Output, strings.Join(def.Outputs, "|"))
+		}
+		resolved.Output = spec.Output
+	}
+
+	return resolved, def, nil
+}
+
+// Result 指标计算结果
+type Result struct {
+	Spec    Spec // 规范规格
+	WarmUp  int
+	Outputs map[string][]float64
+	output  string
+}
+
+// Series 返回规格选定的输出序列
+func (r *Result) Series() []float64 {
+	return r.Outputs[r.output]
+}
+
+// Last 返回选定输出的最新值，数据不足预热长度时ok为false
+func (r *Result) Last() (value float64, ok bool) {
+	series := r.Series()
+	if len(series) == 0 || len(series) <= r.WarmUp {
+		return 0, false
+	}
+	return series[len(series)-1], true
+}
+
+// Compute 解析规格并在K线上计算指标
+func (r *Registry) Compute(text string, data []types.OHLCV) (*Result, error) {
+	spec, err := ParseSpec(text)
+	if err != nil {
+		return nil, err
+	}
+	return r.ComputeSpec(spec, InputsFromOHLCV(data))
+}
+
+// ComputeSpec 在给定输入上计算指标，source可以是inputs中的任意序列
+func (r *Registry) ComputeSpec(spec Spec, inputs Inputs) (*Result, error) {
+	resolved, def, err := r.Resolve(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	in := make(Inputs, len(def.Inputs))
+	for _, input := range def.Inputs {
+		name := input
+		if input == InputSource {
+			name = InputClose
+			if resolved.Source != "" {
+				name = resolved.Source
+			}
+		}
+		series, ok := inputs[name]
+		if !ok {
+			return nil, fmt.Errorf("%s: missing input %q", resolved, name)
+		}
+		in[input] = series
+	}
+
+	params := resolved.Params()
+	outputs := def.Compute(in, params)
+	if len(outputs) != len(def.Outputs) {
+		return nil, fmt.Errorf("%s returned %d outputs, declared %d", def.Name, len(outputs), len(def.Outputs))
+	}
+
+	result := &Result{Spec: resolved, Outputs: make(map[string][]float64, len(outputs)), output: def.Outputs[0]}
+	if resolved.Output != "" {
+		result.output = resolved.Output
+	}
+	for i, name := range def.Outputs {
+		result.Outputs[name] = outputs[i]
+	}
+	if def.WarmUp != nil {
+		result.WarmUp = def.WarmUp(params)
+	}
+	return result, nil
+}
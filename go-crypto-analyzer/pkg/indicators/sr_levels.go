@@ -0,0 +1,182 @@
+package indicators
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/zjc/go-crypto-analyzer/pkg/types"
+)
+
+// srTouch 一次摆动点触及
+type srTouch struct {
+	price  float64
+	index  int
+	volume float64
+	weight float64 // 摆动级别越大权重越高
+}
+
+// SwingSRLevels 将多个摆动级别（左右strength根K线）的摆动高低点按价格聚类成支撑阻力位
+// 价格相差tolerancePct（百分比）以内的摆动点归为同一价位，价位取按权重的加权平均。
+// 评分综合触及次数(50%)、最近触及的时间(30%)和触及时的成交量(20%)，结果按价格从低到高
+func (ti *TechnicalIndicators) SwingSRLevels(data []types.OHLCV, strengths []int, tolerancePct float64) []types.SRLevel {
+	if len(data) == 0 || len(strengths) == 0 {
+		return nil
+	}
+
+	highs := make([]float64, len(data))
+	lows := make([]float64, len(data))
+	avgVolume := 0.0
+	for i, candle := range data {
+		highs[i] = candle.High
+		lows[i] = candle.Low
+		avgVolume += candle.Volume
+	}
+	avgVolume /= float64(len(data))
+
+	maxStrength := 0
+	for _, strength := range strengths {
+		if strength > maxStrength {
+			maxStrength = strength
+		}
+	}
+
+	// 同一根K线在多个级别上都是摆动点时只计一次，保留最高级别的权重
+	touches := make(map[int]srTouch)
+	collect := func(swings []SwingPoint, side, strength int) {
+		weight := 0.5 + 0.5*float64(strength)/float64(maxStrength)
+		for _, swing := range swings {
+			key := swing.Index*2 + side
+			if existing, ok := touches[key]; ok && existing.weight >= weight {
+				continue
+			}
+			touches[key] = srTouch{price: swing.Value, index: swing.Index, volume: data[swing.Index].Volume, weight: weight}
+		}
+	}
+	for _, strength := range strengths {
+		collect(FindSwingHighs(highs, strength), 0, strength)
+		collect(FindSwingLows(lows, strength), 1, strength)
+	}
+	if len(touches) == 0 {
+		return nil
+	}
+
+	sorted := make([]srTouch, 0, len(touches))
+	for _, touch := range touches {
+		sorted = append(sorted, touch)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].price == sorted[j].price {
+			return sorted[i].index < sorted[j].index
+		}
+		return sorted[i].price < sorted[j].price
+	})
+
+	// 按价格顺序贪心聚类：与当前簇均价的距离在容差内则并入
+	levels := make([]types.SRLevel, 0)
+	cluster := []srTouch{sorted[0]}
+	mean := sorted[0].price
+	flush := func() {
+		levels = append(levels, scoreSRCluster(cluster, len(data), avgVolume))
+	}
+	for _, touch := range sorted[1:] {
+		if (touch.price-mean)/mean*100 <= tolerancePct {
+			cluster = append(cluster, touch)
+			sum, weights := 0.0, 0.0
+			for _, t := range cluster {
+				sum += t.price * t.weight
+				weights += t.weight
+			}
+			mean = sum / weights
+			continue
+		}
+		flush()
+		cluster = []srTouch{touch}
+		mean = touch.price
+	}
+	flush()
+
+	return levels
+}
+
+// scoreSRCluster 计算一个摆动点簇的价位和评分
+func scoreSRCluster(cluster []srTouch, dataLen int, avgVolume float64) types.SRLevel {
+	sum, weights, volume := 0.0, 0.0, 0.0
+	last := 0
+	for _, touch := range cluster {
+		sum += touch.price * touch.weight
+		weights += touch.weight
+		volume += touch.volume
+		if touch.index > last {
+			last = touch.index
+		}
+	}
+
+	volumeRatio := 0.0
+	if avgVolume > 0 {
+		volumeRatio = volume / float64(len(cluster)) / avgVolume
+	}
+	barsAgo := dataLen - 1 - last
+
+	touchScore := math.Min(weights/4, 1)
+	recencyScore := 1 - float64(barsAgo)/float64(dataLen)
+	volumeScore := math.Min(volumeRatio/2, 1)
+
+	return types.SRLevel{
+		Price:       sum / weights,
+		Source:      types.SwingSource,
+		Touches:     len(cluster),
+		LastBarsAgo: barsAgo,
+		VolumeRatio: volumeRatio,
+		Score:       0.5*touchScore + 0.3*recencyScore + 0.2*volumeScore,
+	}
+}
+
+// MergeKeyLevels 将配置的关键价位并入摆动价位
+// 与已有价位相差tolerancePct以内时视为共振，标注来源并加分；否则作为独立价位加入，
+// 历史支撑阻力基础分0.3，心理关口0.2。结果按价格从低到高
+func MergeKeyLevels(levels []types.SRLevel, keyLevels types.KeyLevels, tolerancePct float64) []types.SRLevel {
+	merged := append([]types.SRLevel(nil), levels...)
+
+	add := func(prices []float64, source types.SRSource, baseScore float64) {
+		for _, price := range prices {
+			if price <= 0 {
+				continue
+			}
+			matched := -1
+			for i, level := range merged {
+				if math.Abs(level.Price-price)/price*100 <= tolerancePct &&
+					(matched < 0 || math.Abs(level.Price-price) < math.Abs(merged[matched].Price-price)) {
+					matched = i
+				}
+			}
+			if matched < 0 {
+				merged = append(merged, types.SRLevel{Price: price, Source: source, LastBarsAgo: -1, Score: baseScore})
+				continue
+			}
+			if !strings.Contains(string(merged[matched].Source), string(source)) {
+				merged[matched].Source += "+" + source
+				merged[matched].Score = math.Min(merged[matched].Score+0.2, 1)
+			}
+		}
+	}
+	add(keyLevels.HistoricalSupport, types.HistoricalSupportSource, 0.3)
+	add(keyLevels.HistoricalResistance, types.HistoricalResistanceSource, 0.3)
+	add(keyLevels.Psychological, types.PsychologicalSource, 0.2)
+
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Price < merged[j].Price })
+	return merged
+}
+
+// NearestSRLevels 返回价格上方和下方最近的价位，不存在时Price为0
+func NearestSRLevels(levels []types.SRLevel, price float64) (above, below types.SRLevel) {
+	for _, level := range levels {
+		if level.Price > price && (above.Price == 0 || level.Price < above.Price) {
+			above = level
+		}
+		if level.Price < price && level.Price > below.Price {
+			below = level
+		}
+	}
+	return above, below
+}
@@ -62,41 +62,60 @@ func (ti *TechnicalIndicators) EMA(data []float64, period int) []float64 {
 	return ema
 }
 
-// MACD calculates MACD indicator
-func (ti *TechnicalIndicators) MACD(data []float64, fast, slow, signal int) types.MACDAnalysis {
+// MACDSeries calculates the MACD line, signal line and histogram series.
+// The MACD line starts at index slow-1 and the signal line and histogram
+// at index slow+signal-2; earlier values are zero.
+func (ti *TechnicalIndicators) MACDSeries(data []float64, fast, slow, signal int) (macdLine, signalLine, histogram []float64) {
+	macdLine = make([]float64, len(data))
+	signalLine = make([]float64, len(data))
+	histogram = make([]float64, len(data))
 	if len(data) < slow {
-		return types.MACDAnalysis{}
+		return macdLine, signalLine, histogram
 	}
 
 	emaFast := ti.EMA(data, fast)
 	emaSlow := ti.EMA(data, slow)
 
 	// Calculate MACD line
-	macdLine := make([]float64, len(data))
 	for i := slow - 1; i < len(data); i++ {
 		macdLine[i] = emaFast[i] - emaSlow[i]
 	}
 
 	// Calculate signal line
-	signalLine := ti.EMA(macdLine[slow-1:], signal)
+	signalEMA := ti.EMA(macdLine[slow-1:], signal)
+	if len(signalEMA) < signal {
+		return macdLine, signalLine, histogram
+	}
+	for i := signal - 1; i < len(signalEMA); i++ {
+		signalLine[slow-1+i] = signalEMA[i]
+		histogram[slow-1+i] = macdLine[slow-1+i] - signalEMA[i]
+	}
+
+	return macdLine, signalLine, histogram
+}
+
+// MACD calculates MACD indicator
+func (ti *TechnicalIndicators) MACD(data []float64, fast, slow, signal int) types.MACDAnalysis {
+	if len(data) < slow {
+		return types.MACDAnalysis{}
+	}
+
+	macdLine, signalLine, _ := ti.MACDSeries(data, fast, slow, signal)
 
 	// Get latest values
 	latestMACD := macdLine[len(macdLine)-1]
-	latestSignal := 0.0
-	if len(signalLine) > 0 {
-		latestSignal = signalLine[len(signalLine)-1]
-	}
+	latestSignal := signalLine[len(signalLine)-1]
 	histogram := latestMACD - latestSignal
 
 	// Determine trend
-	trend := "中性"
+	trend := types.MACDNeutral
 	if latestMACD > latestSignal {
 		if histogram > 0 {
-			trend = "看涨"
+			trend = types.MACDBullish
 		}
 	} else {
 		if histogram < 0 {
-			trend = "看跌"
+			trend = types.MACDBearish
 		}
 	}
 
@@ -105,14 +124,19 @@ func (ti *TechnicalIndicators) MACD(data []float64, fast, slow, signal int) type
 		Signal:     latestSignal,
 		Histogram:  histogram,
 		Trend:      trend,
-		Divergence: "无背离",
+		Divergence: types.NoDivergence,
 	}
 }
 
-// RSI calculates Relative Strength Index
-func (ti *TechnicalIndicators) RSI(data []float64, period int) float64 {
+// RSISeries calculates the Relative Strength Index for every bar using
+// Wilder's smoothing. The first value is at index period; earlier values are 50.
+func (ti *TechnicalIndicators) RSISeries(data []float64, period int) []float64 {
+	rsi := make([]float64, len(data))
+	for i := range rsi {
+		rsi[i] = 50.0
+	}
 	if len(data) < period+1 {
-		return 50.0
+		return rsi
 	}
 
 	gains := 0.0
@@ -130,6 +154,7 @@ func (ti *TechnicalIndicators) RSI(data []float64, period int) float64 {
 
 	avgGain := gains / float64(period)
 	avgLoss := losses / float64(period)
+	rsi[period] = rsiFromAverages(avgGain, avgLoss)
 
 	// Calculate subsequent values using smoothed average
 	for i := period + 1; i < len(data); i++ {
@@ -141,16 +166,29 @@ func (ti *TechnicalIndicators) RSI(data []float64, period int) float64 {
 			avgGain = (avgGain * (float64(period) - 1)) / float64(period)
 			avgLoss = (avgLoss*(float64(period)-1) + math.Abs(change)) / float64(period)
 		}
+		rsi[i] = rsiFromAverages(avgGain, avgLoss)
+	}
+
+	return rsi
+}
+
+// RSI calculates Relative Strength Index
+func (ti *TechnicalIndicators) RSI(data []float64, period int) float64 {
+	if len(data) < period+1 {
+		return 50.0
 	}
 
+	rsi := ti.RSISeries(data, period)
+	return rsi[len(rsi)-1]
+}
+
+func rsiFromAverages(avgGain, avgLoss float64) float64 {
 	if avgLoss == 0 {
 		return 100.0
 	}
 
 	rs := avgGain / avgLoss
-	rsi := 100.0 - (100.0 / (1.0 + rs))
-
-	return rsi
+	return 100.0 - (100.0 / (1.0 + rs))
 }
 
 // BollingerBands calculates Bollinger Bands
@@ -175,69 +213,116 @@ func (ti *TechnicalIndicators) BollingerBands(data []float64, period int, stdDev
 	return upper, middle, lower
 }
 
-// ADX calculates Average Directional Index
-func (ti *TechnicalIndicators) ADX(high, low, close []float64, period int) float64 {
-	if len(high) < period*2 || len(low) < period*2 || len(close) < period*2 {
-		return 0.0
+// DMIResult holds the series of Wilder's Directional Movement System.
+// Values before the warm-up period are zero.
+type DMIResult struct {
+	ADX     []float64
+	PlusDI  []float64
+	MinusDI []float64
+}
+
+// DMI calculates Wilder's Directional Movement Index (+DI, -DI and ADX).
+// TR, +DM and -DM are smoothed with Wilder's running sum, the first +DI/-DI
+// value is available at index period and the first ADX value at index
+// 2*period-1, matching TA-Lib and common charting platforms.
+func (ti *TechnicalIndicators) DMI(high, low, close []float64, period int) DMIResult {
+	n := len(close)
+	result := DMIResult{
+		ADX:     make([]float64, n),
+		PlusDI:  make([]float64, n),
+		MinusDI: make([]float64, n),
 	}
+	if period <= 0 || len(high) != n || len(low) != n || n <= period {
+		return result
+	}
+
+	var smoothedTR, smoothedPlusDM, smoothedMinusDM float64
+	dx := make([]float64, n)
+	p := float64(period)
 
-	// Calculate True Range
-	tr := make([]float64, len(high))
-	for i := 1; i < len(high); i++ {
+	for i := 1; i < n; i++ {
 		hl := high[i] - low[i]
 		hc := math.Abs(high[i] - close[i-1])
 		lc := math.Abs(low[i] - close[i-1])
-		tr[i] = math.Max(hl, math.Max(hc, lc))
-	}
-
-	// Calculate directional movements
-	plusDM := make([]float64, len(high))
-	minusDM := make([]float64, len(high))
+		tr := math.Max(hl, math.Max(hc, lc))
 
-	for i := 1; i < len(high); i++ {
 		upMove := high[i] - high[i-1]
 		downMove := low[i-1] - low[i]
-
+		plusDM, minusDM := 0.0, 0.0
 		if upMove > downMove && upMove > 0 {
-			plusDM[i] = upMove
+			plusDM = upMove
 		}
 		if downMove > upMove && downMove > 0 {
-			minusDM[i] = downMove
+			minusDM = downMove
 		}
-	}
 
-	// Calculate smoothed values
-	atr := ti.SMA(tr[1:], period)
-	plusDI := make([]float64, len(plusDM))
-	minusDI := make([]float64, len(minusDM))
-
-	if len(atr) > 0 && atr[len(atr)-1] != 0 {
-		smoothedPlusDM := ti.SMA(plusDM[1:], period)
-		smoothedMinusDM := ti.SMA(minusDM[1:], period)
+		// Wilder's smoothing: seed with the sum of the first period-1 values,
+		// then subtract 1/period of the previous total and add the new value
+		if i < period {
+			smoothedTR += tr
+			smoothedPlusDM += plusDM
+			smoothedMinusDM += minusDM
+			continue
+		}
+		smoothedTR = smoothedTR - smoothedTR/p + tr
+		smoothedPlusDM = smoothedPlusDM - smoothedPlusDM/p + plusDM
+		smoothedMinusDM = smoothedMinusDM - smoothedMinusDM/p + minusDM
 
-		for i := 0; i < len(smoothedPlusDM); i++ {
-			if i < len(atr) && atr[i] != 0 {
-				plusDI[i] = 100 * smoothedPlusDM[i] / atr[i]
-				minusDI[i] = 100 * smoothedMinusDM[i] / atr[i]
-			}
+		if smoothedTR != 0 {
+			result.PlusDI[i] = 100 * smoothedPlusDM / smoothedTR
+			result.MinusDI[i] = 100 * smoothedMinusDM / smoothedTR
+		}
+		if sum := result.PlusDI[i] + result.MinusDI[i]; sum != 0 {
+			dx[i] = 100 * math.Abs(result.PlusDI[i]-result.MinusDI[i]) / sum
 		}
 	}
 
-	// Calculate DX and ADX
-	dx := make([]float64, len(plusDI))
-	for i := 0; i < len(plusDI); i++ {
-		sum := plusDI[i] + minusDI[i]
-		if sum != 0 {
-			dx[i] = 100 * math.Abs(plusDI[i]-minusDI[i]) / sum
-		}
+	// ADX starts as the mean of the first period DX values and is then
+	// smoothed with Wilder's moving average
+	first := 2*period - 1
+	if n <= first {
+		return result
 	}
+	sum := 0.0
+	for i := period; i <= first; i++ {
+		sum += dx[i]
+	}
+	result.ADX[first] = sum / p
+	for i := first + 1; i < n; i++ {
+		result.ADX[i] = (result.ADX[i-1]*(p-1) + dx[i]) / p
+	}
+
+	return result
+}
 
-	adxValues := ti.SMA(dx, period)
-	if len(adxValues) > 0 {
-		return adxValues[len(adxValues)-1]
+// ADX calculates Average Directional Index
+func (ti *TechnicalIndicators) ADX(high, low, close []float64, period int) float64 {
+	if len(high) < period*2 || len(low) < period*2 || len(close) < period*2 {
+		return 0.0
 	}
 
-	return 0.0
+	adx := ti.DMI(high, low, close, period).ADX
+	return adx[len(adx)-1]
+}
+
+// LastCross finds the most recent crossing of series a over series b within
+// the last lookback bars. It returns 1 when a crossed above b, -1 when a
+// crossed below b and 0 when no cross happened, together with how many bars
+// ago the cross occurred.
+func LastCross(a, b []float64, lookback int) (direction int, barsAgo int) {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	for i := n - 1; i >= 1 && n-1-i < lookback; i-- {
+		if a[i-1] <= b[i-1] && a[i] > b[i] {
+			return 1, n - 1 - i
+		}
+		if a[i-1] >= b[i-1] && a[i] < b[i] {
+			return -1, n - 1 - i
+		}
+	}
+	return 0, 0
 }
 
 // VolumeAnalysis analyzes volume patterns
@@ -247,7 +332,7 @@ func (ti *TechnicalIndicators) VolumeAnalysis(volume []float64, period int) type
 			CurrentVolume: 0,
 			VolumeMA:      0,
 			VolumeRatio:   0,
-			VolumeTrend:   "数据不足",
+			VolumeTrend:   types.InsufficientVolume,
 		}
 	}
 
@@ -260,11 +345,11 @@ func (ti *TechnicalIndicators) VolumeAnalysis(volume []float64, period int) type
 		volumeRatio = currentVolume / avgVolume
 	}
 
-	volumeTrend := "正常量能"
+	volumeTrend := types.NormalVolume
 	if volumeRatio > 2 {
-		volumeTrend = "放量"
+		volumeTrend = types.HighVolume
 	} else if volumeRatio < 0.5 {
-		volumeTrend = "缩量"
+		volumeTrend = types.LowVolume
 	}
 
 	return types.VolumeAnalysis{
@@ -277,27 +362,5 @@ func (ti *TechnicalIndicators) VolumeAnalysis(volume []float64, period int) type
 
 // PivotPoints calculates pivot points for support and resistance
 func (ti *TechnicalIndicators) PivotPoints(high, low, close float64) types.SRAnalysis {
-	pivot := (high + low + close) / 3
-
-	r1 := 2*pivot - low
-	r2 := pivot + (high - low)
-	r3 := high + 2*(pivot-low)
-
-	s1 := 2*pivot - high
-	s2 := pivot - (high - low)
-	s3 := low - 2*(high-pivot)
-
-	return types.SRAnalysis{
-		Pivot: pivot,
-		Resistance: map[string]float64{
-			"R1": r1,
-			"R2": r2,
-			"R3": r3,
-		},
-		Support: map[string]float64{
-			"S1": s1,
-			"S2": s2,
-			"S3": s3,
-		},
-	}
+	return ti.PivotLevels(PivotFloor, close, high, low, close, close)
 }
\ No newline at end of file
@@ -2,7 +2,11 @@ package indicators
 
 import (
 	"math"
+	"reflect"
 	"testing"
+	"time"
+
+	"github.com/zjc/go-crypto-analyzer/pkg/types"
 )
 
 func TestSMA(t *testing.T) {
@@ -49,7 +53,7 @@ func TestMACD(t *testing.T) {
 	// 生成测试数据
 	data := make([]float64, 50)
 	for i := 0; i < 50; i++ {
-		data[i] = 100 + float64(i)*0.5 // 稳定上升趋势
+		data[i] = 100 + float64(i*i)*0.01 // 加速上升趋势（匀速上涨时MACD与信号线重合）
 	}
 	
 	macd := ti.MACD(data, 12, 26, 9)
@@ -60,7 +64,7 @@ func TestMACD(t *testing.T) {
 	}
 	
 	// 趋势应该是看涨
-	if macd.Trend != "看涨" {
+	if macd.Trend != types.MACDBullish {
 		t.Errorf("MACD trend should be bullish, got %s", macd.Trend)
 	}
 }
@@ -89,4 +93,744 @@ func TestPivotPoints(t *testing.T) {
 	if sr.Support["S1"] >= sr.Pivot {
 		t.Error("S1 should be below pivot")
 	}
-}
\ No newline at end of file
+}
+
+// dmiRefHigh/dmiRefLow/dmiRefClose are the first 60 daily SPY bars of the
+// go-talib test suite (github.com/markcheno/go-talib)
+var (
+	dmiRefHigh  = []float64{202.7, 200.24, 198.62, 198.62, 201.99, 202.25, 200.46, 201.33, 197.03, 197.93, 197.74, 198.62, 199.54, 202.09, 201.93, 201.4, 199.99, 200.16, 198.21, 198.08, 197.95, 200.71, 201.23, 202.13, 203.05, 201.48, 202.93, 203.26, 204.76, 205.6, 206.07, 205.97, 206.17, 207.06, 206.94, 207.76, 207.95, 207.43, 207.3, 207.77, 207.76, 206.23, 206.54, 205.7, 204.57, 202.63, 201.35, 202.99, 203.73, 204.47, 204.21, 207.0, 206.21, 207.68, 207.77, 207.07, 206.03, 203.1, 202.69, 205.3}
+	dmiRefLow   = []float64{200.05, 197.28, 194.84, 196.82, 199.87, 199.4, 197.84, 196.46, 194.56, 194.86, 194.54, 196.12, 196.88, 198.24, 200.67, 199.73, 197.66, 195.87, 194.66, 195.1, 193.86, 198.45, 199.4, 200.63, 200.78, 200.01, 200.54, 201.67, 202.79, 204.54, 204.87, 205.11, 205.01, 204.51, 206.22, 206.5, 206.95, 206.39, 206.34, 206.46, 205.83, 204.83, 205.61, 202.91, 203.35, 200.79, 200.27, 201.05, 200.44, 201.7, 202.8, 202.44, 204.8, 206.17, 206.67, 205.43, 202.45, 200.89, 201.65, 203.68}
+	dmiRefClose = []float64{201.28, 197.64, 195.78, 198.22, 201.74, 200.12, 198.55, 197.99, 196.8, 195.0, 197.55, 197.97, 198.97, 201.93, 200.83, 201.3, 198.64, 196.09, 197.91, 195.42, 197.84, 200.7, 199.93, 201.95, 201.39, 200.49, 202.63, 202.75, 204.7, 205.54, 205.86, 205.88, 205.73, 206.97, 206.94, 207.53, 207.35, 207.11, 206.4, 207.7, 206.85, 205.98, 206.2, 203.3, 204.15, 200.84, 200.37, 202.91, 201.67, 204.36, 203.76, 206.2, 205.26, 207.08, 206.67, 205.51, 202.5, 202.02, 202.48, 204.95}
+)
+
+func TestDMIMatchesTALib(t *testing.T) {
+	ti := NewTechnicalIndicators()
+
+	dmi := ti.DMI(dmiRefHigh, dmiRefLow, dmiRefClose, 14)
+
+	// 参考值由TA-Lib的ADX/PLUS_DI/MINUS_DI(14)计算得出
+	expected := []struct {
+		index   int
+		adx     float64
+		plusDI  float64
+		minusDI float64
+	}{
+		{14, 0, 19.2424, 24.1981},
+		{20, 0, 11.6942, 28.1623},
+		{27, 19.0963, 21.8656, 21.2168},
+		{28, 18.4748, 24.7234, 20.0676},
+		{36, 20.4764, 27.2270, 14.5827},
+		{45, 19.6359, 16.3615, 31.0135},
+		{51, 17.5269, 25.8967, 18.5589},
+		{59, 16.4678, 26.7784, 26.0072},
+	}
+
+	for _, e := range expected {
+		if math.Abs(dmi.ADX[e.index]-e.adx) > 0.0001 {
+			t.Errorf("ADX[%d]: expected %.4f, got %.4f", e.index, e.adx, dmi.ADX[e.index])
+		}
+		if math.Abs(dmi.PlusDI[e.index]-e.plusDI) > 0.0001 {
+			t.Errorf("+DI[%d]: expected %.4f, got %.4f", e.index, e.plusDI, dmi.PlusDI[e.index])
+		}
+		if math.Abs(dmi.MinusDI[e.index]-e.minusDI) > 0.0001 {
+			t.Errorf("-DI[%d]: expected %.4f, got %.4f", e.index, e.minusDI, dmi.MinusDI[e.index])
+		}
+	}
+
+	// ADX未完成预热前应为0
+	if dmi.ADX[26] != 0 {
+		t.Errorf("ADX should be zero before warm-up, got %.4f", dmi.ADX[26])
+	}
+
+	if adx := ti.ADX(dmiRefHigh, dmiRefLow, dmiRefClose, 14); math.Abs(adx-16.4678) > 0.0001 {
+		t.Errorf("ADX should return the latest DMI value, got %.4f", adx)
+	}
+}
+
+func TestDetectDivergences(t *testing.T) {
+	ti := NewTechnicalIndicators()
+
+	// 价格两次探底，第二个低点更低；振荡指标第二个低点更高 -> 常规看涨背离
+	lows := []float64{10, 9, 8, 7, 8, 9, 10, 9.5, 9, 8.5, 8, 7.5, 6.5, 7.5, 8.5, 9.5, 10}
+	highs := make([]float64, len(lows))
+	osc := make([]float64, len(lows))
+	for i, l := range lows {
+		highs[i] = l + 1
+		osc[i] = -l
+	}
+	osc[3] = -20 // 第一个低点指标更弱
+	osc[12] = -10
+
+	divs := ti.DetectDivergences("RSI", highs, lows, osc, 3, 0)
+
+	found := false
+	for _, d := range divs {
+		if d.Type == types.RegularBullishDivergence {
+			found = true
+			if d.StartIndex != 3 || d.EndIndex != 12 {
+				t.Errorf("divergence swings should be 3 and 12, got %d and %d", d.StartIndex, d.EndIndex)
+			}
+			if d.BarsAgo != len(lows)-1-12 {
+				t.Errorf("BarsAgo should be %d, got %d", len(lows)-1-12, d.BarsAgo)
+			}
+			if d.Strength <= 0 || d.Strength > 1 {
+				t.Errorf("strength should be in (0, 1], got %.2f", d.Strength)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("expected a regular bullish divergence, got %+v", divs)
+	}
+}
+
+func TestStochasticRSISeries(t *testing.T) {
+	ti := NewTechnicalIndicators()
+
+	data := make([]float64, 80)
+	for i := range data {
+		data[i] = 100 + 10*math.Sin(float64(i)/5)
+	}
+
+	kLine, dLine := ti.StochasticRSISeries(data, 14, 14, 3, 3)
+
+	// %D应等于%K的3期简单平均
+	last := len(data) - 1
+	expectedD := (kLine[last] + kLine[last-1] + kLine[last-2]) / 3
+	if math.Abs(dLine[last]-expectedD) > 0.0001 {
+		t.Errorf("%%D should be the 3-period SMA of %%K: expected %.4f, got %.4f", expectedD, dLine[last])
+	}
+
+	for i := 14 + 14 + 3 - 2; i < len(data); i++ {
+		if kLine[i] < -1e-9 || kLine[i] > 100+1e-9 {
+			t.Fatalf("%%K out of range at %d: %.2f", i, kLine[i])
+		}
+	}
+
+	k, d := ti.StochasticRSI(data, 14, 14, 3, 3)
+	if k != kLine[last] || d != dLine[last] {
+		t.Errorf("StochasticRSI should return the latest series values")
+	}
+	if k == d {
+		t.Errorf("%%K and %%D should differ on oscillating data, both %.2f", k)
+	}
+}
+
+func TestIchimokuDisplacement(t *testing.T) {
+	ti := NewTechnicalIndicators()
+
+	n := 80
+	high := make([]float64, n)
+	low := make([]float64, n)
+	close := make([]float64, n)
+	for i := 0; i < n; i++ {
+		close[i] = 100 + float64(i)
+		high[i] = close[i] + 1
+		low[i] = close[i] - 1
+	}
+
+	ich := ti.Ichimoku(high, low, close, 9, 26, 52, 26)
+
+	if len(ich.SenkouA) != n+26 || len(ich.SenkouB) != n+26 {
+		t.Fatalf("senkou spans should extend 26 bars ahead, got %d/%d", len(ich.SenkouA), len(ich.SenkouB))
+	}
+
+	// 第i根K线计算的先行带应绘制在i+26
+	i := 60
+	expectedA := (ich.Tenkan[i] + ich.Kijun[i]) / 2
+	if math.Abs(ich.SenkouA[i+26]-expectedA) > 0.0001 {
+		t.Errorf("SenkouA[%d]: expected %.2f, got %.2f", i+26, expectedA, ich.SenkouA[i+26])
+	}
+	expectedB := (high[i] + low[i-51]) / 2
+	if math.Abs(ich.SenkouB[i+26]-expectedB) > 0.0001 {
+		t.Errorf("SenkouB[%d]: expected %.2f, got %.2f", i+26, expectedB, ich.SenkouB[i+26])
+	}
+
+	// 迟行线为26根K线之后的收盘价
+	if ich.Chikou[10] != close[36] {
+		t.Errorf("Chikou[10] should be close[36]=%.2f, got %.2f", close[36], ich.Chikou[10])
+	}
+	if ich.Chikou[n-1] != 0 {
+		t.Errorf("Chikou should be empty for the last 26 bars")
+	}
+}
+
+func TestSessionVWAPResetsAtSessionStart(t *testing.T) {
+	ti := NewTechnicalIndicators()
+
+	// 6根小时K线跨越UTC零点，第4根开始新会话
+	start := time.Date(2025, 8, 1, 21, 0, 0, 0, time.UTC)
+	data := make([]types.OHLCV, 6)
+	for i := range data {
+		price := 100 + float64(i)*10
+		data[i] = types.OHLCV{Time: start.Add(time.Duration(i) * time.Hour), High: price, Low: price, Close: price, Volume: float64(i + 1)}
+	}
+
+	result := ti.SessionVWAP(data, 0)
+
+	// 第一个会话: (100*1 + 110*2) / 3
+	if math.Abs(result.VWAP[1]-320.0/3) > 1e-9 {
+		t.Errorf("VWAP[1]: expected %.4f, got %.4f", 320.0/3, result.VWAP[1])
+	}
+	// 新会话第一根K线的VWAP等于其典型价格，标准差为0
+	if result.VWAP[3] != 130 || result.StdDev[3] != 0 {
+		t.Errorf("VWAP should reset at UTC midnight, got %.4f (std %.4f)", result.VWAP[3], result.StdDev[3])
+	}
+	// (130*4 + 140*5) / 9 及成交量加权标准差
+	vwap := 1220.0 / 9
+	std := math.Sqrt((4*130*130+5*140*140)/9.0 - vwap*vwap)
+	if math.Abs(result.VWAP[4]-vwap) > 1e-9 || math.Abs(result.Upper2[4]-(vwap+2*std)) > 1e-9 {
+		t.Errorf("VWAP[4]: expected %.4f±2*%.4f, got %.4f/%.4f", vwap, std, result.VWAP[4], result.Upper2[4])
+	}
+
+	// 会话从UTC 22:00开始时，第2根K线即为新会话
+	shifted := ti.SessionVWAP(data, 22*time.Hour)
+	if shifted.VWAP[1] != 110 {
+		t.Errorf("VWAP with 22:00 session should reset at index 1, got %.4f", shifted.VWAP[1])
+	}
+
+	anchored := ti.AnchoredVWAP(data, AnchorIndex(data, start.Add(2*time.Hour)))
+	if anchored.VWAP[1] != 0 || anchored.VWAP[2] != 120 {
+		t.Errorf("anchored VWAP should start at index 2, got %.4f/%.4f", anchored.VWAP[1], anchored.VWAP[2])
+	}
+}
+
+func TestTrailingIndicatorsFlipOnReversal(t *testing.T) {
+	ti := NewTechnicalIndicators()
+
+	// 40根上涨后40根下跌
+	n := 80
+	high := make([]float64, n)
+	low := make([]float64, n)
+	close := make([]float64, n)
+	for i := 0; i < n; i++ {
+		price := 100 + float64(i)
+		if i >= 40 {
+			price = 140 - float64(i-40)*1.5
+		}
+		close[i] = price
+		high[i] = price + 1
+		low[i] = price - 1
+	}
+
+	tests := []struct {
+		name   string
+		result TrailingResult
+	}{
+		{"SuperTrend", ti.SuperTrend(high, low, close, 10, 3)},
+		{"ParabolicSAR", ti.ParabolicSAR(high, low, 0.02, 0.2)},
+	}
+
+	for _, tt := range tests {
+		if tt.result.Direction[39] != 1 || tt.result.Level[39] >= low[39] {
+			t.Errorf("%s should trail below price in the uptrend, got dir %d level %.2f", tt.name, tt.result.Direction[39], tt.result.Level[39])
+		}
+		flips := tt.result.FlipEvents(20)
+		if len(flips) != 1 || flips[0].Direction != -1 || flips[0].Index < 40 {
+			t.Errorf("%s should flip short once after the top, got %+v", tt.name, flips)
+		}
+		if tt.result.Level[n-1] <= high[n-1] {
+			t.Errorf("%s should trail above price in the downtrend, got %.2f", tt.name, tt.result.Level[n-1])
+		}
+	}
+}
+
+func TestTTMSqueezeReleasesInBreakoutDirection(t *testing.T) {
+	ti := NewTechnicalIndicators()
+
+	// 交替波动的宽幅K线后横盘收窄，再单边上涨
+	n := 90
+	high := make([]float64, n)
+	low := make([]float64, n)
+	close := make([]float64, n)
+	for i := 0; i < n; i++ {
+		price := 100.0
+		if i%2 == 0 {
+			price = 100.2
+		}
+		if i >= 70 {
+			price = 100 + float64(i-69)*1.5
+		}
+		close[i] = price
+		high[i] = price + 2
+		low[i] = price - 2
+	}
+
+	squeeze := ti.TTMSqueeze(high, low, close, 20, 2.0, 1.5)
+	if !squeeze.On[69] {
+		t.Fatalf("squeeze should be on during the tight range")
+	}
+
+	releases := squeeze.Releases(40)
+	if len(releases) == 0 || releases[0].Index < 70 || releases[0].Direction != 1 {
+		t.Fatalf("expected an upward release after index 70, got %+v", releases)
+	}
+
+	upper, _, lower := ti.DonchianChannels(high, low, 20)
+	if upper[n-1] != high[n-1] || lower[69] != 98 {
+		t.Errorf("unexpected Donchian channel: upper %.2f lower %.2f", upper[n-1], lower[69])
+	}
+
+	percentB, _ := ti.BollingerStats(close, 20, 2.0)
+	if percentB[n-1] <= 0.8 {
+		t.Errorf("%%B should be near the upper band in the breakout, got %.2f", percentB[n-1])
+	}
+}
+
+func TestVolumeProfile(t *testing.T) {
+	ti := NewTechnicalIndicators()
+
+	// 大部分成交集中在105附近，两端少量成交
+	var data []types.OHLCV
+	add := func(low, high, volume float64, count int) {
+		for i := 0; i < count; i++ {
+			data = append(data, types.OHLCV{High: high, Low: low, Close: (high + low) / 2, Volume: volume})
+		}
+	}
+	add(100, 101, 10, 5)
+	add(104.5, 105.5, 100, 20)
+	add(109, 110, 10, 5)
+
+	profile := ti.VolumeProfile(data, 0, 10, 0.7)
+
+	if profile.POC < 104 || profile.POC > 106 {
+		t.Errorf("POC should be near 105, got %.2f", profile.POC)
+	}
+	if profile.VAL > 104.5 || profile.VAH < 105.5 || profile.VAL < 102 || profile.VAH > 108 {
+		t.Errorf("value area should surround the volume cluster, got %.2f-%.2f", profile.VAL, profile.VAH)
+	}
+	if len(profile.HVN) != 1 || len(profile.LVN) != 2 {
+		t.Errorf("expected 1 HVN and 2 LVNs, got %v / %v", profile.HVN, profile.LVN)
+	}
+
+	total := 0.0
+	for _, bin := range profile.Bins {
+		total += bin.Volume
+	}
+	if math.Abs(total-2100) > 1e-6 {
+		t.Errorf("profile should distribute all volume, got %.2f", total)
+	}
+}
+
+func TestDetectCandlePatterns(t *testing.T) {
+	ti := NewTechnicalIndicators()
+
+	// 12根趋势K线后，一根小阴线被阳线吞没
+	build := func(step float64) []types.OHLCV {
+		var data []types.OHLCV
+		price := 100.0
+		for i := 0; i < 12; i++ {
+			data = append(data, types.OHLCV{Open: price, High: price + 2.5, Low: price - 2.5, Close: price + step})
+			price += step
+		}
+		data = append(data, types.OHLCV{Open: price, High: price + 0.5, Low: price - 1.5, Close: price - 1})
+		data = append(data, types.OHLCV{Open: price - 1.5, High: price + 2, Low: price - 2, Close: price + 1.5})
+		return data
+	}
+	findEngulfing := func(data []types.OHLCV) *types.CandlePattern {
+		for _, p := range ti.DetectCandlePatterns(data, len(data)-1) {
+			if p.Name == types.BullishEngulfing {
+				return &p
+			}
+		}
+		return nil
+	}
+
+	engulfing := findEngulfing(build(-2))
+	if engulfing == nil {
+		t.Fatalf("expected a bullish engulfing pattern after a downtrend")
+	}
+	if engulfing.Direction != 1 || !engulfing.ContextOK || engulfing.BarsAgo != 0 || engulfing.Candles != 2 {
+		t.Errorf("engulfing should be bullish with downtrend context, got %+v", *engulfing)
+	}
+
+	// 相同形态出现在上涨趋势中时不满足趋势背景
+	engulfing = findEngulfing(build(2))
+	if engulfing == nil {
+		t.Fatalf("expected a bullish engulfing pattern after an uptrend")
+	}
+	if engulfing.ContextOK {
+		t.Errorf("bullish engulfing after an uptrend should lack trend context")
+	}
+}
+
+// pathFromWaypoints 按折线路径生成K线，每段steps根
+func pathFromWaypoints(waypoints []float64, steps int) (high, low, close []float64) {
+	for w := 1; w < len(waypoints); w++ {
+		for s := 1; s <= steps; s++ {
+			price := waypoints[w-1] + (waypoints[w]-waypoints[w-1])*float64(s)/float64(steps)
+			close = append(close, price)
+			high = append(high, price+0.2)
+			low = append(low, price-0.2)
+		}
+	}
+	return high, low, close
+}
+
+func TestDetectChartPatterns(t *testing.T) {
+	ti := NewTechnicalIndicators()
+
+	find := func(patterns []types.ChartPattern, name types.ChartPatternType) *types.ChartPattern {
+		for i := range patterns {
+			if patterns[i].Name == name {
+				return &patterns[i]
+			}
+		}
+		return nil
+	}
+
+	// 双顶：两次触及110后跌破100的颈线
+	high, low, close := pathFromWaypoints([]float64{95, 100, 110, 100, 110, 97}, 8)
+	p := find(ti.DetectChartPatterns(high, low, close), types.DoubleTop)
+	if p == nil {
+		t.Fatalf("expected a double top")
+	}
+	if p.Direction != -1 || p.Status != types.PatternBrokeDown || math.Abs(p.BreakoutLevel-99.8) > 0.01 || math.Abs(p.Target-89.4) > 0.01 {
+		t.Errorf("unexpected double top: %+v", *p)
+	}
+
+	// 双底：双顶的镜像，两次触及90后反弹到95，尚未突破100的颈线
+	high, low, close = pathFromWaypoints([]float64{105, 100, 90, 100, 90, 95}, 8)
+	p = find(ti.DetectChartPatterns(high, low, close), types.DoubleBottom)
+	if p == nil {
+		t.Fatalf("expected a double bottom")
+	}
+	if p.Direction != 1 || p.Status != types.PatternForming || math.Abs(p.BreakoutLevel-100.2) > 0.01 || math.Abs(p.InvalidationLevel-89.8) > 0.01 {
+		t.Errorf("unexpected double bottom: %+v", *p)
+	}
+
+	// 三重底：三次触及90后突破100的颈线
+	high, low, close = pathFromWaypoints([]float64{105, 100, 90, 100, 90, 100, 90, 103}, 8)
+	p = find(ti.DetectChartPatterns(high, low, close), types.TripleBottom)
+	if p == nil {
+		t.Fatalf("expected a triple bottom")
+	}
+	if p.Direction != 1 || p.Status != types.PatternBrokeOut || math.Abs(p.BreakoutLevel-100.2) > 0.01 {
+		t.Errorf("unexpected triple bottom: %+v", *p)
+	}
+
+	// 头肩底：左肩90、头80、右肩90，颈线100，价格尚未突破
+	high, low, close = pathFromWaypoints([]float64{105, 100, 90, 100, 80, 100, 90, 96}, 8)
+	p = find(ti.DetectChartPatterns(high, low, close), types.InverseHeadAndShoulders)
+	if p == nil {
+		t.Fatalf("expected an inverse head and shoulders")
+	}
+	if p.Direction != 1 || p.Status != types.PatternForming || math.Abs(p.BreakoutLevel-100.2) > 0.01 || math.Abs(p.InvalidationLevel-79.8) > 0.01 {
+		t.Errorf("unexpected inverse head and shoulders: %+v", *p)
+	}
+}
+
+func TestSwingSRLevels(t *testing.T) {
+	ti := NewTechnicalIndicators()
+
+	// 在100-110之间来回震荡三次，最后回到105
+	high, low, close := pathFromWaypoints([]float64{105, 110, 100, 110, 100, 110, 100, 105}, 8)
+	data := make([]types.OHLCV, len(close))
+	for i := range close {
+		data[i] = types.OHLCV{Open: close[i], High: high[i], Low: low[i], Close: close[i], Volume: 1000}
+	}
+
+	levels := ti.SwingSRLevels(data, []int{2, 5, 10}, 0.5)
+	levels = MergeKeyLevels(levels, types.KeyLevels{
+		Psychological:        []float64{120},
+		HistoricalResistance: []float64{110},
+	}, 0.5)
+
+	resistance, support := NearestSRLevels(levels, close[len(close)-1])
+	if math.Abs(resistance.Price-110.2) > 0.01 || resistance.Touches != 3 {
+		t.Fatalf("expected resistance at 110.2 with 3 touches, got %+v", resistance)
+	}
+	if resistance.Source != types.SwingSource+"+"+types.HistoricalResistanceSource {
+		t.Errorf("expected configured resistance merged into swing level, got %q", resistance.Source)
+	}
+	if math.Abs(support.Price-99.8) > 0.01 || support.Touches != 3 {
+		t.Fatalf("expected support at 99.8 with 3 touches, got %+v", support)
+	}
+	if resistance.Score <= support.Score {
+		t.Errorf("confluence with a key level should score higher: %.2f vs %.2f", resistance.Score, support.Score)
+	}
+
+	last := levels[len(levels)-1]
+	if last.Price != 120 || last.Source != types.PsychologicalSource || last.LastBarsAgo != -1 {
+		t.Errorf("expected untested psychological level at 120, got %+v", last)
+	}
+}
+
+func TestPeriodPivots(t *testing.T) {
+	ti := NewTechnicalIndicators()
+
+	// 两个完整UTC日加上第三天的两根小时K线（以+08:00时区给出）
+	loc := time.FixedZone("UTC+8", 8*3600)
+	start := time.Date(2024, 3, 4, 8, 0, 0, 0, loc) // UTC 00:00
+	data := make([]types.OHLCV, 0)
+	for i := 0; i < 50; i++ {
+		price := 100.0 + float64(i%24)
+		data = append(data, types.OHLCV{Time: start.Add(time.Duration(i) * time.Hour), Open: price, High: price + 1, Low: price - 1, Close: price})
+	}
+
+	days := AggregatePeriods(data, PivotDaily)
+	if len(days) != 3 {
+		t.Fatalf("expected 3 daily candles, got %d", len(days))
+	}
+
+	// 前一日：开100 高124 低99 收123，当日开盘100
+	sr, periodStart, partial, ok := ti.PeriodPivots(data, PivotFloor, PivotDaily)
+	if !ok || partial || !periodStart.Equal(time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected pivot period %v partial=%v ok=%v", periodStart, partial, ok)
+	}
+	pivot := (124.0 + 99 + 123) / 3
+	if math.Abs(sr.Pivot-pivot) > 1e-9 || math.Abs(sr.Resistance["R4"]-(124+2*(pivot-99)+25)) > 1e-9 {
+		t.Errorf("unexpected floor pivots: %+v", sr)
+	}
+
+	woodie, _, _, _ := ti.PeriodPivots(data, PivotWoodie, PivotDaily)
+	if math.Abs(woodie.Pivot-(124.0+99+2*100)/4) > 1e-9 {
+		t.Errorf("woodie pivot should weight the current open, got %.4f", woodie.Pivot)
+	}
+
+	demark, _, _, _ := ti.PeriodPivots(data, PivotDeMark, PivotDaily)
+	x := 2*124.0 + 99 + 123 // 收盘高于开盘
+	if math.Abs(demark.Resistance["R1"]-(x/2-99)) > 1e-9 || len(demark.Support) != 1 {
+		t.Errorf("unexpected demark pivots: %+v", demark)
+	}
+
+	// 只有一周的数据时没有已完成的周
+	if _, _, _, ok := ti.PeriodPivots(data, PivotFloor, PivotWeekly); ok {
+		t.Error("expected no completed week")
+	}
+}
+
+func TestFibonacciFromZigZagSwing(t *testing.T) {
+	ti := NewTechnicalIndicators()
+
+	// 回落至90后上涨到120，再回撤到105
+	high, low, _ := pathFromWaypoints([]float64{100, 90, 120, 105}, 8)
+
+	pivots := ti.ZigZag(high, low, 5)
+	if len(pivots) != 4 || pivots[1].IsHigh || !pivots[2].IsHigh || pivots[3].IsHigh {
+		t.Fatalf("expected high/low/high/low zigzag, got %+v", pivots)
+	}
+
+	swingHigh, swingLow, ok := ti.DominantSwing(high, low, 0, 5)
+	if !ok || math.Abs(swingHigh.Price-120.2) > 1e-9 || math.Abs(swingLow.Price-89.8) > 1e-9 {
+		t.Fatalf("expected dominant swing 89.8 -> 120.2, got %+v %+v", swingLow, swingHigh)
+	}
+	if swingLow.Index >= swingHigh.Index {
+		t.Fatalf("expected an upswing, low at %d high at %d", swingLow.Index, swingHigh.Index)
+	}
+
+	retracements, extensions := FibonacciLevels(swingHigh.Price, swingLow.Price, true)
+	if math.Abs(retracements[2].Price-105.0) > 1e-9 || math.Abs(retracements[3].Price-101.4128) > 1e-9 {
+		t.Errorf("unexpected retracements: %+v", retracements)
+	}
+	if math.Abs(extensions[0].Price-128.4688) > 1e-9 {
+		t.Errorf("unexpected 127.2%% extension: %+v", extensions[0])
+	}
+
+	// 下跌波段的扩展位于低点下方
+	_, down := FibonacciLevels(120, 100, false)
+	if math.Abs(down[1].Price-(100-0.618*20)) > 1e-9 {
+		t.Errorf("unexpected downswing extension: %+v", down[1])
+	}
+}
+
+func TestIndicatorRegistry(t *testing.T) {
+	registry := DefaultRegistry()
+
+	data := make([]types.OHLCV, 60)
+	for i := range data {
+		price := 100 + float64(i%7)
+		data[i] = types.OHLCV{Open: price, High: price + 1, Low: price - 1, Close: price, Volume: float64(100 + i)}
+	}
+	closes := make([]float64, len(data))
+	volumes := make([]float64, len(data))
+	for i, candle := range data {
+		closes[i] = candle.Close
+		volumes[i] = candle.Volume
+	}
+	ti := NewTechnicalIndicators()
+
+	// 位置参数、命名参数和默认值解析为同一规范规格
+	for _, text := range []string{"rsi(9)", "RSI(period=9)", "rsi(close, 9)"} {
+		result, err := registry.Compute(text, data)
+		if err != nil {
+			t.Fatalf("%s: %v", text, err)
+		}
+		if result.Spec.String() != "rsi(period=9)" || result.WarmUp != 9 {
+			t.Errorf("%s resolved to %s warm-up %d", text, result.Spec, result.WarmUp)
+		}
+		if value, _ := result.Last(); math.Abs(value-ti.RSI(closes, 9)) > 1e-9 {
+			t.Errorf("%s = %.4f, expected %.4f", text, value, ti.RSI(closes, 9))
+		}
+	}
+
+	volumeMA, err := registry.Compute("sma(volume,20)", data)
+	if err != nil || volumeMA.Spec.String() != "sma(source=volume,period=20)" {
+		t.Fatalf("unexpected volume sma: %v %v", volumeMA, err)
+	}
+	if value, _ := volumeMA.Last(); math.Abs(value-ti.SMA(volumes, 20)[59]) > 1e-9 {
+		t.Errorf("volume sma = %.4f", value)
+	}
+
+	signal, err := registry.Compute("macd(fast=8).signal", data)
+	if err != nil || signal.Spec.String() != "macd(fast=8,slow=26,signal=9).signal" {
+		t.Fatalf("unexpected macd spec: %v %v", signal, err)
+	}
+	_, signalLine, _ := ti.MACDSeries(closes, 8, 26, 9)
+	if value, _ := signal.Last(); value != signalLine[59] {
+		t.Errorf("macd signal = %.4f, expected %.4f", value, signalLine[59])
+	}
+
+	// 规格列表只在括号外拆分
+	if specs := SplitSpecs(" rsi(period=9), macd(8,21,5).signal,,ema "); !reflect.DeepEqual(specs, []string{"rsi(period=9)", "macd(8,21,5).signal", "ema"}) {
+		t.Errorf("SplitSpecs = %q", specs)
+	}
+
+	for _, bad := range []string{"rsi(length=9)", "rsi(0)", "atr(volume)", "macd().foo", "nope(1)", "rsi(9", "sma(close, 2.5)", "macd(fast=8.5)"} {
+		if _, err := registry.Compute(bad, data); err == nil {
+			t.Errorf("expected %q to be rejected", bad)
+		}
+	}
+	// 非整数参数只允许用于倍数等连续参数
+	if bands, err := registry.Compute("bbands(20, 2.5)", data); err != nil || bands.Spec.String() != "bbands(period=20,stddev=2.5)" {
+		t.Errorf("unexpected bbands spec: %v %v", bands, err)
+	}
+
+	// 第三方指标注册后可按规格使用，名称不能重复
+	custom := NewRegistry()
+	range_ := Definition{
+		Name:    "range",
+		Params:  []ParamSpec{{Name: "scale", Default: 1}},
+		Inputs:  []Input{InputHigh, InputLow},
+		Outputs: []string{"range"},
+		Compute: func(in Inputs, p Params) [][]float64 {
+			out := make([]float64, len(in[InputHigh]))
+			for i := range out {
+				out[i] = (in[InputHigh][i] - in[InputLow][i]) * p["scale"]
+			}
+			return [][]float64{out}
+		},
+	}
+	if err := custom.Register(range_); err != nil {
+		t.Fatal(err)
+	}
+	if err := custom.Register(range_); err == nil {
+		t.Error("expected duplicate registration to fail")
+	}
+	result, err := custom.Compute("range(scale=3)", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value, ok := result.Last(); !ok || value != 6 {
+		t.Errorf("custom indicator = %.2f ok=%v, expected 6", value, ok)
+	}
+}
+
+func TestMarketStructure(t *testing.T) {
+	ti := NewTechnicalIndicators()
+
+	// 上涨结构（HH/HL）之后跌破最近的更高低点，形成CHoCH
+	waypoints := []float64{100, 110, 105, 115, 109, 120, 112, 116, 104}
+	closes := []float64{waypoints[0]}
+	for i := 1; i < len(waypoints); i++ {
+		for step := 1; step <= 5; step++ {
+			closes = append(closes, waypoints[i-1]+(waypoints[i]-waypoints[i-1])*float64(step)/5)
+		}
+	}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	data := make([]types.OHLCV, len(closes))
+	for i, c := range closes {
+		open := c
+		if i > 0 {
+			open = closes[i-1]
+		}
+		data[i] = types.OHLCV{Time: start.Add(time.Duration(i) * time.Hour), Open: open, High: c + 0.5, Low: c - 0.5, Close: c, Volume: 1}
+	}
+
+	ms := ti.MarketStructure(data, 2)
+	if !ms.Available {
+		t.Fatal("expected market structure")
+	}
+
+	// 第一组高低点没有可比较的前一点
+	expected := []types.SwingLabel{"", "", types.HigherHigh, types.HigherLow, types.HigherHigh, types.HigherLow, types.LowerHigh}
+	if len(ms.Swings) != len(expected) {
+		t.Fatalf("expected %d swings, got %+v", len(expected), ms.Swings)
+	}
+	for i, swing := range ms.Swings {
+		if swing.Label != expected[i] {
+			t.Errorf("swing %d at %.2f: expected %q, got %q", i, swing.Price, expected[i], swing.Label)
+		}
+	}
+
+	bullishBOS := 0
+	for _, ev := range ms.Events {
+		if ev.Bullish && ev.Type == types.BreakOfStructure {
+			bullishBOS++
+		}
+	}
+	if bullishBOS != 2 {
+		t.Errorf("expected 2 bullish BOS, got %d (%+v)", bullishBOS, ms.Events)
+	}
+
+	ev := ms.LastEvent
+	if ev == nil || ev.Type != types.ChangeOfCharacter || ev.Bullish || math.Abs(ev.Level-111.5) > 1e-9 {
+		t.Fatalf("expected bearish CHoCH at 111.5, got %+v", ev)
+	}
+	if ms.Bias != -1 || ms.Trend != types.Downtrend || math.Abs(ms.ProtectedLevel-116.5) > 1e-9 {
+		t.Errorf("bias %d trend %s protected %.2f", ms.Bias, ms.Trend, ms.ProtectedLevel)
+	}
+}
+
+func TestHeikinAshiAndRenko(t *testing.T) {
+	ti := NewTechnicalIndicators()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	closes := []float64{100, 101, 103, 102, 106, 104, 101, 99, 100, 95}
+	data := make([]types.OHLCV, len(closes))
+	for i, c := range closes {
+		open := 100.0
+		if i > 0 {
+			open = closes[i-1]
+		}
+		data[i] = types.OHLCV{Time: start.Add(time.Duration(i) * time.Hour), Open: open, High: math.Max(open, c) + 0.5, Low: math.Min(open, c) - 0.5, Close: c, Volume: 10}
+	}
+
+	ha := ti.HeikinAshi(data)
+	if len(ha) != len(data) {
+		t.Fatalf("expected %d Heikin-Ashi candles, got %d", len(data), len(ha))
+	}
+	for i, candle := range ha {
+		expectedClose := (data[i].Open + data[i].High + data[i].Low + data[i].Close) / 4
+		if math.Abs(candle.Close-expectedClose) > 1e-9 || candle.High < math.Max(candle.Open, candle.Close) || candle.Low > math.Min(candle.Open, candle.Close) {
+			t.Errorf("bad Heikin-Ashi candle %d: %+v", i, candle)
+		}
+		if i > 0 && math.Abs(candle.Open-(ha[i-1].Open+ha[i-1].Close)/2) > 1e-9 {
+			t.Errorf("Heikin-Ashi open %d not the midpoint of the previous body", i)
+		}
+	}
+
+	// 砖块2：100→102→104→106 三块上涨；回落到101需跌破104-2=102才反转，形成104→102、102→100两块；
+	// 之后99、100不足一块，95形成100→98、98→96两块
+	bricks := ti.Renko(data, 2)
+	expected := [][2]float64{{100, 102}, {102, 104}, {104, 106}, {104, 102}, {102, 100}, {100, 98}, {98, 96}}
+	if len(bricks) != len(expected) {
+		t.Fatalf("expected %d bricks, got %+v", len(expected), bricks)
+	}
+	totalVolume := 0.0
+	for i, brick := range bricks {
+		if brick.Open != expected[i][0] || brick.Close != expected[i][1] {
+			t.Errorf("brick %d: expected %v, got %.0f→%.0f", i, expected[i], brick.Open, brick.Close)
+		}
+		totalVolume += brick.Volume
+	}
+	if totalVolume != 100 {
+		t.Errorf("brick volume should sum to the input volume, got %.0f", totalVolume)
+	}
+
+	transform, err := NewTransform("renko", 0, 3)
+	if err != nil || !transform.Active() || len(transform.Apply(data)) == 0 {
+		t.Errorf("ATR Renko transform failed: %v", err)
+	}
+	if _, err := NewTransform("kagi", 0, 14); err == nil {
+		t.Error("expected unknown transform to be rejected")
+	}
+}
@@ -0,0 +1,175 @@
+package indicators
+
+import "math"
+
+// TrailingResult 跟踪止损类指标序列（SuperTrend / 抛物线SAR）
+// Level为每根K线收盘后的止损位，Direction为1（多头）、-1（空头）或0（预热期）
+type TrailingResult struct {
+	Level     []float64
+	Direction []int
+}
+
+// TrendFlip 跟踪指标的方向翻转事件
+type TrendFlip struct {
+	Index     int
+	Direction int // 1 翻多, -1 翻空
+	Level     float64
+}
+
+// ATRSeries 计算ATR序列（Wilder平滑），前period根K线为0
+func (ti *TechnicalIndicators) ATRSeries(high, low, close []float64, period int) []float64 {
+	atr := make([]float64, len(close))
+	if period <= 0 || len(high) < period+1 || len(low) < period+1 || len(close) < period+1 {
+		return atr
+	}
+
+	tr := make([]float64, len(close))
+	for i := 1; i < len(close); i++ {
+		hl := high[i] - low[i]
+		hc := math.Abs(high[i] - close[i-1])
+		lc := math.Abs(low[i] - close[i-1])
+		tr[i] = math.Max(hl, math.Max(hc, lc))
+	}
+
+	sum := 0.0
+	for i := 1; i <= period; i++ {
+		sum += tr[i]
+	}
+	atr[period] = sum / float64(period)
+
+	for i := period + 1; i < len(tr); i++ {
+		atr[i] = (atr[i-1]*float64(period-1) + tr[i]) / float64(period)
+	}
+
+	return atr
+}
+
+// SuperTrend 计算SuperTrend序列
+// 上下轨为(H+L)/2 ± multiplier*ATR，轨道只向趋势方向收紧，收盘突破反向轨道时翻转
+func (ti *TechnicalIndicators) SuperTrend(high, low, close []float64, atrPeriod int, multiplier float64) TrailingResult {
+	n := len(close)
+	result := TrailingResult{
+		Level:     make([]float64, n),
+		Direction: make([]int, n),
+	}
+	if n < atrPeriod+1 {
+		return result
+	}
+
+	atr := ti.ATRSeries(high, low, close, atrPeriod)
+
+	var upper, lower float64
+	direction := 0
+	for i := atrPeriod; i < n; i++ {
+		hl2 := (high[i] + low[i]) / 2
+		basicUpper := hl2 + multiplier*atr[i]
+		basicLower := hl2 - multiplier*atr[i]
+
+		if direction == 0 {
+			upper, lower = basicUpper, basicLower
+			direction = 1
+			if close[i] < hl2 {
+				direction = -1
+			}
+		} else {
+			if basicUpper < upper || close[i-1] > upper {
+				upper = basicUpper
+			}
+			if basicLower > lower || close[i-1] < lower {
+				lower = basicLower
+			}
+
+			if direction == 1 && close[i] < lower {
+				direction = -1
+			} else if direction == -1 && close[i] > upper {
+				direction = 1
+			}
+		}
+
+		result.Direction[i] = direction
+		if direction == 1 {
+			result.Level[i] = lower
+		} else {
+			result.Level[i] = upper
+		}
+	}
+
+	return result
+}
+
+// ParabolicSAR 计算抛物线SAR序列（Wilder）
+// 加速因子从step开始，每创新极值增加step，最高maxStep；价格触及SAR时翻转并以前一极值为新SAR
+func (ti *TechnicalIndicators) ParabolicSAR(high, low []float64, step, maxStep float64) TrailingResult {
+	n := len(high)
+	result := TrailingResult{
+		Level:     make([]float64, n),
+		Direction: make([]int, n),
+	}
+	if n < 3 || len(low) < n {
+		return result
+	}
+
+	// 以第二根K线相对第一根的方向作为初始趋势
+	up := high[1]+low[1] >= high[0]+low[0]
+	af := step
+	var sar, ep float64
+	if up {
+		sar, ep = low[0], high[1]
+	} else {
+		sar, ep = high[0], low[1]
+	}
+	result.Level[1] = sar
+	result.Direction[1] = sarDirection(up)
+
+	for i := 2; i < n; i++ {
+		sar += af * (ep - sar)
+
+		if up {
+			// SAR不能进入前两根K线的区间
+			sar = math.Min(sar, math.Min(low[i-1], low[i-2]))
+			if low[i] < sar {
+				up = false
+				sar, ep, af = ep, low[i], step
+			} else if high[i] > ep {
+				ep = high[i]
+				af = math.Min(af+step, maxStep)
+			}
+		} else {
+			sar = math.Max(sar, math.Max(high[i-1], high[i-2]))
+			if high[i] > sar {
+				up = true
+				sar, ep, af = ep, high[i], step
+			} else if low[i] < ep {
+				ep = low[i]
+				af = math.Min(af+step, maxStep)
+			}
+		}
+
+		result.Level[i] = sar
+		result.Direction[i] = sarDirection(up)
+	}
+
+	return result
+}
+
+func sarDirection(up bool) int {
+	if up {
+		return 1
+	}
+	return -1
+}
+
+// FlipEvents 返回从start开始的方向翻转事件
+func (r TrailingResult) FlipEvents(start int) []TrendFlip {
+	var flips []TrendFlip
+	if start < 1 {
+		start = 1
+	}
+	for i := start; i < len(r.Direction); i++ {
+		prev, curr := r.Direction[i-1], r.Direction[i]
+		if prev != 0 && curr != 0 && prev != curr {
+			flips = append(flips, TrendFlip{Index: i, Direction: curr, Level: r.Level[i]})
+		}
+	}
+	return flips
+}
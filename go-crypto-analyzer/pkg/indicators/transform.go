@@ -0,0 +1,168 @@
+package indicators
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/zjc/go-crypto-analyzer/pkg/i18n"
+	"github.com/zjc/go-crypto-analyzer/pkg/types"
+)
+
+// TransformKind K线序列变换类型
+type TransformKind string
+
+const (
+	TransformNone       TransformKind = "none"
+	TransformHeikinAshi TransformKind = "heikin-ashi"
+	TransformRenko      TransformKind = "renko"
+)
+
+// ParseTransformKind 解析变换名称，空字符串视为不变换
+func ParseTransformKind(name string) (TransformKind, error) {
+	switch TransformKind(name) {
+	case "", TransformNone:
+		return TransformNone, nil
+	case TransformHeikinAshi, "ha":
+		return TransformHeikinAshi, nil
+	case TransformRenko:
+		return TransformRenko, nil
+	}
+	return "", fmt.Errorf("unknown transform %q (heikin-ashi|renko)", name)
+}
+
+// Transform K线序列变换，输出仍为[]types.OHLCV，可直接交给分析器
+// Renko砖块大小固定为BrickSize，为0时取输入数据最新的ATR(ATRPeriod)
+type Transform struct {
+	Kind      TransformKind
+	BrickSize float64
+	ATRPeriod int
+}
+
+// NewTransform 由命令行参数构造变换，brickSize为0时Renko使用ATR(atrPeriod)砖块
+func NewTransform(kind string, brickSize float64, atrPeriod int) (Transform, error) {
+	parsed, err := ParseTransformKind(kind)
+	if err != nil {
+		return Transform{}, err
+	}
+	if brickSize < 0 || atrPeriod < 1 {
+		return Transform{}, fmt.Errorf("invalid renko brick size %g / ATR period %d", brickSize, atrPeriod)
+	}
+	return Transform{Kind: parsed, BrickSize: brickSize, ATRPeriod: atrPeriod}, nil
+}
+
+// Active 是否需要变换
+func (t Transform) Active() bool {
+	return t.Kind != "" && t.Kind != TransformNone
+}
+
+// String 返回变换的显示文本
+func (t Transform) String() string {
+	switch t.Kind {
+	case TransformHeikinAshi:
+		return i18n.T("transform.heikin_ashi")
+	case TransformRenko:
+		if t.BrickSize > 0 {
+			return i18n.T("transform.renko", t.BrickSize)
+		}
+		return i18n.T("transform.renko_atr", t.ATRPeriod)
+	}
+	return i18n.T("transform.none")
+}
+
+// Apply 对K线序列执行变换
+func (t Transform) Apply(data []types.OHLCV) []types.OHLCV {
+	ti := NewTechnicalIndicators()
+	switch t.Kind {
+	case TransformHeikinAshi:
+		return ti.HeikinAshi(data)
+	case TransformRenko:
+		brick := t.BrickSize
+		if brick <= 0 {
+			brick = ti.RenkoATRBrickSize(data, t.ATRPeriod)
+		}
+		return ti.Renko(data, brick)
+	}
+	return data
+}
+
+// HeikinAshi 计算平均K线：
+// 收盘=(O+H+L+C)/4，开盘=(前开盘+前收盘)/2，高低包含实际高低点
+func (ti *TechnicalIndicators) HeikinAshi(data []types.OHLCV) []types.OHLCV {
+	ha := make([]types.OHLCV, len(data))
+	for i, candle := range data {
+		close := (candle.Open + candle.High + candle.Low + candle.Close) / 4
+		open := (candle.Open + candle.Close) / 2
+		if i > 0 {
+			open = (ha[i-1].Open + ha[i-1].Close) / 2
+		}
+		ha[i] = types.OHLCV{
+			Time:   candle.Time,
+			Open:   open,
+			High:   math.Max(candle.High, math.Max(open, close)),
+			Low:    math.Min(candle.Low, math.Min(open, close)),
+			Close:  close,
+			Volume: candle.Volume,
+		}
+	}
+	return ha
+}
+
+// RenkoATRBrickSize 以最新的ATR作为Renko砖块大小，数据不足ATR周期时取平均振幅
+func (ti *TechnicalIndicators) RenkoATRBrickSize(data []types.OHLCV, period int) float64 {
+	if len(data) == 0 {
+		return 0
+	}
+	if period < 1 {
+		period = 14
+	}
+	highs := make([]float64, len(data))
+	lows := make([]float64, len(data))
+	closes := make([]float64, len(data))
+	for i, candle := range data {
+		highs[i], lows[i], closes[i] = candle.High, candle.Low, candle.Close
+	}
+	if len(data) > period {
+		atr := ti.ATRSeries(highs, lows, closes, period)
+		return atr[len(atr)-1]
+	}
+
+	sum := 0.0
+	for i := range data {
+		sum += highs[i] - lows[i]
+	}
+	return sum / float64(len(data))
+}
+
+// Renko 按收盘价生成砖块：同向需要超过一个砖块，反向需要超过两个砖块（从前一砖块的另一端起算）。
+// 砖块时间为形成它的K线时间，成交量为自上一砖块以来的累计量，同一根K线形成多块时均分
+func (ti *TechnicalIndicators) Renko(data []types.OHLCV, brickSize float64) []types.OHLCV {
+	bricks := make([]types.OHLCV, 0)
+	if len(data) == 0 || brickSize <= 0 {
+		return bricks
+	}
+
+	// hi/lo为最后一块砖的上下沿，初始为零高度
+	hi, lo := data[0].Close, data[0].Close
+	volume := 0.0
+	for _, candle := range data {
+		volume += candle.Volume
+		formed := make([]types.OHLCV, 0)
+		for candle.Close >= hi+brickSize {
+			formed = append(formed, types.OHLCV{Time: candle.Time, Open: hi, High: hi + brickSize, Low: hi, Close: hi + brickSize})
+			lo, hi = hi, hi+brickSize
+		}
+		for candle.Close <= lo-brickSize {
+			formed = append(formed, types.OHLCV{Time: candle.Time, Open: lo, High: lo, Low: lo - brickSize, Close: lo - brickSize})
+			hi, lo = lo, lo-brickSize
+		}
+		if len(formed) == 0 {
+			continue
+		}
+		for i := range formed {
+			formed[i].Volume = volume / float64(len(formed))
+		}
+		bricks = append(bricks, formed...)
+		volume = 0
+	}
+	return bricks
+}
@@ -0,0 +1,144 @@
+package indicators
+
+import (
+	"math"
+
+	"github.com/zjc/go-crypto-analyzer/pkg/types"
+)
+
+// VolumeBin 成交量分布中的一个价格区间
+type VolumeBin struct {
+	Low    float64
+	High   float64
+	Volume float64
+}
+
+// Mid 区间中点价格
+func (b VolumeBin) Mid() float64 {
+	return (b.Low + b.High) / 2
+}
+
+// VolumeProfileResult 成交量分布结果
+type VolumeProfileResult struct {
+	Bins []VolumeBin // 按价格从低到高
+	POC  float64     // 成交量最大的价格（控制点）
+	VAH  float64     // 价值区上沿
+	VAL  float64     // 价值区下沿
+	HVN  []float64   // 高成交量节点，按价格从低到高
+	LVN  []float64   // 低成交量节点，按价格从低到高
+}
+
+// VolumeProfile 计算最近lookback根K线的成交量分布
+// 每根K线的成交量按其最高最低价区间与价格区间的重叠比例分配，
+// 价值区从POC向两侧扩展，每次纳入成交量较大的一侧，直到覆盖valueAreaPct的成交量
+func (ti *TechnicalIndicators) VolumeProfile(data []types.OHLCV, lookback, bins int, valueAreaPct float64) VolumeProfileResult {
+	if lookback <= 0 || lookback > len(data) {
+		lookback = len(data)
+	}
+	if lookback == 0 || bins <= 0 {
+		return VolumeProfileResult{}
+	}
+	window := data[len(data)-lookback:]
+
+	low, high := window[0].Low, window[0].High
+	for _, candle := range window {
+		low = math.Min(low, candle.Low)
+		high = math.Max(high, candle.High)
+	}
+	if high <= low {
+		return VolumeProfileResult{}
+	}
+
+	step := (high - low) / float64(bins)
+	profile := make([]VolumeBin, bins)
+	for i := range profile {
+		profile[i].Low = low + float64(i)*step
+		profile[i].High = profile[i].Low + step
+	}
+
+	binIndex := func(price float64) int {
+		idx := int((price - low) / step)
+		if idx >= bins {
+			idx = bins - 1
+		}
+		if idx < 0 {
+			idx = 0
+		}
+		return idx
+	}
+
+	totalVolume := 0.0
+	for _, candle := range window {
+		totalVolume += candle.Volume
+		rangeSize := candle.High - candle.Low
+		if rangeSize <= 0 {
+			profile[binIndex(candle.Close)].Volume += candle.Volume
+			continue
+		}
+		for i := binIndex(candle.Low); i <= binIndex(candle.High); i++ {
+			overlap := math.Min(candle.High, profile[i].High) - math.Max(candle.Low, profile[i].Low)
+			if overlap > 0 {
+				profile[i].Volume += candle.Volume * overlap / rangeSize
+			}
+		}
+	}
+
+	result := VolumeProfileResult{Bins: profile}
+	if totalVolume == 0 {
+		return result
+	}
+
+	// 控制点
+	poc := 0
+	for i, bin := range profile {
+		if bin.Volume > profile[poc].Volume {
+			poc = i
+		}
+	}
+	result.POC = profile[poc].Mid()
+
+	// 价值区
+	lo, hi := poc, poc
+	covered := profile[poc].Volume
+	for covered < totalVolume*valueAreaPct && (lo > 0 || hi < bins-1) {
+		below, above := -1.0, -1.0
+		if lo > 0 {
+			below = profile[lo-1].Volume
+		}
+		if hi < bins-1 {
+			above = profile[hi+1].Volume
+		}
+		if above >= below {
+			hi++
+			covered += above
+		} else {
+			lo--
+			covered += below
+		}
+	}
+	result.VAL = profile[lo].Low
+	result.VAH = profile[hi].High
+
+	// 高/低成交量节点：平滑后的局部极值
+	smoothed := make([]float64, bins)
+	for i := range profile {
+		sum, count := 0.0, 0
+		for j := i - 1; j <= i+1; j++ {
+			if j >= 0 && j < bins {
+				sum += profile[j].Volume
+				count++
+			}
+		}
+		smoothed[i] = sum / float64(count)
+	}
+	mean := totalVolume / float64(bins)
+	for i := 1; i < bins-1; i++ {
+		if smoothed[i] > smoothed[i-1] && smoothed[i] >= smoothed[i+1] && smoothed[i] > 1.2*mean {
+			result.HVN = append(result.HVN, profile[i].Mid())
+		}
+		if smoothed[i] < smoothed[i-1] && smoothed[i] <= smoothed[i+1] && smoothed[i] < 0.6*mean {
+			result.LVN = append(result.LVN, profile[i].Mid())
+		}
+	}
+	return result
+}
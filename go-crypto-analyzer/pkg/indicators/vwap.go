@@ -0,0 +1,143 @@
+package indicators
+
+import (
+	"math"
+	"time"
+
+	"github.com/zjc/go-crypto-analyzer/pkg/types"
+)
+
+// VWAPResult VWAP及其标准差通道序列，未覆盖的K线值为0
+type VWAPResult struct {
+	VWAP   []float64
+	StdDev []float64
+	Upper1 []float64
+	Lower1 []float64
+	Upper2 []float64
+	Lower2 []float64
+}
+
+func newVWAPResult(n int) VWAPResult {
+	return VWAPResult{
+		VWAP:   make([]float64, n),
+		StdDev: make([]float64, n),
+		Upper1: make([]float64, n),
+		Lower1: make([]float64, n),
+		Upper2: make([]float64, n),
+		Lower2: make([]float64, n),
+	}
+}
+
+// vwapAccumulator 累计成交量加权的典型价格及其平方，用于计算VWAP和成交量加权标准差
+type vwapAccumulator struct {
+	volume float64
+	pv     float64
+	pv2    float64
+}
+
+func (acc *vwapAccumulator) reset() {
+	*acc = vwapAccumulator{}
+}
+
+func (acc *vwapAccumulator) add(candle types.OHLCV) {
+	tp := (candle.High + candle.Low + candle.Close) / 3
+	acc.volume += candle.Volume
+	acc.pv += tp * candle.Volume
+	acc.pv2 += tp * tp * candle.Volume
+}
+
+func (acc *vwapAccumulator) fill(result VWAPResult, i int, fallback float64) {
+	if acc.volume == 0 {
+		// 无成交量时退化为典型价格
+		result.VWAP[i] = fallback
+		result.Upper1[i], result.Lower1[i] = fallback, fallback
+		result.Upper2[i], result.Lower2[i] = fallback, fallback
+		return
+	}
+
+	vwap := acc.pv / acc.volume
+	std := math.Sqrt(math.Max(acc.pv2/acc.volume-vwap*vwap, 0))
+
+	result.VWAP[i] = vwap
+	result.StdDev[i] = std
+	result.Upper1[i] = vwap + std
+	result.Lower1[i] = vwap - std
+	result.Upper2[i] = vwap + 2*std
+	result.Lower2[i] = vwap - 2*std
+}
+
+// SessionStart 返回K线所属会话的开始时间
+// 会话在每天UTC零点加上sessionOffset时重置（如8h表示UTC 08:00）
+func SessionStart(t time.Time, sessionOffset time.Duration) time.Time {
+	shifted := t.UTC().Add(-sessionOffset)
+	day := time.Date(shifted.Year(), shifted.Month(), shifted.Day(), 0, 0, 0, 0, time.UTC)
+	return day.Add(sessionOffset).In(t.Location())
+}
+
+// SessionVWAP 计算会话VWAP及±1σ/±2σ通道，每个会话开始时重新累计
+func (ti *TechnicalIndicators) SessionVWAP(data []types.OHLCV, sessionOffset time.Duration) VWAPResult {
+	result := newVWAPResult(len(data))
+
+	var acc vwapAccumulator
+	var session time.Time
+	for i, candle := range data {
+		start := SessionStart(candle.Time, sessionOffset)
+		if i == 0 || !start.Equal(session) {
+			session = start
+			acc.reset()
+		}
+		acc.add(candle)
+		acc.fill(result, i, (candle.High+candle.Low+candle.Close)/3)
+	}
+
+	return result
+}
+
+// AnchoredVWAP 从anchor索引开始计算锚定VWAP及±1σ/±2σ通道，anchor之前的值为0
+func (ti *TechnicalIndicators) AnchoredVWAP(data []types.OHLCV, anchor int) VWAPResult {
+	result := newVWAPResult(len(data))
+	if anchor < 0 || anchor >= len(data) {
+		return result
+	}
+
+	var acc vwapAccumulator
+	for i := anchor; i < len(data); i++ {
+		acc.add(data[i])
+		acc.fill(result, i, (data[i].High+data[i].Low+data[i].Close)/3)
+	}
+
+	return result
+}
+
+// AnchorIndex 返回第一根开盘时间不早于t的K线索引，找不到时返回-1
+func AnchorIndex(data []types.OHLCV, t time.Time) int {
+	for i, candle := range data {
+		if !candle.Time.Before(t) {
+			return i
+		}
+	}
+	return -1
+}
+
+// LastMajorSwing 返回最近一个强度为strength的摆动低点（isHigh为false）或高点的索引，找不到时返回-1
+func LastMajorSwing(data []types.OHLCV, strength int, isHigh bool) int {
+	values := make([]float64, len(data))
+	for i, candle := range data {
+		if isHigh {
+			values[i] = candle.High
+		} else {
+			values[i] = candle.Low
+		}
+	}
+
+	var swings []SwingPoint
+	if isHigh {
+		swings = FindSwingHighs(values, strength)
+	} else {
+		swings = FindSwingLows(values, strength)
+	}
+	if len(swings) == 0 {
+		return -1
+	}
+	return swings[len(swings)-1].Index
+}
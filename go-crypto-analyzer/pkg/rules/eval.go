@@ -0,0 +1,685 @@
+package rules
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Env 求值环境：标识符到值的映射，值为Func时可作为函数调用
+type Env map[string]interface{}
+
+// Func 环境提供的函数
+type Func func(args []interface{}) (interface{}, error)
+
+// Scope 静态检查用的作用域：标识符到类型，nil类型表示动态值不做检查
+type Scope map[string]reflect.Type
+
+var (
+	funcType   = reflect.TypeOf(Func(nil))
+	numberType = reflect.TypeOf(0.0)
+	stringType = reflect.TypeOf("")
+	boolType   = reflect.TypeOf(false)
+)
+
+// builtinArity 内置函数的参数个数，-1表示至少一个
+var builtinArity = map[string]int{
+	"abs":    1,
+	"min":    -1,
+	"max":    -1,
+	"len":    1,
+	"join":   2,
+	"format": 2,
+	"if":     3,
+}
+
+// node 语法树节点
+type node interface {
+	eval(env Env) (interface{}, error)
+	check(scope Scope) (reflect.Type, error)
+	children() []node
+}
+
+func walk(n node, visit func(node)) {
+	visit(n)
+	for _, child := range n.children() {
+		walk(child, visit)
+	}
+}
+
+type literalNode struct {
+	value interface{}
+}
+
+func (n *literalNode) eval(env Env) (interface{}, error) { return n.value, nil }
+
+func (n *literalNode) check(scope Scope) (reflect.Type, error) {
+	if n.value == nil {
+		return nil, nil
+	}
+	return reflect.TypeOf(n.value), nil
+}
+
+func (n *literalNode) children() []node { return nil }
+
+type identNode struct {
+	name string
+}
+
+func (n *identNode) eval(env Env) (interface{}, error) {
+	value, ok := env[n.name]
+	if !ok {
+		return nil, fmt.Errorf("unknown identifier %q", n.name)
+	}
+	return Normalize(value), nil
+}
+
+func (n *identNode) check(scope Scope) (reflect.Type, error) {
+	t, ok := scope[n.name]
+	if !ok {
+		return nil, fmt.Errorf("unknown identifier %q", n.name)
+	}
+	return t, nil
+}
+
+func (n *identNode) children() []node { return nil }
+
+type memberNode struct {
+	operand node
+	name    string
+}
+
+func (n *memberNode) eval(env Env) (interface{}, error) {
+	operand, err := n.operand.eval(env)
+	if err != nil || operand == nil {
+		return nil, err
+	}
+	v := indirect(reflect.ValueOf(operand))
+	switch v.Kind() {
+	case reflect.Invalid:
+		return nil, nil
+	case reflect.Struct:
+		if field, ok := v.Type().FieldByName(n.name); ok && field.PkgPath == "" {
+			return Normalize(v.FieldByIndex(field.Index).Interface()), nil
+		}
+	case reflect.Map:
+		if v.Type().Key().Kind() == reflect.String {
+			value := v.MapIndex(reflect.ValueOf(n.name).Convert(v.Type().Key()))
+			if !value.IsValid() {
+				return nil, nil
+			}
+			return Normalize(value.Interface()), nil
+		}
+	}
+	return nil, fmt.Errorf("%s has no field %s", v.Type(), n.name)
+}
+
+func (n *memberNode) check(scope Scope) (reflect.Type, error) {
+	t, err := n.operand.check(scope)
+	if err != nil || t == nil {
+		return nil, err
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		if field, ok := t.FieldByName(n.name); ok && field.PkgPath == "" {
+			return field.Type, nil
+		}
+	case reflect.Map:
+		if t.Key().Kind() == reflect.String {
+			return t.Elem(), nil
+		}
+	case reflect.Interface:
+		return nil, nil
+	}
+	return nil, fmt.Errorf("%s has no field %s", t, n.name)
+}
+
+func (n *memberNode) children() []node { return []node{n.operand} }
+
+type methodNode struct {
+	operand node
+	name    string
+	args    []node
+}
+
+func (n *methodNode) eval(env Env) (interface{}, error) {
+	operand, err := n.operand.eval(env)
+	if err != nil || operand == nil {
+		return nil, err
+	}
+	args, err := evalArgs(n.args, env)
+	if err != nil {
+		return nil, err
+	}
+
+	v := reflect.ValueOf(operand)
+	method := v.MethodByName(n.name)
+	if !method.IsValid() && v.Kind() != reflect.Ptr {
+		// 指针接收者的方法在副本上调用
+		ptr := reflect.New(v.Type())
+		ptr.Elem().Set(v)
+		method = ptr.MethodByName(n.name)
+	}
+	if !method.IsValid() {
+		return nil, fmt.Errorf("%s has no method %s", v.Type(), n.name)
+	}
+
+	mt := method.Type()
+	if mt.NumIn() != len(args) || mt.IsVariadic() {
+		return nil, fmt.Errorf("%s.%s takes %d arguments, got %d", v.Type(), n.name, mt.NumIn(), len(args))
+	}
+	in := make([]reflect.Value, len(args))
+	for i, arg := range args {
+		if arg == nil {
+			in[i] = reflect.Zero(mt.In(i))
+			continue
+		}
+		av := reflect.ValueOf(arg)
+		if !av.Type().ConvertibleTo(mt.In(i)) {
+			return nil, fmt.Errorf("%s.%s: argument %d must be %s, got %T", v.Type(), n.name, i+1, mt.In(i), arg)
+		}
+		in[i] = av.Convert(mt.In(i))
+	}
+
+	out := method.Call(in)
+	if len(out) == 0 {
+		return nil, nil
+	}
+	if last := out[len(out)-1]; last.Type() == reflect.TypeOf((*error)(nil)).Elem() && !last.IsNil() {
+		return nil, last.Interface().(error)
+	}
+	return Normalize(out[0].Interface()), nil
+}
+
+func (n *methodNode) check(scope Scope) (reflect.Type, error) {
+	t, err := n.operand.check(scope)
+	if err != nil {
+		return nil, err
+	}
+	for _, arg := range n.args {
+		if _, err := arg.check(scope); err != nil {
+			return nil, err
+		}
+	}
+	if t == nil || t.Kind() == reflect.Interface {
+		return nil, nil
+	}
+	method, ok := t.MethodByName(n.name)
+	if !ok && t.Kind() != reflect.Ptr {
+		method, ok = reflect.PtrTo(t).MethodByName(n.name)
+	}
+	if !ok {
+		return nil, fmt.Errorf("%s has no method %s", t, n.name)
+	}
+	// 方法类型的第一个参数是接收者
+	if method.Type.NumIn()-1 != len(n.args) {
+		return nil, fmt.Errorf("%s.%s takes %d arguments, got %d", t, n.name, method.Type.NumIn()-1, len(n.args))
+	}
+	if method.Type.NumOut() == 0 {
+		return nil, nil
+	}
+	return method.Type.Out(0), nil
+}
+
+func (n *methodNode) children() []node { return append([]node{n.operand}, n.args...) }
+
+type indexNode struct {
+	operand node
+	index   node
+}
+
+func (n *indexNode) eval(env Env) (interface{}, error) {
+	operand, err := n.operand.eval(env)
+	if err != nil || operand == nil {
+		return nil, err
+	}
+	index, err := n.index.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	v := indirect(reflect.ValueOf(operand))
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array, reflect.String:
+		i, ok := index.(float64)
+		if !ok {
+			return nil, fmt.Errorf("index must be a number, got %T", index)
+		}
+		if i < 0 || int(i) >= v.Len() {
+			return nil, nil
+		}
+		return Normalize(v.Index(int(i)).Interface()), nil
+	case reflect.Map:
+		key := reflect.ValueOf(index)
+		if index == nil || !key.Type().ConvertibleTo(v.Type().Key()) {
+			return nil, fmt.Errorf("bad key %v for %s", index, v.Type())
+		}
+		value := v.MapIndex(key.Convert(v.Type().Key()))
+		if !value.IsValid() {
+			return nil, nil
+		}
+		return Normalize(value.Interface()), nil
+	}
+	return nil, fmt.Errorf("cannot index %s", v.Type())
+}
+
+func (n *indexNode) check(scope Scope) (reflect.Type, error) {
+	t, err := n.operand.check(scope)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := n.index.check(scope); err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, nil
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return t.Elem(), nil
+	case reflect.Interface:
+		return nil, nil
+	}
+	return nil, fmt.Errorf("cannot index %s", t)
+}
+
+func (n *indexNode) children() []node { return []node{n.operand, n.index} }
+
+type callNode struct {
+	name string
+	args []node
+}
+
+func (n *callNode) eval(env Env) (interface{}, error) {
+	// if只对选中的分支求值
+	if n.name == "if" {
+		cond, err := n.args[0].eval(env)
+		if err != nil {
+			return nil, err
+		}
+		if Truthy(cond) {
+			return n.args[1].eval(env)
+		}
+		return n.args[2].eval(env)
+	}
+
+	args, err := evalArgs(n.args, env)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := builtinArity[n.name]; ok {
+		return callBuiltin(n.name, args)
+	}
+	fn, ok := env[n.name].(Func)
+	if !ok {
+		return nil, fmt.Errorf("unknown function %q", n.name)
+	}
+	value, err := fn(args)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", n.name, err)
+	}
+	return Normalize(value), nil
+}
+
+func (n *callNode) check(scope Scope) (reflect.Type, error) {
+	argTypes := make([]reflect.Type, len(n.args))
+	for i, arg := range n.args {
+		t, err := arg.check(scope)
+		if err != nil {
+			return nil, err
+		}
+		argTypes[i] = t
+	}
+	// 内置函数的参数个数已在解析时检查
+	if _, ok := builtinArity[n.name]; ok {
+		switch n.name {
+		case "if":
+			// 两个分支类型相同，或一个分支为nil时取另一分支的类型
+			switch {
+			case isNilLiteral(n.args[2]):
+				return argTypes[1], nil
+			case isNilLiteral(n.args[1]):
+				return argTypes[2], nil
+			case argTypes[1] == argTypes[2]:
+				return argTypes[1], nil
+			}
+			return nil, nil
+		case "join", "format":
+			return stringType, nil
+		}
+		return numberType, nil
+	}
+	if scope[n.name] != funcType {
+		return nil, fmt.Errorf("unknown function %q", n.name)
+	}
+	return nil, nil
+}
+
+func (n *callNode) children() []node { return n.args }
+
+func isNilLiteral(n node) bool {
+	lit, ok := n.(*literalNode)
+	return ok && lit.value == nil
+}
+
+func callBuiltin(name string, args []interface{}) (interface{}, error) {
+	switch name {
+	case "len":
+		if args[0] == nil {
+			return 0.0, nil
+		}
+		v := indirect(reflect.ValueOf(args[0]))
+		switch v.Kind() {
+		case reflect.Slice, reflect.Array, reflect.Map, reflect.String:
+			return float64(v.Len()), nil
+		}
+		return nil, fmt.Errorf("len of %T", args[0])
+	case "format":
+		spec, _ := args[1].(string)
+		return Format(args[0], spec), nil
+	case "join":
+		sep, _ := args[1].(string)
+		if args[0] == nil {
+			return "", nil
+		}
+		v := indirect(reflect.ValueOf(args[0]))
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			return nil, fmt.Errorf("join of %T", args[0])
+		}
+		parts := make([]string, v.Len())
+		for i := range parts {
+			parts[i] = Format(Normalize(v.Index(i).Interface()), "")
+		}
+		return strings.Join(parts, sep), nil
+	}
+
+	numbers := make([]float64, len(args))
+	for i, arg := range args {
+		number, ok := arg.(float64)
+		if !ok {
+			return nil, fmt.Errorf("%s: argument %d is not a number", name, i+1)
+		}
+		numbers[i] = number
+	}
+	switch name {
+	case "abs":
+		return math.Abs(numbers[0]), nil
+	case "min":
+		result := numbers[0]
+		for _, number := range numbers[1:] {
+			result = math.Min(result, number)
+		}
+		return result, nil
+	default: // max
+		result := numbers[0]
+		for _, number := range numbers[1:] {
+			result = math.Max(result, number)
+		}
+		return result, nil
+	}
+}
+
+type unaryNode struct {
+	op      string
+	operand node
+}
+
+func (n *unaryNode) eval(env Env) (interface{}, error) {
+	operand, err := n.operand.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	if n.op == "not" {
+		return !Truthy(operand), nil
+	}
+	if operand == nil {
+		return nil, nil
+	}
+	number, ok := operand.(float64)
+	if !ok {
+		return nil, fmt.Errorf("cannot negate %T", operand)
+	}
+	return -number, nil
+}
+
+func (n *unaryNode) check(scope Scope) (reflect.Type, error) {
+	if _, err := n.operand.check(scope); err != nil {
+		return nil, err
+	}
+	if n.op == "not" {
+		return boolType, nil
+	}
+	return numberType, nil
+}
+
+func (n *unaryNode) children() []node { return []node{n.operand} }
+
+type binaryNode struct {
+	op          string
+	left, right node
+}
+
+func (n *binaryNode) eval(env Env) (interface{}, error) {
+	left, err := n.left.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	// and/or短路求值，右侧可依赖左侧的判空
+	switch n.op {
+	case "and":
+		if !Truthy(left) {
+			return false, nil
+		}
+		right, err := n.right.eval(env)
+		return Truthy(right), err
+	case "or":
+		if Truthy(left) {
+			return true, nil
+		}
+		right, err := n.right.eval(env)
+		return Truthy(right), err
+	}
+
+	right, err := n.right.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "==":
+		return equal(left, right), nil
+	case "!=":
+		return !equal(left, right), nil
+	}
+
+	// nil参与比较为假，参与运算结果为nil
+	if left == nil || right == nil {
+		switch n.op {
+		case "<", "<=", ">", ">=":
+			return false, nil
+		}
+		return nil, nil
+	}
+
+	if n.op == "+" {
+		_, leftString := left.(string)
+		_, rightString := right.(string)
+		if leftString || rightString {
+			return Format(left, "") + Format(right, ""), nil
+		}
+	}
+
+	a, aok := left.(float64)
+	b, bok := right.(float64)
+	if !aok || !bok {
+		as, asok := left.(string)
+		bs, bsok := right.(string)
+		if asok && bsok {
+			switch n.op {
+			case "<":
+				return as < bs, nil
+			case "<=":
+				return as <= bs, nil
+			case ">":
+				return as > bs, nil
+			case ">=":
+				return as >= bs, nil
+			}
+		}
+		return nil, fmt.Errorf("cannot apply %s to %T and %T", n.op, left, right)
+	}
+	switch n.op {
+	case "+":
+		return a + b, nil
+	case "-":
+		return a - b, nil
+	case "*":
+		return a * b, nil
+	case "/":
+		return a / b, nil
+	case "<":
+		return a < b, nil
+	case "<=":
+		return a <= b, nil
+	case ">":
+		return a > b, nil
+	default: // >=
+		return a >= b, nil
+	}
+}
+
+func (n *binaryNode) check(scope Scope) (reflect.Type, error) {
+	if _, err := n.left.check(scope); err != nil {
+		return nil, err
+	}
+	if _, err := n.right.check(scope); err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "-", "*", "/":
+		return numberType, nil
+	case "+":
+		return nil, nil
+	}
+	return boolType, nil
+}
+
+func (n *binaryNode) children() []node { return []node{n.left, n.right} }
+
+func evalArgs(nodes []node, env Env) ([]interface{}, error) {
+	args := make([]interface{}, len(nodes))
+	for i, arg := range nodes {
+		value, err := arg.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = value
+	}
+	return args, nil
+}
+
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// Normalize 统一值的表示：数值为float64，字符串类型为string，空指针为nil，
+// 结构体、切片等保持原值
+func Normalize(value interface{}) interface{} {
+	if _, ok := value.(Func); ok {
+		return value
+	}
+	v := reflect.ValueOf(value)
+	if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	case reflect.String:
+		return v.String()
+	}
+	return value
+}
+
+// Restore 把Normalize后的值还原为静态类型t（如int、TrendDirection），
+// t为nil、非基本类型或无法转换时原样返回
+func Restore(value interface{}, t reflect.Type) interface{} {
+	if t == nil || value == nil {
+		return value
+	}
+	switch t.Kind() {
+	case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	default:
+		return value
+	}
+	v := reflect.ValueOf(value)
+	if v.Kind() != t.Kind() && (v.Kind() == reflect.String || t.Kind() == reflect.String || v.Kind() == reflect.Bool || t.Kind() == reflect.Bool) {
+		return value
+	}
+	if !v.Type().ConvertibleTo(t) {
+		return value
+	}
+	return v.Convert(t).Interface()
+}
+
+// Truthy 条件真值：布尔值本身，非零数值，非空字符串，nil为假
+func Truthy(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case float64:
+		return v != 0 && !math.IsNaN(v)
+	case string:
+		return v != ""
+	}
+	return true
+}
+
+func equal(a, b interface{}) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// Format 按格式说明格式化值，spec如 ".2f"、"d"，为空时数值取最短表示
+func Format(value interface{}, spec string) string {
+	if value == nil {
+		return ""
+	}
+	number, isNumber := value.(float64)
+	switch {
+	case spec == "" && isNumber:
+		return strconv.FormatFloat(number, 'f', -1, 64)
+	case spec == "":
+		return fmt.Sprint(value)
+	case isNumber && strings.HasSuffix(spec, "d"):
+		return fmt.Sprintf("%"+spec, int64(number))
+	}
+	return fmt.Sprintf("%"+spec, value)
+}
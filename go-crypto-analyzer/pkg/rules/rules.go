@@ -0,0 +1,340 @@
+// Package rules 实现规则文件使用的标量表达式和描述模板，例如
+//
+//	Momentum.RSI > Thresholds.RSIOverbought and PriceChange > 0
+//	if(item.BarsAgo > 0, item.BarsAgo + '根K线前', '当前K线')
+//	价格({CurrentPrice:.2f})高于MA5({MAAnalysis.MA5:.2f})
+//
+// 标识符区分大小写，按名称访问结构体字段、map键、方法（x.Method(args)）和下标（x[i]），
+// 空指针的字段为nil。支持四则运算（字符串可用+拼接）、比较、and/or/not，
+// 以及 abs/min/max/len/join/format/if 内置函数和环境中提供的函数。
+package rules
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// Expression 已解析的标量表达式
+type Expression struct {
+	text string
+	root node
+}
+
+// Parse 解析表达式
+func Parse(text string) (*Expression, error) {
+	tokens, err := tokenize(text)
+	if err != nil {
+		return nil, fmt.Errorf("expression %q: %w", text, err)
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("expression %q: %w", text, err)
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf("expression %q: unexpected %q at position %d", text, tok.text, tok.pos)
+	}
+	return &Expression{text: text, root: root}, nil
+}
+
+// Text 返回原始表达式文本
+func (e *Expression) Text() string {
+	return e.text
+}
+
+// Eval 在环境中求值，数值统一为float64
+func (e *Expression) Eval(env Env) (interface{}, error) {
+	value, err := e.root.eval(env)
+	if err != nil {
+		return nil, fmt.Errorf("expression %q: %w", e.text, err)
+	}
+	return value, nil
+}
+
+// Check 按作用域中的类型静态检查标识符、字段、方法和函数是否存在
+func (e *Expression) Check(scope Scope) error {
+	if _, err := e.root.check(scope); err != nil {
+		return fmt.Errorf("expression %q: %w", e.text, err)
+	}
+	return nil
+}
+
+// TypeOf 静态检查表达式并返回结果类型，nil表示动态类型
+func (e *Expression) TypeOf(scope Scope) (reflect.Type, error) {
+	t, err := e.root.check(scope)
+	if err != nil {
+		return nil, fmt.Errorf("expression %q: %w", e.text, err)
+	}
+	return t, nil
+}
+
+// StringArgs 返回调用函数name时的字符串字面量参数，如 expr('rsi(9) < 30') 中的表达式文本
+func (e *Expression) StringArgs(name string) []string {
+	args := make([]string, 0)
+	walk(e.root, func(n node) {
+		if call, ok := n.(*callNode); ok && call.name == name {
+			for _, arg := range call.args {
+				if lit, ok := arg.(*literalNode); ok {
+					if text, ok := lit.value.(string); ok {
+						args = append(args, text)
+					}
+				}
+			}
+		}
+	})
+	return args
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// accept 当前词法单元是给定运算符或关键字之一时消费并返回
+func (p *parser) accept(texts ...string) (string, bool) {
+	tok := p.peek()
+	if tok.kind != tokenOp && tok.kind != tokenIdent {
+		return "", false
+	}
+	for _, text := range texts {
+		if tok.text == text {
+			p.next()
+			return text, true
+		}
+	}
+	return "", false
+}
+
+func (p *parser) expect(text string) error {
+	tok := p.next()
+	if tok.kind != tokenOp || tok.text != text {
+		return fmt.Errorf("expected %q at position %d", text, tok.pos)
+	}
+	return nil
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("or", "||"); !ok {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: "or", left: left, right: right}
+	}
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("and", "&&"); !ok {
+			return left, nil
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: "and", left: left, right: right}
+	}
+}
+
+func (p *parser) parseNot() (node, error) {
+	if _, ok := p.accept("not", "!"); ok {
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{op: "not", operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	if op, ok := p.accept(">=", "<=", "==", "!=", ">", "<"); ok {
+		right, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		return &binaryNode{op: op, left: left, right: right}, nil
+	}
+	return left, nil
+}
+
+func (p *parser) parseAdditive() (node, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept("+", "-")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *parser) parseMultiplicative() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept("*", "/")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if _, ok := p.accept("-"); ok {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{op: "-", operand: operand}, nil
+	}
+	return p.parsePostfix()
+}
+
+// parsePostfix 解析字段、方法调用和下标
+func (p *parser) parsePostfix() (node, error) {
+	operand, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("."); ok {
+			tok := p.next()
+			if tok.kind != tokenIdent {
+				return nil, fmt.Errorf("expected field name after '.' at position %d", tok.pos)
+			}
+			if _, ok := p.accept("("); ok {
+				args, err := p.parseArgs()
+				if err != nil {
+					return nil, err
+				}
+				operand = &methodNode{operand: operand, name: tok.text, args: args}
+			} else {
+				operand = &memberNode{operand: operand, name: tok.text}
+			}
+			continue
+		}
+		if _, ok := p.accept("["); ok {
+			index, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			operand = &indexNode{operand: operand, index: index}
+			continue
+		}
+		return operand, nil
+	}
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenNumber:
+		value, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("bad number %q at position %d", tok.text, tok.pos)
+		}
+		return &literalNode{value: value}, nil
+	case tokenString:
+		return &literalNode{value: tok.text}, nil
+	case tokenIdent:
+		switch tok.text {
+		case "true":
+			return &literalNode{value: true}, nil
+		case "false":
+			return &literalNode{value: false}, nil
+		case "nil":
+			return &literalNode{value: nil}, nil
+		}
+		if _, ok := p.accept("("); ok {
+			args, err := p.parseArgs()
+			if err != nil {
+				return nil, err
+			}
+			// 内置函数在解析时检查参数个数，未经Check的表达式求值时不会越界
+			if arity, ok := builtinArity[tok.text]; ok && ((arity < 0 && len(args) == 0) || (arity >= 0 && len(args) != arity)) {
+				return nil, fmt.Errorf("%s: wrong number of arguments (%d) at position %d", tok.text, len(args), tok.pos)
+			}
+			return &callNode{name: tok.text, args: args}, nil
+		}
+		return &identNode{name: tok.text}, nil
+	case tokenOp:
+		if tok.text == "(" {
+			inner, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			return inner, p.expect(")")
+		}
+	}
+	if tok.kind == tokenEOF {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+}
+
+// parseArgs 解析左括号之后的参数列表
+func (p *parser) parseArgs() ([]node, error) {
+	args := make([]node, 0)
+	if _, ok := p.accept(")"); ok {
+		return args, nil
+	}
+	for {
+		arg, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+		if _, ok := p.accept(")"); ok {
+			return args, nil
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+}
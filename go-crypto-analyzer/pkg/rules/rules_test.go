@@ -0,0 +1,172 @@
+package rules
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testLevel struct {
+	Price float64
+	Label string
+}
+
+type testEvent struct {
+	BarsAgo int
+}
+
+type testResult struct {
+	Price  float64
+	Levels []testLevel
+	Last   *testEvent
+	Names  map[string]float64
+}
+
+func (r testResult) Above(price float64) float64 {
+	for _, level := range r.Levels {
+		if level.Price > price {
+			return level.Price
+		}
+	}
+	return 0
+}
+
+func TestExpression(t *testing.T) {
+	result := testResult{
+		Price:  100,
+		Levels: []testLevel{{Price: 95, Label: "S1"}, {Price: 105.5, Label: "R1"}},
+		Names:  map[string]float64{"R1": 105.5},
+	}
+	env := Env{"R": result, "x": 3, "name": "RSI"}
+
+	cases := map[string]interface{}{
+		"1 + 2 * 3 - 4 / 2":                        5.0,
+		"-(1 + 2) * 2":                             -6.0,
+		"R.Price > 99 and not (x == 4)":            true,
+		"R.Price < 99 or x >= 3 && x != 2":         true,
+		"R.Levels[1].Label":                        "R1",
+		"len(R.Levels) + abs(-2) + max(1, x, 2)":   7.0,
+		"R.Names.R1 - R.Above(R.Price)":            0.0,
+		"R.Last.BarsAgo":                           nil,
+		"R.Last != nil and R.Last.BarsAgo < 5":     false,
+		"if(x > 2, name + '超买', 1 / 0)":            "RSI超买",
+		"x + '根K线前'":                               "3根K线前",
+		"format(R.Price / 3, '.2f')":               "33.33",
+		"join(R.Levels, '、') == ''":                false,
+		"R.Last.BarsAgo * 2":                       nil,
+		"min(R.Levels[0].Price, R.Price)":          95.0,
+		"\"a\\\"b\" + 'c'":                         "a\"bc",
+		"R.Levels[0].Label < R.Levels[1].Label":    false,
+		"len(nil) == 0 and R.Names.missing == nil": true,
+	}
+	for text, want := range cases {
+		e, err := Parse(text)
+		if err != nil {
+			t.Errorf("parse %s: %v", text, err)
+			continue
+		}
+		got, err := e.Eval(env)
+		if err != nil {
+			t.Errorf("eval %s: %v", text, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s = %#v, want %#v", text, got, want)
+		}
+	}
+
+	// 语法错误和内置函数参数个数错误
+	for _, text := range []string{"1 +", "(1", "a.", "1 = 2", "'abc", "f(1,", "abs()", "if(1)", "if(x, 1)", "join(x)", "format(x)", "min()", "len(1, 2)"} {
+		if _, err := Parse(text); err == nil {
+			t.Errorf("expected %q to fail", text)
+		}
+	}
+
+	// 运行期错误
+	if _, err := mustParse(t, "unknown + 1").Eval(env); err == nil {
+		t.Error("expected unknown identifier to fail")
+	}
+	if _, err := mustParse(t, "R.Missing").Eval(env); err == nil {
+		t.Error("expected missing field to fail")
+	}
+
+	// 静态检查
+	scope := Scope{"R": reflect.TypeOf(result), "x": reflect.TypeOf(0.0), "f": reflect.TypeOf(Func(nil))}
+	for text, ok := range map[string]bool{
+		"R.Levels[0].Price > R.Above(x)": true,
+		"R.Last.BarsAgo":                 true,
+		"f('rsi(9) < 30') and x > 1":     true,
+		"R.Levels[0].Prise":              false,
+		"R.Below(x)":                     false,
+		"R.Above()":                      false,
+		"y > 1":                          false,
+		"g(1)":                           false,
+	} {
+		err := mustParse(t, text).Check(scope)
+		if (err == nil) != ok {
+			t.Errorf("check %s: %v", text, err)
+		}
+	}
+
+	// 静态类型用于还原求值结果，if的nil分支取另一分支的类型
+	typ, err := mustParse(t, "if(x > 1, R.Last.BarsAgo, nil)").TypeOf(scope)
+	if err != nil || typ != reflect.TypeOf(0) {
+		t.Errorf("if type: %v %v", typ, err)
+	}
+	if v := Restore(19.0, typ); v != 19 {
+		t.Errorf("restore: %#v", v)
+	}
+	if v := Restore("R1", reflect.TypeOf(0)); v != "R1" {
+		t.Errorf("restore should leave inconvertible values: %#v", v)
+	}
+
+	args := mustParse(t, "f('rsi(9) < 30') or f('close > ema(close,20)') and x > 0").StringArgs("f")
+	if len(args) != 2 || args[1] != "close > ema(close,20)" {
+		t.Errorf("string args: %v", args)
+	}
+}
+
+func TestTemplate(t *testing.T) {
+	env := Env{"price": 113443.031, "bars": 3, "name": "SuperTrend", "ratio": 0.618, "list": []string{"摆动", "轴心点R1"}}
+
+	cases := map[string]string{
+		"价格({price:.2f})高于MA5":                      "价格(113443.03)高于MA5",
+		"{name}翻多（{bars}根K线前）":                      "SuperTrend翻多（3根K线前）",
+		"{ratio * 100:.1f}%回撤位与{join(list, '、')}共振": "61.8%回撤位与摆动、轴心点R1共振",
+		"{{字面量}} {bars:d}根":                         "{字面量} 3根",
+		"{if(bars > 0, bars + '根K线前', '当前K线')}":     "3根K线前",
+		"%B:{ratio:.2f}":                            "%B:0.62",
+	}
+	for text, want := range cases {
+		tmpl, err := ParseTemplate(text)
+		if err != nil {
+			t.Errorf("parse %s: %v", text, err)
+			continue
+		}
+		got, err := tmpl.Execute(env)
+		if err != nil || got != want {
+			t.Errorf("%s = %q (%v), want %q", text, got, err, want)
+		}
+	}
+
+	for _, text := range []string{"价格({price", "{price +}"} {
+		if _, err := ParseTemplate(text); err == nil {
+			t.Errorf("expected %q to fail", text)
+		}
+	}
+
+	tmpl, _ := ParseTemplate("{missing:.2f}")
+	if err := tmpl.Check(Scope{"price": nil}); err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Errorf("expected unknown identifier in template check, got %v", err)
+	}
+}
+
+// mustParse 解析测试表达式，失败时终止测试
+func mustParse(t *testing.T, text string) *Expression {
+	t.Helper()
+	e, err := Parse(text)
+	if err != nil {
+		t.Fatalf("parse %s: %v", text, err)
+	}
+	return e
+}
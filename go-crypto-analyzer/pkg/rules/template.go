@@ -0,0 +1,131 @@
+package rules
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// formatSpec 占位符的格式说明，如 {x:.2f}、{n:d}、{name:s}
+var formatSpec = regexp.MustCompile(`^[-+ #0]*\d*(\.\d+)?[dfgeEsvx]$`)
+
+// Template 描述模板：{表达式} 或 {表达式:格式} 占位符替换为求值结果，{{ 和 }} 表示花括号本身
+type Template struct {
+	text  string
+	parts []templatePart
+}
+
+type templatePart struct {
+	literal string
+	expr    *Expression
+	spec    string
+}
+
+// ParseTemplate 解析描述模板
+func ParseTemplate(text string) (*Template, error) {
+	t := &Template{text: text}
+	runes := []rune(text)
+	var literal strings.Builder
+	flush := func() {
+		if literal.Len() > 0 {
+			t.parts = append(t.parts, templatePart{literal: literal.String()})
+			literal.Reset()
+		}
+	}
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if r == '}' {
+			if i+1 < len(runes) && runes[i+1] == '}' {
+				i++
+			}
+			literal.WriteRune(r)
+			continue
+		}
+		if r != '{' {
+			literal.WriteRune(r)
+			continue
+		}
+		if i+1 < len(runes) && runes[i+1] == '{' {
+			literal.WriteRune(r)
+			i++
+			continue
+		}
+
+		// 找到不在字符串内的右括号
+		end, quote := -1, rune(0)
+		for j := i + 1; j < len(runes) && end < 0; j++ {
+			switch {
+			case quote != 0 && runes[j] == quote:
+				quote = 0
+			case quote != 0:
+			case runes[j] == '\'' || runes[j] == '"':
+				quote = runes[j]
+			case runes[j] == '}':
+				end = j
+			}
+		}
+		if end < 0 {
+			return nil, fmt.Errorf("template %q: unclosed '{' at position %d", text, i)
+		}
+
+		inner, spec := string(runes[i+1:end]), ""
+		if colon := strings.LastIndex(inner, ":"); colon >= 0 && formatSpec.MatchString(inner[colon+1:]) {
+			inner, spec = inner[:colon], inner[colon+1:]
+		}
+		expr, err := Parse(strings.TrimSpace(inner))
+		if err != nil {
+			return nil, fmt.Errorf("template %q: %w", text, err)
+		}
+		flush()
+		t.parts = append(t.parts, templatePart{expr: expr, spec: spec})
+		i = end
+	}
+	flush()
+	return t, nil
+}
+
+// Text 返回原始模板文本
+func (t *Template) Text() string {
+	return t.text
+}
+
+// Execute 在环境中渲染模板
+func (t *Template) Execute(env Env) (string, error) {
+	var sb strings.Builder
+	for _, part := range t.parts {
+		if part.expr == nil {
+			sb.WriteString(part.literal)
+			continue
+		}
+		value, err := part.expr.Eval(env)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(Format(value, part.spec))
+	}
+	return sb.String(), nil
+}
+
+// Check 静态检查模板中的全部表达式
+func (t *Template) Check(scope Scope) error {
+	for _, part := range t.parts {
+		if part.expr != nil {
+			if err := part.expr.Check(scope); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// StringArgs 返回模板中调用函数name时的字符串字面量参数
+func (t *Template) StringArgs(name string) []string {
+	args := make([]string, 0)
+	for _, part := range t.parts {
+		if part.expr != nil {
+			args = append(args, part.expr.StringArgs(name)...)
+		}
+	}
+	return args
+}
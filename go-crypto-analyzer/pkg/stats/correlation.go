@@ -0,0 +1,125 @@
+package stats
+
+import (
+	"math"
+	"sort"
+)
+
+// Pearson 皮尔逊相关系数，长度不一致或任一序列无波动时ok为false
+func Pearson(x, y []float64) (float64, bool) {
+	if len(x) != len(y) || len(x) < 3 {
+		return 0, false
+	}
+	meanX, meanY := mean(x), mean(y)
+	cov, varX, varY := 0.0, 0.0, 0.0
+	for i := range x {
+		dx, dy := x[i]-meanX, y[i]-meanY
+		cov += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+	if varX == 0 || varY == 0 {
+		return 0, false
+	}
+	return cov / math.Sqrt(varX*varY), true
+}
+
+// Spearman 斯皮尔曼秩相关系数：对秩（并列取平均秩）计算皮尔逊相关
+func Spearman(x, y []float64) (float64, bool) {
+	if len(x) != len(y) {
+		return 0, false
+	}
+	return Pearson(ranks(x), ranks(y))
+}
+
+// Beta y对基准x的贝塔：cov(x,y)/var(x)
+func Beta(y, x []float64) (float64, bool) {
+	if len(x) != len(y) || len(x) < 3 {
+		return 0, false
+	}
+	meanX, meanY := mean(x), mean(y)
+	cov, varX := 0.0, 0.0
+	for i := range x {
+		dx := x[i] - meanX
+		cov += dx * (y[i] - meanY)
+		varX += dx * dx
+	}
+	if varX == 0 {
+		return 0, false
+	}
+	return cov / varX, true
+}
+
+// RollingCorrelation 以window为窗口滚动计算皮尔逊相关，数据不足的位置为NaN
+func RollingCorrelation(x, y []float64, window int) []float64 {
+	result := make([]float64, len(x))
+	for i := range result {
+		result[i] = math.NaN()
+		if i+1 < window || len(y) != len(x) {
+			continue
+		}
+		if rho, ok := Pearson(x[i+1-window:i+1], y[i+1-window:i+1]); ok {
+			result[i] = rho
+		}
+	}
+	return result
+}
+
+// EffectiveBets 相关矩阵的有效独立押注数 N²/Σρ²（等价于特征值的参与率），
+// 完全不相关时为N，完全相关时为1
+func EffectiveBets(matrix [][]float64) float64 {
+	n := float64(len(matrix))
+	sum := 0.0
+	for _, row := range matrix {
+		for _, rho := range row {
+			sum += rho * rho
+		}
+	}
+	if sum == 0 {
+		return 0
+	}
+	return n * n / sum
+}
+
+// LogReturns 对数收益率
+func LogReturns(closes []float64) []float64 {
+	if len(closes) < 2 {
+		return nil
+	}
+	returns := make([]float64, len(closes)-1)
+	for i := 1; i < len(closes); i++ {
+		returns[i-1] = math.Log(closes[i] / closes[i-1])
+	}
+	return returns
+}
+
+func mean(values []float64) float64 {
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// ranks 返回从1开始的秩，并列值取平均秩
+func ranks(values []float64) []float64 {
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return values[order[a]] < values[order[b]] })
+
+	result := make([]float64, len(values))
+	for i := 0; i < len(order); {
+		j := i
+		for j+1 < len(order) && values[order[j+1]] == values[order[i]] {
+			j++
+		}
+		rank := float64(i+j)/2 + 1
+		for k := i; k <= j; k++ {
+			result[order[k]] = rank
+		}
+		i = j + 1
+	}
+	return result
+}
@@ -0,0 +1,131 @@
+package stats
+
+import "math"
+
+// 由Hurst指数和方差比得出的市场状态
+const (
+	RegimeTrending      = "trending"       // 趋势延续
+	RegimeMeanReverting = "mean_reverting" // 均值回归
+	RegimeRandomWalk    = "random_walk"    // 随机游走
+)
+
+// Hurst 以聚合方差法估计Hurst指数：对数价格在lag期上的差分标准差 ∝ lag^H，
+// 对lag=2..maxLag做双对数回归取斜率。H>0.5趋势延续，H<0.5均值回归
+func Hurst(closes []float64, maxLag int) (float64, bool) {
+	if maxLag < 3 || len(closes) < 2*maxLag {
+		return 0, false
+	}
+
+	logs := make([]float64, len(closes))
+	for i, c := range closes {
+		if c <= 0 {
+			return 0, false
+		}
+		logs[i] = math.Log(c)
+	}
+
+	xs := make([]float64, 0, maxLag-1)
+	ys := make([]float64, 0, maxLag-1)
+	for lag := 2; lag <= maxLag; lag++ {
+		diffs := make([]float64, len(logs)-lag)
+		for i := range diffs {
+			diffs[i] = logs[i+lag] - logs[i]
+		}
+		std := math.Sqrt(sampleVariance(diffs))
+		if std <= 0 {
+			continue
+		}
+		xs = append(xs, math.Log(float64(lag)))
+		ys = append(ys, math.Log(std))
+	}
+	if len(xs) < 2 {
+		return 0, false
+	}
+	return slope(xs, ys), true
+}
+
+// RollingHurst 以window根K线滚动计算Hurst指数，数据不足的位置为0
+func RollingHurst(closes []float64, window, maxLag int) []float64 {
+	result := make([]float64, len(closes))
+	for i := window - 1; i < len(closes); i++ {
+		if h, ok := Hurst(closes[i-window+1:i+1], maxLag); ok {
+			result[i] = h
+		}
+	}
+	return result
+}
+
+// VarianceRatio Lo–MacKinlay方差比：q期重叠收益率方差与q倍单期方差之比，
+// 同时返回同方差假设下的z统计量。VR>1趋势延续，VR<1均值回归
+func VarianceRatio(closes []float64, q int) (vr, z float64, ok bool) {
+	if q < 2 || len(closes) < 2*q+1 {
+		return 0, 0, false
+	}
+
+	returns := make([]float64, len(closes)-1)
+	for i := range returns {
+		if closes[i] <= 0 || closes[i+1] <= 0 {
+			return 0, 0, false
+		}
+		returns[i] = math.Log(closes[i+1] / closes[i])
+	}
+	t := float64(len(returns))
+	mean := 0.0
+	for _, r := range returns {
+		mean += r
+	}
+	mean /= t
+
+	var1 := 0.0
+	for _, r := range returns {
+		var1 += (r - mean) * (r - mean)
+	}
+	var1 /= t - 1
+	if var1 == 0 {
+		return 0, 0, false
+	}
+
+	// 重叠q期收益率，无偏修正 m = q(T-q+1)(1-q/T)
+	fq := float64(q)
+	varq := 0.0
+	for i := q; i <= len(returns); i++ {
+		sum := 0.0
+		for _, r := range returns[i-q : i] {
+			sum += r
+		}
+		varq += (sum - fq*mean) * (sum - fq*mean)
+	}
+	varq /= fq * (t - fq + 1) * (1 - fq/t)
+
+	vr = varq / var1
+	phi := 2 * (2*fq - 1) * (fq - 1) / (3 * fq * t)
+	return vr, (vr - 1) / math.Sqrt(phi), true
+}
+
+// ClassifyRegime 综合Hurst指数和方差比判断市场状态，两者方向一致才判定为趋势或均值回归
+func ClassifyRegime(hurst, varianceRatio float64) string {
+	switch {
+	case hurst > 0.55 && varianceRatio > 1:
+		return RegimeTrending
+	case hurst < 0.45 && varianceRatio < 1:
+		return RegimeMeanReverting
+	}
+	return RegimeRandomWalk
+}
+
+// slope 最小二乘回归斜率
+func slope(xs, ys []float64) float64 {
+	n := float64(len(xs))
+	sumX, sumY, sumXY, sumXX := 0.0, 0.0, 0.0, 0.0
+	for i := range xs {
+		sumX += xs[i]
+		sumY += ys[i]
+		sumXY += xs[i] * ys[i]
+		sumXX += xs[i] * xs[i]
+	}
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return 0
+	}
+	return (n*sumXY - sumX*sumY) / denominator
+}
@@ -0,0 +1,119 @@
+package stats
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/zjc/go-crypto-analyzer/pkg/types"
+)
+
+func TestVolatilityAndRegime(t *testing.T) {
+	// 4小时K线，每根高低价区间固定为2%
+	rng := rand.New(rand.NewSource(7))
+	data := make([]types.OHLCV, 200)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	price := 100.0
+	for i := range data {
+		open := price
+		price *= math.Exp(rng.NormFloat64() * 0.005)
+		mid := math.Sqrt(open * price)
+		data[i] = types.OHLCV{Time: start.Add(time.Duration(i) * 4 * time.Hour), Open: open, Close: price,
+			High: mid * math.Exp(0.01), Low: mid * math.Exp(-0.01), Volume: 1}
+	}
+
+	interval, ok := InferInterval(data)
+	if !ok || interval != 4*time.Hour || PeriodsPerYear(interval) != 2190 {
+		t.Fatalf("unexpected interval %v", interval)
+	}
+
+	// Parkinson: ln(H/L)=0.02 => σ = 0.02/√(4ln2)
+	sigma, ok := Volatility(data, Parkinson, 20)
+	if !ok || math.Abs(sigma-0.02/math.Sqrt(4*math.Ln2)) > 1e-9 {
+		t.Errorf("parkinson: got %v", sigma)
+	}
+	annual, _ := AnnualizedVolatility(data, Parkinson, 20)
+	if math.Abs(annual-sigma*math.Sqrt(2190)) > 1e-9 {
+		t.Errorf("annualization: got %v", annual)
+	}
+	for _, estimator := range Estimators {
+		if v, ok := Volatility(data, estimator, 50); !ok || v <= 0 {
+			t.Errorf("%s: got %v ok=%v", estimator, v, ok)
+		}
+	}
+	if _, ok := Volatility(data[:10], YangZhang, 20); ok {
+		t.Error("expected insufficient data")
+	}
+
+	// 持续单向漂移且收益正相关为趋势，来回振荡为均值回归
+	trending := make([]float64, 200)
+	reverting := make([]float64, 200)
+	trending[0], reverting[0] = 100, 100
+	step := 0.0
+	for i := 1; i < len(trending); i++ {
+		step = 0.8*step + rng.NormFloat64()*0.002
+		trending[i] = trending[i-1] * math.Exp(0.001+step)
+		reverting[i] = 100 * math.Exp(0.02*math.Sin(float64(i))+rng.NormFloat64()*0.001)
+	}
+
+	h, ok := Hurst(trending, 20)
+	vr, _, _ := VarianceRatio(trending, 4)
+	if !ok || ClassifyRegime(h, vr) != RegimeTrending {
+		t.Errorf("trending series: hurst %.2f vr %.2f", h, vr)
+	}
+	h, ok = Hurst(reverting, 20)
+	vr, z, _ := VarianceRatio(reverting, 4)
+	if !ok || ClassifyRegime(h, vr) != RegimeMeanReverting || z >= 0 {
+		t.Errorf("mean reverting series: hurst %.2f vr %.2f z %.2f", h, vr, z)
+	}
+
+	rolling := RollingHurst(trending, 100, 20)
+	if rolling[98] != 0 || rolling[len(rolling)-1] <= 0.5 {
+		t.Errorf("rolling hurst: got %v at 98 and %v at end", rolling[98], rolling[len(rolling)-1])
+	}
+}
+
+func TestCorrelation(t *testing.T) {
+	x := []float64{0.01, -0.02, 0.015, 0.03, -0.01, 0.005}
+	y := make([]float64, len(x))
+	for i, v := range x {
+		y[i] = 2*v + 0.001
+	}
+
+	if rho, ok := Pearson(x, y); !ok || math.Abs(rho-1) > 1e-9 {
+		t.Errorf("pearson of linear series: got %v", rho)
+	}
+	if beta, ok := Beta(y, x); !ok || math.Abs(beta-2) > 1e-9 {
+		t.Errorf("beta: got %v, expected 2", beta)
+	}
+
+	// 单调但非线性的关系：Spearman为1，Pearson小于1
+	cubed := make([]float64, len(x))
+	for i, v := range x {
+		cubed[i] = v * v * v
+	}
+	spearman, _ := Spearman(x, cubed)
+	pearson, _ := Pearson(x, cubed)
+	if math.Abs(spearman-1) > 1e-9 || pearson >= 1-1e-6 {
+		t.Errorf("spearman %v pearson %v", spearman, pearson)
+	}
+	if r := ranks([]float64{3, 1, 3, 2}); r[0] != 3.5 || r[2] != 3.5 || r[1] != 1 {
+		t.Errorf("tied ranks: got %v", r)
+	}
+
+	if _, ok := Pearson(x, make([]float64, len(x))); ok {
+		t.Error("expected constant series to be rejected")
+	}
+
+	identity := [][]float64{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+	ones := [][]float64{{1, 1, 1}, {1, 1, 1}, {1, 1, 1}}
+	if EffectiveBets(identity) != 3 || EffectiveBets(ones) != 1 {
+		t.Errorf("effective bets: %v %v", EffectiveBets(identity), EffectiveBets(ones))
+	}
+
+	rolling := RollingCorrelation(x, y, 4)
+	if !math.IsNaN(rolling[2]) || math.Abs(rolling[5]-1) > 1e-9 {
+		t.Errorf("rolling correlation: got %v", rolling)
+	}
+}
@@ -0,0 +1,155 @@
+// Package stats 提供已实现波动率估计量以及趋势/均值回归状态统计
+package stats
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/zjc/go-crypto-analyzer/pkg/types"
+)
+
+// Estimator 波动率估计量
+type Estimator string
+
+const (
+	CloseToClose   Estimator = "close"           // 收盘价对数收益率标准差
+	Parkinson      Estimator = "parkinson"       // 高低价区间
+	GarmanKlass    Estimator = "garman_klass"    // 高低价与开收价
+	RogersSatchell Estimator = "rogers_satchell" // 允许漂移的高低开收估计
+	YangZhang      Estimator = "yang_zhang"      // 隔夜跳空+开收+Rogers–Satchell加权
+)
+
+// Estimators 全部估计量，按输出顺序排列
+var Estimators = []Estimator{CloseToClose, Parkinson, GarmanKlass, RogersSatchell, YangZhang}
+
+// ParseEstimator 解析估计量名称
+func ParseEstimator(name string) (Estimator, error) {
+	for _, e := range Estimators {
+		if string(e) == name {
+			return e, nil
+		}
+	}
+	return "", fmt.Errorf("unknown volatility estimator %q (close|parkinson|garman_klass|rogers_satchell|yang_zhang)", name)
+}
+
+// InferInterval 以相邻K线时间差的中位数推断K线周期
+func InferInterval(data []types.OHLCV) (time.Duration, bool) {
+	if len(data) < 2 {
+		return 0, false
+	}
+	diffs := make([]time.Duration, 0, len(data)-1)
+	for i := 1; i < len(data); i++ {
+		if d := data[i].Time.Sub(data[i-1].Time); d > 0 {
+			diffs = append(diffs, d)
+		}
+	}
+	if len(diffs) == 0 {
+		return 0, false
+	}
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i] < diffs[j] })
+	return diffs[len(diffs)/2], true
+}
+
+// PeriodsPerYear 一年中的K线数量，加密货币全天候交易按365天计
+func PeriodsPerYear(interval time.Duration) float64 {
+	if interval <= 0 {
+		return 0
+	}
+	return float64(365*24*time.Hour) / float64(interval)
+}
+
+// Volatility 计算最近window根K线的单根K线波动率（未年化）
+// 收盘价和Yang–Zhang估计需要额外一根K线的前收盘价
+func Volatility(data []types.OHLCV, estimator Estimator, window int) (float64, bool) {
+	if window < 2 || len(data) < window+1 {
+		return 0, false
+	}
+	bars := data[len(data)-window:]
+	prev := data[len(data)-window-1 : len(data)-1]
+	n := float64(window)
+
+	var variance float64
+	switch estimator {
+	case CloseToClose:
+		returns := make([]float64, window)
+		for i := range bars {
+			returns[i] = math.Log(bars[i].Close / prev[i].Close)
+		}
+		variance = sampleVariance(returns)
+	case Parkinson:
+		sum := 0.0
+		for _, bar := range bars {
+			hl := math.Log(bar.High / bar.Low)
+			sum += hl * hl
+		}
+		variance = sum / (4 * math.Ln2 * n)
+	case GarmanKlass:
+		sum := 0.0
+		for _, bar := range bars {
+			hl := math.Log(bar.High / bar.Low)
+			co := math.Log(bar.Close / bar.Open)
+			sum += 0.5*hl*hl - (2*math.Ln2-1)*co*co
+		}
+		variance = sum / n
+	case RogersSatchell:
+		variance = rogersSatchell(bars)
+	case YangZhang:
+		overnight := make([]float64, window)
+		openClose := make([]float64, window)
+		for i := range bars {
+			overnight[i] = math.Log(bars[i].Open / prev[i].Close)
+			openClose[i] = math.Log(bars[i].Close / bars[i].Open)
+		}
+		k := 0.34 / (1.34 + (n+1)/(n-1))
+		variance = sampleVariance(overnight) + k*sampleVariance(openClose) + (1-k)*rogersSatchell(bars)
+	default:
+		return 0, false
+	}
+
+	if variance < 0 || math.IsNaN(variance) {
+		return 0, false
+	}
+	return math.Sqrt(variance), true
+}
+
+// AnnualizedVolatility 按数据的实际K线周期年化波动率
+func AnnualizedVolatility(data []types.OHLCV, estimator Estimator, window int) (float64, bool) {
+	interval, ok := InferInterval(data)
+	if !ok {
+		return 0, false
+	}
+	sigma, ok := Volatility(data, estimator, window)
+	if !ok {
+		return 0, false
+	}
+	return sigma * math.Sqrt(PeriodsPerYear(interval)), true
+}
+
+func rogersSatchell(bars []types.OHLCV) float64 {
+	sum := 0.0
+	for _, bar := range bars {
+		sum += math.Log(bar.High/bar.Close)*math.Log(bar.High/bar.Open) +
+			math.Log(bar.Low/bar.Close)*math.Log(bar.Low/bar.Open)
+	}
+	return sum / float64(len(bars))
+}
+
+// sampleVariance 样本方差（n-1）
+func sampleVariance(values []float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+	mean := 0.0
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+
+	sum := 0.0
+	for _, v := range values {
+		sum += (v - mean) * (v - mean)
+	}
+	return sum / float64(len(values)-1)
+}
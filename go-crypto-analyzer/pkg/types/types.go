@@ -2,6 +2,8 @@ package types
 
 import (
 	"time"
+
+	"github.com/zjc/go-crypto-analyzer/pkg/i18n"
 )
 
 // OHLCV represents a candlestick data point
@@ -14,28 +16,40 @@ type OHLCV struct {
 	Volume float64
 }
 
-// TrendDirection represents the direction of a trend
+// TrendDirection represents the direction of a trend. Values are stable codes;
+// String returns the display label
 type TrendDirection string
 
 const (
-	StrongUptrend   TrendDirection = "强劲上涨趋势"
-	Uptrend         TrendDirection = "上涨趋势"
-	Sideways        TrendDirection = "横盘震荡"
-	Downtrend       TrendDirection = "下跌趋势"
-	StrongDowntrend TrendDirection = "强劲下跌趋势"
+	StrongUptrend   TrendDirection = "STRONG_UPTREND"
+	Uptrend         TrendDirection = "UPTREND"
+	Sideways        TrendDirection = "SIDEWAYS"
+	Downtrend       TrendDirection = "DOWNTREND"
+	StrongDowntrend TrendDirection = "STRONG_DOWNTREND"
 )
 
-// TrendStrength represents the strength of a trend
+// String returns the display label of the trend direction
+func (t TrendDirection) String() string {
+	return i18n.Label("trend", string(t))
+}
+
+// TrendStrength represents the strength of a trend. Values are stable codes;
+// String returns the display label
 type TrendStrength string
 
 const (
-	VeryStrong TrendStrength = "非常强"
-	Strong     TrendStrength = "强"
-	Moderate   TrendStrength = "中等"
-	Weak       TrendStrength = "弱"
-	NoTrend    TrendStrength = "无趋势"
+	VeryStrong TrendStrength = "VERY_STRONG"
+	Strong     TrendStrength = "STRONG"
+	Moderate   TrendStrength = "MODERATE"
+	Weak       TrendStrength = "WEAK"
+	NoTrend    TrendStrength = "NO_TREND"
 )
 
+// String returns the display label of the trend strength
+func (t TrendStrength) String() string {
+	return i18n.Label("strength", string(t))
+}
+
 // Analysis represents the complete analysis result
 type Analysis struct {
 	Symbol          string
@@ -49,6 +63,36 @@ type Analysis struct {
 	TrendStrength   TrendStrengthAnalysis
 	Volume          VolumeAnalysis
 	SupportResistance SRAnalysis
+	Divergences     []Divergence
+	Ichimoku        IchimokuAnalysis
+	VWAP            VWAPAnalysis
+	SuperTrend      TrailingStopAnalysis
+	ParabolicSAR    TrailingStopAnalysis
+	Channels        ChannelAnalysis
+	CandlePatterns  []CandlePattern
+	ChartPatterns   []ChartPattern
+	Fibonacci       FibonacciAnalysis
+	Volatility      VolatilityAnalysis
+	Structure       MarketStructureAnalysis
+	// RelativeStrength is set when the symbol is ranked against a watchlist
+	RelativeStrength *RelativeStrengthScore
+	// Indicators holds the latest values of indicators requested by spec,
+	// keyed by canonical spec such as "rsi(period=9)"
+	Indicators map[string]float64
+	// Expressions holds the latest values of custom indicator expressions in
+	// the order they were added
+	Expressions []ExpressionResult
+}
+
+// ExpressionResult is the latest value of a custom indicator expression
+type ExpressionResult struct {
+	Expression string
+	Value      float64
+	// Boolean marks conditions, whose Value is 1 (true) or 0 (false)
+	Boolean bool
+	// Valid is false when the expression is undefined on the latest candle,
+	// e.g. an indicator still in its warm-up period
+	Valid bool
 }
 
 // MAAnalysis represents moving average analysis
@@ -68,20 +112,580 @@ type MACDAnalysis struct {
 	MACD       float64
 	Signal     float64
 	Histogram  float64
-	Trend      string
-	Divergence string
+	Trend      MACDTrend
+	Divergence DivergenceType
+}
+
+// MACDTrend is the MACD line's position against its signal line. Values are
+// stable codes; String returns the display label
+type MACDTrend string
+
+const (
+	MACDBullish MACDTrend = "BULLISH"
+	MACDNeutral MACDTrend = "NEUTRAL"
+	MACDBearish MACDTrend = "BEARISH"
+)
+
+// String returns the display label of the MACD trend
+func (t MACDTrend) String() string {
+	return i18n.Label("macd", string(t))
+}
+
+// DivergenceType represents the kind of price/oscillator divergence. Values
+// are stable codes; String returns the display label
+type DivergenceType string
+
+const (
+	NoDivergence             DivergenceType = "NONE"
+	RegularBullishDivergence DivergenceType = "REGULAR_BULLISH"
+	RegularBearishDivergence DivergenceType = "REGULAR_BEARISH"
+	HiddenBullishDivergence  DivergenceType = "HIDDEN_BULLISH"
+	HiddenBearishDivergence  DivergenceType = "HIDDEN_BEARISH"
+)
+
+// String returns the display label of the divergence type
+func (t DivergenceType) String() string {
+	return i18n.Label("divergence", string(t))
+}
+
+// Divergence represents a divergence between price swings and an oscillator
+type Divergence struct {
+	Indicator      string // MACD, RSI or OBV
+	Type           DivergenceType
+	StartIndex     int // bar index of the earlier swing
+	EndIndex       int // bar index of the later swing
+	BarsAgo        int // bars between the later swing and the latest candle
+	StartTime      time.Time
+	EndTime        time.Time
+	PriceStart     float64
+	PriceEnd       float64
+	IndicatorStart float64
+	IndicatorEnd   float64
+	Strength       float64 // 0-1
 }
 
 // MomentumAnalysis represents momentum indicators
 type MomentumAnalysis struct {
 	RSI      float64
-	Momentum string
+	Momentum MomentumState
+	// Stochastic RSI(14,14,3,3) smoothed %K and %D
+	StochRSIK float64
+	StochRSID float64
+	// StochRSICross is the latest K/D cross inside an extreme zone:
+	// OversoldGoldenCross, OverboughtDeathCross or "" when none happened recently
+	StochRSICross        CrossType
+	StochRSICrossBarsAgo int
+}
+
+// MomentumState classifies RSI against the configured thresholds. Values are
+// stable codes; String returns the display label
+type MomentumState string
+
+const (
+	MomentumOverbought MomentumState = "OVERBOUGHT"
+	MomentumStrong     MomentumState = "STRONG"
+	MomentumNeutral    MomentumState = "NEUTRAL"
+	MomentumWeak       MomentumState = "WEAK"
+	MomentumOversold   MomentumState = "OVERSOLD"
+)
+
+// String returns the display label of the momentum state
+func (m MomentumState) String() string {
+	return i18n.Label("momentum", string(m))
 }
 
 // TrendStrengthAnalysis represents trend strength
 type TrendStrengthAnalysis struct {
 	ADX      float64
+	PlusDI   float64
+	MinusDI  float64
 	Strength TrendStrength
+	// DICross is the most recent +DI/-DI crossover: GoldenCross (+DI crossed
+	// above -DI), DeathCross (+DI crossed below -DI) or "" when no recent cross
+	DICross        CrossType
+	DICrossBarsAgo int
+}
+
+// CrossType is the kind of a line crossover. Values are stable codes;
+// String returns the display label
+type CrossType string
+
+const (
+	GoldenCross          CrossType = "GOLDEN"
+	DeathCross           CrossType = "DEATH"
+	OversoldGoldenCross  CrossType = "OVERSOLD_GOLDEN"
+	OverboughtDeathCross CrossType = "OVERBOUGHT_DEATH"
+)
+
+// String returns the display label of the crossover
+func (c CrossType) String() string {
+	return i18n.Label("cross", string(c))
+}
+
+// IchimokuAnalysis represents Ichimoku Kinko Hyo analysis (9/26/52, displacement 26)
+type IchimokuAnalysis struct {
+	Available bool // false when there is not enough data for the current cloud
+	Tenkan    float64
+	Kijun     float64
+	// SenkouA/SenkouB are the cloud values at the current candle,
+	// FutureSenkouA/FutureSenkouB the cloud plotted 26 candles ahead
+	SenkouA        float64
+	SenkouB        float64
+	FutureSenkouA  float64
+	FutureSenkouB  float64
+	CloudTop       float64
+	CloudBottom    float64
+	CloudThickness float64       // cloud height as % of price
+	CloudColor     CloudColor    // BullishCloud (A>B) or BearishCloud (A<B) of the future cloud
+	PricePosition  CloudPosition // AboveCloud, InsideCloud or BelowCloud
+	// TKCross is the latest Tenkan/Kijun cross in the last 5 candles (GoldenCross/
+	// DeathCross/"") and TKCrossPosition where it happened relative to the cloud
+	TKCross         CrossType
+	TKCrossPosition CloudPosition
+	TKCrossBarsAgo  int
+	// Chikou compares the current close with the candle 26 bars ago:
+	// ChikouAbove, ChikouBelow or ChikouTangled
+	Chikou       float64
+	ChikouStatus ChikouStatus
+	// TwistBarsAhead is the distance to the next Kumo twist in the projected
+	// cloud, 0 when none is projected
+	TwistBarsAhead int
+}
+
+// CloudColor is the color of the Ichimoku cloud. Values are stable codes;
+// String returns the display label
+type CloudColor string
+
+const (
+	BullishCloud CloudColor = "BULLISH"
+	BearishCloud CloudColor = "BEARISH"
+)
+
+// String returns the display label of the cloud color
+func (c CloudColor) String() string {
+	return i18n.Label("cloud", string(c))
+}
+
+// CloudPosition is a price relative to the Ichimoku cloud. Values are stable
+// codes; String returns the display label
+type CloudPosition string
+
+const (
+	AboveCloud  CloudPosition = "ABOVE"
+	InsideCloud CloudPosition = "INSIDE"
+	BelowCloud  CloudPosition = "BELOW"
+)
+
+// String returns the display label of the cloud position
+func (p CloudPosition) String() string {
+	return i18n.Label("cloud_position", string(p))
+}
+
+// ChikouStatus compares the Chikou span with the price it is plotted against.
+// Values are stable codes; String returns the display label
+type ChikouStatus string
+
+const (
+	ChikouAbove   ChikouStatus = "ABOVE"
+	ChikouBelow   ChikouStatus = "BELOW"
+	ChikouTangled ChikouStatus = "TANGLED"
+)
+
+// String returns the display label of the Chikou status
+func (c ChikouStatus) String() string {
+	return i18n.Label("chikou", string(c))
+}
+
+// VWAPAnalysis represents session VWAP with standard-deviation bands and
+// anchored VWAPs from the last major swing points
+type VWAPAnalysis struct {
+	SessionStart time.Time
+	SessionVWAP  float64
+	StdDev       float64
+	Upper1       float64
+	Lower1       float64
+	Upper2       float64
+	Lower2       float64
+	// BandPosition is the price zone relative to the session bands
+	BandPosition VWAPBand
+	// AnchoredLow/AnchoredHigh are VWAPs anchored at the last major swing
+	// low/high, 0 when no swing was found
+	AnchoredLow      float64
+	AnchoredLowTime  time.Time
+	AnchoredHigh     float64
+	AnchoredHighTime time.Time
+	// AnchoredCustom is the VWAP anchored at a user supplied time, 0 when unset
+	AnchoredCustom     float64
+	AnchoredCustomTime time.Time
+}
+
+// VWAPBand is the price zone relative to the VWAP standard-deviation bands.
+// Values are stable codes; String returns the display label
+type VWAPBand string
+
+const (
+	AboveUpper2  VWAPBand = "ABOVE_UPPER_2"
+	Upper1To2    VWAPBand = "UPPER_1_2"
+	VWAPToUpper1 VWAPBand = "VWAP_UPPER_1"
+	Lower1ToVWAP VWAPBand = "LOWER_1_VWAP"
+	Lower1To2    VWAPBand = "LOWER_1_2"
+	BelowLower2  VWAPBand = "BELOW_LOWER_2"
+)
+
+// String returns the display label of the VWAP band
+func (b VWAPBand) String() string {
+	return i18n.Label("vwap_band", string(b))
+}
+
+// TrailingStopAnalysis represents a trailing-stop trend indicator
+// (SuperTrend or Parabolic SAR)
+type TrailingStopAnalysis struct {
+	Available bool
+	Level     float64 // current stop level
+	Direction StopSide // LongSide or ShortSide
+	Distance  float64 // distance from price to the stop level as % of price
+	// FlipBarsAgo is the number of candles since the last direction flip,
+	// -1 when no flip happened in the analyzed data
+	FlipBarsAgo int
+}
+
+// StopSide is the side a trailing stop protects. Values are stable codes;
+// String returns the display label
+type StopSide string
+
+const (
+	LongSide  StopSide = "LONG"
+	ShortSide StopSide = "SHORT"
+)
+
+// String returns the display label of the stop side
+func (s StopSide) String() string {
+	return i18n.Label("side", string(s))
+}
+
+// ChannelAnalysis represents Bollinger, Keltner and Donchian channels and the
+// TTM squeeze (Bollinger Bands inside Keltner Channels)
+type ChannelAnalysis struct {
+	Available       bool
+	BollingerPeriod int
+	BollingerStdDev float64
+	BBUpper         float64
+	BBMiddle        float64
+	BBLower         float64
+	PercentB        float64 // (price-lower)/(upper-lower), <0 below / >1 above the bands
+	Bandwidth       float64 // (upper-lower)/middle as %
+	// BandwidthRank is the percentile (0-100) of the current bandwidth within
+	// the analyzed candles, low values mean unusually tight bands
+	BandwidthRank float64
+	KCUpper       float64
+	KCMiddle      float64
+	KCLower       float64
+	DonchianPeriod int
+	DonchianUpper  float64
+	DonchianLower  float64
+	// DonchianBreakout is BreakoutUp/BreakoutDown when the close broke the
+	// previous candle's Donchian channel, "" otherwise
+	DonchianBreakout ChannelBreakout
+	Squeeze          bool // squeeze is on at the current candle
+	SqueezeBars      int  // consecutive candles the current squeeze has lasted
+	// SqueezeFired is SqueezeFiredUp/SqueezeFiredDown when a squeeze released
+	// within the last 3 candles, "" otherwise
+	SqueezeFired        SqueezeRelease
+	SqueezeFiredBarsAgo int
+	SqueezeMomentum     float64
+}
+
+// ChannelBreakout is the direction of a channel breakout. Values are stable
+// codes; String returns the display label
+type ChannelBreakout string
+
+const (
+	BreakoutUp   ChannelBreakout = "UP"
+	BreakoutDown ChannelBreakout = "DOWN"
+)
+
+// String returns the display label of the channel breakout
+func (b ChannelBreakout) String() string {
+	return i18n.Label("breakout", string(b))
+}
+
+// SqueezeRelease is the direction a volatility squeeze released in. Values
+// are stable codes; String returns the display label
+type SqueezeRelease string
+
+const (
+	SqueezeFiredUp   SqueezeRelease = "UP"
+	SqueezeFiredDown SqueezeRelease = "DOWN"
+)
+
+// String returns the display label of the squeeze release
+func (r SqueezeRelease) String() string {
+	return i18n.Label("squeeze", string(r))
+}
+
+// CandlePattern represents a candlestick pattern completed at a candle
+type CandlePattern struct {
+	Name      CandlePatternType // e.g. BullishEngulfing, Hammer
+	Direction int               // 1 bullish, -1 bearish, 0 indecision
+	Candles   int               // number of candles forming the pattern
+	Index     int               // index of the candle completing the pattern
+	BarsAgo   int
+	Time      time.Time
+	// Reliability is the base weight of the pattern (0-1)
+	Reliability float64
+	// RequiredTrend is the prior trend the pattern needs to be meaningful
+	// (1 uptrend, -1 downtrend, 0 none) and ContextOK whether it was present
+	RequiredTrend int
+	ContextOK     bool
+}
+
+// CandlePatternType names a candlestick pattern. Values are stable codes;
+// String returns the display label
+type CandlePatternType string
+
+const (
+	GravestoneDoji     CandlePatternType = "GRAVESTONE_DOJI"
+	DragonflyDoji      CandlePatternType = "DRAGONFLY_DOJI"
+	LongLeggedDoji     CandlePatternType = "LONG_LEGGED_DOJI"
+	Doji               CandlePatternType = "DOJI"
+	Hammer             CandlePatternType = "HAMMER"
+	ShootingStar       CandlePatternType = "SHOOTING_STAR"
+	BullishEngulfing   CandlePatternType = "BULLISH_ENGULFING"
+	BearishEngulfing   CandlePatternType = "BEARISH_ENGULFING"
+	BullishHarami      CandlePatternType = "BULLISH_HARAMI"
+	BearishHarami      CandlePatternType = "BEARISH_HARAMI"
+	PiercingLine       CandlePatternType = "PIERCING_LINE"
+	DarkCloudCover     CandlePatternType = "DARK_CLOUD_COVER"
+	InsideBar          CandlePatternType = "INSIDE_BAR"
+	OutsideBar         CandlePatternType = "OUTSIDE_BAR"
+	MorningStar        CandlePatternType = "MORNING_STAR"
+	EveningStar        CandlePatternType = "EVENING_STAR"
+	ThreeWhiteSoldiers CandlePatternType = "THREE_WHITE_SOLDIERS"
+	ThreeBlackCrows    CandlePatternType = "THREE_BLACK_CROWS"
+)
+
+// String returns the display label of the candlestick pattern
+func (t CandlePatternType) String() string {
+	return i18n.Label("candle", string(t))
+}
+
+// ChartPattern represents a geometric chart pattern built on swing points
+type ChartPattern struct {
+	Name ChartPatternType // e.g. DoubleTop, InverseHeadAndShoulders, AscendingTriangle
+	// Direction is the breakout direction the pattern implies (1 bullish,
+	// -1 bearish), 0 for a symmetrical triangle that has not broken out yet
+	Direction  int
+	StartIndex int
+	EndIndex   int
+	// BreakoutLevel is the neckline/trendline value at the current candle whose
+	// break confirms the pattern, InvalidationLevel the level that voids it
+	BreakoutLevel     float64
+	InvalidationLevel float64
+	Target            float64 // measured-move target
+	Confidence        float64 // 0-1
+	Status            ChartPatternStatus // PatternForming, PatternBrokeOut or PatternBrokeDown
+	BreakoutBarsAgo   int                // candles since the confirmed breakout, -1 while forming
+}
+
+// ChartPatternType names a chart pattern. Values are stable codes; String
+// returns the display label
+type ChartPatternType string
+
+const (
+	DoubleTop               ChartPatternType = "DOUBLE_TOP"
+	DoubleBottom            ChartPatternType = "DOUBLE_BOTTOM"
+	TripleTop               ChartPatternType = "TRIPLE_TOP"
+	TripleBottom            ChartPatternType = "TRIPLE_BOTTOM"
+	HeadAndShoulders        ChartPatternType = "HEAD_AND_SHOULDERS"
+	InverseHeadAndShoulders ChartPatternType = "INVERSE_HEAD_AND_SHOULDERS"
+	AscendingTriangle       ChartPatternType = "ASCENDING_TRIANGLE"
+	DescendingTriangle      ChartPatternType = "DESCENDING_TRIANGLE"
+	SymmetricalTriangle     ChartPatternType = "SYMMETRICAL_TRIANGLE"
+	BullFlag                ChartPatternType = "BULL_FLAG"
+	BearFlag                ChartPatternType = "BEAR_FLAG"
+	BullishPennant          ChartPatternType = "BULLISH_PENNANT"
+	BearishPennant          ChartPatternType = "BEARISH_PENNANT"
+)
+
+// String returns the display label of the chart pattern
+func (t ChartPatternType) String() string {
+	return i18n.Label("chart", string(t))
+}
+
+// ChartPatternStatus tells whether a chart pattern has been confirmed. Values
+// are stable codes; String returns the display label
+type ChartPatternStatus string
+
+const (
+	PatternForming   ChartPatternStatus = "FORMING"
+	PatternBrokeOut  ChartPatternStatus = "BROKE_OUT"
+	PatternBrokeDown ChartPatternStatus = "BROKE_DOWN"
+)
+
+// String returns the display label of the chart pattern status
+func (s ChartPatternStatus) String() string {
+	return i18n.Label("pattern_status", string(s))
+}
+
+// SwingLabel labels a swing point against the previous swing of the same side
+type SwingLabel string
+
+const (
+	HigherHigh SwingLabel = "HH"
+	LowerHigh  SwingLabel = "LH"
+	HigherLow  SwingLabel = "HL"
+	LowerLow   SwingLabel = "LL"
+)
+
+// StructureSwing is a labeled swing point; the first swing of each side has
+// an empty Label
+type StructureSwing struct {
+	Index  int
+	Time   time.Time
+	Price  float64
+	IsHigh bool
+	Label  SwingLabel
+}
+
+// StructureEventType is a break of structure (continuation) or a change of
+// character (first break against the prevailing structure)
+type StructureEventType string
+
+const (
+	BreakOfStructure  StructureEventType = "BOS"
+	ChangeOfCharacter StructureEventType = "CHoCH"
+)
+
+// StructureEvent is a candle close beyond a confirmed swing point
+type StructureEvent struct {
+	Type    StructureEventType
+	Bullish bool
+	// Level is the price of the broken swing point and SwingIndex its candle
+	Level      float64
+	SwingIndex int
+	Index      int
+	Time       time.Time
+	BarsAgo    int
+}
+
+// MarketStructureAnalysis is the swing structure (HH/HL/LH/LL) of the market,
+// its break events and the trend it implies
+type MarketStructureAnalysis struct {
+	Available bool
+	// Bias is 1 for bullish structure, -1 for bearish and 0 before the first
+	// break; Trend classifies it together with the latest swing labels
+	Bias  int
+	Trend TrendDirection
+	// Swings are the labeled swing points, oldest first
+	Swings    []StructureSwing
+	LastHigh  StructureSwing
+	LastLow   StructureSwing
+	Events    []StructureEvent
+	LastEvent *StructureEvent
+	// ProtectedLevel is the swing whose break would change the character of
+	// the structure: the last swing low in bullish structure, the last swing
+	// high in bearish structure
+	ProtectedLevel float64
+}
+
+// VolatilityAnalysis holds realized volatility estimates annualized by the
+// actual candle interval, and the Hurst exponent / variance ratio regime
+type VolatilityAnalysis struct {
+	Available      bool
+	Interval       time.Duration
+	PeriodsPerYear float64
+	// Window is the number of candles of the short-term estimates and
+	// LongWindow that of the long-term Yang–Zhang baseline
+	Window     int
+	LongWindow int
+	// Annualized volatility (0.5 = 50%) by estimator over Window candles
+	CloseToClose   float64
+	Parkinson      float64
+	GarmanKlass    float64
+	RogersSatchell float64
+	YangZhang      float64
+	// LongTerm is the Yang–Zhang volatility over LongWindow candles and Ratio
+	// the short-term Yang–Zhang volatility relative to it
+	LongTerm float64
+	Ratio    float64
+	// Hurst exponent (>0.5 trending, <0.5 mean reverting) and the Lo–MacKinlay
+	// variance ratio with its z statistic; HurstAvailable is false when the
+	// data is too short for the Hurst regression
+	HurstAvailable bool
+	Hurst          float64
+	VarianceRatio  float64
+	VarianceRatioZ float64
+	// Regime is trending, mean_reverting or random_walk
+	Regime string
+}
+
+// FibLevel represents a Fibonacci retracement or extension level
+type FibLevel struct {
+	Ratio float64 // e.g. 0.618, 1.272
+	Price float64
+	Kind  FibKind // Retracement or Extension
+}
+
+// FibKind tells a Fibonacci retracement from an extension. Values are stable
+// codes; String returns the display label
+type FibKind string
+
+const (
+	Retracement FibKind = "RETRACEMENT"
+	Extension   FibKind = "EXTENSION"
+)
+
+// String returns the display label of the Fibonacci level kind
+func (k FibKind) String() string {
+	return i18n.Label("fib", string(k))
+}
+
+// FibonacciAnalysis represents Fibonacci levels of the dominant ZigZag swing
+type FibonacciAnalysis struct {
+	Available bool
+	// Upswing is true when the swing low came before the swing high, so the
+	// retracements are measured down from the high and extensions lie above it
+	Upswing       bool
+	SwingHigh     float64
+	SwingLow      float64
+	SwingHighTime time.Time
+	SwingLowTime  time.Time
+	Retracements  []FibLevel
+	Extensions    []FibLevel
+	// Testing is the level price is currently testing (zero Ratio when none)
+	// and TestingDistance the distance from price to it in % of price, positive
+	// when price is above the level
+	Testing         FibLevel
+	TestingDistance float64
+}
+
+// Levels returns the retracement and extension levels together
+func (f FibonacciAnalysis) Levels() []FibLevel {
+	return append(append([]FibLevel{}, f.Retracements...), f.Extensions...)
+}
+
+// NextLevelAbove returns the nearest Fibonacci level strictly above price,
+// 0 when there is none
+func (f FibonacciAnalysis) NextLevelAbove(price float64) float64 {
+	next := 0.0
+	for _, level := range f.Levels() {
+		if level.Price > price && (next == 0 || level.Price < next) {
+			next = level.Price
+		}
+	}
+	return next
+}
+
+// NextLevelBelow returns the nearest Fibonacci level strictly below price,
+// 0 when there is none
+func (f FibonacciAnalysis) NextLevelBelow(price float64) float64 {
+	next := 0.0
+	for _, level := range f.Levels() {
+		if level.Price > 0 && level.Price < price && level.Price > next {
+			next = level.Price
+		}
+	}
+	return next
 }
 
 // VolumeAnalysis represents volume analysis
@@ -89,35 +693,196 @@ type VolumeAnalysis struct {
 	CurrentVolume float64
 	VolumeMA      float64
 	VolumeRatio   float64
-	VolumeTrend   string
+	VolumeTrend   VolumeTrend
+}
+
+// VolumeTrend classifies the current volume against its moving average.
+// Values are stable codes; String returns the display label
+type VolumeTrend string
+
+const (
+	HighVolume         VolumeTrend = "HIGH"
+	NormalVolume       VolumeTrend = "NORMAL"
+	LowVolume          VolumeTrend = "LOW"
+	InsufficientVolume VolumeTrend = "INSUFFICIENT_DATA"
+)
+
+// String returns the display label of the volume trend
+func (v VolumeTrend) String() string {
+	return i18n.Label("volume", string(v))
 }
 
 // SRAnalysis represents support and resistance analysis
 type SRAnalysis struct {
 	Pivot      float64
-	Resistance map[string]float64
-	Support    map[string]float64
+	Resistance map[string]float64 // "R1".."R4" (DeMark only has R1)
+	Support    map[string]float64 // "S1".."S4" (DeMark only has S1)
+	// PivotMethod is the pivot formula (floor, fibonacci, camarilla, woodie or
+	// demark) and PivotPeriod the completed period it was computed from ("day",
+	// "week" or "month"), empty when the last candle had to be used instead.
+	// PivotPartial is set when the data starts after PivotPeriodStart
+	PivotMethod      string
+	PivotPeriod      string
+	PivotPeriodStart time.Time
+	PivotPartial     bool
+	// Volume profile levels over the analysis lookback: point of control,
+	// 70% value area high/low and high/low-volume nodes (ascending).
+	// All zero/empty when the profile is unavailable
+	POC float64
+	VAH float64
+	VAL float64
+	HVN []float64
+	LVN []float64
+	// Levels are clustered swing highs/lows merged with configured key levels,
+	// ascending by price. NearestResistance/NearestSupport are the closest
+	// levels above/below the current price (zero Price when none) and the
+	// distances are in % of price
+	Levels             []SRLevel
+	NearestResistance  SRLevel
+	NearestSupport     SRLevel
+	ResistanceDistance float64
+	SupportDistance    float64
+}
+
+// SRLevel represents a support/resistance level built from clustered swing
+// points and/or configured key levels
+type SRLevel struct {
+	Price float64
+	// Source lists where the level comes from, e.g. SwingSource or
+	// PsychologicalSource, joined with "+" when several agree
+	Source      SRSource
+	Touches     int     // swing points in the cluster
+	LastBarsAgo int     // candles since the latest touch, -1 for untested key levels
+	VolumeRatio float64 // average touch volume relative to the average volume
+	Score       float64 // 0-1, from touches, recency and volume
+}
+
+// SRSource is where a support/resistance level comes from. Values are stable
+// codes, several joined with "+"; String returns the display label
+type SRSource string
+
+const (
+	SwingSource                SRSource = "SWING"
+	PsychologicalSource        SRSource = "PSYCHOLOGICAL"
+	HistoricalSupportSource    SRSource = "HISTORICAL_SUPPORT"
+	HistoricalResistanceSource SRSource = "HISTORICAL_RESISTANCE"
+)
+
+// String returns the display label of the level source
+func (s SRSource) String() string {
+	return i18n.Label("source", string(s))
+}
+
+// ResistanceTarget returns the nearest resistance level, falling back to the
+// classic pivot R1 when no level lies above price
+func (sr SRAnalysis) ResistanceTarget() float64 {
+	if sr.NearestResistance.Price > 0 {
+		return sr.NearestResistance.Price
+	}
+	return sr.Resistance["R1"]
+}
+
+// SupportTarget returns the nearest support level, falling back to the
+// classic pivot S1 when no level lies below price
+func (sr SRAnalysis) SupportTarget() float64 {
+	if sr.NearestSupport.Price > 0 {
+		return sr.NearestSupport.Price
+	}
+	return sr.Support["S1"]
+}
+
+// NextVolumeLevelAbove returns the nearest volume-profile level (POC, VAH, VAL
+// or HVN) strictly above price, 0 when there is none
+func (sr SRAnalysis) NextVolumeLevelAbove(price float64) float64 {
+	next := 0.0
+	for _, level := range append([]float64{sr.POC, sr.VAH, sr.VAL}, sr.HVN...) {
+		if level > price && (next == 0 || level < next) {
+			next = level
+		}
+	}
+	return next
+}
+
+// NextVolumeLevelBelow returns the nearest volume-profile level (POC, VAH, VAL
+// or HVN) strictly below price, 0 when there is none
+func (sr SRAnalysis) NextVolumeLevelBelow(price float64) float64 {
+	next := 0.0
+	for _, level := range append([]float64{sr.POC, sr.VAH, sr.VAL}, sr.HVN...) {
+		if level > 0 && level < price && level > next {
+			next = level
+		}
+	}
+	return next
 }
 
 // Evidence represents a piece of analysis evidence
 type Evidence struct {
+	// ID is the stable code of the rule that produced the evidence, such as
+	// "ma.price_above_ma5"; logic identifies evidence by ID, never by text
+	ID          string
 	Type        EvidenceType
+	// Category is a category code such as "ma" or "rsi"
 	Category    string
 	Description string
 	Strength    float64
 	Data        map[string]interface{}
 }
 
-// EvidenceType represents the type of evidence
+// CategoryLabel returns the display label of the evidence category; unknown
+// categories (e.g. from custom rules) are shown as written
+func (e Evidence) CategoryLabel() string {
+	return i18n.Label("category", e.Category)
+}
+
+// EvidenceType represents the type of evidence. Values are stable codes;
+// String returns the display label
 type EvidenceType string
 
 const (
-	BullishEvidence EvidenceType = "看涨证据"
-	BearishEvidence EvidenceType = "看跌证据"
-	NeutralEvidence EvidenceType = "中性证据"
-	WarningEvidence EvidenceType = "警告信号"
+	BullishEvidence EvidenceType = "BULLISH"
+	BearishEvidence EvidenceType = "BEARISH"
+	NeutralEvidence EvidenceType = "NEUTRAL"
+	WarningEvidence EvidenceType = "WARNING"
 )
 
+// String returns the display label of the evidence type
+func (t EvidenceType) String() string {
+	return i18n.Label("evidence", string(t))
+}
+
+// Judgment is the overall call on the collected evidence. Values are stable
+// codes; String returns the display label
+type Judgment string
+
+const (
+	StrongBullishJudgment Judgment = "STRONG_BULLISH"
+	BullishJudgment       Judgment = "BULLISH"
+	NeutralJudgment       Judgment = "NEUTRAL"
+	BearishJudgment       Judgment = "BEARISH"
+	StrongBearishJudgment Judgment = "STRONG_BEARISH"
+)
+
+// String returns the display label of the judgment
+func (j Judgment) String() string {
+	return i18n.Label("judgment", string(j))
+}
+
+// SignalConflict is a disagreement between pieces of evidence that calls for
+// caution. Values are stable codes; String returns the display label
+type SignalConflict string
+
+const (
+	// MAMACDConflict is the MA5/MA20 cross pointing against MACD
+	MAMACDConflict SignalConflict = "MA_MACD"
+	// VolumeSurgeDownConflict is a selloff on surging volume
+	VolumeSurgeDownConflict SignalConflict = "VOLUME_SURGE_DOWN"
+)
+
+// String returns the display label of the conflict
+func (c SignalConflict) String() string {
+	return i18n.Label("conflict", string(c))
+}
+
 // CryptoConfig represents cryptocurrency configuration
 type CryptoConfig struct {
 	Symbol       string
@@ -139,6 +904,155 @@ type KeyLevels struct {
 type FearGreedIndex struct {
 	Value          int
 	Classification string
-	Sentiment      string
+	Sentiment      Sentiment
 	Timestamp      time.Time
-}
\ No newline at end of file
+}
+
+// Sentiment is the market mood implied by the fear and greed index. Values
+// are stable codes; String returns the display label
+type Sentiment string
+
+const (
+	ExtremeFear  Sentiment = "EXTREME_FEAR"
+	Fear         Sentiment = "FEAR"
+	NeutralMood  Sentiment = "NEUTRAL"
+	Greed        Sentiment = "GREED"
+	ExtremeGreed Sentiment = "EXTREME_GREED"
+)
+
+// String returns the display label of the sentiment
+func (s Sentiment) String() string {
+	return i18n.Label("sentiment", string(s))
+}
+
+// CrossAssetAnalysis holds the correlation structure of a watchlist computed
+// on log returns aligned on common timestamps
+type CrossAssetAnalysis struct {
+	Symbols []string
+	// Observations is the number of aligned returns in the window ending at End
+	Observations int
+	Start        time.Time
+	End          time.Time
+	// Pearson and Spearman correlation matrices, indexed like Symbols
+	Pearson  [][]float64
+	Spearman [][]float64
+	// Benchmark is the symbol betas are measured against (usually BTCUSDT)
+	Benchmark string
+	Betas     []AssetBeta
+	// Clusters groups symbols whose average pairwise correlation exceeds the
+	// clustering threshold, largest first; singletons are omitted
+	Clusters           [][]string
+	AverageCorrelation float64
+	// EffectiveBets is N²/Σρ², the number of independent bets the watchlist
+	// amounts to (N when uncorrelated, 1 when perfectly correlated)
+	EffectiveBets float64
+	// RollingWindow is the number of returns each rolling correlation is
+	// computed on; Rolling holds one series per symbol pair
+	RollingWindow int
+	Rolling       []PairCorrelation
+	Warnings      []string
+}
+
+// PairCorrelation is the rolling Pearson correlation of two symbols, one value
+// per aligned return of the correlation window ending at End (NaN until the
+// rolling window is filled)
+type PairCorrelation struct {
+	A      string
+	B      string
+	Values []float64
+}
+
+// AssetBeta is the beta and correlation of a symbol to the benchmark;
+// PreviousCorrelation is the correlation over the preceding window (NaN when
+// the data does not cover it)
+type AssetBeta struct {
+	Symbol              string
+	Beta                float64
+	Correlation         float64
+	PreviousCorrelation float64
+}
+
+// RelativeStrengthAnalysis ranks a watchlist by performance over several
+// lookbacks relative to the benchmark and to an equal-weight basket
+type RelativeStrengthAnalysis struct {
+	AsOf time.Time
+	// Lookbacks are labels such as "7d", indexing the per-lookback slices
+	Lookbacks []string
+	Benchmark string
+	// BenchmarkReturns and BasketReturns are simple returns per lookback (NaN
+	// when the data does not cover it)
+	BenchmarkReturns []float64
+	BasketReturns    []float64
+	// Leaderboard is sorted by composite score, strongest first; symbols
+	// without any covered lookback come last with Rank 0
+	Leaderboard []RelativeStrengthScore
+}
+
+// Score returns the leaderboard entry of symbol
+func (rs RelativeStrengthAnalysis) Score(symbol string) (RelativeStrengthScore, bool) {
+	for _, score := range rs.Leaderboard {
+		if score.Symbol == symbol {
+			return score, true
+		}
+	}
+	return RelativeStrengthScore{}, false
+}
+
+// RelativeStrengthScore is the relative strength of a symbol within a watchlist
+type RelativeStrengthScore struct {
+	Symbol string
+	Rank   int
+	// Returns, VsBenchmark and VsBasket are indexed like the lookbacks; the
+	// relative values are the change of the ratio line, (1+r)/(1+r_ref)-1
+	Returns     []float64
+	VsBenchmark []float64
+	VsBasket    []float64
+	// RSLineNewHigh marks a ratio line against the benchmark at its high over
+	// the longest covered lookback
+	RSLineNewHigh bool
+	// Composite is the weighted log excess return over the basket in percent
+	Composite float64
+	// Percentile is the position of Composite within the watchlist, 0-100
+	Percentile float64
+}
+
+// MultiTimeframeAnalysis is the confluence of one symbol across timeframes
+type MultiTimeframeAnalysis struct {
+	Symbol string
+	// Timeframes are ordered from the shortest to the longest interval
+	Timeframes []TimeframeAnalysis
+	// Confluence is the weighted average of the timeframe scores in percent,
+	// -100 (all bearish) to 100 (all bullish)
+	Confluence float64
+	Direction  TrendDirection
+	// Alignment is the share of the weight agreeing with the direction, 0-1
+	Alignment float64
+	Conflicts []TimeframeConflict
+}
+
+// TimeframeAnalysis is the alignment matrix row of one timeframe
+type TimeframeAnalysis struct {
+	Interval string
+	// Weight grows with the interval so that higher timeframes dominate
+	Weight     float64
+	Trend      TrendDirection
+	TrendScore float64
+	RSI        float64
+	Momentum   MomentumState
+	MACDTrend  MACDTrend
+	// EvidenceStrength is the total strength of the collected evidence
+	EvidenceStrength float64
+	// Bias is 1 for an uptrend, -1 for a downtrend and 0 for sideways
+	Bias int
+	// Score combines trend and evidence, -1 to 1
+	Score float64
+}
+
+// TimeframeConflict is a lower timeframe trading against a higher one, such
+// as a 1h uptrend inside a 1d downtrend
+type TimeframeConflict struct {
+	Lower       string
+	Higher      string
+	LowerTrend  TrendDirection
+	HigherTrend TrendDirection
+}
@@ -3,6 +3,8 @@ package utils
 import (
 	"fmt"
 	"time"
+
+	"github.com/zjc/go-crypto-analyzer/pkg/i18n"
 )
 
 // Timer 性能计时器
@@ -23,9 +25,9 @@ func NewTimer(name string) *Timer {
 func (t *Timer) Stop() {
 	duration := time.Since(t.start)
 	if duration > time.Second {
-		fmt.Printf("⏱️  %s 耗时: %.2f秒\n", t.name, duration.Seconds())
+		fmt.Println(i18n.T("timer.seconds", t.name, duration.Seconds()))
 	} else if duration > time.Millisecond {
-		fmt.Printf("⏱️  %s 耗时: %dms\n", t.name, duration.Milliseconds())
+		fmt.Println(i18n.T("timer.milliseconds", t.name, duration.Milliseconds()))
 	}
 }
 